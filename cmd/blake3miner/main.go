@@ -0,0 +1,183 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+// blake3miner is a standalone Blake3 proof-of-work miner, mining the work
+// packages of one or more nodes without running a node itself.
+package main
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/internal/flags"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	nodeFlag = &cli.StringSliceFlag{
+		Name:  "node",
+		Usage: "RPC endpoint of a node to mine for, repeat for fallback nodes in order of preference",
+		Value: cli.NewStringSlice("http://127.0.0.1:8545"),
+	}
+	threadsFlag = &cli.IntFlag{
+		Name:  "threads",
+		Usage: "number of mining threads (0 = one per CPU)",
+	}
+	affinityFlag = &cli.StringFlag{
+		Name:  "affinity",
+		Usage: "comma separated list of CPUs the mining threads are pinned to, round robin (Linux only)",
+	}
+	recheckFlag = &cli.DurationFlag{
+		Name:  "recheck",
+		Usage: "interval of polling the node for new work",
+		Value: time.Second,
+	}
+	notifyFlag = &cli.StringFlag{
+		Name:  "notify.addr",
+		Usage: "listen address for work pushed by nodes running with --miner.notify",
+	}
+	reportFlag = &cli.DurationFlag{
+		Name:  "report",
+		Usage: "interval of reporting the hashrate of every thread to the node (0 = disabled)",
+		Value: 10 * time.Second,
+	}
+	idFlag = &cli.StringFlag{
+		Name:  "id",
+		Usage: "identifier of the miner in hashrate reports (default = random)",
+	}
+	benchmarkFlag = &cli.BoolFlag{
+		Name:  "benchmark",
+		Usage: "measure the hashrate of the mining threads instead of mining",
+	}
+	benchmarkTimeFlag = &cli.DurationFlag{
+		Name:  "benchmark.time",
+		Usage: "duration of the benchmark",
+		Value: 30 * time.Second,
+	}
+	benchmarkIterationsFlag = &cli.Uint64Flag{
+		Name:  "benchmark.iterations",
+		Usage: "number of chained Blake3 rounds per hash in the benchmark",
+		Value: params.DefaultBlake3Iterations,
+	}
+	verbosityFlag = &cli.IntFlag{
+		Name:  "verbosity",
+		Usage: "log verbosity (0-5)",
+		Value: int(log.LvlInfo),
+	}
+)
+
+var app = flags.NewApp("standalone Blake3 proof-of-work miner")
+
+func init() {
+	app.Flags = []cli.Flag{
+		nodeFlag,
+		threadsFlag,
+		affinityFlag,
+		recheckFlag,
+		notifyFlag,
+		reportFlag,
+		idFlag,
+		benchmarkFlag,
+		benchmarkTimeFlag,
+		benchmarkIterationsFlag,
+		verbosityFlag,
+	}
+	app.Action = run
+}
+
+func main() {
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx *cli.Context) error {
+	log.Root().SetHandler(log.LvlFilterHandler(log.Lvl(ctx.Int(verbosityFlag.Name)), log.StreamHandler(os.Stderr, log.TerminalFormat(true))))
+
+	threads := ctx.Int(threadsFlag.Name)
+	if threads <= 0 {
+		threads = runtime.NumCPU()
+	}
+	affinity, err := parseCPUs(ctx.String(affinityFlag.Name))
+	if err != nil {
+		return fmt.Errorf("invalid --%s: %v", affinityFlag.Name, err)
+	}
+	if ctx.Bool(benchmarkFlag.Name) {
+		iters, duration := ctx.Uint64(benchmarkIterationsFlag.Name), ctx.Duration(benchmarkTimeFlag.Name)
+		fmt.Printf("Benchmarking %d threads, %d Blake3 rounds per hash, for %v\n", threads, iters, duration)
+
+		var total float64
+		for i, rate := range benchmark(threads, affinity, iters, duration) {
+			fmt.Printf("Thread %3d: %10.2f H/s\n", i, rate)
+			total += rate
+		}
+		fmt.Printf("Total:      %10.2f H/s\n", total)
+		return nil
+	}
+	id := common.HexToHash(ctx.String(idFlag.Name))
+	if !ctx.IsSet(idFlag.Name) {
+		rand.Read(id[:])
+	}
+	m := newMiner(config{
+		nodes:    ctx.StringSlice(nodeFlag.Name),
+		threads:  threads,
+		affinity: affinity,
+		recheck:  ctx.Duration(recheckFlag.Name),
+		report:   ctx.Duration(reportFlag.Name),
+		notify:   ctx.String(notifyFlag.Name),
+		id:       id,
+	})
+	if err := m.start(); err != nil {
+		return err
+	}
+	defer m.stop()
+
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+	<-sigc
+	log.Info("Got interrupt, shutting down...")
+	return nil
+}
+
+// parseCPUs parses a comma separated list of CPU indices.
+func parseCPUs(list string) ([]int, error) {
+	if list == "" {
+		return nil, nil
+	}
+	var cpus []int
+	for _, field := range strings.Split(list, ",") {
+		cpu, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, err
+		}
+		if cpu < 0 {
+			return nil, fmt.Errorf("negative CPU %d", cpu)
+		}
+		cpus = append(cpus, cpu)
+	}
+	return cpus, nil
+}
@@ -0,0 +1,499 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// rpcTimeout is the maximum time an RPC request to a node may take.
+const rpcTimeout = 5 * time.Second
+
+var (
+	errNoNodes      = errors.New("no nodes configured")
+	errNoWork       = errors.New("no node returned work")
+	errLegacyWork   = errors.New("legacy DAG work packages are not supported")
+	errInvalidWork  = errors.New("invalid work package")
+	errZeroTarget   = errors.New("work package with zero target")
+	errMinerStopped = errors.New("miner stopped")
+)
+
+// config contains the settings of the miner.
+type config struct {
+	nodes    []string      // RPC endpoints of the nodes, in order of preference
+	threads  int           // Number of mining threads
+	affinity []int         // CPUs the mining threads are pinned to, round robin
+	recheck  time.Duration // Interval of polling the active node for new work
+	report   time.Duration // Interval of reporting the hashrate, zero if disabled
+	notify   string        // Listen address for pushed work, empty if disabled
+	id       common.Hash   // Identifier of the miner in hashrate reports
+}
+
+// work is a work package handed out by a node.
+type work struct {
+	sealHash   common.Hash
+	iterations uint64   // Blake3 rounds per hash
+	target     *big.Int // Boundary the digest must not exceed, 2^256/difficulty
+	number     uint64
+	node       int // Index of the node to submit solutions to
+}
+
+// task is a work package handed to a mining thread.
+type task struct {
+	work  *work
+	abort chan struct{} // Closed when the work is superseded
+}
+
+// solution is a nonce found by a mining thread.
+type solution struct {
+	work   *work
+	nonce  types.BlockNonce
+	digest common.Hash
+}
+
+// parseWork decodes a work package in the eth_getWork format.
+func parseWork(pkg []string) (*work, error) {
+	if len(pkg) < 4 {
+		return nil, fmt.Errorf("%w: %d fields", errInvalidWork, len(pkg))
+	}
+	hash, err := hexutil.Decode(pkg[0])
+	if err != nil || len(hash) != common.HashLength {
+		return nil, fmt.Errorf("%w: seal hash %q", errInvalidWork, pkg[0])
+	}
+	// Legacy nodes hand out the 32 byte DAG seed hash instead of the rounds
+	if len(pkg[1]) == 2+2*common.HashLength {
+		return nil, errLegacyWork
+	}
+	iterations, err := hexutil.DecodeUint64(pkg[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: iterations %q", errInvalidWork, pkg[1])
+	}
+	target, err := hexutil.Decode(pkg[2])
+	if err != nil || len(target) > common.HashLength {
+		return nil, fmt.Errorf("%w: target %q", errInvalidWork, pkg[2])
+	}
+	number, err := hexutil.DecodeUint64(pkg[3])
+	if err != nil {
+		return nil, fmt.Errorf("%w: number %q", errInvalidWork, pkg[3])
+	}
+	w := &work{
+		sealHash:   common.BytesToHash(hash),
+		iterations: iterations,
+		target:     new(big.Int).SetBytes(target),
+		number:     number,
+	}
+	if w.target.Sign() == 0 {
+		return nil, errZeroTarget
+	}
+	return w, nil
+}
+
+// miner searches the nonces of the work packages fetched from the nodes on a
+// number of threads, submitting the solutions back to the nodes.
+type miner struct {
+	config config
+
+	clients []*rpc.Client // Connections to the nodes, nil if not dialed yet
+	active  int           // Index of the node work is fetched from
+
+	tasks    []chan *task   // Work channels of the mining threads
+	attempts []atomic.Int64 // Nonces tried by every thread since the last report
+	found    chan *solution // Solutions found by the mining threads
+	pushed   chan []string  // Work packages pushed by the nodes
+
+	listener net.Listener // Listener of the pushed work, nil if disabled
+	server   *http.Server
+
+	quit     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+func newMiner(config config) *miner {
+	m := &miner{
+		config:   config,
+		clients:  make([]*rpc.Client, len(config.nodes)),
+		tasks:    make([]chan *task, config.threads),
+		attempts: make([]atomic.Int64, config.threads),
+		found:    make(chan *solution),
+		pushed:   make(chan []string),
+		quit:     make(chan struct{}),
+	}
+	for i := range m.tasks {
+		m.tasks[i] = make(chan *task)
+	}
+	return m
+}
+
+// start launches the mining threads, the work listener if enabled and the main
+// loop of the miner.
+func (m *miner) start() error {
+	if len(m.config.nodes) == 0 {
+		return errNoNodes
+	}
+	if m.config.notify != "" {
+		if err := m.listen(m.config.notify); err != nil {
+			return err
+		}
+	}
+	for i := range m.tasks {
+		m.wg.Add(1)
+		go m.thread(i)
+	}
+	m.wg.Add(1)
+	go m.loop()
+
+	log.Info("Started Blake3 miner", "threads", m.config.threads, "nodes", len(m.config.nodes), "id", m.config.id)
+	return nil
+}
+
+// stop terminates the miner and waits for all threads to exit.
+func (m *miner) stop() {
+	m.stopOnce.Do(func() {
+		close(m.quit)
+		if m.server != nil {
+			m.server.Close()
+		}
+		m.wg.Wait()
+
+		for _, client := range m.clients {
+			if client != nil {
+				client.Close()
+			}
+		}
+	})
+}
+
+// cpu returns the CPU the given thread is pinned to, or -1 if not pinned.
+func (m *miner) cpu(thread int) int {
+	if len(m.config.affinity) == 0 {
+		return -1
+	}
+	return m.config.affinity[thread%len(m.config.affinity)]
+}
+
+// thread is a mining thread, searching the nonces of every task it receives
+// until a solution is found or the task is aborted.
+func (m *miner) thread(id int) {
+	defer m.wg.Done()
+
+	if cpu := m.cpu(id); cpu >= 0 {
+		runtime.LockOSThread()
+		if err := pinThread(cpu); err != nil {
+			log.Warn("Failed to pin mining thread", "thread", id, "cpu", cpu, "err", err)
+		}
+	}
+	mark := func(attempts int64) { m.attempts[id].Add(attempts) }
+
+	for {
+		select {
+		case t := <-m.tasks[id]:
+			nonce, digest, ok := ethash.SearchBlake3(t.work.sealHash, t.work.iterations, t.work.target, randomSeed(), t.abort, mark)
+			if !ok {
+				continue
+			}
+			log.Debug("Found solution", "thread", id, "number", t.work.number, "sealhash", t.work.sealHash, "nonce", nonce)
+			select {
+			case m.found <- &solution{work: t.work, nonce: nonce, digest: digest}:
+			case <-t.abort:
+			case <-m.quit:
+				return
+			}
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// loop keeps the mining threads busy with the latest work, submits their
+// solutions and reports their hashrate.
+func (m *miner) loop() {
+	defer m.wg.Done()
+
+	var (
+		current *work
+		abort   chan struct{}
+		report  <-chan time.Time
+		last    = time.Now()
+	)
+	recheck := time.NewTicker(m.config.recheck)
+	defer recheck.Stop()
+
+	if m.config.report > 0 {
+		ticker := time.NewTicker(m.config.report)
+		defer ticker.Stop()
+		report = ticker.C
+	}
+	defer func() {
+		if abort != nil {
+			close(abort)
+		}
+	}()
+	// update hands new work to the mining threads, aborting the previous one
+	update := func(w *work) {
+		if current != nil && current.sealHash == w.sealHash {
+			return
+		}
+		if abort != nil {
+			close(abort)
+		}
+		current, abort = w, make(chan struct{})
+		log.Info("New work received", "number", w.number, "sealhash", w.sealHash, "node", m.config.nodes[w.node])
+
+		t := &task{work: w, abort: abort}
+		for _, tasks := range m.tasks {
+			select {
+			case tasks <- t:
+			case <-m.quit:
+				return
+			}
+		}
+	}
+	fetch := func() {
+		if w, err := m.fetchWork(); err != nil {
+			log.Warn("Failed to fetch work", "err", err)
+		} else {
+			update(w)
+		}
+	}
+	fetch()
+
+	for {
+		select {
+		case <-recheck.C:
+			fetch()
+
+		case pkg := <-m.pushed:
+			w, err := parseWork(pkg)
+			if err != nil {
+				log.Warn("Invalid work pushed", "err", err)
+				continue
+			}
+			w.node = m.active
+			update(w)
+
+		case s := <-m.found:
+			m.submitWork(s)
+
+			// Stop searching solved work, until the node hands out new work
+			if s.work == current && abort != nil {
+				close(abort)
+				abort = nil
+			}
+
+		case now := <-report:
+			m.reportHashrate(now.Sub(last))
+			last = now
+
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// client returns the connection to the given node, dialing it if needed.
+func (m *miner) client(node int) (*rpc.Client, error) {
+	if m.clients[node] != nil {
+		return m.clients[node], nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+
+	client, err := rpc.DialContext(ctx, m.config.nodes[node])
+	if err != nil {
+		return nil, err
+	}
+	m.clients[node] = client
+	return client, nil
+}
+
+// call executes an RPC request on the given node, dropping the connection if
+// the node can't be reached.
+func (m *miner) call(node int, result interface{}, method string, args ...interface{}) error {
+	client, err := m.client(node)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+
+	err = client.CallContext(ctx, result, method, args...)
+	if _, ok := err.(rpc.Error); err != nil && !ok {
+		client.Close()
+		m.clients[node] = nil
+	}
+	return err
+}
+
+// fetchWork requests the current work package of the active node, failing over
+// to the next node if the active one can't provide work.
+func (m *miner) fetchWork() (*work, error) {
+	for i := 0; i < len(m.config.nodes); i++ {
+		node := (m.active + i) % len(m.config.nodes)
+
+		var pkg []string
+		err := m.call(node, &pkg, "eth_getWork")
+		if err == nil {
+			var w *work
+			if w, err = parseWork(pkg); err == nil {
+				if node != m.active {
+					log.Warn("Switched to fallback node", "node", m.config.nodes[node], "previous", m.config.nodes[m.active])
+					m.active = node
+				}
+				w.node = node
+				return w, nil
+			}
+		}
+		log.Debug("Node failed to provide work", "node", m.config.nodes[node], "err", err)
+	}
+	return nil, errNoWork
+}
+
+// submitWork hands a solution to the node the work was fetched from.
+func (m *miner) submitWork(s *solution) {
+	var (
+		node     = m.config.nodes[s.work.node]
+		accepted bool
+	)
+	if err := m.call(s.work.node, &accepted, "eth_submitWork", s.nonce, s.work.sealHash, s.digest); err != nil {
+		log.Warn("Failed to submit solution", "node", node, "number", s.work.number, "sealhash", s.work.sealHash, "err", err)
+		return
+	}
+	if accepted {
+		log.Info("Solution accepted", "node", node, "number", s.work.number, "sealhash", s.work.sealHash, "nonce", s.nonce)
+	} else {
+		log.Warn("Solution rejected", "node", node, "number", s.work.number, "sealhash", s.work.sealHash, "nonce", s.nonce)
+	}
+}
+
+// threadID returns the identifier the hashrate of a thread is reported under.
+func (m *miner) threadID(thread int) common.Hash {
+	var index [8]byte
+	binary.BigEndian.PutUint64(index[:], uint64(thread))
+	return crypto.Keccak256Hash(m.config.id[:], index[:])
+}
+
+// reportHashrate submits the hashrate every thread achieved over the elapsed
+// time to the active node.
+func (m *miner) reportHashrate(elapsed time.Duration) {
+	var total uint64
+	for i := range m.attempts {
+		rate := uint64(float64(m.attempts[i].Swap(0)) / elapsed.Seconds())
+		total += rate
+
+		var ok bool
+		if err := m.call(m.active, &ok, "eth_submitHashrate", hexutil.Uint64(rate), m.threadID(i)); err != nil {
+			log.Debug("Failed to report hashrate", "thread", i, "err", err)
+		}
+	}
+	log.Info("Mining", "hashrate", total, "node", m.config.nodes[m.active])
+}
+
+// listen starts accepting the work packages pushed by nodes running with
+// --miner.notify, in the default array format.
+func (m *miner) listen(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	m.listener = listener
+	m.server = &http.Server{Handler: http.HandlerFunc(m.handlePush), ReadHeaderTimeout: rpcTimeout}
+	go m.server.Serve(listener)
+
+	log.Info("Listening for pushed work", "addr", listener.Addr())
+	return nil
+}
+
+// handlePush serves a work notification of a node.
+func (m *miner) handlePush(w http.ResponseWriter, r *http.Request) {
+	var pkg []string
+	if err := json.NewDecoder(r.Body).Decode(&pkg); err != nil {
+		http.Error(w, "work package expected, run the node without --miner.notify.full", http.StatusBadRequest)
+		return
+	}
+	select {
+	case m.pushed <- pkg:
+	case <-m.quit:
+		http.Error(w, errMinerStopped.Error(), http.StatusServiceUnavailable)
+	}
+}
+
+// randomSeed returns a random nonce to start a search from.
+func randomSeed() uint64 {
+	var seed [8]byte
+	rand.Read(seed[:])
+	return binary.BigEndian.Uint64(seed[:])
+}
+
+// benchmark measures the hashrate of the given number of threads, mining with
+// the given number of Blake3 rounds per hash for the given duration.
+func benchmark(threads int, affinity []int, iterations uint64, duration time.Duration) []float64 {
+	var (
+		attempts = make([]atomic.Int64, threads)
+		abort    = make(chan struct{})
+		wg       sync.WaitGroup
+		hash     common.Hash
+		target   = big.NewInt(1) // Practically unreachable, mine until aborted
+	)
+	rand.Read(hash[:])
+
+	start := time.Now()
+	for i := 0; i < threads; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+
+			if len(affinity) > 0 {
+				runtime.LockOSThread()
+				if err := pinThread(affinity[id%len(affinity)]); err != nil {
+					log.Warn("Failed to pin mining thread", "thread", id, "err", err)
+				}
+			}
+			ethash.SearchBlake3(hash, iterations, target, randomSeed(), abort, func(n int64) { attempts[id].Add(n) })
+		}(i)
+	}
+	time.Sleep(duration)
+	close(abort)
+	wg.Wait()
+
+	elapsed := time.Since(start).Seconds()
+	rates := make([]float64, threads)
+	for i := range rates {
+		rates[i] = float64(attempts[i].Load()) / elapsed
+	}
+	return rates
+}
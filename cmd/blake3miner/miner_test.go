@@ -0,0 +1,244 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var two256 = new(big.Int).Lsh(big.NewInt(1), 256)
+
+// fakeNode is a node handing out a single work package over RPC, verifying the
+// submitted solutions and recording the reported hashrates.
+type fakeNode struct {
+	url       string
+	work      [4]string // Work package, empty if the node has no work
+	hash      common.Hash
+	iters     uint64
+	target    *big.Int
+	solutions chan types.BlockNonce
+
+	lock  sync.Mutex
+	rates map[common.Hash]uint64
+}
+
+func newFakeNode(t *testing.T, hasWork bool) *fakeNode {
+	node := &fakeNode{
+		hash:      common.HexToHash("0x1234"),
+		iters:     1,
+		target:    new(big.Int).Div(two256, big.NewInt(64)),
+		solutions: make(chan types.BlockNonce, 16),
+		rates:     make(map[common.Hash]uint64),
+	}
+	if hasWork {
+		node.work = [4]string{node.hash.Hex(), hexutil.EncodeUint64(node.iters), common.BytesToHash(node.target.Bytes()).Hex(), "0x1"}
+	}
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &fakeNodeAPI{node}); err != nil {
+		t.Fatalf("failed to register fake node API: %v", err)
+	}
+	srv := httptest.NewServer(server)
+	t.Cleanup(func() {
+		srv.Close()
+		server.Stop()
+	})
+	node.url = srv.URL
+	return node
+}
+
+// pushWork sends the work package of the node to a miner, the way nodes running
+// with --miner.notify do.
+func (n *fakeNode) pushWork(t *testing.T, url string) {
+	work := [4]string{n.hash.Hex(), hexutil.EncodeUint64(n.iters), common.BytesToHash(n.target.Bytes()).Hex(), "0x1"}
+	blob, _ := json.Marshal(work)
+
+	resp, err := http.Post(url, "application/json", bytes.NewReader(blob))
+	if err != nil {
+		t.Fatalf("failed to push work: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("work push rejected: %s", resp.Status)
+	}
+}
+
+// waitSolution waits for a valid solution to be submitted to the node.
+func (n *fakeNode) waitSolution(t *testing.T) {
+	t.Helper()
+
+	select {
+	case nonce := <-n.solutions:
+		t.Logf("solution %x submitted", nonce)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no solution submitted")
+	}
+}
+
+type fakeNodeAPI struct {
+	node *fakeNode
+}
+
+func (api *fakeNodeAPI) GetWork() ([4]string, error) {
+	if api.node.work[0] == "" {
+		return [4]string{}, errors.New("no mining work available yet")
+	}
+	return api.node.work, nil
+}
+
+func (api *fakeNodeAPI) SubmitWork(nonce types.BlockNonce, hash, digest common.Hash) bool {
+	if hash != api.node.hash {
+		return false
+	}
+	want := ethash.Blake3Digest(hash, nonce.Uint64(), api.node.iters)
+	if digest != want || want.Big().Cmp(api.node.target) > 0 {
+		return false
+	}
+	api.node.solutions <- nonce
+	return true
+}
+
+func (api *fakeNodeAPI) SubmitHashrate(rate hexutil.Uint64, id common.Hash) bool {
+	api.node.lock.Lock()
+	defer api.node.lock.Unlock()
+
+	api.node.rates[id] = uint64(rate)
+	return true
+}
+
+func startTestMiner(t *testing.T, config config) *miner {
+	if config.threads == 0 {
+		config.threads = 2
+	}
+	config.recheck = 50 * time.Millisecond
+	config.id = common.HexToHash("0xabcd")
+
+	m := newMiner(config)
+	if err := m.start(); err != nil {
+		t.Fatalf("failed to start miner: %v", err)
+	}
+	t.Cleanup(m.stop)
+	return m
+}
+
+// Tests that the miner solves the work of a node and reports the hashrate of
+// every thread separately.
+func TestMining(t *testing.T) {
+	node := newFakeNode(t, true)
+	m := startTestMiner(t, config{nodes: []string{node.url}, threads: 3, report: 100 * time.Millisecond})
+
+	node.waitSolution(t)
+
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		node.lock.Lock()
+		reports := len(node.rates)
+		node.lock.Unlock()
+
+		if reports == 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("hashrate reports mismatch: have %d threads, want 3", reports)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	node.lock.Lock()
+	defer node.lock.Unlock()
+	for i := 0; i < 3; i++ {
+		if _, ok := node.rates[m.threadID(i)]; !ok {
+			t.Errorf("thread %d: hashrate not reported", i)
+		}
+	}
+}
+
+// Tests that the miner fails over to the next node if the preferred one can't
+// be reached or doesn't provide work.
+func TestFailover(t *testing.T) {
+	dead := httptest.NewServer(http.NotFoundHandler())
+	dead.Close()
+
+	idle := newFakeNode(t, false)
+	node := newFakeNode(t, true)
+	m := startTestMiner(t, config{nodes: []string{dead.URL, idle.url, node.url}})
+
+	node.waitSolution(t)
+	if len(idle.solutions) != 0 {
+		t.Fatalf("solution submitted to idle node")
+	}
+	m.stop()
+	if m.active != 2 {
+		t.Fatalf("active node mismatch: have %d, want 2", m.active)
+	}
+}
+
+// Tests that the miner mines work pushed by a node.
+func TestPushedWork(t *testing.T) {
+	node := newFakeNode(t, false)
+	m := startTestMiner(t, config{nodes: []string{node.url}, notify: "127.0.0.1:0"})
+
+	node.pushWork(t, "http://"+m.listener.Addr().String())
+	node.waitSolution(t)
+}
+
+func TestParseWork(t *testing.T) {
+	target := common.BytesToHash(new(big.Int).Div(two256, big.NewInt(1000)).Bytes()).Hex()
+	tests := []struct {
+		pkg []string
+		err error
+	}{
+		{[]string{common.Hash{1}.Hex(), "0x4c570", target, "0x10"}, nil},
+		{[]string{common.Hash{1}.Hex(), common.Hash{2}.Hex(), target, "0x10"}, errLegacyWork},
+		{[]string{common.Hash{1}.Hex(), "0x4c570", common.Hash{}.Hex(), "0x10"}, errZeroTarget},
+		{[]string{"0x01", "0x4c570", target, "0x10"}, errInvalidWork},
+		{[]string{common.Hash{1}.Hex(), "0x4c570", target}, errInvalidWork},
+	}
+	for i, tt := range tests {
+		w, err := parseWork(tt.pkg)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+			continue
+		}
+		if err == nil && (w.iterations != 0x4c570 || w.number != 0x10 || common.BigToHash(w.target).Hex() != target) {
+			t.Errorf("test %d: work mismatch: have %+v", i, w)
+		}
+	}
+}
+
+func TestBenchmark(t *testing.T) {
+	rates := benchmark(2, nil, 1, 100*time.Millisecond)
+	if len(rates) != 2 {
+		t.Fatalf("thread count mismatch: have %d, want 2", len(rates))
+	}
+	for i, rate := range rates {
+		if rate <= 0 {
+			t.Errorf("thread %d: no hashrate measured", i)
+		}
+	}
+}
@@ -289,8 +289,10 @@ func readInput(ctx *cli.Context) (*bbInput, error) {
 			inputData.PowMode = ethash.ModeTest
 		case "fake":
 			inputData.PowMode = ethash.ModeFake
+		case "legacy":
+			inputData.PowMode = ethash.ModeLegacy
 		default:
-			return nil, NewError(ErrorConfig, fmt.Errorf("unknown pow mode: %s, supported modes: test, fake, normal", ethashMode))
+			return nil, NewError(ErrorConfig, fmt.Errorf("unknown pow mode: %s, supported modes: test, fake, normal, legacy", ethashMode))
 		}
 	}
 	if headerStr == stdinSelector || ommersStr == stdinSelector || txsStr == stdinSelector || cliqueStr == stdinSelector {
@@ -49,13 +49,14 @@ var (
 		Name:      "init",
 		Usage:     "Bootstrap and initialize a new genesis block",
 		ArgsUsage: "<genesisPath>",
-		Flags:     flags.Merge([]cli.Flag{utils.CachePreimagesFlag}, utils.DatabasePathFlags),
+		Flags:     flags.Merge([]cli.Flag{utils.CachePreimagesFlag}, utils.NetworkFlags, utils.DatabasePathFlags),
 		Description: `
 The init command initializes a new genesis block and definition for the network.
 This is a destructive action and changes the network in which you will be
 participating.
 
-It expects the genesis file as argument.`,
+It expects the genesis file as argument, unless a network preset is set. If both
+are given, the genesis file must match the genesis of the preset.`,
 	}
 	dumpGenesisCommand = &cli.Command{
 		Action:    dumpGenesis,
@@ -169,22 +170,36 @@ This command dumps out the state for a given block (or latest, if none provided)
 // initGenesis will initialise the given JSON format genesis file and writes it as
 // the zero'd block (i.e. genesis) or will fail hard if it can't succeed.
 func initGenesis(ctx *cli.Context) error {
-	if ctx.Args().Len() != 1 {
+	var (
+		preset  = utils.MakeGenesis(ctx)
+		genesis *core.Genesis
+	)
+	switch {
+	case ctx.Args().Len() == 0 && preset != nil:
+		genesis = preset
+	case ctx.Args().Len() != 1:
 		utils.Fatalf("need genesis.json file as the only argument")
-	}
-	genesisPath := ctx.Args().First()
-	if len(genesisPath) == 0 {
-		utils.Fatalf("invalid path to genesis file")
-	}
-	file, err := os.Open(genesisPath)
-	if err != nil {
-		utils.Fatalf("Failed to read genesis file: %v", err)
-	}
-	defer file.Close()
+	default:
+		genesisPath := ctx.Args().First()
+		if len(genesisPath) == 0 {
+			utils.Fatalf("invalid path to genesis file")
+		}
+		file, err := os.Open(genesisPath)
+		if err != nil {
+			utils.Fatalf("Failed to read genesis file: %v", err)
+		}
+		defer file.Close()
 
-	genesis := new(core.Genesis)
-	if err := json.NewDecoder(file).Decode(genesis); err != nil {
-		utils.Fatalf("invalid genesis file: %v", err)
+		genesis = new(core.Genesis)
+		if err := json.NewDecoder(file).Decode(genesis); err != nil {
+			utils.Fatalf("invalid genesis file: %v", err)
+		}
+		// Refuse to initialise a preset network with a foreign genesis
+		if preset != nil {
+			if have, want := genesis.ToBlock().Hash(), preset.ToBlock().Hash(); have != want {
+				utils.Fatalf("Genesis file mismatches the network preset: have %x, want %x", have, want)
+			}
+		}
 	}
 	// Open and initialise both full and light databases
 	stack, _ := makeConfigNode(ctx)
@@ -205,6 +220,16 @@ func initGenesis(ctx *cli.Context) error {
 		chaindb.Close()
 		log.Info("Successfully wrote genesis state", "database", name, "hash", hash)
 	}
+	// Print the reward schedule the chain will run with, so operators can
+	// verify it before starting the node.
+	schedule := genesis.Config.LibertySchedule()
+	if genesis.Config.Liberty == nil {
+		log.Warn("Genesis has no liberty section, using default reward schedule")
+	}
+	for _, epoch := range schedule.Epochs {
+		log.Info("Reward epoch", "name", epoch.Name, "start", epoch.StartBlock,
+			"miner", epoch.MinerReward, "staking", epoch.StakingReward, "devfund", epoch.DevFund)
+	}
 	return nil
 }
 
@@ -18,7 +18,9 @@ package main
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"math/big"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -30,13 +32,16 @@ import (
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/console/prompt"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state/snapshot"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/internal/flags"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/olekukonko/tablewriter"
 	"github.com/urfave/cli/v2"
@@ -69,6 +74,7 @@ Remove blockchain and state databases`,
 			dbExportCmd,
 			dbMetadataCmd,
 			dbCheckStateContentCmd,
+			dbSupplyCmd,
 		},
 	}
 	dbInspectCmd = &cli.Command{
@@ -184,6 +190,19 @@ WARNING: This is a low-level operation which may cause database corruption!`,
 		}, utils.NetworkFlags, utils.DatabasePathFlags),
 		Description: "Exports the specified chain data to an RLP encoded stream, optionally gzip-compressed.",
 	}
+	dbSupplyCmd = &cli.Command{
+		Action:    dbSupply,
+		Name:      "supply",
+		Usage:     "Recompute the total coin supply from the genesis allocations",
+		ArgsUsage: "<number (optional)>",
+		Flags: flags.Merge([]cli.Flag{
+			utils.SyncModeFlag,
+		}, utils.NetworkFlags, utils.DatabasePathFlags),
+		Description: `This command sums up the genesis allocations and the issuance of every canonical
+block up to the given one (or the head block), the block and uncle rewards minus the
+burned base fees. The checkpoints of the supply index met along the way are checked
+against the recomputed total.`,
+	}
 	dbMetadataCmd = &cli.Command{
 		Action: showMetaData,
 		Name:   "metadata",
@@ -340,6 +359,81 @@ func checkStateContent(ctx *cli.Context) error {
 	return nil
 }
 
+func dbSupply(ctx *cli.Context) error {
+	if ctx.NArg() > 1 {
+		return fmt.Errorf("max 1 argument: %v", ctx.Command.ArgsUsage)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	defer db.Close()
+
+	head := rawdb.ReadHeadHeader(db)
+	if head == nil {
+		return errors.New("no head header found")
+	}
+	number := head.Number.Uint64()
+	if ctx.NArg() == 1 {
+		n, err := strconv.ParseUint(ctx.Args().First(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("failed to parse block number: %v", err)
+		}
+		if n > number {
+			return fmt.Errorf("block #%d beyond the head #%d", n, number)
+		}
+		number = n
+	}
+	config := rawdb.ReadChainConfig(db, rawdb.ReadCanonicalHash(db, 0))
+	if config == nil {
+		return errors.New("no chain config found")
+	}
+	genesis, err := core.GenesisSupply(db)
+	if err != nil {
+		return err
+	}
+	var reward core.RewardFunc
+	if config.Clique == nil {
+		reward = ethash.BlockReward
+	}
+	var (
+		supply    = new(big.Int).Set(genesis)
+		size      = params.SupplyIndexBlocks
+		checked   int
+		mismatch  int
+		startTime = time.Now()
+		lastLog   = time.Now()
+	)
+	for n := uint64(1); n <= number; n++ {
+		if err := core.AccumulateSupply(db, config, reward, supply, n, n); err != nil {
+			return err
+		}
+		// Audit the supply index if a checkpoint ends here
+		if (n+1)%size == 0 {
+			if indexed := rawdb.ReadSupply(db, (n+1)/size-1, rawdb.ReadCanonicalHash(db, n)); indexed != nil {
+				checked++
+				if indexed.Cmp(supply) != 0 {
+					mismatch++
+					log.Error("Supply checkpoint mismatch", "number", n, "indexed", indexed, "computed", supply)
+				}
+			}
+		}
+		if time.Since(lastLog) > 8*time.Second {
+			log.Info("Recomputing supply", "number", n, "elapsed", common.PrettyDuration(time.Since(startTime)))
+			lastLog = time.Now()
+		}
+	}
+	fmt.Printf("Block:       %d\n", number)
+	fmt.Printf("Genesis:     %v\n", genesis)
+	fmt.Printf("Net issued:  %v\n", new(big.Int).Sub(supply, genesis))
+	fmt.Printf("Supply:      %v\n", supply)
+	fmt.Printf("Checkpoints: %d checked, %d mismatched\n", checked, mismatch)
+	if mismatch > 0 {
+		return fmt.Errorf("%d supply checkpoints mismatched", mismatch)
+	}
+	return nil
+}
+
 func showLeveldbStats(db ethdb.KeyValueStater) {
 	if stats, err := db.Stat("leveldb.stats"); err != nil {
 		log.Warn("Failed to read database stats", "error", err)
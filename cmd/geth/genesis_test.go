@@ -22,6 +22,8 @@ import (
 	"path/filepath"
 	"strconv"
 	"testing"
+
+	"github.com/ethereum/go-ethereum/params"
 )
 
 var customGenesisTests = []struct {
@@ -94,6 +96,36 @@ func TestCustomGenesis(t *testing.T) {
 	}
 }
 
+// Tests that genesis files mismatching a network preset are refused, and that
+// the Liberty Project preset only runs on a datadir initialized with a genesis.
+func TestPresetGenesis(t *testing.T) {
+	t.Parallel()
+
+	datadir := t.TempDir()
+	json := filepath.Join(datadir, "genesis.json")
+	if err := os.WriteFile(json, []byte(customGenesisTests[0].genesis), 0600); err != nil {
+		t.Fatalf("failed to write genesis file: %v", err)
+	}
+	geth := runGeth(t, "--datadir", datadir, "init", "--sepolia", json)
+	geth.ExpectRegexp(`Fatal: Genesis file mismatches the network preset: have [0-9a-f]{64}, want ` + params.SepoliaGenesisHash.Hex()[2:] + "\n")
+	geth.ExpectExit()
+
+	args := []string{"--liberty", "--syncmode=full", "--cache", "16",
+		"--datadir", datadir, "--maxpeers", "0", "--port", "0", "--authrpc.port", "0",
+		"--nodiscover", "--nat", "none", "--ipcdisable",
+		"--exec", "net.version", "console"}
+
+	geth = runGeth(t, args...)
+	geth.ExpectRegexp("Fatal: Liberty Project network not initialized, run geth init with the released genesis.json first\n")
+	geth.ExpectExit()
+
+	runGeth(t, "--datadir", datadir, "init", json).WaitExit()
+
+	geth = runGeth(t, args...)
+	geth.ExpectRegexp(`"16384"\n`)
+	geth.ExpectExit()
+}
+
 // TestCustomBackend that the backend selection and detection (leveldb vs pebble) works properly.
 func TestCustomBackend(t *testing.T) {
 	t.Parallel()
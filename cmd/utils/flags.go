@@ -157,6 +157,11 @@ var (
 		Usage:    "Sepolia network: pre-configured proof-of-work test network",
 		Category: flags.EthCategory,
 	}
+	LibertyFlag = &cli.BoolFlag{
+		Name:     "liberty",
+		Usage:    "Liberty Project network: pre-configured proof-of-work network, the datadir must be initialized with the released genesis.json",
+		Category: flags.EthCategory,
+	}
 
 	// Dev mode
 	DeveloperFlag = &cli.BoolFlag{
@@ -247,6 +252,16 @@ var (
 		Value:    ethconfig.Defaults.TxLookupLimit,
 		Category: flags.EthCategory,
 	}
+	LibertyMaxReorgFlag = &cli.Uint64Flag{
+		Name:     "liberty.maxreorg",
+		Usage:    "Maximum number of blocks a chain reorg may drop (0 = no limit)",
+		Category: flags.EthCategory,
+	}
+	LibertyFinalityFlag = &cli.BoolFlag{
+		Name:     "liberty.finality",
+		Usage:    "Refuse reorgs dropping the trusted checkpoint, or a checkpoint signed by the configured checkpoint oracle signers",
+		Category: flags.EthCategory,
+	}
 	LightKDFFlag = &cli.BoolFlag{
 		Name:     "lightkdf",
 		Usage:    "Reduce key-derivation RAM & CPU usage at some expense of KDF strength",
@@ -327,49 +342,54 @@ var (
 	}
 
 	// Ethash settings
+	EthashLegacyFlag = &cli.BoolFlag{
+		Name:     "ethash.legacy",
+		Usage:    "Use the original DAG based Ethash proof-of-work instead of Blake3 (test chains only)",
+		Category: flags.EthashCategory,
+	}
 	EthashCacheDirFlag = &flags.DirectoryFlag{
 		Name:     "ethash.cachedir",
-		Usage:    "Directory to store the ethash verification caches (default = inside the datadir)",
+		Usage:    "Directory to store the legacy ethash verification caches (default = inside the datadir)",
 		Category: flags.EthashCategory,
 	}
 	EthashCachesInMemoryFlag = &cli.IntFlag{
 		Name:     "ethash.cachesinmem",
-		Usage:    "Number of recent ethash caches to keep in memory (16MB each)",
+		Usage:    "Number of recent legacy ethash caches to keep in memory (16MB each)",
 		Value:    ethconfig.Defaults.Ethash.CachesInMem,
 		Category: flags.EthashCategory,
 	}
 	EthashCachesOnDiskFlag = &cli.IntFlag{
 		Name:     "ethash.cachesondisk",
-		Usage:    "Number of recent ethash caches to keep on disk (16MB each)",
+		Usage:    "Number of recent legacy ethash caches to keep on disk (16MB each)",
 		Value:    ethconfig.Defaults.Ethash.CachesOnDisk,
 		Category: flags.EthashCategory,
 	}
 	EthashCachesLockMmapFlag = &cli.BoolFlag{
 		Name:     "ethash.cacheslockmmap",
-		Usage:    "Lock memory maps of recent ethash caches",
+		Usage:    "Lock memory maps of recent legacy ethash caches",
 		Category: flags.EthashCategory,
 	}
 	EthashDatasetDirFlag = &flags.DirectoryFlag{
 		Name:     "ethash.dagdir",
-		Usage:    "Directory to store the ethash mining DAGs",
+		Usage:    "Directory to store the legacy ethash mining DAGs",
 		Value:    flags.DirectoryString(ethconfig.Defaults.Ethash.DatasetDir),
 		Category: flags.EthashCategory,
 	}
 	EthashDatasetsInMemoryFlag = &cli.IntFlag{
 		Name:     "ethash.dagsinmem",
-		Usage:    "Number of recent ethash mining DAGs to keep in memory (1+GB each)",
+		Usage:    "Number of recent legacy ethash mining DAGs to keep in memory (1+GB each)",
 		Value:    ethconfig.Defaults.Ethash.DatasetsInMem,
 		Category: flags.EthashCategory,
 	}
 	EthashDatasetsOnDiskFlag = &cli.IntFlag{
 		Name:     "ethash.dagsondisk",
-		Usage:    "Number of recent ethash mining DAGs to keep on disk (1+GB each)",
+		Usage:    "Number of recent legacy ethash mining DAGs to keep on disk (1+GB each)",
 		Value:    ethconfig.Defaults.Ethash.DatasetsOnDisk,
 		Category: flags.EthashCategory,
 	}
 	EthashDatasetsLockMmapFlag = &cli.BoolFlag{
 		Name:     "ethash.dagslockmmap",
-		Usage:    "Lock memory maps for recent ethash mining DAGs",
+		Usage:    "Lock memory maps for recent legacy ethash mining DAGs",
 		Category: flags.EthashCategory,
 	}
 
@@ -396,6 +416,23 @@ var (
 		Value:    txpool.DefaultConfig.Rejournal,
 		Category: flags.TxPoolCategory,
 	}
+	TxPoolRemoteJournalFlag = &cli.StringFlag{
+		Name:     "txpool.remotejournal",
+		Usage:    "Disk journal for remote transactions to survive node restarts (disabled if empty)",
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolRemoteJournalSizeFlag = &cli.Uint64Flag{
+		Name:     "txpool.remotejournalsize",
+		Usage:    "Maximum size of the remote transaction journal in megabytes",
+		Value:    txpool.DefaultConfig.RemoteJournalSize / 1024 / 1024,
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolRemoteRejournalFlag = &cli.DurationFlag{
+		Name:     "txpool.remoterejournal",
+		Usage:    "Time interval to regenerate the remote transaction journal",
+		Value:    txpool.DefaultConfig.RemoteRejournal,
+		Category: flags.TxPoolCategory,
+	}
 	TxPoolPriceLimitFlag = &cli.Uint64Flag{
 		Name:     "txpool.pricelimit",
 		Usage:    "Minimum gas price limit to enforce for acceptance into the pool",
@@ -526,6 +563,40 @@ var (
 		Usage:    "Notify with pending block headers instead of work packages",
 		Category: flags.MinerCategory,
 	}
+	MinerStratumFlag = &cli.StringFlag{
+		Name:     "miner.stratum",
+		Usage:    "Listen address of the built-in stratum+tcp server for remote miners (e.g. 0.0.0.0:8008)",
+		Category: flags.MinerCategory,
+	}
+	MinerStratumDifficultyFlag = &cli.Uint64Flag{
+		Name:     "miner.stratum.diff",
+		Usage:    "Initial and minimum share difficulty of stratum miners, raised automatically for faster miners",
+		Value:    ethconfig.Defaults.Miner.StratumDifficulty,
+		Category: flags.MinerCategory,
+	}
+	MinerPoolFlag = &cli.BoolFlag{
+		Name:     "miner.pool",
+		Usage:    "Enable share accounting and reward payouts for stratum miners",
+		Category: flags.MinerCategory,
+	}
+	MinerPoolSchemeFlag = &cli.StringFlag{
+		Name:     "miner.pool.scheme",
+		Usage:    `Reward payout scheme of the pool ("pplns" or "prop")`,
+		Value:    ethconfig.Defaults.Miner.Pool.Scheme,
+		Category: flags.MinerCategory,
+	}
+	MinerPoolWindowFlag = &cli.Uint64Flag{
+		Name:     "miner.pool.window",
+		Usage:    "PPLNS window as a multiple of the found block's difficulty",
+		Value:    ethconfig.Defaults.Miner.Pool.Window,
+		Category: flags.MinerCategory,
+	}
+	MinerPoolMaturityFlag = &cli.Uint64Flag{
+		Name:     "miner.pool.maturity",
+		Usage:    "Number of confirmations before the pool rewards of a found block are final",
+		Value:    ethconfig.Defaults.Miner.Pool.Maturity,
+		Category: flags.MinerCategory,
+	}
 	MinerGasLimitFlag = &cli.Uint64Flag{
 		Name:     "miner.gaslimit",
 		Usage:    "Target gas ceiling for mined blocks",
@@ -565,6 +636,17 @@ var (
 		Value:    ethconfig.Defaults.Miner.NewPayloadTimeout,
 		Category: flags.MinerCategory,
 	}
+	MinerOrderingFlag = &cli.StringFlag{
+		Name:     "miner.ordering",
+		Usage:    "Transaction ordering policy of mined blocks ('local', 'price', 'fifo' or 'lanes')",
+		Value:    miner.OrderingLocalFirst,
+		Category: flags.MinerCategory,
+	}
+	MinerLanesFlag = &cli.StringFlag{
+		Name:     "miner.lanes",
+		Usage:    "Priority lanes of the 'lanes' ordering policy, highest first (semicolon separated lanes of comma separated accounts)",
+		Category: flags.MinerCategory,
+	}
 
 	// Account settings
 	UnlockedAccountFlag = &cli.StringFlag{
@@ -1004,7 +1086,7 @@ var (
 		SepoliaFlag,
 	}
 	// NetworkFlags is the flag group of all built-in supported networks.
-	NetworkFlags = append([]cli.Flag{MainnetFlag}, TestnetFlags...)
+	NetworkFlags = append([]cli.Flag{MainnetFlag, LibertyFlag}, TestnetFlags...)
 
 	// DatabasePathFlags is the flag group of all database path flags.
 	DatabasePathFlags = []cli.Flag{
@@ -1087,6 +1169,8 @@ func setBootstrapNodes(ctx *cli.Context, cfg *p2p.Config) {
 		urls = params.RinkebyBootnodes
 	case ctx.Bool(GoerliFlag.Name):
 		urls = params.GoerliBootnodes
+	case ctx.Bool(LibertyFlag.Name):
+		urls = params.LibertyBootnodes
 	}
 
 	// don't apply defaults if BootstrapNodes is already set
@@ -1581,6 +1665,15 @@ func setTxPool(ctx *cli.Context, cfg *txpool.Config) {
 	if ctx.IsSet(TxPoolRejournalFlag.Name) {
 		cfg.Rejournal = ctx.Duration(TxPoolRejournalFlag.Name)
 	}
+	if ctx.IsSet(TxPoolRemoteJournalFlag.Name) {
+		cfg.RemoteJournal = ctx.String(TxPoolRemoteJournalFlag.Name)
+	}
+	if ctx.IsSet(TxPoolRemoteJournalSizeFlag.Name) {
+		cfg.RemoteJournalSize = ctx.Uint64(TxPoolRemoteJournalSizeFlag.Name) * 1024 * 1024
+	}
+	if ctx.IsSet(TxPoolRemoteRejournalFlag.Name) {
+		cfg.RemoteRejournal = ctx.Duration(TxPoolRemoteRejournalFlag.Name)
+	}
 	if ctx.IsSet(TxPoolPriceLimitFlag.Name) {
 		cfg.PriceLimit = ctx.Uint64(TxPoolPriceLimitFlag.Name)
 	}
@@ -1605,6 +1698,9 @@ func setTxPool(ctx *cli.Context, cfg *txpool.Config) {
 }
 
 func setEthash(ctx *cli.Context, cfg *ethconfig.Config) {
+	if ctx.Bool(EthashLegacyFlag.Name) {
+		cfg.Ethash.PowMode = ethash.ModeLegacy
+	}
 	if ctx.IsSet(EthashCacheDirFlag.Name) {
 		cfg.Ethash.CacheDir = ctx.String(EthashCacheDirFlag.Name)
 	}
@@ -1636,6 +1732,24 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 		cfg.Notify = strings.Split(ctx.String(MinerNotifyFlag.Name), ",")
 	}
 	cfg.NotifyFull = ctx.Bool(MinerNotifyFullFlag.Name)
+	if ctx.IsSet(MinerStratumFlag.Name) {
+		cfg.Stratum = ctx.String(MinerStratumFlag.Name)
+	}
+	if ctx.IsSet(MinerStratumDifficultyFlag.Name) {
+		cfg.StratumDifficulty = ctx.Uint64(MinerStratumDifficultyFlag.Name)
+	}
+	if ctx.IsSet(MinerPoolFlag.Name) {
+		cfg.Pool.Enabled = ctx.Bool(MinerPoolFlag.Name)
+	}
+	if ctx.IsSet(MinerPoolSchemeFlag.Name) {
+		cfg.Pool.Scheme = ctx.String(MinerPoolSchemeFlag.Name)
+	}
+	if ctx.IsSet(MinerPoolWindowFlag.Name) {
+		cfg.Pool.Window = ctx.Uint64(MinerPoolWindowFlag.Name)
+	}
+	if ctx.IsSet(MinerPoolMaturityFlag.Name) {
+		cfg.Pool.Maturity = ctx.Uint64(MinerPoolMaturityFlag.Name)
+	}
 	if ctx.IsSet(MinerExtraDataFlag.Name) {
 		cfg.ExtraData = []byte(ctx.String(MinerExtraDataFlag.Name))
 	}
@@ -1654,6 +1768,26 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 	if ctx.IsSet(MinerNewPayloadTimeout.Name) {
 		cfg.NewPayloadTimeout = ctx.Duration(MinerNewPayloadTimeout.Name)
 	}
+	if ctx.IsSet(MinerOrderingFlag.Name) {
+		cfg.Ordering = ctx.String(MinerOrderingFlag.Name)
+	}
+	if ctx.IsSet(MinerLanesFlag.Name) {
+		cfg.Lanes = nil
+		for _, lane := range strings.Split(ctx.String(MinerLanesFlag.Name), ";") {
+			var accounts []common.Address
+			for _, account := range strings.Split(lane, ",") {
+				if trimmed := strings.TrimSpace(account); !common.IsHexAddress(trimmed) {
+					Fatalf("Invalid account in --miner.lanes: %s", trimmed)
+				} else {
+					accounts = append(accounts, common.HexToAddress(trimmed))
+				}
+			}
+			cfg.Lanes = append(cfg.Lanes, accounts)
+		}
+	}
+	if _, err := miner.NewOrderingPolicy(cfg); err != nil {
+		Fatalf("Invalid --miner.ordering: %v", err)
+	}
 }
 
 func setRequiredBlocks(ctx *cli.Context, cfg *ethconfig.Config) {
@@ -1728,7 +1862,7 @@ func CheckExclusive(ctx *cli.Context, args ...interface{}) {
 // SetEthConfig applies eth-related command line flags to the config.
 func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	// Avoid conflicting network flags
-	CheckExclusive(ctx, MainnetFlag, DeveloperFlag, RinkebyFlag, GoerliFlag, SepoliaFlag)
+	CheckExclusive(ctx, MainnetFlag, DeveloperFlag, RinkebyFlag, GoerliFlag, SepoliaFlag, LibertyFlag)
 	CheckExclusive(ctx, LightServeFlag, SyncModeFlag, "light")
 	CheckExclusive(ctx, DeveloperFlag, ExternalSignerFlag) // Can't use both ephemeral unlocked and external signer
 	if ctx.String(GCModeFlag.Name) == "archive" && ctx.Uint64(TxLookupLimitFlag.Name) != 0 {
@@ -1798,6 +1932,12 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(TxLookupLimitFlag.Name) {
 		cfg.TxLookupLimit = ctx.Uint64(TxLookupLimitFlag.Name)
 	}
+	if ctx.IsSet(LibertyMaxReorgFlag.Name) {
+		cfg.MaxReorg = ctx.Uint64(LibertyMaxReorgFlag.Name)
+	}
+	if ctx.IsSet(LibertyFinalityFlag.Name) {
+		cfg.Finality = ctx.Bool(LibertyFinalityFlag.Name)
+	}
 	if ctx.IsSet(CacheFlag.Name) || ctx.IsSet(CacheTrieFlag.Name) {
 		cfg.TrieCleanCache = ctx.Int(CacheFlag.Name) * ctx.Int(CacheTrieFlag.Name) / 100
 	}
@@ -1894,6 +2034,16 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 		}
 		cfg.Genesis = core.DefaultGoerliGenesisBlock()
 		SetDNSDiscoveryDefaults(cfg, params.GoerliGenesisHash)
+	case ctx.Bool(LibertyFlag.Name):
+		if !ctx.IsSet(NetworkIdFlag.Name) {
+			cfg.NetworkId = 16384
+		}
+		// The genesis of the network is released as a file instead of being
+		// built in, refuse to start on a datadir not initialized with it.
+		readonly := common.FileExist(stack.ResolvePath("chaindata"))
+		chaindb := MakeChainDatabase(ctx, stack, readonly)
+		checkLibertyGenesis(chaindb)
+		chaindb.Close()
 	case ctx.Bool(DeveloperFlag.Name):
 		if !ctx.IsSet(NetworkIdFlag.Name) {
 			cfg.NetworkId = 1337
@@ -2203,6 +2353,18 @@ func DialRPCWithHeaders(endpoint string, headers []string) (*rpc.Client, error)
 	return rpc.DialOptions(context.Background(), endpoint, opts...)
 }
 
+// checkLibertyGenesis terminates unless the database was initialized with the
+// genesis of the Liberty Project network. The genesis isn't built in, a node
+// started on an empty database would create the Ethereum mainnet one instead.
+func checkLibertyGenesis(db ethdb.Database) {
+	switch rawdb.ReadCanonicalHash(db, 0) {
+	case common.Hash{}:
+		Fatalf("Liberty Project network not initialized, run geth init with the released genesis.json first")
+	case params.MainnetGenesisHash:
+		Fatalf("Database contains the Ethereum mainnet, not the Liberty Project network")
+	}
+}
+
 func MakeGenesis(ctx *cli.Context) *core.Genesis {
 	var genesis *core.Genesis
 	switch {
@@ -2226,6 +2388,9 @@ func MakeChain(ctx *cli.Context, stack *node.Node, readonly bool) (*core.BlockCh
 		gspec   = MakeGenesis(ctx)
 		chainDb = MakeChainDatabase(ctx, stack, readonly)
 	)
+	if ctx.Bool(LibertyFlag.Name) {
+		checkLibertyGenesis(chainDb)
+	}
 	cliqueConfig, err := core.LoadCliqueConfig(chainDb, gspec)
 	if err != nil {
 		Fatalf("%v", err)
@@ -462,6 +462,23 @@ func hashimotoFull(dataset []uint32, hash []byte, nonce uint64) ([]byte, []byte)
 	return hashimoto(hash, nonce, uint64(len(dataset))*4, lookup)
 }
 
+// hashimotoBlake3 computes the proof-of-work digest of the Blake3 algorithm for
+// a particular seal hash and nonce: the Blake3 hash of the seal hash and the big
+// endian nonce, chained through iters further rounds of Blake3. The digest is
+// both the block's mix digest and the value compared against the target, and
+// is shared between sealing and verification so the two can never disagree.
+func hashimotoBlake3(hash []byte, nonce uint64, iters uint64) []byte {
+	seed := make([]byte, 40)
+	copy(seed, hash)
+	binary.BigEndian.PutUint64(seed[32:], nonce)
+
+	digest := blake3.Sum256(seed)
+	for i := uint64(0); i < iters; i++ {
+		digest = blake3.Sum256(digest[:])
+	}
+	return digest[:]
+}
+
 const maxEpoch = 2048
 
 // datasetSizes is a lookup table for the ethash dataset size for the first 2048
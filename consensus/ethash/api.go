@@ -18,10 +18,15 @@ package ethash
 
 import (
 	"errors"
+	"fmt"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/shared"
 )
 
 var errEthashStopped = errors.New("ethash stopped")
@@ -33,10 +38,10 @@ type API struct {
 
 // GetWork returns a work package for external miner.
 //
-// The work package consists of 3 strings:
+// The work package consists of 4 strings:
 //
 //	result[0] - 32 bytes hex encoded current block header pow-hash
-//	result[1] - 32 bytes hex encoded seed hash used for DAG
+//	result[1] - hex encoded number of Blake3 iterations (seed hash used for DAG in legacy mode)
 //	result[2] - 32 bytes hex encoded boundary condition ("target"), 2^256/difficulty
 //	result[3] - hex encoded block number
 func (api *API) GetWork() ([4]string, error) {
@@ -65,19 +70,11 @@ func (api *API) GetWork() ([4]string, error) {
 // It returns an indication if the work was accepted.
 // Note either an invalid solution, a stale work a non-existent work will return false.
 func (api *API) SubmitWork(nonce types.BlockNonce, hash, digest common.Hash) bool {
-	// log.Println("====== Start of API SubmitWork function ======")
-
 	if api.ethash.remote == nil {
-		// log.Println("Liberty Project: Remote sealer is nil, cannot submit work")
 		return false
 	}
 
 	var errc = make(chan error, 1)
-
-	// Log before sending to submitWorkCh
-	// log.Printf("Liberty Project: Preparing to send to submitWorkCh - Nonce: %s, Hash: %s, Digest: %s",
-	// 	nonce, hash.Hex(), digest.Hex())
-
 	select {
 	case api.ethash.remote.submitWorkCh <- &mineResult{
 		nonce:     nonce,
@@ -85,16 +82,44 @@ func (api *API) SubmitWork(nonce types.BlockNonce, hash, digest common.Hash) boo
 		hash:      hash,
 		errc:      errc,
 	}:
-		// Log confirmation that data was sent to channel
-		// log.Println("Liberty Project: Sent to submitWorkCh")
 	case <-api.ethash.remote.exitCh:
-		// log.Println("Liberty Project: Exiting submitWork due to remote exit channel closure")
 		return false
 	}
 
 	// Get execution result from channel and return status
 	err := <-errc
-	// log.Println("====== End of API SubmitWork function ======")
+	return err == nil
+}
+
+// GetWorkFor returns a work package for an external miner, whose block credits
+// the rewards and fees to the given payout address. The layout of the package
+// is the same as for GetWork. Solutions may be submitted via either SubmitWork
+// or SubmitWorkFor. Only the most recently requested packages are kept for each
+// parent block, older ones are dropped and have to be requested again.
+func (api *API) GetWorkFor(payout common.Address) ([4]string, error) {
+	return api.ethash.payoutWork(payout)
+}
+
+// SubmitWorkFor can be used by external miners to submit the POW solution of a
+// work package obtained via GetWorkFor. It returns false if the solution is not
+// accepted, or if the work package does not pay the given address.
+func (api *API) SubmitWorkFor(nonce types.BlockNonce, hash, digest common.Hash, payout common.Address) bool {
+	if api.ethash.remote == nil {
+		return false
+	}
+	var errc = make(chan error, 1)
+	select {
+	case api.ethash.remote.submitWorkCh <- &mineResult{
+		nonce:        nonce,
+		mixDigest:    digest,
+		hash:         hash,
+		errc:         errc,
+		minerAddress: payout,
+	}:
+	case <-api.ethash.remote.exitCh:
+		return false
+	}
+	err := <-errc
 	return err == nil
 }
 
@@ -125,3 +150,280 @@ func (api *API) SubmitHashrate(rate hexutil.Uint64, id common.Hash) bool {
 func (api *API) GetHashrate() uint64 {
 	return uint64(api.ethash.Hashrate())
 }
+
+// RPCWorkSubmission is a solution submitted by a remote miner and the verdict
+// of the sealer.
+type RPCWorkSubmission struct {
+	Number    hexutil.Uint64   `json:"number"`
+	SealHash  common.Hash      `json:"sealHash"`
+	Nonce     types.BlockNonce `json:"nonce"`
+	MixDigest common.Hash      `json:"mixDigest"`
+	Payout    *common.Address  `json:"payout,omitempty"`
+	Status    string           `json:"status"`
+	Reason    string           `json:"reason,omitempty"`
+	Time      hexutil.Uint64   `json:"time"`
+}
+
+// RPCWork is a work package issued to remote miners, together with the
+// solutions submitted for it.
+type RPCWork struct {
+	Number      hexutil.Uint64       `json:"number"`
+	SealHash    common.Hash          `json:"sealHash"`
+	ParentHash  common.Hash          `json:"parentHash"`
+	Coinbase    common.Address       `json:"coinbase"`
+	Difficulty  *hexutil.Big         `json:"difficulty"`
+	Iterations  hexutil.Uint64       `json:"iterations"`
+	Time        hexutil.Uint64       `json:"time"`
+	Submissions []*RPCWorkSubmission `json:"submissions"`
+}
+
+// RPCWorkHistory is the work issued to remote miners over a range of blocks.
+type RPCWorkHistory struct {
+	Works     []*RPCWork           `json:"works"`
+	Unmatched []*RPCWorkSubmission `json:"unmatched"` // Submissions for work never issued or already pruned
+}
+
+// GetWorkHistory returns the work packages issued to remote miners for the
+// given number of most recent blocks, defaulting to all retained ones, along
+// with every solution submitted and the reason of its rejection, if any.
+func (api *API) GetWorkHistory(blocks *hexutil.Uint64) (*RPCWorkHistory, error) {
+	var depth uint64
+	if blocks != nil {
+		depth = uint64(*blocks)
+	}
+	history, err := api.ethash.workHistory(depth)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		res   = &RPCWorkHistory{Works: make([]*RPCWork, 0, len(history.works)), Unmatched: []*RPCWorkSubmission{}}
+		works = make(map[common.Hash]*RPCWork)
+	)
+	for _, w := range history.works {
+		work := &RPCWork{
+			Number:      hexutil.Uint64(w.Number),
+			SealHash:    w.SealHash,
+			ParentHash:  w.ParentHash,
+			Coinbase:    w.Coinbase,
+			Difficulty:  (*hexutil.Big)(w.Difficulty),
+			Iterations:  hexutil.Uint64(w.Iterations),
+			Time:        hexutil.Uint64(w.Time),
+			Submissions: []*RPCWorkSubmission{},
+		}
+		works[w.SealHash] = work
+		res.Works = append(res.Works, work)
+	}
+	for _, s := range history.submissions {
+		submission := &RPCWorkSubmission{
+			Number:    hexutil.Uint64(s.Number),
+			SealHash:  s.SealHash,
+			Nonce:     s.Nonce,
+			MixDigest: s.MixDigest,
+			Status:    s.Status,
+			Reason:    s.Reason,
+			Time:      hexutil.Uint64(s.Time),
+		}
+		if s.Payout != (common.Address{}) {
+			payout := s.Payout
+			submission.Payout = &payout
+		}
+		if work := works[s.SealHash]; work != nil {
+			work.Submissions = append(work.Submissions, submission)
+		} else {
+			res.Unmatched = append(res.Unmatched, submission)
+		}
+	}
+	return res, nil
+}
+
+// RewardAPI exposes the block reward schedule of the chain for the RPC interface.
+type RewardAPI struct {
+	chain consensus.ChainHeaderReader
+}
+
+// RPCReward is a single balance credit issued by a block.
+type RPCReward struct {
+	Kind    string         `json:"kind"`
+	Address common.Address `json:"address"`
+	Amount  *hexutil.Big   `json:"amount"`
+}
+
+// RPCRewardSchedule is the reward epoch in effect at a block.
+type RPCRewardSchedule struct {
+	Number        hexutil.Uint64  `json:"number"`
+	Epoch         string          `json:"epoch"`
+	EpochStart    hexutil.Uint64  `json:"epochStart"`
+	NextEpoch     *hexutil.Uint64 `json:"nextEpoch"` // First block of the next epoch, nil in the last one
+	MinerReward   *hexutil.Big    `json:"minerReward"`
+	StakingReward *hexutil.Big    `json:"stakingReward"`
+	DevFund       *hexutil.Big    `json:"devFund"`
+	UncleRewards  bool            `json:"uncleRewards"`
+	Recipients    []RPCReward     `json:"recipients"` // Dev-fund and staking breakdown
+}
+
+// RPCBlockRewards is the breakdown of all balance credits issued by a block.
+type RPCBlockRewards struct {
+	Number  hexutil.Uint64 `json:"number"`
+	Hash    common.Hash    `json:"hash"`
+	Epoch   string         `json:"epoch"`
+	Total   *hexutil.Big   `json:"total"`
+	Rewards []RPCReward    `json:"rewards"`
+}
+
+// resolve returns the block number a block tag refers to. Any block number is
+// accepted, including ones the chain did not reach yet.
+func (api *RewardAPI) resolve(number rpc.BlockNumber) uint64 {
+	switch number {
+	case rpc.EarliestBlockNumber:
+		return 0
+	case rpc.PendingBlockNumber:
+		return api.chain.CurrentHeader().Number.Uint64() + 1
+	}
+	if number < 0 {
+		return api.chain.CurrentHeader().Number.Uint64()
+	}
+	return uint64(number)
+}
+
+func newRPCRewards(rewards []Reward) ([]RPCReward, *big.Int) {
+	var (
+		res   = make([]RPCReward, 0, len(rewards))
+		total = new(big.Int)
+	)
+	for _, reward := range rewards {
+		res = append(res, RPCReward{Kind: reward.Kind, Address: reward.Address, Amount: (*hexutil.Big)(reward.Amount)})
+		total.Add(total, reward.Amount)
+	}
+	return res, total
+}
+
+// GetRewardSchedule returns the reward epoch in effect at the given block, the
+// first block of the following epoch and the split of the dev-fund and staking
+// rewards between their recipients. The block may lie in the future.
+func (api *RewardAPI) GetRewardSchedule(number rpc.BlockNumber) *RPCRewardSchedule {
+	var (
+		config   = api.chain.Config()
+		schedule = config.LibertySchedule()
+		num      = api.resolve(number)
+		epoch    = shared.GetCurrentEpoch(config, num)
+	)
+	// Break down the rewards of an uncle-less block paying an unknown coinbase
+	header := &types.Header{Number: new(big.Int).SetUint64(num)}
+
+	var recipients []Reward
+	for _, reward := range BlockRewards(config, header, nil) {
+		if reward.Kind != RewardMiner {
+			recipients = append(recipients, reward)
+		}
+	}
+	res := &RPCRewardSchedule{
+		Number:        hexutil.Uint64(num),
+		Epoch:         epoch.Name,
+		EpochStart:    hexutil.Uint64(epoch.StartBlock),
+		MinerReward:   (*hexutil.Big)(epoch.MinerReward),
+		StakingReward: (*hexutil.Big)(epoch.StakingReward),
+		DevFund:       (*hexutil.Big)(epoch.DevFund),
+		UncleRewards:  schedule.IsUncleRewards(header.Number),
+	}
+	res.Recipients, _ = newRPCRewards(recipients)
+
+	for _, next := range schedule.Epochs {
+		if next.StartBlock > num {
+			start := hexutil.Uint64(next.StartBlock)
+			res.NextEpoch = &start
+			break
+		}
+	}
+	return res
+}
+
+// GetBlockRewards returns all balance credits issued by the given block of the
+// canonical chain, including the rewards of any uncles it includes.
+func (api *RewardAPI) GetBlockRewards(number rpc.BlockNumber) (*RPCBlockRewards, error) {
+	header := api.chain.GetHeaderByNumber(api.resolve(number))
+	if header == nil {
+		return nil, nil
+	}
+	var uncles []*types.Header
+	if header.UncleHash != types.EmptyUncleHash {
+		chain, ok := api.chain.(consensus.ChainReader)
+		if !ok {
+			return nil, errors.New("uncles not available")
+		}
+		block := chain.GetBlock(header.Hash(), header.Number.Uint64())
+		if block == nil {
+			return nil, fmt.Errorf("block #%d body not found", header.Number)
+		}
+		uncles = block.Uncles()
+	}
+	rewards, total := newRPCRewards(BlockRewards(api.chain.Config(), header, uncles))
+	return &RPCBlockRewards{
+		Number:  hexutil.Uint64(header.Number.Uint64()),
+		Hash:    header.Hash(),
+		Epoch:   shared.GetCurrentEpoch(api.chain.Config(), header.Number.Uint64()).Name,
+		Total:   (*hexutil.Big)(total),
+		Rewards: rewards,
+	}, nil
+}
+
+// NetworkAPI exposes the mining statistics of the network for the RPC interface.
+type NetworkAPI struct {
+	chain consensus.ChainReader
+}
+
+// RPCMinerStat is the share of the blocks of a window sealed by a coinbase.
+type RPCMinerStat struct {
+	Coinbase common.Address `json:"coinbase"`
+	Blocks   hexutil.Uint64 `json:"blocks"`
+	Share    float64        `json:"share"`
+}
+
+// RPCNetworkStats are the mining statistics of the network over a block window.
+type RPCNetworkStats struct {
+	From       hexutil.Uint64 `json:"from"`
+	To         hexutil.Uint64 `json:"to"`
+	Hashrate   *hexutil.Big   `json:"hashrate"`
+	Difficulty *hexutil.Big   `json:"difficulty"`
+	BlockTime  float64        `json:"blockTime"` // Seconds
+	Uncles     hexutil.Uint64 `json:"uncles"`
+	UncleRate  float64        `json:"uncleRate"`
+	Miners     []RPCMinerStat `json:"miners"`
+}
+
+// NetworkStats returns the network hashrate, the average block time, the uncle
+// rate and the distribution of the blocks between coinbases, estimated over the
+// given number of most recent blocks, DefaultStatsWindow if omitted.
+func (api *NetworkAPI) NetworkStats(blocks *hexutil.Uint64) (*RPCNetworkStats, error) {
+	window := uint64(DefaultStatsWindow)
+	if blocks != nil {
+		window = uint64(*blocks)
+	}
+	head := api.chain.CurrentHeader().Number.Uint64()
+	stats, err := EstimateNetworkStats(head, window, api.chain.GetHeaderByNumber, func(hash common.Hash, number uint64) []*types.Header {
+		if block := api.chain.GetBlock(hash, number); block != nil {
+			return block.Uncles()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := &RPCNetworkStats{
+		From:       hexutil.Uint64(stats.From),
+		To:         hexutil.Uint64(stats.To),
+		Hashrate:   (*hexutil.Big)(stats.Hashrate),
+		Difficulty: (*hexutil.Big)(stats.Difficulty),
+		BlockTime:  stats.BlockTime,
+		Uncles:     hexutil.Uint64(stats.Uncles),
+		UncleRate:  stats.UncleRate,
+		Miners:     make([]RPCMinerStat, 0, len(stats.Miners)),
+	}
+	for _, miner := range stats.Miners {
+		res.Miners = append(res.Miners, RPCMinerStat{
+			Coinbase: miner.Coinbase,
+			Blocks:   hexutil.Uint64(miner.Blocks),
+			Share:    miner.Share,
+		})
+	}
+	return res, nil
+}
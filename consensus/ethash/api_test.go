@@ -0,0 +1,167 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethash
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// testBlockReader is a chain reader serving a canonical block per height.
+type testBlockReader struct {
+	testChainReader
+	blocks map[uint64]*types.Block
+	head   uint64
+}
+
+func (cr *testBlockReader) CurrentHeader() *types.Header { return cr.blocks[cr.head].Header() }
+
+func (cr *testBlockReader) GetHeaderByNumber(number uint64) *types.Header {
+	if block := cr.blocks[number]; block != nil {
+		return block.Header()
+	}
+	return nil
+}
+
+func (cr *testBlockReader) GetBlock(hash common.Hash, number uint64) *types.Block {
+	if block := cr.blocks[number]; block != nil && block.Hash() == hash {
+		return block
+	}
+	return nil
+}
+
+// Tests that the reward RPC reports exactly the balances credited by the block
+// rewards, uncles included.
+func TestGetBlockRewards(t *testing.T) {
+	config := *params.AllEthashProtocolChanges
+	config.Liberty = &params.LibertyConfig{
+		Epochs:           params.DefaultLibertyConfig.Epochs,
+		UncleRewardBlock: big.NewInt(500001),
+	}
+	var (
+		uncles = []*types.Header{
+			{Number: big.NewInt(500008), Coinbase: common.Address{0xbb}},
+			{Number: big.NewInt(500004), Coinbase: common.Address{0xcc}},
+		}
+		header = &types.Header{Number: big.NewInt(500010), Coinbase: common.Address{0xaa}, Difficulty: big.NewInt(1)}
+		block  = types.NewBlock(header, nil, uncles, nil, trie.NewStackTrie(nil))
+		chain  = &testBlockReader{
+			testChainReader: testChainReader{config: &config},
+			blocks:          map[uint64]*types.Block{500010: block},
+			head:            500010,
+		}
+		api = &RewardAPI{chain}
+	)
+	res, err := api.GetBlockRewards(rpc.LatestBlockNumber)
+	if err != nil {
+		t.Fatalf("failed to retrieve block rewards: %v", err)
+	}
+	if res.Hash != block.Hash() || res.Epoch != "Unity" {
+		t.Fatalf("block mismatch: have %x/%s, want %x/Unity", res.Hash, res.Epoch, block.Hash())
+	}
+	// Every credit reported must match the state transition
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	NewFaker().accumulateRewards(&config, block.Header(), statedb, nil, block.Uncles())
+
+	var (
+		credits = make(map[common.Address]*big.Int)
+		kinds   = make(map[string]int)
+		total   = new(big.Int)
+	)
+	for _, reward := range res.Rewards {
+		if credits[reward.Address] == nil {
+			credits[reward.Address] = new(big.Int)
+		}
+		credits[reward.Address].Add(credits[reward.Address], reward.Amount.ToInt())
+		total.Add(total, reward.Amount.ToInt())
+		kinds[reward.Kind]++
+	}
+	for addr, credit := range credits {
+		if balance := statedb.GetBalance(addr); balance.Cmp(credit) != 0 {
+			t.Errorf("credit of %x mismatch: have %v, want %v", addr, credit, balance)
+		}
+	}
+	if total.Cmp(res.Total.ToInt()) != 0 {
+		t.Errorf("total mismatch: have %v, want %v", res.Total, total)
+	}
+	if kinds[RewardMiner] != 1 || kinds[RewardUncle] != 2 || kinds[RewardDevFund] != 3 || kinds[RewardStaking] != 2 {
+		t.Errorf("reward kinds mismatch: %v", kinds)
+	}
+	// Unknown blocks are reported as missing
+	if res, err := api.GetBlockRewards(1); res != nil || err != nil {
+		t.Errorf("missing block reported: %v, %v", res, err)
+	}
+}
+
+// Tests the reported reward schedule around an epoch boundary.
+func TestGetRewardSchedule(t *testing.T) {
+	var (
+		config = *params.AllEthashProtocolChanges
+		chain  = &testBlockReader{
+			testChainReader: testChainReader{config: &config},
+			blocks:          map[uint64]*types.Block{10: types.NewBlockWithHeader(&types.Header{Number: big.NewInt(10)})},
+			head:            10,
+		}
+		api    = &RewardAPI{chain}
+		epochs = params.DefaultLibertyConfig.Epochs
+	)
+	tests := []struct {
+		number     rpc.BlockNumber
+		epoch      string
+		next       uint64
+		recipients int
+	}{
+		{rpc.LatestBlockNumber, "Freedom", 500001, 3},
+		{rpc.PendingBlockNumber, "Freedom", 500001, 3},
+		{500000, "Freedom", 500001, 3},
+		{500001, "Unity", 1050001, 5},
+		{20_000_000, "Finality", 0, 5},
+	}
+	for i, tt := range tests {
+		res := api.GetRewardSchedule(tt.number)
+		if res.Epoch != tt.epoch {
+			t.Errorf("test %d: epoch mismatch: have %s, want %s", i, res.Epoch, tt.epoch)
+		}
+		if tt.next == 0 && res.NextEpoch != nil {
+			t.Errorf("test %d: unexpected next epoch %v", i, *res.NextEpoch)
+		}
+		if tt.next != 0 && (res.NextEpoch == nil || uint64(*res.NextEpoch) != tt.next) {
+			t.Errorf("test %d: next epoch mismatch: have %v, want %d", i, res.NextEpoch, tt.next)
+		}
+		if len(res.Recipients) != tt.recipients {
+			t.Errorf("test %d: recipient count mismatch: have %d, want %d", i, len(res.Recipients), tt.recipients)
+		}
+		shared := new(big.Int)
+		for _, recipient := range res.Recipients {
+			shared.Add(shared, recipient.Amount.ToInt())
+		}
+		if want := new(big.Int).Add(res.DevFund.ToInt(), res.StakingReward.ToInt()); shared.Cmp(want) != 0 {
+			t.Errorf("test %d: recipient total mismatch: have %v, want %v", i, shared, want)
+		}
+	}
+	if res := api.GetRewardSchedule(rpc.PendingBlockNumber); uint64(res.Number) != 11 || res.MinerReward.ToInt().Cmp(epochs[0].MinerReward) != 0 {
+		t.Errorf("pending schedule mismatch: number %d, miner reward %v", res.Number, res.MinerReward)
+	}
+}
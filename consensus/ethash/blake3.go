@@ -0,0 +1,134 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethash
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// The proof-of-work digest chains Blake3 over inputs of at most 64 bytes, each
+// of which is hashed by a single compression of one block. The kernels below
+// implement just that compression, keeping the chain in the word domain, so
+// that no hasher state, padding or output encoding is repeated per round. The
+// vectorised kernels of blake3_amd64.s run the chains of several nonces in
+// lockstep, one per vector lane.
+
+// blake3MaxLanes is the largest number of nonces whose digests are computed in
+// lockstep.
+const blake3MaxLanes = 16
+
+// blake3IV is the initialisation vector of Blake3, shared with SHA-256.
+var blake3IV = [8]uint32{
+	0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a, 0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19,
+}
+
+// blake3Flags are the domain flags of the only block of a single chunk root
+// input: chunk start, chunk end and root.
+const blake3Flags = 1<<0 | 1<<1 | 1<<3
+
+// blake3Schedule is the order the message words are consumed in by each of the
+// seven rounds, the message permutation being applied between rounds.
+var blake3Schedule = [7][16]uint8{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	{2, 6, 3, 10, 7, 0, 4, 13, 1, 11, 12, 5, 9, 14, 15, 8},
+	{3, 4, 10, 12, 13, 2, 7, 14, 6, 5, 9, 0, 11, 15, 8, 1},
+	{10, 7, 12, 9, 14, 3, 13, 15, 4, 0, 11, 2, 5, 8, 1, 6},
+	{12, 13, 9, 11, 15, 10, 14, 8, 7, 2, 5, 3, 0, 1, 6, 4},
+	{9, 14, 11, 5, 8, 12, 15, 1, 13, 3, 0, 10, 2, 6, 4, 7},
+	{11, 15, 5, 0, 1, 9, 8, 6, 14, 10, 2, 12, 3, 4, 7, 13},
+}
+
+// blake3Words is a 256 bit digest, as little endian words.
+type blake3Words [8]uint32
+
+// blake3Seed returns the input block of the first round of the digest chain,
+// the seal hash followed by the big endian nonce.
+func blake3Seed(hash *blake3Words, nonce uint64) (block [16]uint32) {
+	copy(block[:8], hash[:])
+	block[8] = bits.ReverseBytes32(uint32(nonce >> 32))
+	block[9] = bits.ReverseBytes32(uint32(nonce))
+	return block
+}
+
+// blake3Compress returns the 256 bit Blake3 digest of the single block input of
+// the given length.
+func blake3Compress(m *[16]uint32, length uint32) (out blake3Words) {
+	v0, v1, v2, v3 := blake3IV[0], blake3IV[1], blake3IV[2], blake3IV[3]
+	v4, v5, v6, v7 := blake3IV[4], blake3IV[5], blake3IV[6], blake3IV[7]
+	v8, v9, v10, v11 := blake3IV[0], blake3IV[1], blake3IV[2], blake3IV[3]
+	v12, v13, v14, v15 := uint32(0), uint32(0), length, uint32(blake3Flags)
+
+	for r := range blake3Schedule {
+		s := &blake3Schedule[r]
+
+		// Mix the columns
+		v0, v4, v8, v12 = blake3G(v0, v4, v8, v12, m[s[0]], m[s[1]])
+		v1, v5, v9, v13 = blake3G(v1, v5, v9, v13, m[s[2]], m[s[3]])
+		v2, v6, v10, v14 = blake3G(v2, v6, v10, v14, m[s[4]], m[s[5]])
+		v3, v7, v11, v15 = blake3G(v3, v7, v11, v15, m[s[6]], m[s[7]])
+
+		// Mix the diagonals
+		v0, v5, v10, v15 = blake3G(v0, v5, v10, v15, m[s[8]], m[s[9]])
+		v1, v6, v11, v12 = blake3G(v1, v6, v11, v12, m[s[10]], m[s[11]])
+		v2, v7, v8, v13 = blake3G(v2, v7, v8, v13, m[s[12]], m[s[13]])
+		v3, v4, v9, v14 = blake3G(v3, v4, v9, v14, m[s[14]], m[s[15]])
+	}
+	return blake3Words{v0 ^ v8, v1 ^ v9, v2 ^ v10, v3 ^ v11, v4 ^ v12, v5 ^ v13, v6 ^ v14, v7 ^ v15}
+}
+
+// blake3G is the quarter round mixing function of Blake3.
+func blake3G(a, b, c, d, mx, my uint32) (uint32, uint32, uint32, uint32) {
+	a += b + mx
+	d = bits.RotateLeft32(d^a, -16)
+	c += d
+	b = bits.RotateLeft32(b^c, -12)
+	a += b + my
+	d = bits.RotateLeft32(d^a, -8)
+	c += d
+	b = bits.RotateLeft32(b^c, -7)
+	return a, b, c, d
+}
+
+// blake3Chain computes the proof-of-work digest of a single nonce: the Blake3
+// digest of the seal hash and nonce, rehashed the given number of times.
+func blake3Chain(hash *blake3Words, nonce uint64, iters uint64) blake3Words {
+	block := blake3Seed(hash, nonce)
+	digest := blake3Compress(&block, 40)
+
+	block = [16]uint32{}
+	for i := uint64(0); i < iters; i++ {
+		copy(block[:8], digest[:])
+		digest = blake3Compress(&block, 32)
+	}
+	return digest
+}
+
+// toWords converts a 32 byte hash into little endian words.
+func toWords(hash []byte) (words blake3Words) {
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint32(hash[4*i:])
+	}
+	return words
+}
+
+// fromWords converts little endian words into a 32 byte hash.
+func fromWords(words *blake3Words, hash []byte) {
+	for i, w := range words {
+		binary.LittleEndian.PutUint32(hash[4*i:], w)
+	}
+}
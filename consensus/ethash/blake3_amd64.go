@@ -0,0 +1,99 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build amd64 && !gccgo
+// +build amd64,!gccgo
+
+package ethash
+
+import (
+	"encoding/binary"
+	"math/bits"
+
+	"github.com/zeebo/blake3"
+	"golang.org/x/sys/cpu"
+)
+
+//go:generate go run blake3_gen.go
+
+var (
+	useAVX512 = cpu.X86.HasAVX512F
+	useAVX2   = cpu.X86.HasAVX2
+)
+
+//go:noescape
+func blake3ChainAVX512(hash *blake3Words, nonces *[2][16]uint32, iters uint64, out *[8][16]uint32)
+
+//go:noescape
+func blake3ChainAVX2(hash *blake3Words, nonces *[2][8]uint32, iters uint64, out *[8][8]uint32)
+
+// blake3ChainBatch computes the proof-of-work digests of consecutive nonces,
+// starting at the given one, as many at once as the vector units of the CPU
+// allow. The number of digests written to out is returned.
+func blake3ChainBatch(hash *blake3Words, nonce uint64, iters uint64, out *[blake3MaxLanes]blake3Words) int {
+	switch {
+	case useAVX512:
+		var (
+			nonces [2][16]uint32
+			words  [8][16]uint32
+		)
+		for l := range nonces[0] {
+			nonces[0][l] = bits.ReverseBytes32(uint32((nonce + uint64(l)) >> 32))
+			nonces[1][l] = bits.ReverseBytes32(uint32(nonce + uint64(l)))
+		}
+		blake3ChainAVX512(hash, &nonces, iters, &words)
+		for l := range nonces[0] {
+			for i := range words {
+				out[l][i] = words[i][l]
+			}
+		}
+		return 16
+
+	case useAVX2:
+		var (
+			nonces [2][8]uint32
+			words  [8][8]uint32
+		)
+		for l := range nonces[0] {
+			nonces[0][l] = bits.ReverseBytes32(uint32((nonce + uint64(l)) >> 32))
+			nonces[1][l] = bits.ReverseBytes32(uint32(nonce + uint64(l)))
+		}
+		blake3ChainAVX2(hash, &nonces, iters, &words)
+		for l := range nonces[0] {
+			for i := range words {
+				out[l][i] = words[i][l]
+			}
+		}
+		return 8
+
+	default:
+		// The assembly of the blake3 package beats the portable kernel on CPUs
+		// without wide vectors.
+		var (
+			seed   [40]byte
+			digest [32]byte
+		)
+		fromWords(hash, seed[:])
+		binary.BigEndian.PutUint64(seed[32:], nonce)
+
+		digest = blake3.Sum256(seed[:])
+		for i := uint64(0); i < iters; i++ {
+			digest = blake3.Sum256(digest[:])
+		}
+		out[0] = toWords(digest[:])
+		return 1
+	}
+}
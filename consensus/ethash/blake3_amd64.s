@@ -0,0 +1,3667 @@
+// Code generated by blake3_gen.go. DO NOT EDIT.
+
+//go:build amd64 && !gccgo
+// +build amd64,!gccgo
+
+#include "textflag.h"
+
+DATA ·blake3IV<>+0x00(SB)/4, $0x6a09e667
+DATA ·blake3IV<>+0x04(SB)/4, $0xbb67ae85
+DATA ·blake3IV<>+0x08(SB)/4, $0x3c6ef372
+DATA ·blake3IV<>+0x0c(SB)/4, $0xa54ff53a
+DATA ·blake3IV<>+0x10(SB)/4, $0x510e527f
+DATA ·blake3IV<>+0x14(SB)/4, $0x9b05688c
+DATA ·blake3IV<>+0x18(SB)/4, $0x1f83d9ab
+DATA ·blake3IV<>+0x1c(SB)/4, $0x5be0cd19
+GLOBL ·blake3IV<>(SB), (NOPTR+RODATA), $32
+
+DATA ·blake3Params<>+0x00(SB)/4, $40
+DATA ·blake3Params<>+0x04(SB)/4, $32
+DATA ·blake3Params<>+0x08(SB)/4, $11
+GLOBL ·blake3Params<>(SB), (NOPTR+RODATA), $12
+
+DATA ·blake3Rot16<>+0x00(SB)/8, $0x0504070601000302
+DATA ·blake3Rot16<>+0x08(SB)/8, $0x0d0c0f0e09080b0a
+DATA ·blake3Rot16<>+0x10(SB)/8, $0x0504070601000302
+DATA ·blake3Rot16<>+0x18(SB)/8, $0x0d0c0f0e09080b0a
+GLOBL ·blake3Rot16<>(SB), (NOPTR+RODATA), $32
+
+DATA ·blake3Rot8<>+0x00(SB)/8, $0x0407060500030201
+DATA ·blake3Rot8<>+0x08(SB)/8, $0x0c0f0e0d080b0a09
+DATA ·blake3Rot8<>+0x10(SB)/8, $0x0407060500030201
+DATA ·blake3Rot8<>+0x18(SB)/8, $0x0c0f0e0d080b0a09
+GLOBL ·blake3Rot8<>(SB), (NOPTR+RODATA), $32
+
+// func blake3ChainAVX512(hash *blake3Words, nonces *[2][16]uint32, iters uint64, out *[8][16]uint32)
+// Requires: AVX512F
+TEXT ·blake3ChainAVX512(SB), NOSPLIT, $0-32
+	MOVQ hash+0(FP), AX
+	MOVQ nonces+8(FP), BX
+	MOVQ iters+16(FP), CX
+	MOVQ out+24(FP), DX
+	VPBROADCASTD 0(AX), Z16
+	VPBROADCASTD 4(AX), Z17
+	VPBROADCASTD 8(AX), Z18
+	VPBROADCASTD 12(AX), Z19
+	VPBROADCASTD 16(AX), Z20
+	VPBROADCASTD 20(AX), Z21
+	VPBROADCASTD 24(AX), Z22
+	VPBROADCASTD 28(AX), Z23
+	VMOVDQU32 (BX), Z24
+	VMOVDQU32 64(BX), Z25
+	VPBROADCASTD ·blake3IV<>+0x00(SB), Z0
+	VPBROADCASTD ·blake3IV<>+0x04(SB), Z1
+	VPBROADCASTD ·blake3IV<>+0x08(SB), Z2
+	VPBROADCASTD ·blake3IV<>+0x0c(SB), Z3
+	VPBROADCASTD ·blake3IV<>+0x10(SB), Z4
+	VPBROADCASTD ·blake3IV<>+0x14(SB), Z5
+	VPBROADCASTD ·blake3IV<>+0x18(SB), Z6
+	VPBROADCASTD ·blake3IV<>+0x1c(SB), Z7
+	VMOVDQA32 Z0, Z8
+	VMOVDQA32 Z1, Z9
+	VMOVDQA32 Z2, Z10
+	VMOVDQA32 Z3, Z11
+	VPXORD Z12, Z12, Z12
+	VPXORD Z13, Z13, Z13
+	VPBROADCASTD ·blake3Params<>+0x00(SB), Z14
+	VPBROADCASTD ·blake3Params<>+0x08(SB), Z15
+	VPADDD Z4, Z0, Z0
+	VPADDD Z5, Z1, Z1
+	VPADDD Z6, Z2, Z2
+	VPADDD Z7, Z3, Z3
+	VPADDD Z16, Z0, Z0
+	VPADDD Z18, Z1, Z1
+	VPADDD Z20, Z2, Z2
+	VPADDD Z22, Z3, Z3
+	VPXORD Z0, Z12, Z12
+	VPXORD Z1, Z13, Z13
+	VPXORD Z2, Z14, Z14
+	VPXORD Z3, Z15, Z15
+	VPRORD $16, Z12, Z12
+	VPRORD $16, Z13, Z13
+	VPRORD $16, Z14, Z14
+	VPRORD $16, Z15, Z15
+	VPADDD Z12, Z8, Z8
+	VPADDD Z13, Z9, Z9
+	VPADDD Z14, Z10, Z10
+	VPADDD Z15, Z11, Z11
+	VPXORD Z8, Z4, Z4
+	VPXORD Z9, Z5, Z5
+	VPXORD Z10, Z6, Z6
+	VPXORD Z11, Z7, Z7
+	VPRORD $12, Z4, Z4
+	VPRORD $12, Z5, Z5
+	VPRORD $12, Z6, Z6
+	VPRORD $12, Z7, Z7
+	VPADDD Z4, Z0, Z0
+	VPADDD Z5, Z1, Z1
+	VPADDD Z6, Z2, Z2
+	VPADDD Z7, Z3, Z3
+	VPADDD Z17, Z0, Z0
+	VPADDD Z19, Z1, Z1
+	VPADDD Z21, Z2, Z2
+	VPADDD Z23, Z3, Z3
+	VPXORD Z0, Z12, Z12
+	VPXORD Z1, Z13, Z13
+	VPXORD Z2, Z14, Z14
+	VPXORD Z3, Z15, Z15
+	VPRORD $8, Z12, Z12
+	VPRORD $8, Z13, Z13
+	VPRORD $8, Z14, Z14
+	VPRORD $8, Z15, Z15
+	VPADDD Z12, Z8, Z8
+	VPADDD Z13, Z9, Z9
+	VPADDD Z14, Z10, Z10
+	VPADDD Z15, Z11, Z11
+	VPXORD Z8, Z4, Z4
+	VPXORD Z9, Z5, Z5
+	VPXORD Z10, Z6, Z6
+	VPXORD Z11, Z7, Z7
+	VPRORD $7, Z4, Z4
+	VPRORD $7, Z5, Z5
+	VPRORD $7, Z6, Z6
+	VPRORD $7, Z7, Z7
+	VPADDD Z5, Z0, Z0
+	VPADDD Z6, Z1, Z1
+	VPADDD Z7, Z2, Z2
+	VPADDD Z4, Z3, Z3
+	VPADDD Z24, Z0, Z0
+	VPXORD Z1, Z12, Z12
+	VPXORD Z2, Z13, Z13
+	VPXORD Z3, Z14, Z14
+	VPXORD Z0, Z15, Z15
+	VPRORD $16, Z12, Z12
+	VPRORD $16, Z13, Z13
+	VPRORD $16, Z14, Z14
+	VPRORD $16, Z15, Z15
+	VPADDD Z12, Z11, Z11
+	VPADDD Z13, Z8, Z8
+	VPADDD Z14, Z9, Z9
+	VPADDD Z15, Z10, Z10
+	VPXORD Z11, Z6, Z6
+	VPXORD Z8, Z7, Z7
+	VPXORD Z9, Z4, Z4
+	VPXORD Z10, Z5, Z5
+	VPRORD $12, Z6, Z6
+	VPRORD $12, Z7, Z7
+	VPRORD $12, Z4, Z4
+	VPRORD $12, Z5, Z5
+	VPADDD Z6, Z1, Z1
+	VPADDD Z7, Z2, Z2
+	VPADDD Z4, Z3, Z3
+	VPADDD Z5, Z0, Z0
+	VPXORD Z1, Z12, Z12
+	VPXORD Z2, Z13, Z13
+	VPXORD Z3, Z14, Z14
+	VPADDD Z25, Z0, Z0
+	VPRORD $8, Z12, Z12
+	VPRORD $8, Z13, Z13
+	VPRORD $8, Z14, Z14
+	VPXORD Z0, Z15, Z15
+	VPADDD Z12, Z11, Z11
+	VPADDD Z13, Z8, Z8
+	VPADDD Z14, Z9, Z9
+	VPRORD $8, Z15, Z15
+	VPXORD Z11, Z6, Z6
+	VPXORD Z8, Z7, Z7
+	VPXORD Z9, Z4, Z4
+	VPADDD Z15, Z10, Z10
+	VPRORD $7, Z6, Z6
+	VPRORD $7, Z7, Z7
+	VPRORD $7, Z4, Z4
+	VPXORD Z10, Z5, Z5
+	VPRORD $7, Z5, Z5
+	VPADDD Z4, Z0, Z0
+	VPADDD Z5, Z1, Z1
+	VPADDD Z6, Z2, Z2
+	VPADDD Z7, Z3, Z3
+	VPADDD Z18, Z0, Z0
+	VPADDD Z19, Z1, Z1
+	VPADDD Z23, Z2, Z2
+	VPADDD Z20, Z3, Z3
+	VPXORD Z0, Z12, Z12
+	VPXORD Z1, Z13, Z13
+	VPXORD Z2, Z14, Z14
+	VPXORD Z3, Z15, Z15
+	VPRORD $16, Z12, Z12
+	VPRORD $16, Z13, Z13
+	VPRORD $16, Z14, Z14
+	VPRORD $16, Z15, Z15
+	VPADDD Z12, Z8, Z8
+	VPADDD Z13, Z9, Z9
+	VPADDD Z14, Z10, Z10
+	VPADDD Z15, Z11, Z11
+	VPXORD Z8, Z4, Z4
+	VPXORD Z9, Z5, Z5
+	VPXORD Z10, Z6, Z6
+	VPXORD Z11, Z7, Z7
+	VPRORD $12, Z4, Z4
+	VPRORD $12, Z5, Z5
+	VPRORD $12, Z6, Z6
+	VPRORD $12, Z7, Z7
+	VPADDD Z4, Z0, Z0
+	VPADDD Z5, Z1, Z1
+	VPADDD Z6, Z2, Z2
+	VPADDD Z7, Z3, Z3
+	VPADDD Z22, Z0, Z0
+	VPXORD Z1, Z13, Z13
+	VPADDD Z16, Z2, Z2
+	VPXORD Z3, Z15, Z15
+	VPXORD Z0, Z12, Z12
+	VPRORD $8, Z13, Z13
+	VPXORD Z2, Z14, Z14
+	VPRORD $8, Z15, Z15
+	VPRORD $8, Z12, Z12
+	VPADDD Z13, Z9, Z9
+	VPRORD $8, Z14, Z14
+	VPADDD Z15, Z11, Z11
+	VPADDD Z12, Z8, Z8
+	VPXORD Z9, Z5, Z5
+	VPADDD Z14, Z10, Z10
+	VPXORD Z11, Z7, Z7
+	VPXORD Z8, Z4, Z4
+	VPRORD $7, Z5, Z5
+	VPXORD Z10, Z6, Z6
+	VPRORD $7, Z7, Z7
+	VPRORD $7, Z4, Z4
+	VPRORD $7, Z6, Z6
+	VPADDD Z5, Z0, Z0
+	VPADDD Z6, Z1, Z1
+	VPADDD Z7, Z2, Z2
+	VPADDD Z4, Z3, Z3
+	VPADDD Z17, Z0, Z0
+	VPXORD Z1, Z12, Z12
+	VPADDD Z25, Z2, Z2
+	VPXORD Z3, Z14, Z14
+	VPXORD Z0, Z15, Z15
+	VPRORD $16, Z12, Z12
+	VPXORD Z2, Z13, Z13
+	VPRORD $16, Z14, Z14
+	VPRORD $16, Z15, Z15
+	VPADDD Z12, Z11, Z11
+	VPRORD $16, Z13, Z13
+	VPADDD Z14, Z9, Z9
+	VPADDD Z15, Z10, Z10
+	VPXORD Z11, Z6, Z6
+	VPADDD Z13, Z8, Z8
+	VPXORD Z9, Z4, Z4
+	VPXORD Z10, Z5, Z5
+	VPRORD $12, Z6, Z6
+	VPXORD Z8, Z7, Z7
+	VPRORD $12, Z4, Z4
+	VPRORD $12, Z5, Z5
+	VPADDD Z6, Z1, Z1
+	VPRORD $12, Z7, Z7
+	VPADDD Z4, Z3, Z3
+	VPADDD Z5, Z0, Z0
+	VPADDD Z21, Z1, Z1
+	VPADDD Z7, Z2, Z2
+	VPADDD Z24, Z3, Z3
+	VPXORD Z0, Z15, Z15
+	VPXORD Z1, Z12, Z12
+	VPXORD Z2, Z13, Z13
+	VPXORD Z3, Z14, Z14
+	VPRORD $8, Z15, Z15
+	VPRORD $8, Z12, Z12
+	VPRORD $8, Z13, Z13
+	VPRORD $8, Z14, Z14
+	VPADDD Z15, Z10, Z10
+	VPADDD Z12, Z11, Z11
+	VPADDD Z13, Z8, Z8
+	VPADDD Z14, Z9, Z9
+	VPXORD Z10, Z5, Z5
+	VPXORD Z11, Z6, Z6
+	VPXORD Z8, Z7, Z7
+	VPXORD Z9, Z4, Z4
+	VPRORD $7, Z5, Z5
+	VPRORD $7, Z6, Z6
+	VPRORD $7, Z7, Z7
+	VPRORD $7, Z4, Z4
+	VPADDD Z4, Z0, Z0
+	VPADDD Z5, Z1, Z1
+	VPADDD Z6, Z2, Z2
+	VPADDD Z7, Z3, Z3
+	VPADDD Z19, Z0, Z0
+	VPXORD Z1, Z13, Z13
+	VPXORD Z2, Z14, Z14
+	VPADDD Z23, Z3, Z3
+	VPXORD Z0, Z12, Z12
+	VPRORD $16, Z13, Z13
+	VPRORD $16, Z14, Z14
+	VPXORD Z3, Z15, Z15
+	VPRORD $16, Z12, Z12
+	VPADDD Z13, Z9, Z9
+	VPADDD Z14, Z10, Z10
+	VPRORD $16, Z15, Z15
+	VPADDD Z12, Z8, Z8
+	VPXORD Z9, Z5, Z5
+	VPXORD Z10, Z6, Z6
+	VPADDD Z15, Z11, Z11
+	VPXORD Z8, Z4, Z4
+	VPRORD $12, Z5, Z5
+	VPRORD $12, Z6, Z6
+	VPXORD Z11, Z7, Z7
+	VPRORD $12, Z4, Z4
+	VPADDD Z5, Z1, Z1
+	VPADDD Z6, Z2, Z2
+	VPRORD $12, Z7, Z7
+	VPADDD Z4, Z0, Z0
+	VPXORD Z1, Z13, Z13
+	VPADDD Z18, Z2, Z2
+	VPADDD Z7, Z3, Z3
+	VPADDD Z20, Z0, Z0
+	VPRORD $8, Z13, Z13
+	VPXORD Z2, Z14, Z14
+	VPXORD Z3, Z15, Z15
+	VPXORD Z0, Z12, Z12
+	VPADDD Z13, Z9, Z9
+	VPRORD $8, Z14, Z14
+	VPRORD $8, Z15, Z15
+	VPRORD $8, Z12, Z12
+	VPXORD Z9, Z5, Z5
+	VPADDD Z14, Z10, Z10
+	VPADDD Z15, Z11, Z11
+	VPADDD Z12, Z8, Z8
+	VPRORD $7, Z5, Z5
+	VPXORD Z10, Z6, Z6
+	VPXORD Z11, Z7, Z7
+	VPXORD Z8, Z4, Z4
+	VPRORD $7, Z6, Z6
+	VPRORD $7, Z7, Z7
+	VPRORD $7, Z4, Z4
+	VPADDD Z5, Z0, Z0
+	VPADDD Z6, Z1, Z1
+	VPADDD Z7, Z2, Z2
+	VPADDD Z4, Z3, Z3
+	VPADDD Z22, Z0, Z0
+	VPADDD Z25, Z1, Z1
+	VPXORD Z2, Z13, Z13
+	VPADDD Z24, Z3, Z3
+	VPXORD Z0, Z15, Z15
+	VPXORD Z1, Z12, Z12
+	VPRORD $16, Z13, Z13
+	VPXORD Z3, Z14, Z14
+	VPRORD $16, Z15, Z15
+	VPRORD $16, Z12, Z12
+	VPADDD Z13, Z8, Z8
+	VPRORD $16, Z14, Z14
+	VPADDD Z15, Z10, Z10
+	VPADDD Z12, Z11, Z11
+	VPXORD Z8, Z7, Z7
+	VPADDD Z14, Z9, Z9
+	VPXORD Z10, Z5, Z5
+	VPXORD Z11, Z6, Z6
+	VPRORD $12, Z7, Z7
+	VPXORD Z9, Z4, Z4
+	VPRORD $12, Z5, Z5
+	VPRORD $12, Z6, Z6
+	VPADDD Z7, Z2, Z2
+	VPRORD $12, Z4, Z4
+	VPADDD Z5, Z0, Z0
+	VPADDD Z6, Z1, Z1
+	VPXORD Z2, Z13, Z13
+	VPADDD Z4, Z3, Z3
+	VPADDD Z21, Z0, Z0
+	VPADDD Z16, Z1, Z1
+	VPRORD $8, Z13, Z13
+	VPADDD Z17, Z3, Z3
+	VPXORD Z0, Z15, Z15
+	VPXORD Z1, Z12, Z12
+	VPADDD Z13, Z8, Z8
+	VPXORD Z3, Z14, Z14
+	VPRORD $8, Z15, Z15
+	VPRORD $8, Z12, Z12
+	VPXORD Z8, Z7, Z7
+	VPRORD $8, Z14, Z14
+	VPADDD Z15, Z10, Z10
+	VPADDD Z12, Z11, Z11
+	VPRORD $7, Z7, Z7
+	VPADDD Z14, Z9, Z9
+	VPXORD Z10, Z5, Z5
+	VPXORD Z11, Z6, Z6
+	VPXORD Z9, Z4, Z4
+	VPRORD $7, Z5, Z5
+	VPRORD $7, Z6, Z6
+	VPRORD $7, Z4, Z4
+	VPADDD Z4, Z0, Z0
+	VPADDD Z5, Z1, Z1
+	VPADDD Z6, Z2, Z2
+	VPADDD Z7, Z3, Z3
+	VPXORD Z0, Z12, Z12
+	VPXORD Z1, Z13, Z13
+	VPXORD Z2, Z14, Z14
+	VPXORD Z3, Z15, Z15
+	VPRORD $16, Z12, Z12
+	VPRORD $16, Z13, Z13
+	VPRORD $16, Z14, Z14
+	VPRORD $16, Z15, Z15
+	VPADDD Z12, Z8, Z8
+	VPADDD Z13, Z9, Z9
+	VPADDD Z14, Z10, Z10
+	VPADDD Z15, Z11, Z11
+	VPXORD Z8, Z4, Z4
+	VPXORD Z9, Z5, Z5
+	VPXORD Z10, Z6, Z6
+	VPXORD Z11, Z7, Z7
+	VPRORD $12, Z4, Z4
+	VPRORD $12, Z5, Z5
+	VPRORD $12, Z6, Z6
+	VPRORD $12, Z7, Z7
+	VPADDD Z4, Z0, Z0
+	VPADDD Z5, Z1, Z1
+	VPADDD Z6, Z2, Z2
+	VPADDD Z7, Z3, Z3
+	VPADDD Z23, Z0, Z0
+	VPADDD Z25, Z1, Z1
+	VPADDD Z19, Z2, Z2
+	VPXORD Z3, Z15, Z15
+	VPXORD Z0, Z12, Z12
+	VPXORD Z1, Z13, Z13
+	VPXORD Z2, Z14, Z14
+	VPRORD $8, Z15, Z15
+	VPRORD $8, Z12, Z12
+	VPRORD $8, Z13, Z13
+	VPRORD $8, Z14, Z14
+	VPADDD Z15, Z11, Z11
+	VPADDD Z12, Z8, Z8
+	VPADDD Z13, Z9, Z9
+	VPADDD Z14, Z10, Z10
+	VPXORD Z11, Z7, Z7
+	VPXORD Z8, Z4, Z4
+	VPXORD Z9, Z5, Z5
+	VPXORD Z10, Z6, Z6
+	VPRORD $7, Z7, Z7
+	VPRORD $7, Z4, Z4
+	VPRORD $7, Z5, Z5
+	VPRORD $7, Z6, Z6
+	VPADDD Z5, Z0, Z0
+	VPADDD Z6, Z1, Z1
+	VPADDD Z7, Z2, Z2
+	VPADDD Z4, Z3, Z3
+	VPADDD Z20, Z0, Z0
+	VPXORD Z1, Z12, Z12
+	VPADDD Z21, Z2, Z2
+	VPADDD Z17, Z3, Z3
+	VPXORD Z0, Z15, Z15
+	VPRORD $16, Z12, Z12
+	VPXORD Z2, Z13, Z13
+	VPXORD Z3, Z14, Z14
+	VPRORD $16, Z15, Z15
+	VPADDD Z12, Z11, Z11
+	VPRORD $16, Z13, Z13
+	VPRORD $16, Z14, Z14
+	VPADDD Z15, Z10, Z10
+	VPXORD Z11, Z6, Z6
+	VPADDD Z13, Z8, Z8
+	VPADDD Z14, Z9, Z9
+	VPXORD Z10, Z5, Z5
+	VPRORD $12, Z6, Z6
+	VPXORD Z8, Z7, Z7
+	VPXORD Z9, Z4, Z4
+	VPRORD $12, Z5, Z5
+	VPADDD Z6, Z1, Z1
+	VPRORD $12, Z7, Z7
+	VPRORD $12, Z4, Z4
+	VPADDD Z5, Z0, Z0
+	VPADDD Z18, Z1, Z1
+	VPADDD Z7, Z2, Z2
+	VPADDD Z4, Z3, Z3
+	VPADDD Z16, Z0, Z0
+	VPXORD Z1, Z12, Z12
+	VPADDD Z24, Z2, Z2
+	VPADDD Z22, Z3, Z3
+	VPXORD Z0, Z15, Z15
+	VPRORD $8, Z12, Z12
+	VPXORD Z2, Z13, Z13
+	VPXORD Z3, Z14, Z14
+	VPRORD $8, Z15, Z15
+	VPADDD Z12, Z11, Z11
+	VPRORD $8, Z13, Z13
+	VPRORD $8, Z14, Z14
+	VPADDD Z15, Z10, Z10
+	VPXORD Z11, Z6, Z6
+	VPADDD Z13, Z8, Z8
+	VPADDD Z14, Z9, Z9
+	VPXORD Z10, Z5, Z5
+	VPRORD $7, Z6, Z6
+	VPXORD Z8, Z7, Z7
+	VPXORD Z9, Z4, Z4
+	VPRORD $7, Z5, Z5
+	VPRORD $7, Z7, Z7
+	VPRORD $7, Z4, Z4
+	VPADDD Z4, Z0, Z0
+	VPADDD Z5, Z1, Z1
+	VPADDD Z6, Z2, Z2
+	VPADDD Z7, Z3, Z3
+	VPXORD Z0, Z12, Z12
+	VPADDD Z25, Z1, Z1
+	VPXORD Z2, Z14, Z14
+	VPXORD Z3, Z15, Z15
+	VPRORD $16, Z12, Z12
+	VPXORD Z1, Z13, Z13
+	VPRORD $16, Z14, Z14
+	VPRORD $16, Z15, Z15
+	VPADDD Z12, Z8, Z8
+	VPRORD $16, Z13, Z13
+	VPADDD Z14, Z10, Z10
+	VPADDD Z15, Z11, Z11
+	VPXORD Z8, Z4, Z4
+	VPADDD Z13, Z9, Z9
+	VPXORD Z10, Z6, Z6
+	VPXORD Z11, Z7, Z7
+	VPRORD $12, Z4, Z4
+	VPXORD Z9, Z5, Z5
+	VPRORD $12, Z6, Z6
+	VPRORD $12, Z7, Z7
+	VPADDD Z4, Z0, Z0
+	VPRORD $12, Z5, Z5
+	VPADDD Z6, Z2, Z2
+	VPADDD Z7, Z3, Z3
+	VPXORD Z0, Z12, Z12
+	VPADDD Z5, Z1, Z1
+	VPXORD Z2, Z14, Z14
+	VPADDD Z24, Z3, Z3
+	VPRORD $8, Z12, Z12
+	VPXORD Z1, Z13, Z13
+	VPRORD $8, Z14, Z14
+	VPXORD Z3, Z15, Z15
+	VPADDD Z12, Z8, Z8
+	VPRORD $8, Z13, Z13
+	VPADDD Z14, Z10, Z10
+	VPRORD $8, Z15, Z15
+	VPXORD Z8, Z4, Z4
+	VPADDD Z13, Z9, Z9
+	VPXORD Z10, Z6, Z6
+	VPADDD Z15, Z11, Z11
+	VPRORD $7, Z4, Z4
+	VPXORD Z9, Z5, Z5
+	VPRORD $7, Z6, Z6
+	VPXORD Z11, Z7, Z7
+	VPRORD $7, Z5, Z5
+	VPRORD $7, Z7, Z7
+	VPADDD Z5, Z0, Z0
+	VPADDD Z6, Z1, Z1
+	VPADDD Z7, Z2, Z2
+	VPADDD Z4, Z3, Z3
+	VPADDD Z23, Z0, Z0
+	VPADDD Z21, Z1, Z1
+	VPADDD Z16, Z2, Z2
+	VPADDD Z22, Z3, Z3
+	VPXORD Z0, Z15, Z15
+	VPXORD Z1, Z12, Z12
+	VPXORD Z2, Z13, Z13
+	VPXORD Z3, Z14, Z14
+	VPRORD $16, Z15, Z15
+	VPRORD $16, Z12, Z12
+	VPRORD $16, Z13, Z13
+	VPRORD $16, Z14, Z14
+	VPADDD Z15, Z10, Z10
+	VPADDD Z12, Z11, Z11
+	VPADDD Z13, Z8, Z8
+	VPADDD Z14, Z9, Z9
+	VPXORD Z10, Z5, Z5
+	VPXORD Z11, Z6, Z6
+	VPXORD Z8, Z7, Z7
+	VPXORD Z9, Z4, Z4
+	VPRORD $12, Z5, Z5
+	VPRORD $12, Z6, Z6
+	VPRORD $12, Z7, Z7
+	VPRORD $12, Z4, Z4
+	VPADDD Z5, Z0, Z0
+	VPADDD Z6, Z1, Z1
+	VPADDD Z7, Z2, Z2
+	VPADDD Z4, Z3, Z3
+	VPADDD Z18, Z0, Z0
+	VPADDD Z19, Z1, Z1
+	VPADDD Z17, Z2, Z2
+	VPADDD Z20, Z3, Z3
+	VPXORD Z0, Z15, Z15
+	VPXORD Z1, Z12, Z12
+	VPXORD Z2, Z13, Z13
+	VPXORD Z3, Z14, Z14
+	VPRORD $8, Z15, Z15
+	VPRORD $8, Z12, Z12
+	VPRORD $8, Z13, Z13
+	VPRORD $8, Z14, Z14
+	VPADDD Z15, Z10, Z10
+	VPADDD Z12, Z11, Z11
+	VPADDD Z13, Z8, Z8
+	VPADDD Z14, Z9, Z9
+	VPXORD Z10, Z5, Z5
+	VPXORD Z11, Z6, Z6
+	VPXORD Z8, Z7, Z7
+	VPXORD Z9, Z4, Z4
+	VPRORD $7, Z5, Z5
+	VPRORD $7, Z6, Z6
+	VPRORD $7, Z7, Z7
+	VPRORD $7, Z4, Z4
+	VPADDD Z4, Z0, Z0
+	VPADDD Z5, Z1, Z1
+	VPADDD Z6, Z2, Z2
+	VPADDD Z7, Z3, Z3
+	VPADDD Z25, Z0, Z0
+	VPXORD Z1, Z13, Z13
+	VPADDD Z24, Z2, Z2
+	VPXORD Z3, Z15, Z15
+	VPXORD Z0, Z12, Z12
+	VPRORD $16, Z13, Z13
+	VPXORD Z2, Z14, Z14
+	VPRORD $16, Z15, Z15
+	VPRORD $16, Z12, Z12
+	VPADDD Z13, Z9, Z9
+	VPRORD $16, Z14, Z14
+	VPADDD Z15, Z11, Z11
+	VPADDD Z12, Z8, Z8
+	VPXORD Z9, Z5, Z5
+	VPADDD Z14, Z10, Z10
+	VPXORD Z11, Z7, Z7
+	VPXORD Z8, Z4, Z4
+	VPRORD $12, Z5, Z5
+	VPXORD Z10, Z6, Z6
+	VPRORD $12, Z7, Z7
+	VPRORD $12, Z4, Z4
+	VPADDD Z5, Z1, Z1
+	VPRORD $12, Z6, Z6
+	VPADDD Z7, Z3, Z3
+	VPADDD Z4, Z0, Z0
+	VPADDD Z21, Z1, Z1
+	VPADDD Z6, Z2, Z2
+	VPADDD Z17, Z3, Z3
+	VPXORD Z0, Z12, Z12
+	VPXORD Z1, Z13, Z13
+	VPXORD Z2, Z14, Z14
+	VPXORD Z3, Z15, Z15
+	VPRORD $8, Z12, Z12
+	VPRORD $8, Z13, Z13
+	VPRORD $8, Z14, Z14
+	VPRORD $8, Z15, Z15
+	VPADDD Z12, Z8, Z8
+	VPADDD Z13, Z9, Z9
+	VPADDD Z14, Z10, Z10
+	VPADDD Z15, Z11, Z11
+	VPXORD Z8, Z4, Z4
+	VPXORD Z9, Z5, Z5
+	VPXORD Z10, Z6, Z6
+	VPXORD Z11, Z7, Z7
+	VPRORD $7, Z4, Z4
+	VPRORD $7, Z5, Z5
+	VPRORD $7, Z6, Z6
+	VPRORD $7, Z7, Z7
+	VPADDD Z5, Z0, Z0
+	VPADDD Z6, Z1, Z1
+	VPADDD Z7, Z2, Z2
+	VPADDD Z4, Z3, Z3
+	VPXORD Z0, Z15, Z15
+	VPADDD Z16, Z1, Z1
+	VPADDD Z18, Z2, Z2
+	VPADDD Z20, Z3, Z3
+	VPRORD $16, Z15, Z15
+	VPXORD Z1, Z12, Z12
+	VPXORD Z2, Z13, Z13
+	VPXORD Z3, Z14, Z14
+	VPADDD Z15, Z10, Z10
+	VPRORD $16, Z12, Z12
+	VPRORD $16, Z13, Z13
+	VPRORD $16, Z14, Z14
+	VPXORD Z10, Z5, Z5
+	VPADDD Z12, Z11, Z11
+	VPADDD Z13, Z8, Z8
+	VPADDD Z14, Z9, Z9
+	VPRORD $12, Z5, Z5
+	VPXORD Z11, Z6, Z6
+	VPXORD Z8, Z7, Z7
+	VPXORD Z9, Z4, Z4
+	VPADDD Z5, Z0, Z0
+	VPRORD $12, Z6, Z6
+	VPRORD $12, Z7, Z7
+	VPRORD $12, Z4, Z4
+	VPADDD Z19, Z0, Z0
+	VPADDD Z6, Z1, Z1
+	VPADDD Z7, Z2, Z2
+	VPADDD Z4, Z3, Z3
+	VPXORD Z0, Z15, Z15
+	VPXORD Z1, Z12, Z12
+	VPADDD Z22, Z2, Z2
+	VPADDD Z23, Z3, Z3
+	VPRORD $8, Z15, Z15
+	VPRORD $8, Z12, Z12
+	VPXORD Z2, Z13, Z13
+	VPXORD Z3, Z14, Z14
+	VPADDD Z15, Z10, Z10
+	VPADDD Z12, Z11, Z11
+	VPRORD $8, Z13, Z13
+	VPRORD $8, Z14, Z14
+	VPXORD Z10, Z5, Z5
+	VPXORD Z11, Z6, Z6
+	VPADDD Z13, Z8, Z8
+	VPADDD Z14, Z9, Z9
+	VPRORD $7, Z5, Z5
+	VPRORD $7, Z6, Z6
+	VPXORD Z8, Z7, Z7
+	VPXORD Z9, Z4, Z4
+	VPRORD $7, Z7, Z7
+	VPRORD $7, Z4, Z4
+	VPADDD Z4, Z0, Z0
+	VPADDD Z5, Z1, Z1
+	VPADDD Z6, Z2, Z2
+	VPADDD Z7, Z3, Z3
+	VPXORD Z0, Z12, Z12
+	VPADDD Z21, Z1, Z1
+	VPADDD Z17, Z2, Z2
+	VPADDD Z24, Z3, Z3
+	VPRORD $16, Z12, Z12
+	VPXORD Z1, Z13, Z13
+	VPXORD Z2, Z14, Z14
+	VPXORD Z3, Z15, Z15
+	VPADDD Z12, Z8, Z8
+	VPRORD $16, Z13, Z13
+	VPRORD $16, Z14, Z14
+	VPRORD $16, Z15, Z15
+	VPXORD Z8, Z4, Z4
+	VPADDD Z13, Z9, Z9
+	VPADDD Z14, Z10, Z10
+	VPADDD Z15, Z11, Z11
+	VPRORD $12, Z4, Z4
+	VPXORD Z9, Z5, Z5
+	VPXORD Z10, Z6, Z6
+	VPXORD Z11, Z7, Z7
+	VPADDD Z4, Z0, Z0
+	VPRORD $12, Z5, Z5
+	VPRORD $12, Z6, Z6
+	VPRORD $12, Z7, Z7
+	VPXORD Z0, Z12, Z12
+	VPADDD Z5, Z1, Z1
+	VPADDD Z6, Z2, Z2
+	VPADDD Z7, Z3, Z3
+	VPRORD $8, Z12, Z12
+	VPADDD Z16, Z1, Z1
+	VPADDD Z25, Z2, Z2
+	VPADDD Z22, Z3, Z3
+	VPADDD Z12, Z8, Z8
+	VPXORD Z1, Z13, Z13
+	VPXORD Z2, Z14, Z14
+	VPXORD Z3, Z15, Z15
+	VPXORD Z8, Z4, Z4
+	VPRORD $8, Z13, Z13
+	VPRORD $8, Z14, Z14
+	VPRORD $8, Z15, Z15
+	VPRORD $7, Z4, Z4
+	VPADDD Z13, Z9, Z9
+	VPADDD Z14, Z10, Z10
+	VPADDD Z15, Z11, Z11
+	VPXORD Z9, Z5, Z5
+	VPXORD Z10, Z6, Z6
+	VPXORD Z11, Z7, Z7
+	VPRORD $7, Z5, Z5
+	VPRORD $7, Z6, Z6
+	VPRORD $7, Z7, Z7
+	VPADDD Z5, Z0, Z0
+	VPADDD Z6, Z1, Z1
+	VPADDD Z7, Z2, Z2
+	VPADDD Z4, Z3, Z3
+	VPXORD Z0, Z15, Z15
+	VPADDD Z18, Z1, Z1
+	VPADDD Z19, Z2, Z2
+	VPADDD Z23, Z3, Z3
+	VPRORD $16, Z15, Z15
+	VPXORD Z1, Z12, Z12
+	VPXORD Z2, Z13, Z13
+	VPXORD Z3, Z14, Z14
+	VPADDD Z15, Z10, Z10
+	VPRORD $16, Z12, Z12
+	VPRORD $16, Z13, Z13
+	VPRORD $16, Z14, Z14
+	VPXORD Z10, Z5, Z5
+	VPADDD Z12, Z11, Z11
+	VPADDD Z13, Z8, Z8
+	VPADDD Z14, Z9, Z9
+	VPRORD $12, Z5, Z5
+	VPXORD Z11, Z6, Z6
+	VPXORD Z8, Z7, Z7
+	VPXORD Z9, Z4, Z4
+	VPADDD Z5, Z0, Z0
+	VPRORD $12, Z6, Z6
+	VPRORD $12, Z7, Z7
+	VPRORD $12, Z4, Z4
+	VPXORD Z0, Z15, Z15
+	VPADDD Z6, Z1, Z1
+	VPADDD Z7, Z2, Z2
+	VPADDD Z4, Z3, Z3
+	VPRORD $8, Z15, Z15
+	VPXORD Z1, Z12, Z12
+	VPADDD Z20, Z2, Z2
+	VPXORD Z3, Z14, Z14
+	VPADDD Z15, Z10, Z10
+	VPRORD $8, Z12, Z12
+	VPXORD Z2, Z13, Z13
+	VPRORD $8, Z14, Z14
+	VPXORD Z10, Z5, Z5
+	VPADDD Z12, Z11, Z11
+	VPRORD $8, Z13, Z13
+	VPADDD Z14, Z9, Z9
+	VPRORD $7, Z5, Z5
+	VPXORD Z11, Z6, Z6
+	VPADDD Z13, Z8, Z8
+	VPXORD Z9, Z4, Z4
+	VPRORD $7, Z6, Z6
+	VPXORD Z8, Z7, Z7
+	VPRORD $7, Z4, Z4
+	VPRORD $7, Z7, Z7
+	VPXORD Z8, Z0, Z16
+	VPXORD Z9, Z1, Z17
+	VPXORD Z10, Z2, Z18
+	VPXORD Z11, Z3, Z19
+	VPXORD Z12, Z4, Z20
+	VPXORD Z13, Z5, Z21
+	VPXORD Z14, Z6, Z22
+	VPXORD Z15, Z7, Z23
+	TESTQ CX, CX
+	JZ   done
+loop:
+	VPBROADCASTD ·blake3IV<>+0x00(SB), Z0
+	VPBROADCASTD ·blake3IV<>+0x04(SB), Z1
+	VPBROADCASTD ·blake3IV<>+0x08(SB), Z2
+	VPBROADCASTD ·blake3IV<>+0x0c(SB), Z3
+	VPBROADCASTD ·blake3IV<>+0x10(SB), Z4
+	VPBROADCASTD ·blake3IV<>+0x14(SB), Z5
+	VPBROADCASTD ·blake3IV<>+0x18(SB), Z6
+	VPBROADCASTD ·blake3IV<>+0x1c(SB), Z7
+	VMOVDQA32 Z0, Z8
+	VMOVDQA32 Z1, Z9
+	VMOVDQA32 Z2, Z10
+	VMOVDQA32 Z3, Z11
+	VPXORD Z12, Z12, Z12
+	VPXORD Z13, Z13, Z13
+	VPBROADCASTD ·blake3Params<>+0x04(SB), Z14
+	VPBROADCASTD ·blake3Params<>+0x08(SB), Z15
+	VPADDD Z4, Z0, Z0
+	VPADDD Z5, Z1, Z1
+	VPADDD Z6, Z2, Z2
+	VPADDD Z7, Z3, Z3
+	VPADDD Z16, Z0, Z0
+	VPADDD Z18, Z1, Z1
+	VPADDD Z20, Z2, Z2
+	VPADDD Z22, Z3, Z3
+	VPXORD Z0, Z12, Z12
+	VPXORD Z1, Z13, Z13
+	VPXORD Z2, Z14, Z14
+	VPXORD Z3, Z15, Z15
+	VPRORD $16, Z12, Z12
+	VPRORD $16, Z13, Z13
+	VPRORD $16, Z14, Z14
+	VPRORD $16, Z15, Z15
+	VPADDD Z12, Z8, Z8
+	VPADDD Z13, Z9, Z9
+	VPADDD Z14, Z10, Z10
+	VPADDD Z15, Z11, Z11
+	VPXORD Z8, Z4, Z4
+	VPXORD Z9, Z5, Z5
+	VPXORD Z10, Z6, Z6
+	VPXORD Z11, Z7, Z7
+	VPRORD $12, Z4, Z4
+	VPRORD $12, Z5, Z5
+	VPRORD $12, Z6, Z6
+	VPRORD $12, Z7, Z7
+	VPADDD Z4, Z0, Z0
+	VPADDD Z5, Z1, Z1
+	VPADDD Z6, Z2, Z2
+	VPADDD Z7, Z3, Z3
+	VPADDD Z17, Z0, Z0
+	VPADDD Z19, Z1, Z1
+	VPADDD Z21, Z2, Z2
+	VPADDD Z23, Z3, Z3
+	VPXORD Z0, Z12, Z12
+	VPXORD Z1, Z13, Z13
+	VPXORD Z2, Z14, Z14
+	VPXORD Z3, Z15, Z15
+	VPRORD $8, Z12, Z12
+	VPRORD $8, Z13, Z13
+	VPRORD $8, Z14, Z14
+	VPRORD $8, Z15, Z15
+	VPADDD Z12, Z8, Z8
+	VPADDD Z13, Z9, Z9
+	VPADDD Z14, Z10, Z10
+	VPADDD Z15, Z11, Z11
+	VPXORD Z8, Z4, Z4
+	VPXORD Z9, Z5, Z5
+	VPXORD Z10, Z6, Z6
+	VPXORD Z11, Z7, Z7
+	VPRORD $7, Z4, Z4
+	VPRORD $7, Z5, Z5
+	VPRORD $7, Z6, Z6
+	VPRORD $7, Z7, Z7
+	VPADDD Z5, Z0, Z0
+	VPADDD Z6, Z1, Z1
+	VPADDD Z7, Z2, Z2
+	VPADDD Z4, Z3, Z3
+	VPXORD Z0, Z15, Z15
+	VPXORD Z1, Z12, Z12
+	VPXORD Z2, Z13, Z13
+	VPXORD Z3, Z14, Z14
+	VPRORD $16, Z15, Z15
+	VPRORD $16, Z12, Z12
+	VPRORD $16, Z13, Z13
+	VPRORD $16, Z14, Z14
+	VPADDD Z15, Z10, Z10
+	VPADDD Z12, Z11, Z11
+	VPADDD Z13, Z8, Z8
+	VPADDD Z14, Z9, Z9
+	VPXORD Z10, Z5, Z5
+	VPXORD Z11, Z6, Z6
+	VPXORD Z8, Z7, Z7
+	VPXORD Z9, Z4, Z4
+	VPRORD $12, Z5, Z5
+	VPRORD $12, Z6, Z6
+	VPRORD $12, Z7, Z7
+	VPRORD $12, Z4, Z4
+	VPADDD Z5, Z0, Z0
+	VPADDD Z6, Z1, Z1
+	VPADDD Z7, Z2, Z2
+	VPADDD Z4, Z3, Z3
+	VPXORD Z0, Z15, Z15
+	VPXORD Z1, Z12, Z12
+	VPXORD Z2, Z13, Z13
+	VPXORD Z3, Z14, Z14
+	VPRORD $8, Z15, Z15
+	VPRORD $8, Z12, Z12
+	VPRORD $8, Z13, Z13
+	VPRORD $8, Z14, Z14
+	VPADDD Z15, Z10, Z10
+	VPADDD Z12, Z11, Z11
+	VPADDD Z13, Z8, Z8
+	VPADDD Z14, Z9, Z9
+	VPXORD Z10, Z5, Z5
+	VPXORD Z11, Z6, Z6
+	VPXORD Z8, Z7, Z7
+	VPXORD Z9, Z4, Z4
+	VPRORD $7, Z5, Z5
+	VPRORD $7, Z6, Z6
+	VPRORD $7, Z7, Z7
+	VPRORD $7, Z4, Z4
+	VPADDD Z4, Z0, Z0
+	VPADDD Z5, Z1, Z1
+	VPADDD Z6, Z2, Z2
+	VPADDD Z7, Z3, Z3
+	VPADDD Z18, Z0, Z0
+	VPADDD Z19, Z1, Z1
+	VPADDD Z23, Z2, Z2
+	VPADDD Z20, Z3, Z3
+	VPXORD Z0, Z12, Z12
+	VPXORD Z1, Z13, Z13
+	VPXORD Z2, Z14, Z14
+	VPXORD Z3, Z15, Z15
+	VPRORD $16, Z12, Z12
+	VPRORD $16, Z13, Z13
+	VPRORD $16, Z14, Z14
+	VPRORD $16, Z15, Z15
+	VPADDD Z12, Z8, Z8
+	VPADDD Z13, Z9, Z9
+	VPADDD Z14, Z10, Z10
+	VPADDD Z15, Z11, Z11
+	VPXORD Z8, Z4, Z4
+	VPXORD Z9, Z5, Z5
+	VPXORD Z10, Z6, Z6
+	VPXORD Z11, Z7, Z7
+	VPRORD $12, Z4, Z4
+	VPRORD $12, Z5, Z5
+	VPRORD $12, Z6, Z6
+	VPRORD $12, Z7, Z7
+	VPADDD Z4, Z0, Z0
+	VPADDD Z5, Z1, Z1
+	VPADDD Z6, Z2, Z2
+	VPADDD Z7, Z3, Z3
+	VPADDD Z22, Z0, Z0
+	VPXORD Z1, Z13, Z13
+	VPADDD Z16, Z2, Z2
+	VPXORD Z3, Z15, Z15
+	VPXORD Z0, Z12, Z12
+	VPRORD $8, Z13, Z13
+	VPXORD Z2, Z14, Z14
+	VPRORD $8, Z15, Z15
+	VPRORD $8, Z12, Z12
+	VPADDD Z13, Z9, Z9
+	VPRORD $8, Z14, Z14
+	VPADDD Z15, Z11, Z11
+	VPADDD Z12, Z8, Z8
+	VPXORD Z9, Z5, Z5
+	VPADDD Z14, Z10, Z10
+	VPXORD Z11, Z7, Z7
+	VPXORD Z8, Z4, Z4
+	VPRORD $7, Z5, Z5
+	VPXORD Z10, Z6, Z6
+	VPRORD $7, Z7, Z7
+	VPRORD $7, Z4, Z4
+	VPRORD $7, Z6, Z6
+	VPADDD Z5, Z0, Z0
+	VPADDD Z6, Z1, Z1
+	VPADDD Z7, Z2, Z2
+	VPADDD Z4, Z3, Z3
+	VPADDD Z17, Z0, Z0
+	VPXORD Z1, Z12, Z12
+	VPXORD Z2, Z13, Z13
+	VPXORD Z3, Z14, Z14
+	VPXORD Z0, Z15, Z15
+	VPRORD $16, Z12, Z12
+	VPRORD $16, Z13, Z13
+	VPRORD $16, Z14, Z14
+	VPRORD $16, Z15, Z15
+	VPADDD Z12, Z11, Z11
+	VPADDD Z13, Z8, Z8
+	VPADDD Z14, Z9, Z9
+	VPADDD Z15, Z10, Z10
+	VPXORD Z11, Z6, Z6
+	VPXORD Z8, Z7, Z7
+	VPXORD Z9, Z4, Z4
+	VPXORD Z10, Z5, Z5
+	VPRORD $12, Z6, Z6
+	VPRORD $12, Z7, Z7
+	VPRORD $12, Z4, Z4
+	VPRORD $12, Z5, Z5
+	VPADDD Z6, Z1, Z1
+	VPADDD Z7, Z2, Z2
+	VPADDD Z4, Z3, Z3
+	VPADDD Z5, Z0, Z0
+	VPADDD Z21, Z1, Z1
+	VPXORD Z2, Z13, Z13
+	VPXORD Z3, Z14, Z14
+	VPXORD Z0, Z15, Z15
+	VPXORD Z1, Z12, Z12
+	VPRORD $8, Z13, Z13
+	VPRORD $8, Z14, Z14
+	VPRORD $8, Z15, Z15
+	VPRORD $8, Z12, Z12
+	VPADDD Z13, Z8, Z8
+	VPADDD Z14, Z9, Z9
+	VPADDD Z15, Z10, Z10
+	VPADDD Z12, Z11, Z11
+	VPXORD Z8, Z7, Z7
+	VPXORD Z9, Z4, Z4
+	VPXORD Z10, Z5, Z5
+	VPXORD Z11, Z6, Z6
+	VPRORD $7, Z7, Z7
+	VPRORD $7, Z4, Z4
+	VPRORD $7, Z5, Z5
+	VPRORD $7, Z6, Z6
+	VPADDD Z4, Z0, Z0
+	VPADDD Z5, Z1, Z1
+	VPADDD Z6, Z2, Z2
+	VPADDD Z7, Z3, Z3
+	VPADDD Z19, Z0, Z0
+	VPXORD Z1, Z13, Z13
+	VPXORD Z2, Z14, Z14
+	VPADDD Z23, Z3, Z3
+	VPXORD Z0, Z12, Z12
+	VPRORD $16, Z13, Z13
+	VPRORD $16, Z14, Z14
+	VPXORD Z3, Z15, Z15
+	VPRORD $16, Z12, Z12
+	VPADDD Z13, Z9, Z9
+	VPADDD Z14, Z10, Z10
+	VPRORD $16, Z15, Z15
+	VPADDD Z12, Z8, Z8
+	VPXORD Z9, Z5, Z5
+	VPXORD Z10, Z6, Z6
+	VPADDD Z15, Z11, Z11
+	VPXORD Z8, Z4, Z4
+	VPRORD $12, Z5, Z5
+	VPRORD $12, Z6, Z6
+	VPXORD Z11, Z7, Z7
+	VPRORD $12, Z4, Z4
+	VPADDD Z5, Z1, Z1
+	VPADDD Z6, Z2, Z2
+	VPRORD $12, Z7, Z7
+	VPADDD Z4, Z0, Z0
+	VPXORD Z1, Z13, Z13
+	VPADDD Z18, Z2, Z2
+	VPADDD Z7, Z3, Z3
+	VPADDD Z20, Z0, Z0
+	VPRORD $8, Z13, Z13
+	VPXORD Z2, Z14, Z14
+	VPXORD Z3, Z15, Z15
+	VPXORD Z0, Z12, Z12
+	VPADDD Z13, Z9, Z9
+	VPRORD $8, Z14, Z14
+	VPRORD $8, Z15, Z15
+	VPRORD $8, Z12, Z12
+	VPXORD Z9, Z5, Z5
+	VPADDD Z14, Z10, Z10
+	VPADDD Z15, Z11, Z11
+	VPADDD Z12, Z8, Z8
+	VPRORD $7, Z5, Z5
+	VPXORD Z10, Z6, Z6
+	VPXORD Z11, Z7, Z7
+	VPXORD Z8, Z4, Z4
+	VPRORD $7, Z6, Z6
+	VPRORD $7, Z7, Z7
+	VPRORD $7, Z4, Z4
+	VPADDD Z5, Z0, Z0
+	VPADDD Z6, Z1, Z1
+	VPADDD Z7, Z2, Z2
+	VPADDD Z4, Z3, Z3
+	VPADDD Z22, Z0, Z0
+	VPXORD Z1, Z12, Z12
+	VPXORD Z2, Z13, Z13
+	VPXORD Z3, Z14, Z14
+	VPXORD Z0, Z15, Z15
+	VPRORD $16, Z12, Z12
+	VPRORD $16, Z13, Z13
+	VPRORD $16, Z14, Z14
+	VPRORD $16, Z15, Z15
+	VPADDD Z12, Z11, Z11
+	VPADDD Z13, Z8, Z8
+	VPADDD Z14, Z9, Z9
+	VPADDD Z15, Z10, Z10
+	VPXORD Z11, Z6, Z6
+	VPXORD Z8, Z7, Z7
+	VPXORD Z9, Z4, Z4
+	VPXORD Z10, Z5, Z5
+	VPRORD $12, Z6, Z6
+	VPRORD $12, Z7, Z7
+	VPRORD $12, Z4, Z4
+	VPRORD $12, Z5, Z5
+	VPADDD Z6, Z1, Z1
+	VPADDD Z7, Z2, Z2
+	VPADDD Z4, Z3, Z3
+	VPADDD Z5, Z0, Z0
+	VPADDD Z16, Z1, Z1
+	VPXORD Z2, Z13, Z13
+	VPADDD Z17, Z3, Z3
+	VPADDD Z21, Z0, Z0
+	VPXORD Z1, Z12, Z12
+	VPRORD $8, Z13, Z13
+	VPXORD Z3, Z14, Z14
+	VPXORD Z0, Z15, Z15
+	VPRORD $8, Z12, Z12
+	VPADDD Z13, Z8, Z8
+	VPRORD $8, Z14, Z14
+	VPRORD $8, Z15, Z15
+	VPADDD Z12, Z11, Z11
+	VPXORD Z8, Z7, Z7
+	VPADDD Z14, Z9, Z9
+	VPADDD Z15, Z10, Z10
+	VPXORD Z11, Z6, Z6
+	VPRORD $7, Z7, Z7
+	VPXORD Z9, Z4, Z4
+	VPXORD Z10, Z5, Z5
+	VPRORD $7, Z6, Z6
+	VPRORD $7, Z4, Z4
+	VPRORD $7, Z5, Z5
+	VPADDD Z4, Z0, Z0
+	VPADDD Z5, Z1, Z1
+	VPADDD Z6, Z2, Z2
+	VPADDD Z7, Z3, Z3
+	VPXORD Z0, Z12, Z12
+	VPXORD Z1, Z13, Z13
+	VPXORD Z2, Z14, Z14
+	VPXORD Z3, Z15, Z15
+	VPRORD $16, Z12, Z12
+	VPRORD $16, Z13, Z13
+	VPRORD $16, Z14, Z14
+	VPRORD $16, Z15, Z15
+	VPADDD Z12, Z8, Z8
+	VPADDD Z13, Z9, Z9
+	VPADDD Z14, Z10, Z10
+	VPADDD Z15, Z11, Z11
+	VPXORD Z8, Z4, Z4
+	VPXORD Z9, Z5, Z5
+	VPXORD Z10, Z6, Z6
+	VPXORD Z11, Z7, Z7
+	VPRORD $12, Z4, Z4
+	VPRORD $12, Z5, Z5
+	VPRORD $12, Z6, Z6
+	VPRORD $12, Z7, Z7
+	VPADDD Z4, Z0, Z0
+	VPADDD Z5, Z1, Z1
+	VPADDD Z6, Z2, Z2
+	VPADDD Z7, Z3, Z3
+	VPADDD Z23, Z0, Z0
+	VPXORD Z1, Z13, Z13
+	VPADDD Z19, Z2, Z2
+	VPXORD Z3, Z15, Z15
+	VPXORD Z0, Z12, Z12
+	VPRORD $8, Z13, Z13
+	VPXORD Z2, Z14, Z14
+	VPRORD $8, Z15, Z15
+	VPRORD $8, Z12, Z12
+	VPADDD Z13, Z9, Z9
+	VPRORD $8, Z14, Z14
+	VPADDD Z15, Z11, Z11
+	VPADDD Z12, Z8, Z8
+	VPXORD Z9, Z5, Z5
+	VPADDD Z14, Z10, Z10
+	VPXORD Z11, Z7, Z7
+	VPXORD Z8, Z4, Z4
+	VPRORD $7, Z5, Z5
+	VPXORD Z10, Z6, Z6
+	VPRORD $7, Z7, Z7
+	VPRORD $7, Z4, Z4
+	VPRORD $7, Z6, Z6
+	VPADDD Z5, Z0, Z0
+	VPADDD Z6, Z1, Z1
+	VPADDD Z7, Z2, Z2
+	VPADDD Z4, Z3, Z3
+	VPADDD Z20, Z0, Z0
+	VPXORD Z1, Z12, Z12
+	VPADDD Z21, Z2, Z2
+	VPADDD Z17, Z3, Z3
+	VPXORD Z0, Z15, Z15
+	VPRORD $16, Z12, Z12
+	VPXORD Z2, Z13, Z13
+	VPXORD Z3, Z14, Z14
+	VPRORD $16, Z15, Z15
+	VPADDD Z12, Z11, Z11
+	VPRORD $16, Z13, Z13
+	VPRORD $16, Z14, Z14
+	VPADDD Z15, Z10, Z10
+	VPXORD Z11, Z6, Z6
+	VPADDD Z13, Z8, Z8
+	VPADDD Z14, Z9, Z9
+	VPXORD Z10, Z5, Z5
+	VPRORD $12, Z6, Z6
+	VPXORD Z8, Z7, Z7
+	VPXORD Z9, Z4, Z4
+	VPRORD $12, Z5, Z5
+	VPADDD Z6, Z1, Z1
+	VPRORD $12, Z7, Z7
+	VPRORD $12, Z4, Z4
+	VPADDD Z5, Z0, Z0
+	VPADDD Z18, Z1, Z1
+	VPADDD Z7, Z2, Z2
+	VPADDD Z4, Z3, Z3
+	VPADDD Z16, Z0, Z0
+	VPXORD Z1, Z12, Z12
+	VPXORD Z2, Z13, Z13
+	VPADDD Z22, Z3, Z3
+	VPXORD Z0, Z15, Z15
+	VPRORD $8, Z12, Z12
+	VPRORD $8, Z13, Z13
+	VPXORD Z3, Z14, Z14
+	VPRORD $8, Z15, Z15
+	VPADDD Z12, Z11, Z11
+	VPADDD Z13, Z8, Z8
+	VPRORD $8, Z14, Z14
+	VPADDD Z15, Z10, Z10
+	VPXORD Z11, Z6, Z6
+	VPXORD Z8, Z7, Z7
+	VPADDD Z14, Z9, Z9
+	VPXORD Z10, Z5, Z5
+	VPRORD $7, Z6, Z6
+	VPRORD $7, Z7, Z7
+	VPXORD Z9, Z4, Z4
+	VPRORD $7, Z5, Z5
+	VPRORD $7, Z4, Z4
+	VPADDD Z4, Z0, Z0
+	VPADDD Z5, Z1, Z1
+	VPADDD Z6, Z2, Z2
+	VPADDD Z7, Z3, Z3
+	VPXORD Z0, Z12, Z12
+	VPXORD Z1, Z13, Z13
+	VPXORD Z2, Z14, Z14
+	VPXORD Z3, Z15, Z15
+	VPRORD $16, Z12, Z12
+	VPRORD $16, Z13, Z13
+	VPRORD $16, Z14, Z14
+	VPRORD $16, Z15, Z15
+	VPADDD Z12, Z8, Z8
+	VPADDD Z13, Z9, Z9
+	VPADDD Z14, Z10, Z10
+	VPADDD Z15, Z11, Z11
+	VPXORD Z8, Z4, Z4
+	VPXORD Z9, Z5, Z5
+	VPXORD Z10, Z6, Z6
+	VPXORD Z11, Z7, Z7
+	VPRORD $12, Z4, Z4
+	VPRORD $12, Z5, Z5
+	VPRORD $12, Z6, Z6
+	VPRORD $12, Z7, Z7
+	VPADDD Z4, Z0, Z0
+	VPADDD Z5, Z1, Z1
+	VPADDD Z6, Z2, Z2
+	VPADDD Z7, Z3, Z3
+	VPXORD Z0, Z12, Z12
+	VPXORD Z1, Z13, Z13
+	VPXORD Z2, Z14, Z14
+	VPXORD Z3, Z15, Z15
+	VPRORD $8, Z12, Z12
+	VPRORD $8, Z13, Z13
+	VPRORD $8, Z14, Z14
+	VPRORD $8, Z15, Z15
+	VPADDD Z12, Z8, Z8
+	VPADDD Z13, Z9, Z9
+	VPADDD Z14, Z10, Z10
+	VPADDD Z15, Z11, Z11
+	VPXORD Z8, Z4, Z4
+	VPXORD Z9, Z5, Z5
+	VPXORD Z10, Z6, Z6
+	VPXORD Z11, Z7, Z7
+	VPRORD $7, Z4, Z4
+	VPRORD $7, Z5, Z5
+	VPRORD $7, Z6, Z6
+	VPRORD $7, Z7, Z7
+	VPADDD Z5, Z0, Z0
+	VPADDD Z6, Z1, Z1
+	VPADDD Z7, Z2, Z2
+	VPADDD Z4, Z3, Z3
+	VPADDD Z23, Z0, Z0
+	VPADDD Z21, Z1, Z1
+	VPADDD Z16, Z2, Z2
+	VPADDD Z22, Z3, Z3
+	VPXORD Z0, Z15, Z15
+	VPXORD Z1, Z12, Z12
+	VPXORD Z2, Z13, Z13
+	VPXORD Z3, Z14, Z14
+	VPRORD $16, Z15, Z15
+	VPRORD $16, Z12, Z12
+	VPRORD $16, Z13, Z13
+	VPRORD $16, Z14, Z14
+	VPADDD Z15, Z10, Z10
+	VPADDD Z12, Z11, Z11
+	VPADDD Z13, Z8, Z8
+	VPADDD Z14, Z9, Z9
+	VPXORD Z10, Z5, Z5
+	VPXORD Z11, Z6, Z6
+	VPXORD Z8, Z7, Z7
+	VPXORD Z9, Z4, Z4
+	VPRORD $12, Z5, Z5
+	VPRORD $12, Z6, Z6
+	VPRORD $12, Z7, Z7
+	VPRORD $12, Z4, Z4
+	VPADDD Z5, Z0, Z0
+	VPADDD Z6, Z1, Z1
+	VPADDD Z7, Z2, Z2
+	VPADDD Z4, Z3, Z3
+	VPADDD Z18, Z0, Z0
+	VPADDD Z19, Z1, Z1
+	VPADDD Z17, Z2, Z2
+	VPADDD Z20, Z3, Z3
+	VPXORD Z0, Z15, Z15
+	VPXORD Z1, Z12, Z12
+	VPXORD Z2, Z13, Z13
+	VPXORD Z3, Z14, Z14
+	VPRORD $8, Z15, Z15
+	VPRORD $8, Z12, Z12
+	VPRORD $8, Z13, Z13
+	VPRORD $8, Z14, Z14
+	VPADDD Z15, Z10, Z10
+	VPADDD Z12, Z11, Z11
+	VPADDD Z13, Z8, Z8
+	VPADDD Z14, Z9, Z9
+	VPXORD Z10, Z5, Z5
+	VPXORD Z11, Z6, Z6
+	VPXORD Z8, Z7, Z7
+	VPXORD Z9, Z4, Z4
+	VPRORD $7, Z5, Z5
+	VPRORD $7, Z6, Z6
+	VPRORD $7, Z7, Z7
+	VPRORD $7, Z4, Z4
+	VPADDD Z4, Z0, Z0
+	VPADDD Z5, Z1, Z1
+	VPADDD Z6, Z2, Z2
+	VPADDD Z7, Z3, Z3
+	VPXORD Z0, Z12, Z12
+	VPXORD Z1, Z13, Z13
+	VPXORD Z2, Z14, Z14
+	VPXORD Z3, Z15, Z15
+	VPRORD $16, Z12, Z12
+	VPRORD $16, Z13, Z13
+	VPRORD $16, Z14, Z14
+	VPRORD $16, Z15, Z15
+	VPADDD Z12, Z8, Z8
+	VPADDD Z13, Z9, Z9
+	VPADDD Z14, Z10, Z10
+	VPADDD Z15, Z11, Z11
+	VPXORD Z8, Z4, Z4
+	VPXORD Z9, Z5, Z5
+	VPXORD Z10, Z6, Z6
+	VPXORD Z11, Z7, Z7
+	VPRORD $12, Z4, Z4
+	VPRORD $12, Z5, Z5
+	VPRORD $12, Z6, Z6
+	VPRORD $12, Z7, Z7
+	VPADDD Z4, Z0, Z0
+	VPADDD Z5, Z1, Z1
+	VPADDD Z6, Z2, Z2
+	VPADDD Z7, Z3, Z3
+	VPXORD Z0, Z12, Z12
+	VPADDD Z21, Z1, Z1
+	VPXORD Z2, Z14, Z14
+	VPADDD Z17, Z3, Z3
+	VPRORD $8, Z12, Z12
+	VPXORD Z1, Z13, Z13
+	VPRORD $8, Z14, Z14
+	VPXORD Z3, Z15, Z15
+	VPADDD Z12, Z8, Z8
+	VPRORD $8, Z13, Z13
+	VPADDD Z14, Z10, Z10
+	VPRORD $8, Z15, Z15
+	VPXORD Z8, Z4, Z4
+	VPADDD Z13, Z9, Z9
+	VPXORD Z10, Z6, Z6
+	VPADDD Z15, Z11, Z11
+	VPRORD $7, Z4, Z4
+	VPXORD Z9, Z5, Z5
+	VPRORD $7, Z6, Z6
+	VPXORD Z11, Z7, Z7
+	VPRORD $7, Z5, Z5
+	VPRORD $7, Z7, Z7
+	VPADDD Z5, Z0, Z0
+	VPADDD Z6, Z1, Z1
+	VPADDD Z7, Z2, Z2
+	VPADDD Z4, Z3, Z3
+	VPXORD Z0, Z15, Z15
+	VPADDD Z16, Z1, Z1
+	VPADDD Z18, Z2, Z2
+	VPADDD Z20, Z3, Z3
+	VPRORD $16, Z15, Z15
+	VPXORD Z1, Z12, Z12
+	VPXORD Z2, Z13, Z13
+	VPXORD Z3, Z14, Z14
+	VPADDD Z15, Z10, Z10
+	VPRORD $16, Z12, Z12
+	VPRORD $16, Z13, Z13
+	VPRORD $16, Z14, Z14
+	VPXORD Z10, Z5, Z5
+	VPADDD Z12, Z11, Z11
+	VPADDD Z13, Z8, Z8
+	VPADDD Z14, Z9, Z9
+	VPRORD $12, Z5, Z5
+	VPXORD Z11, Z6, Z6
+	VPXORD Z8, Z7, Z7
+	VPXORD Z9, Z4, Z4
+	VPADDD Z5, Z0, Z0
+	VPRORD $12, Z6, Z6
+	VPRORD $12, Z7, Z7
+	VPRORD $12, Z4, Z4
+	VPADDD Z19, Z0, Z0
+	VPADDD Z6, Z1, Z1
+	VPADDD Z7, Z2, Z2
+	VPADDD Z4, Z3, Z3
+	VPXORD Z0, Z15, Z15
+	VPXORD Z1, Z12, Z12
+	VPADDD Z22, Z2, Z2
+	VPADDD Z23, Z3, Z3
+	VPRORD $8, Z15, Z15
+	VPRORD $8, Z12, Z12
+	VPXORD Z2, Z13, Z13
+	VPXORD Z3, Z14, Z14
+	VPADDD Z15, Z10, Z10
+	VPADDD Z12, Z11, Z11
+	VPRORD $8, Z13, Z13
+	VPRORD $8, Z14, Z14
+	VPXORD Z10, Z5, Z5
+	VPXORD Z11, Z6, Z6
+	VPADDD Z13, Z8, Z8
+	VPADDD Z14, Z9, Z9
+	VPRORD $7, Z5, Z5
+	VPRORD $7, Z6, Z6
+	VPXORD Z8, Z7, Z7
+	VPXORD Z9, Z4, Z4
+	VPRORD $7, Z7, Z7
+	VPRORD $7, Z4, Z4
+	VPADDD Z4, Z0, Z0
+	VPADDD Z5, Z1, Z1
+	VPADDD Z6, Z2, Z2
+	VPADDD Z7, Z3, Z3
+	VPXORD Z0, Z12, Z12
+	VPADDD Z21, Z1, Z1
+	VPADDD Z17, Z2, Z2
+	VPXORD Z3, Z15, Z15
+	VPRORD $16, Z12, Z12
+	VPXORD Z1, Z13, Z13
+	VPXORD Z2, Z14, Z14
+	VPRORD $16, Z15, Z15
+	VPADDD Z12, Z8, Z8
+	VPRORD $16, Z13, Z13
+	VPRORD $16, Z14, Z14
+	VPADDD Z15, Z11, Z11
+	VPXORD Z8, Z4, Z4
+	VPADDD Z13, Z9, Z9
+	VPADDD Z14, Z10, Z10
+	VPXORD Z11, Z7, Z7
+	VPRORD $12, Z4, Z4
+	VPXORD Z9, Z5, Z5
+	VPXORD Z10, Z6, Z6
+	VPRORD $12, Z7, Z7
+	VPADDD Z4, Z0, Z0
+	VPRORD $12, Z5, Z5
+	VPRORD $12, Z6, Z6
+	VPADDD Z7, Z3, Z3
+	VPXORD Z0, Z12, Z12
+	VPADDD Z5, Z1, Z1
+	VPADDD Z6, Z2, Z2
+	VPADDD Z22, Z3, Z3
+	VPRORD $8, Z12, Z12
+	VPADDD Z16, Z1, Z1
+	VPXORD Z2, Z14, Z14
+	VPXORD Z3, Z15, Z15
+	VPADDD Z12, Z8, Z8
+	VPXORD Z1, Z13, Z13
+	VPRORD $8, Z14, Z14
+	VPRORD $8, Z15, Z15
+	VPXORD Z8, Z4, Z4
+	VPRORD $8, Z13, Z13
+	VPADDD Z14, Z10, Z10
+	VPADDD Z15, Z11, Z11
+	VPRORD $7, Z4, Z4
+	VPADDD Z13, Z9, Z9
+	VPXORD Z10, Z6, Z6
+	VPXORD Z11, Z7, Z7
+	VPXORD Z9, Z5, Z5
+	VPRORD $7, Z6, Z6
+	VPRORD $7, Z7, Z7
+	VPRORD $7, Z5, Z5
+	VPADDD Z5, Z0, Z0
+	VPADDD Z6, Z1, Z1
+	VPADDD Z7, Z2, Z2
+	VPADDD Z4, Z3, Z3
+	VPXORD Z0, Z15, Z15
+	VPADDD Z18, Z1, Z1
+	VPADDD Z19, Z2, Z2
+	VPADDD Z23, Z3, Z3
+	VPRORD $16, Z15, Z15
+	VPXORD Z1, Z12, Z12
+	VPXORD Z2, Z13, Z13
+	VPXORD Z3, Z14, Z14
+	VPADDD Z15, Z10, Z10
+	VPRORD $16, Z12, Z12
+	VPRORD $16, Z13, Z13
+	VPRORD $16, Z14, Z14
+	VPXORD Z10, Z5, Z5
+	VPADDD Z12, Z11, Z11
+	VPADDD Z13, Z8, Z8
+	VPADDD Z14, Z9, Z9
+	VPRORD $12, Z5, Z5
+	VPXORD Z11, Z6, Z6
+	VPXORD Z8, Z7, Z7
+	VPXORD Z9, Z4, Z4
+	VPADDD Z5, Z0, Z0
+	VPRORD $12, Z6, Z6
+	VPRORD $12, Z7, Z7
+	VPRORD $12, Z4, Z4
+	VPXORD Z0, Z15, Z15
+	VPADDD Z6, Z1, Z1
+	VPADDD Z7, Z2, Z2
+	VPADDD Z4, Z3, Z3
+	VPRORD $8, Z15, Z15
+	VPXORD Z1, Z12, Z12
+	VPADDD Z20, Z2, Z2
+	VPXORD Z3, Z14, Z14
+	VPADDD Z15, Z10, Z10
+	VPRORD $8, Z12, Z12
+	VPXORD Z2, Z13, Z13
+	VPRORD $8, Z14, Z14
+	VPXORD Z10, Z5, Z5
+	VPADDD Z12, Z11, Z11
+	VPRORD $8, Z13, Z13
+	VPADDD Z14, Z9, Z9
+	VPRORD $7, Z5, Z5
+	VPXORD Z11, Z6, Z6
+	VPADDD Z13, Z8, Z8
+	VPXORD Z9, Z4, Z4
+	VPRORD $7, Z6, Z6
+	VPXORD Z8, Z7, Z7
+	VPRORD $7, Z4, Z4
+	VPRORD $7, Z7, Z7
+	VPXORD Z8, Z0, Z16
+	VPXORD Z9, Z1, Z17
+	VPXORD Z10, Z2, Z18
+	VPXORD Z11, Z3, Z19
+	VPXORD Z12, Z4, Z20
+	VPXORD Z13, Z5, Z21
+	VPXORD Z14, Z6, Z22
+	VPXORD Z15, Z7, Z23
+	DECQ CX
+	JNZ  loop
+done:
+	VMOVDQU32 Z16, 0(DX)
+	VMOVDQU32 Z17, 64(DX)
+	VMOVDQU32 Z18, 128(DX)
+	VMOVDQU32 Z19, 192(DX)
+	VMOVDQU32 Z20, 256(DX)
+	VMOVDQU32 Z21, 320(DX)
+	VMOVDQU32 Z22, 384(DX)
+	VMOVDQU32 Z23, 448(DX)
+	VZEROUPPER
+	RET
+
+// func blake3ChainAVX2(hash *blake3Words, nonces *[2][8]uint32, iters uint64, out *[8][8]uint32)
+// Requires: AVX2
+TEXT ·blake3ChainAVX2(SB), 0, $768-32
+	MOVQ hash+0(FP), AX
+	MOVQ nonces+8(FP), BX
+	MOVQ iters+16(FP), CX
+	MOVQ out+24(FP), DX
+	VPBROADCASTD 0(AX), Y0
+	VMOVDQU Y0, 512(SP)
+	VPBROADCASTD 4(AX), Y0
+	VMOVDQU Y0, 544(SP)
+	VPBROADCASTD 8(AX), Y0
+	VMOVDQU Y0, 576(SP)
+	VPBROADCASTD 12(AX), Y0
+	VMOVDQU Y0, 608(SP)
+	VPBROADCASTD 16(AX), Y0
+	VMOVDQU Y0, 640(SP)
+	VPBROADCASTD 20(AX), Y0
+	VMOVDQU Y0, 672(SP)
+	VPBROADCASTD 24(AX), Y0
+	VMOVDQU Y0, 704(SP)
+	VPBROADCASTD 28(AX), Y0
+	VMOVDQU Y0, 736(SP)
+	VPBROADCASTD ·blake3IV<>+0x00(SB), Y0
+	VPBROADCASTD ·blake3IV<>+0x04(SB), Y1
+	VPBROADCASTD ·blake3IV<>+0x08(SB), Y2
+	VPBROADCASTD ·blake3IV<>+0x0c(SB), Y3
+	VPBROADCASTD ·blake3IV<>+0x10(SB), Y4
+	VPBROADCASTD ·blake3IV<>+0x14(SB), Y5
+	VPBROADCASTD ·blake3IV<>+0x18(SB), Y6
+	VPBROADCASTD ·blake3IV<>+0x1c(SB), Y7
+	VMOVDQA Y0, Y8
+	VMOVDQA Y1, Y9
+	VMOVDQA Y2, Y10
+	VMOVDQA Y3, Y11
+	VPXOR Y12, Y12, Y12
+	VPXOR Y13, Y13, Y13
+	VPBROADCASTD ·blake3Params<>+0x00(SB), Y14
+	VPBROADCASTD ·blake3Params<>+0x08(SB), Y15
+	VMOVDQU Y3, 96(SP)
+	VPADDD Y4, Y0, Y0
+	VPADDD 512(SP), Y0, Y0
+	VPXOR Y0, Y12, Y12
+	VPSHUFB ·blake3Rot16<>(SB), Y12, Y12
+	VPADDD Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPSRLD $12, Y4, Y3
+	VPSLLD $20, Y4, Y4
+	VPOR Y3, Y4, Y4
+	VPADDD Y4, Y0, Y0
+	VPADDD 544(SP), Y0, Y0
+	VPXOR Y0, Y12, Y12
+	VPSHUFB ·blake3Rot8<>(SB), Y12, Y12
+	VPADDD Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPSRLD $7, Y4, Y3
+	VPSLLD $25, Y4, Y4
+	VPOR Y3, Y4, Y4
+	VPADDD Y5, Y1, Y1
+	VPADDD 576(SP), Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPSHUFB ·blake3Rot16<>(SB), Y13, Y13
+	VPADDD Y13, Y9, Y9
+	VPXOR Y9, Y5, Y5
+	VPSRLD $12, Y5, Y3
+	VPSLLD $20, Y5, Y5
+	VPOR Y3, Y5, Y5
+	VPADDD Y5, Y1, Y1
+	VPADDD 608(SP), Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPSHUFB ·blake3Rot8<>(SB), Y13, Y13
+	VPADDD Y13, Y9, Y9
+	VPXOR Y9, Y5, Y5
+	VPSRLD $7, Y5, Y3
+	VPSLLD $25, Y5, Y5
+	VPOR Y3, Y5, Y5
+	VPADDD Y6, Y2, Y2
+	VPADDD 640(SP), Y2, Y2
+	VPXOR Y2, Y14, Y14
+	VPSHUFB ·blake3Rot16<>(SB), Y14, Y14
+	VPADDD Y14, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPSRLD $12, Y6, Y3
+	VPSLLD $20, Y6, Y6
+	VPOR Y3, Y6, Y6
+	VPADDD Y6, Y2, Y2
+	VPADDD 672(SP), Y2, Y2
+	VPXOR Y2, Y14, Y14
+	VPSHUFB ·blake3Rot8<>(SB), Y14, Y14
+	VPADDD Y14, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPSRLD $7, Y6, Y3
+	VPSLLD $25, Y6, Y6
+	VPOR Y3, Y6, Y6
+	VMOVDQU Y4, 128(SP)
+	VMOVDQU 96(SP), Y3
+	VPADDD Y7, Y3, Y3
+	VPADDD 704(SP), Y3, Y3
+	VPXOR Y3, Y15, Y15
+	VPSHUFB ·blake3Rot16<>(SB), Y15, Y15
+	VPADDD Y15, Y11, Y11
+	VPXOR Y11, Y7, Y7
+	VPSRLD $12, Y7, Y4
+	VPSLLD $20, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y7, Y3, Y3
+	VPADDD 736(SP), Y3, Y3
+	VPXOR Y3, Y15, Y15
+	VPSHUFB ·blake3Rot8<>(SB), Y15, Y15
+	VPADDD Y15, Y11, Y11
+	VPXOR Y11, Y7, Y7
+	VPSRLD $7, Y7, Y4
+	VPSLLD $25, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y5, Y0, Y0
+	VPADDD (BX), Y0, Y0
+	VPXOR Y0, Y15, Y15
+	VPSHUFB ·blake3Rot16<>(SB), Y15, Y15
+	VPADDD Y15, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPSRLD $12, Y5, Y4
+	VPSLLD $20, Y5, Y5
+	VPOR Y4, Y5, Y5
+	VPADDD Y5, Y0, Y0
+	VPADDD 32(BX), Y0, Y0
+	VPXOR Y0, Y15, Y15
+	VPSHUFB ·blake3Rot8<>(SB), Y15, Y15
+	VPADDD Y15, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPSRLD $7, Y5, Y4
+	VPSLLD $25, Y5, Y5
+	VPOR Y4, Y5, Y5
+	VPADDD Y6, Y1, Y1
+	VPXOR Y1, Y12, Y12
+	VPSHUFB ·blake3Rot16<>(SB), Y12, Y12
+	VPADDD Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPSRLD $12, Y6, Y4
+	VPSLLD $20, Y6, Y6
+	VPOR Y4, Y6, Y6
+	VPADDD Y6, Y1, Y1
+	VPXOR Y1, Y12, Y12
+	VPSHUFB ·blake3Rot8<>(SB), Y12, Y12
+	VPADDD Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPSRLD $7, Y6, Y4
+	VPSLLD $25, Y6, Y6
+	VPOR Y4, Y6, Y6
+	VPADDD Y7, Y2, Y2
+	VPXOR Y2, Y13, Y13
+	VPSHUFB ·blake3Rot16<>(SB), Y13, Y13
+	VPADDD Y13, Y8, Y8
+	VPXOR Y8, Y7, Y7
+	VPSRLD $12, Y7, Y4
+	VPSLLD $20, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y7, Y2, Y2
+	VPXOR Y2, Y13, Y13
+	VPSHUFB ·blake3Rot8<>(SB), Y13, Y13
+	VPADDD Y13, Y8, Y8
+	VPXOR Y8, Y7, Y7
+	VPSRLD $7, Y7, Y4
+	VPSLLD $25, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VMOVDQU Y7, 224(SP)
+	VMOVDQU 128(SP), Y4
+	VPADDD Y4, Y3, Y3
+	VPXOR Y3, Y14, Y14
+	VPSHUFB ·blake3Rot16<>(SB), Y14, Y14
+	VPADDD Y14, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPSRLD $12, Y4, Y7
+	VPSLLD $20, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y3, Y3
+	VPXOR Y3, Y14, Y14
+	VPSHUFB ·blake3Rot8<>(SB), Y14, Y14
+	VPADDD Y14, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPSRLD $7, Y4, Y7
+	VPSLLD $25, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y0, Y0
+	VPADDD 576(SP), Y0, Y0
+	VPXOR Y0, Y12, Y12
+	VPSHUFB ·blake3Rot16<>(SB), Y12, Y12
+	VPADDD Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPSRLD $12, Y4, Y7
+	VPSLLD $20, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y0, Y0
+	VPADDD 704(SP), Y0, Y0
+	VPXOR Y0, Y12, Y12
+	VPSHUFB ·blake3Rot8<>(SB), Y12, Y12
+	VPADDD Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPSRLD $7, Y4, Y7
+	VPSLLD $25, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y5, Y1, Y1
+	VPADDD 608(SP), Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPSHUFB ·blake3Rot16<>(SB), Y13, Y13
+	VPADDD Y13, Y9, Y9
+	VPXOR Y9, Y5, Y5
+	VPSRLD $12, Y5, Y7
+	VPSLLD $20, Y5, Y5
+	VPOR Y7, Y5, Y5
+	VPADDD Y5, Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPSHUFB ·blake3Rot8<>(SB), Y13, Y13
+	VPADDD Y13, Y9, Y9
+	VPXOR Y9, Y5, Y5
+	VPSRLD $7, Y5, Y7
+	VPSLLD $25, Y5, Y5
+	VPOR Y7, Y5, Y5
+	VPADDD Y6, Y2, Y2
+	VPADDD 736(SP), Y2, Y2
+	VPXOR Y2, Y14, Y14
+	VPSHUFB ·blake3Rot16<>(SB), Y14, Y14
+	VPADDD Y14, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPSRLD $12, Y6, Y7
+	VPSLLD $20, Y6, Y6
+	VPOR Y7, Y6, Y6
+	VPADDD Y6, Y2, Y2
+	VPADDD 512(SP), Y2, Y2
+	VPXOR Y2, Y14, Y14
+	VPSHUFB ·blake3Rot8<>(SB), Y14, Y14
+	VPADDD Y14, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPSRLD $7, Y6, Y7
+	VPSLLD $25, Y6, Y6
+	VPOR Y7, Y6, Y6
+	VMOVDQU Y4, 128(SP)
+	VMOVDQU 224(SP), Y7
+	VPADDD Y7, Y3, Y3
+	VPADDD 640(SP), Y3, Y3
+	VPXOR Y3, Y15, Y15
+	VPSHUFB ·blake3Rot16<>(SB), Y15, Y15
+	VPADDD Y15, Y11, Y11
+	VPXOR Y11, Y7, Y7
+	VPSRLD $12, Y7, Y4
+	VPSLLD $20, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y7, Y3, Y3
+	VPXOR Y3, Y15, Y15
+	VPSHUFB ·blake3Rot8<>(SB), Y15, Y15
+	VPADDD Y15, Y11, Y11
+	VPXOR Y11, Y7, Y7
+	VPSRLD $7, Y7, Y4
+	VPSLLD $25, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y5, Y0, Y0
+	VPADDD 544(SP), Y0, Y0
+	VPXOR Y0, Y15, Y15
+	VPSHUFB ·blake3Rot16<>(SB), Y15, Y15
+	VPADDD Y15, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPSRLD $12, Y5, Y4
+	VPSLLD $20, Y5, Y5
+	VPOR Y4, Y5, Y5
+	VPADDD Y5, Y0, Y0
+	VPXOR Y0, Y15, Y15
+	VPSHUFB ·blake3Rot8<>(SB), Y15, Y15
+	VPADDD Y15, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPSRLD $7, Y5, Y4
+	VPSLLD $25, Y5, Y5
+	VPOR Y4, Y5, Y5
+	VPADDD Y6, Y1, Y1
+	VPXOR Y1, Y12, Y12
+	VPSHUFB ·blake3Rot16<>(SB), Y12, Y12
+	VPADDD Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPSRLD $12, Y6, Y4
+	VPSLLD $20, Y6, Y6
+	VPOR Y4, Y6, Y6
+	VPADDD Y6, Y1, Y1
+	VPADDD 672(SP), Y1, Y1
+	VPXOR Y1, Y12, Y12
+	VPSHUFB ·blake3Rot8<>(SB), Y12, Y12
+	VPADDD Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPSRLD $7, Y6, Y4
+	VPSLLD $25, Y6, Y6
+	VPOR Y4, Y6, Y6
+	VPADDD Y7, Y2, Y2
+	VPADDD 32(BX), Y2, Y2
+	VPXOR Y2, Y13, Y13
+	VPSHUFB ·blake3Rot16<>(SB), Y13, Y13
+	VPADDD Y13, Y8, Y8
+	VPXOR Y8, Y7, Y7
+	VPSRLD $12, Y7, Y4
+	VPSLLD $20, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y7, Y2, Y2
+	VPXOR Y2, Y13, Y13
+	VPSHUFB ·blake3Rot8<>(SB), Y13, Y13
+	VPADDD Y13, Y8, Y8
+	VPXOR Y8, Y7, Y7
+	VPSRLD $7, Y7, Y4
+	VPSLLD $25, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VMOVDQU Y7, 224(SP)
+	VMOVDQU 128(SP), Y4
+	VPADDD Y4, Y3, Y3
+	VPXOR Y3, Y14, Y14
+	VPSHUFB ·blake3Rot16<>(SB), Y14, Y14
+	VPADDD Y14, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPSRLD $12, Y4, Y7
+	VPSLLD $20, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y3, Y3
+	VPADDD (BX), Y3, Y3
+	VPXOR Y3, Y14, Y14
+	VPSHUFB ·blake3Rot8<>(SB), Y14, Y14
+	VPADDD Y14, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPSRLD $7, Y4, Y7
+	VPSLLD $25, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y0, Y0
+	VPADDD 608(SP), Y0, Y0
+	VPXOR Y0, Y12, Y12
+	VPSHUFB ·blake3Rot16<>(SB), Y12, Y12
+	VPADDD Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPSRLD $12, Y4, Y7
+	VPSLLD $20, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y0, Y0
+	VPADDD 640(SP), Y0, Y0
+	VPXOR Y0, Y12, Y12
+	VPSHUFB ·blake3Rot8<>(SB), Y12, Y12
+	VPADDD Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPSRLD $7, Y4, Y7
+	VPSLLD $25, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y5, Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPSHUFB ·blake3Rot16<>(SB), Y13, Y13
+	VPADDD Y13, Y9, Y9
+	VPXOR Y9, Y5, Y5
+	VPSRLD $12, Y5, Y7
+	VPSLLD $20, Y5, Y5
+	VPOR Y7, Y5, Y5
+	VPADDD Y5, Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPSHUFB ·blake3Rot8<>(SB), Y13, Y13
+	VPADDD Y13, Y9, Y9
+	VPXOR Y9, Y5, Y5
+	VPSRLD $7, Y5, Y7
+	VPSLLD $25, Y5, Y5
+	VPOR Y7, Y5, Y5
+	VPADDD Y6, Y2, Y2
+	VPXOR Y2, Y14, Y14
+	VPSHUFB ·blake3Rot16<>(SB), Y14, Y14
+	VPADDD Y14, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPSRLD $12, Y6, Y7
+	VPSLLD $20, Y6, Y6
+	VPOR Y7, Y6, Y6
+	VPADDD Y6, Y2, Y2
+	VPADDD 576(SP), Y2, Y2
+	VPXOR Y2, Y14, Y14
+	VPSHUFB ·blake3Rot8<>(SB), Y14, Y14
+	VPADDD Y14, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPSRLD $7, Y6, Y7
+	VPSLLD $25, Y6, Y6
+	VPOR Y7, Y6, Y6
+	VMOVDQU Y4, 128(SP)
+	VMOVDQU 224(SP), Y7
+	VPADDD Y7, Y3, Y3
+	VPADDD 736(SP), Y3, Y3
+	VPXOR Y3, Y15, Y15
+	VPSHUFB ·blake3Rot16<>(SB), Y15, Y15
+	VPADDD Y15, Y11, Y11
+	VPXOR Y11, Y7, Y7
+	VPSRLD $12, Y7, Y4
+	VPSLLD $20, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y7, Y3, Y3
+	VPXOR Y3, Y15, Y15
+	VPSHUFB ·blake3Rot8<>(SB), Y15, Y15
+	VPADDD Y15, Y11, Y11
+	VPXOR Y11, Y7, Y7
+	VPSRLD $7, Y7, Y4
+	VPSLLD $25, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y5, Y0, Y0
+	VPADDD 704(SP), Y0, Y0
+	VPXOR Y0, Y15, Y15
+	VPSHUFB ·blake3Rot16<>(SB), Y15, Y15
+	VPADDD Y15, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPSRLD $12, Y5, Y4
+	VPSLLD $20, Y5, Y5
+	VPOR Y4, Y5, Y5
+	VPADDD Y5, Y0, Y0
+	VPADDD 672(SP), Y0, Y0
+	VPXOR Y0, Y15, Y15
+	VPSHUFB ·blake3Rot8<>(SB), Y15, Y15
+	VPADDD Y15, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPSRLD $7, Y5, Y4
+	VPSLLD $25, Y5, Y5
+	VPOR Y4, Y5, Y5
+	VPADDD Y6, Y1, Y1
+	VPADDD 32(BX), Y1, Y1
+	VPXOR Y1, Y12, Y12
+	VPSHUFB ·blake3Rot16<>(SB), Y12, Y12
+	VPADDD Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPSRLD $12, Y6, Y4
+	VPSLLD $20, Y6, Y6
+	VPOR Y4, Y6, Y6
+	VPADDD Y6, Y1, Y1
+	VPADDD 512(SP), Y1, Y1
+	VPXOR Y1, Y12, Y12
+	VPSHUFB ·blake3Rot8<>(SB), Y12, Y12
+	VPADDD Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPSRLD $7, Y6, Y4
+	VPSLLD $25, Y6, Y6
+	VPOR Y4, Y6, Y6
+	VPADDD Y7, Y2, Y2
+	VPXOR Y2, Y13, Y13
+	VPSHUFB ·blake3Rot16<>(SB), Y13, Y13
+	VPADDD Y13, Y8, Y8
+	VPXOR Y8, Y7, Y7
+	VPSRLD $12, Y7, Y4
+	VPSLLD $20, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y7, Y2, Y2
+	VPXOR Y2, Y13, Y13
+	VPSHUFB ·blake3Rot8<>(SB), Y13, Y13
+	VPADDD Y13, Y8, Y8
+	VPXOR Y8, Y7, Y7
+	VPSRLD $7, Y7, Y4
+	VPSLLD $25, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VMOVDQU Y7, 224(SP)
+	VMOVDQU 128(SP), Y4
+	VPADDD Y4, Y3, Y3
+	VPADDD (BX), Y3, Y3
+	VPXOR Y3, Y14, Y14
+	VPSHUFB ·blake3Rot16<>(SB), Y14, Y14
+	VPADDD Y14, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPSRLD $12, Y4, Y7
+	VPSLLD $20, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y3, Y3
+	VPADDD 544(SP), Y3, Y3
+	VPXOR Y3, Y14, Y14
+	VPSHUFB ·blake3Rot8<>(SB), Y14, Y14
+	VPADDD Y14, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPSRLD $7, Y4, Y7
+	VPSLLD $25, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y0, Y0
+	VPXOR Y0, Y12, Y12
+	VPSHUFB ·blake3Rot16<>(SB), Y12, Y12
+	VPADDD Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPSRLD $12, Y4, Y7
+	VPSLLD $20, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y0, Y0
+	VPADDD 736(SP), Y0, Y0
+	VPXOR Y0, Y12, Y12
+	VPSHUFB ·blake3Rot8<>(SB), Y12, Y12
+	VPADDD Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPSRLD $7, Y4, Y7
+	VPSLLD $25, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y5, Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPSHUFB ·blake3Rot16<>(SB), Y13, Y13
+	VPADDD Y13, Y9, Y9
+	VPXOR Y9, Y5, Y5
+	VPSRLD $12, Y5, Y7
+	VPSLLD $20, Y5, Y5
+	VPOR Y7, Y5, Y5
+	VPADDD Y5, Y1, Y1
+	VPADDD 32(BX), Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPSHUFB ·blake3Rot8<>(SB), Y13, Y13
+	VPADDD Y13, Y9, Y9
+	VPXOR Y9, Y5, Y5
+	VPSRLD $7, Y5, Y7
+	VPSLLD $25, Y5, Y5
+	VPOR Y7, Y5, Y5
+	VPADDD Y6, Y2, Y2
+	VPXOR Y2, Y14, Y14
+	VPSHUFB ·blake3Rot16<>(SB), Y14, Y14
+	VPADDD Y14, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPSRLD $12, Y6, Y7
+	VPSLLD $20, Y6, Y6
+	VPOR Y7, Y6, Y6
+	VPADDD Y6, Y2, Y2
+	VPADDD 608(SP), Y2, Y2
+	VPXOR Y2, Y14, Y14
+	VPSHUFB ·blake3Rot8<>(SB), Y14, Y14
+	VPADDD Y14, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPSRLD $7, Y6, Y7
+	VPSLLD $25, Y6, Y6
+	VPOR Y7, Y6, Y6
+	VMOVDQU Y4, 128(SP)
+	VMOVDQU 224(SP), Y7
+	VPADDD Y7, Y3, Y3
+	VPXOR Y3, Y15, Y15
+	VPSHUFB ·blake3Rot16<>(SB), Y15, Y15
+	VPADDD Y15, Y11, Y11
+	VPXOR Y11, Y7, Y7
+	VPSRLD $12, Y7, Y4
+	VPSLLD $20, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y7, Y3, Y3
+	VPXOR Y3, Y15, Y15
+	VPSHUFB ·blake3Rot8<>(SB), Y15, Y15
+	VPADDD Y15, Y11, Y11
+	VPXOR Y11, Y7, Y7
+	VPSRLD $7, Y7, Y4
+	VPSLLD $25, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y5, Y0, Y0
+	VPADDD 640(SP), Y0, Y0
+	VPXOR Y0, Y15, Y15
+	VPSHUFB ·blake3Rot16<>(SB), Y15, Y15
+	VPADDD Y15, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPSRLD $12, Y5, Y4
+	VPSLLD $20, Y5, Y5
+	VPOR Y4, Y5, Y5
+	VPADDD Y5, Y0, Y0
+	VPADDD 512(SP), Y0, Y0
+	VPXOR Y0, Y15, Y15
+	VPSHUFB ·blake3Rot8<>(SB), Y15, Y15
+	VPADDD Y15, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPSRLD $7, Y5, Y4
+	VPSLLD $25, Y5, Y5
+	VPOR Y4, Y5, Y5
+	VPADDD Y6, Y1, Y1
+	VPXOR Y1, Y12, Y12
+	VPSHUFB ·blake3Rot16<>(SB), Y12, Y12
+	VPADDD Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPSRLD $12, Y6, Y4
+	VPSLLD $20, Y6, Y6
+	VPOR Y4, Y6, Y6
+	VPADDD Y6, Y1, Y1
+	VPADDD 576(SP), Y1, Y1
+	VPXOR Y1, Y12, Y12
+	VPSHUFB ·blake3Rot8<>(SB), Y12, Y12
+	VPADDD Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPSRLD $7, Y6, Y4
+	VPSLLD $25, Y6, Y6
+	VPOR Y4, Y6, Y6
+	VPADDD Y7, Y2, Y2
+	VPADDD 672(SP), Y2, Y2
+	VPXOR Y2, Y13, Y13
+	VPSHUFB ·blake3Rot16<>(SB), Y13, Y13
+	VPADDD Y13, Y8, Y8
+	VPXOR Y8, Y7, Y7
+	VPSRLD $12, Y7, Y4
+	VPSLLD $20, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y7, Y2, Y2
+	VPADDD (BX), Y2, Y2
+	VPXOR Y2, Y13, Y13
+	VPSHUFB ·blake3Rot8<>(SB), Y13, Y13
+	VPADDD Y13, Y8, Y8
+	VPXOR Y8, Y7, Y7
+	VPSRLD $7, Y7, Y4
+	VPSLLD $25, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VMOVDQU Y7, 224(SP)
+	VMOVDQU 128(SP), Y4
+	VPADDD Y4, Y3, Y3
+	VPADDD 544(SP), Y3, Y3
+	VPXOR Y3, Y14, Y14
+	VPSHUFB ·blake3Rot16<>(SB), Y14, Y14
+	VPADDD Y14, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPSRLD $12, Y4, Y7
+	VPSLLD $20, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y3, Y3
+	VPADDD 704(SP), Y3, Y3
+	VPXOR Y3, Y14, Y14
+	VPSHUFB ·blake3Rot8<>(SB), Y14, Y14
+	VPADDD Y14, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPSRLD $7, Y4, Y7
+	VPSLLD $25, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y0, Y0
+	VPXOR Y0, Y12, Y12
+	VPSHUFB ·blake3Rot16<>(SB), Y12, Y12
+	VPADDD Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPSRLD $12, Y4, Y7
+	VPSLLD $20, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y0, Y0
+	VPXOR Y0, Y12, Y12
+	VPSHUFB ·blake3Rot8<>(SB), Y12, Y12
+	VPADDD Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPSRLD $7, Y4, Y7
+	VPSLLD $25, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y5, Y1, Y1
+	VPADDD 32(BX), Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPSHUFB ·blake3Rot16<>(SB), Y13, Y13
+	VPADDD Y13, Y9, Y9
+	VPXOR Y9, Y5, Y5
+	VPSRLD $12, Y5, Y7
+	VPSLLD $20, Y5, Y5
+	VPOR Y7, Y5, Y5
+	VPADDD Y5, Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPSHUFB ·blake3Rot8<>(SB), Y13, Y13
+	VPADDD Y13, Y9, Y9
+	VPXOR Y9, Y5, Y5
+	VPSRLD $7, Y5, Y7
+	VPSLLD $25, Y5, Y5
+	VPOR Y7, Y5, Y5
+	VPADDD Y6, Y2, Y2
+	VPXOR Y2, Y14, Y14
+	VPSHUFB ·blake3Rot16<>(SB), Y14, Y14
+	VPADDD Y14, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPSRLD $12, Y6, Y7
+	VPSLLD $20, Y6, Y6
+	VPOR Y7, Y6, Y6
+	VPADDD Y6, Y2, Y2
+	VPXOR Y2, Y14, Y14
+	VPSHUFB ·blake3Rot8<>(SB), Y14, Y14
+	VPADDD Y14, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPSRLD $7, Y6, Y7
+	VPSLLD $25, Y6, Y6
+	VPOR Y7, Y6, Y6
+	VMOVDQU Y4, 128(SP)
+	VMOVDQU 224(SP), Y7
+	VPADDD Y7, Y3, Y3
+	VPXOR Y3, Y15, Y15
+	VPSHUFB ·blake3Rot16<>(SB), Y15, Y15
+	VPADDD Y15, Y11, Y11
+	VPXOR Y11, Y7, Y7
+	VPSRLD $12, Y7, Y4
+	VPSLLD $20, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y7, Y3, Y3
+	VPADDD (BX), Y3, Y3
+	VPXOR Y3, Y15, Y15
+	VPSHUFB ·blake3Rot8<>(SB), Y15, Y15
+	VPADDD Y15, Y11, Y11
+	VPXOR Y11, Y7, Y7
+	VPSRLD $7, Y7, Y4
+	VPSLLD $25, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y5, Y0, Y0
+	VPADDD 736(SP), Y0, Y0
+	VPXOR Y0, Y15, Y15
+	VPSHUFB ·blake3Rot16<>(SB), Y15, Y15
+	VPADDD Y15, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPSRLD $12, Y5, Y4
+	VPSLLD $20, Y5, Y5
+	VPOR Y4, Y5, Y5
+	VPADDD Y5, Y0, Y0
+	VPADDD 576(SP), Y0, Y0
+	VPXOR Y0, Y15, Y15
+	VPSHUFB ·blake3Rot8<>(SB), Y15, Y15
+	VPADDD Y15, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPSRLD $7, Y5, Y4
+	VPSLLD $25, Y5, Y5
+	VPOR Y4, Y5, Y5
+	VPADDD Y6, Y1, Y1
+	VPADDD 672(SP), Y1, Y1
+	VPXOR Y1, Y12, Y12
+	VPSHUFB ·blake3Rot16<>(SB), Y12, Y12
+	VPADDD Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPSRLD $12, Y6, Y4
+	VPSLLD $20, Y6, Y6
+	VPOR Y4, Y6, Y6
+	VPADDD Y6, Y1, Y1
+	VPADDD 608(SP), Y1, Y1
+	VPXOR Y1, Y12, Y12
+	VPSHUFB ·blake3Rot8<>(SB), Y12, Y12
+	VPADDD Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPSRLD $7, Y6, Y4
+	VPSLLD $25, Y6, Y6
+	VPOR Y4, Y6, Y6
+	VPADDD Y7, Y2, Y2
+	VPADDD 512(SP), Y2, Y2
+	VPXOR Y2, Y13, Y13
+	VPSHUFB ·blake3Rot16<>(SB), Y13, Y13
+	VPADDD Y13, Y8, Y8
+	VPXOR Y8, Y7, Y7
+	VPSRLD $12, Y7, Y4
+	VPSLLD $20, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y7, Y2, Y2
+	VPADDD 544(SP), Y2, Y2
+	VPXOR Y2, Y13, Y13
+	VPSHUFB ·blake3Rot8<>(SB), Y13, Y13
+	VPADDD Y13, Y8, Y8
+	VPXOR Y8, Y7, Y7
+	VPSRLD $7, Y7, Y4
+	VPSLLD $25, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VMOVDQU Y7, 224(SP)
+	VMOVDQU 128(SP), Y4
+	VPADDD Y4, Y3, Y3
+	VPADDD 704(SP), Y3, Y3
+	VPXOR Y3, Y14, Y14
+	VPSHUFB ·blake3Rot16<>(SB), Y14, Y14
+	VPADDD Y14, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPSRLD $12, Y4, Y7
+	VPSLLD $20, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y3, Y3
+	VPADDD 640(SP), Y3, Y3
+	VPXOR Y3, Y14, Y14
+	VPSHUFB ·blake3Rot8<>(SB), Y14, Y14
+	VPADDD Y14, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPSRLD $7, Y4, Y7
+	VPSLLD $25, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y0, Y0
+	VPADDD 32(BX), Y0, Y0
+	VPXOR Y0, Y12, Y12
+	VPSHUFB ·blake3Rot16<>(SB), Y12, Y12
+	VPADDD Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPSRLD $12, Y4, Y7
+	VPSLLD $20, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y0, Y0
+	VPXOR Y0, Y12, Y12
+	VPSHUFB ·blake3Rot8<>(SB), Y12, Y12
+	VPADDD Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPSRLD $7, Y4, Y7
+	VPSLLD $25, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y5, Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPSHUFB ·blake3Rot16<>(SB), Y13, Y13
+	VPADDD Y13, Y9, Y9
+	VPXOR Y9, Y5, Y5
+	VPSRLD $12, Y5, Y7
+	VPSLLD $20, Y5, Y5
+	VPOR Y7, Y5, Y5
+	VPADDD Y5, Y1, Y1
+	VPADDD 672(SP), Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPSHUFB ·blake3Rot8<>(SB), Y13, Y13
+	VPADDD Y13, Y9, Y9
+	VPXOR Y9, Y5, Y5
+	VPSRLD $7, Y5, Y7
+	VPSLLD $25, Y5, Y5
+	VPOR Y7, Y5, Y5
+	VPADDD Y6, Y2, Y2
+	VPADDD (BX), Y2, Y2
+	VPXOR Y2, Y14, Y14
+	VPSHUFB ·blake3Rot16<>(SB), Y14, Y14
+	VPADDD Y14, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPSRLD $12, Y6, Y7
+	VPSLLD $20, Y6, Y6
+	VPOR Y7, Y6, Y6
+	VPADDD Y6, Y2, Y2
+	VPXOR Y2, Y14, Y14
+	VPSHUFB ·blake3Rot8<>(SB), Y14, Y14
+	VPADDD Y14, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPSRLD $7, Y6, Y7
+	VPSLLD $25, Y6, Y6
+	VPOR Y7, Y6, Y6
+	VMOVDQU Y4, 128(SP)
+	VMOVDQU 224(SP), Y7
+	VPADDD Y7, Y3, Y3
+	VPXOR Y3, Y15, Y15
+	VPSHUFB ·blake3Rot16<>(SB), Y15, Y15
+	VPADDD Y15, Y11, Y11
+	VPXOR Y11, Y7, Y7
+	VPSRLD $12, Y7, Y4
+	VPSLLD $20, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y7, Y3, Y3
+	VPADDD 544(SP), Y3, Y3
+	VPXOR Y3, Y15, Y15
+	VPSHUFB ·blake3Rot8<>(SB), Y15, Y15
+	VPADDD Y15, Y11, Y11
+	VPXOR Y11, Y7, Y7
+	VPSRLD $7, Y7, Y4
+	VPSLLD $25, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y5, Y0, Y0
+	VPXOR Y0, Y15, Y15
+	VPSHUFB ·blake3Rot16<>(SB), Y15, Y15
+	VPADDD Y15, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPSRLD $12, Y5, Y4
+	VPSLLD $20, Y5, Y5
+	VPOR Y4, Y5, Y5
+	VPADDD Y5, Y0, Y0
+	VPADDD 608(SP), Y0, Y0
+	VPXOR Y0, Y15, Y15
+	VPSHUFB ·blake3Rot8<>(SB), Y15, Y15
+	VPADDD Y15, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPSRLD $7, Y5, Y4
+	VPSLLD $25, Y5, Y5
+	VPOR Y4, Y5, Y5
+	VPADDD Y6, Y1, Y1
+	VPADDD 512(SP), Y1, Y1
+	VPXOR Y1, Y12, Y12
+	VPSHUFB ·blake3Rot16<>(SB), Y12, Y12
+	VPADDD Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPSRLD $12, Y6, Y4
+	VPSLLD $20, Y6, Y6
+	VPOR Y4, Y6, Y6
+	VPADDD Y6, Y1, Y1
+	VPXOR Y1, Y12, Y12
+	VPSHUFB ·blake3Rot8<>(SB), Y12, Y12
+	VPADDD Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPSRLD $7, Y6, Y4
+	VPSLLD $25, Y6, Y6
+	VPOR Y4, Y6, Y6
+	VPADDD Y7, Y2, Y2
+	VPADDD 576(SP), Y2, Y2
+	VPXOR Y2, Y13, Y13
+	VPSHUFB ·blake3Rot16<>(SB), Y13, Y13
+	VPADDD Y13, Y8, Y8
+	VPXOR Y8, Y7, Y7
+	VPSRLD $12, Y7, Y4
+	VPSLLD $20, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y7, Y2, Y2
+	VPADDD 704(SP), Y2, Y2
+	VPXOR Y2, Y13, Y13
+	VPSHUFB ·blake3Rot8<>(SB), Y13, Y13
+	VPADDD Y13, Y8, Y8
+	VPXOR Y8, Y7, Y7
+	VPSRLD $7, Y7, Y4
+	VPSLLD $25, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VMOVDQU Y7, 224(SP)
+	VMOVDQU 128(SP), Y4
+	VPADDD Y4, Y3, Y3
+	VPADDD 640(SP), Y3, Y3
+	VPXOR Y3, Y14, Y14
+	VPSHUFB ·blake3Rot16<>(SB), Y14, Y14
+	VPADDD Y14, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPSRLD $12, Y4, Y7
+	VPSLLD $20, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y3, Y3
+	VPADDD 736(SP), Y3, Y3
+	VPXOR Y3, Y14, Y14
+	VPSHUFB ·blake3Rot8<>(SB), Y14, Y14
+	VPADDD Y14, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPSRLD $7, Y4, Y7
+	VPSLLD $25, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y0, Y0
+	VPXOR Y0, Y12, Y12
+	VPSHUFB ·blake3Rot16<>(SB), Y12, Y12
+	VPADDD Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPSRLD $12, Y4, Y7
+	VPSLLD $20, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y0, Y0
+	VPXOR Y0, Y12, Y12
+	VPSHUFB ·blake3Rot8<>(SB), Y12, Y12
+	VPADDD Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPSRLD $7, Y4, Y7
+	VPSLLD $25, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y5, Y1, Y1
+	VPADDD 672(SP), Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPSHUFB ·blake3Rot16<>(SB), Y13, Y13
+	VPADDD Y13, Y9, Y9
+	VPXOR Y9, Y5, Y5
+	VPSRLD $12, Y5, Y7
+	VPSLLD $20, Y5, Y5
+	VPOR Y7, Y5, Y5
+	VPADDD Y5, Y1, Y1
+	VPADDD 512(SP), Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPSHUFB ·blake3Rot8<>(SB), Y13, Y13
+	VPADDD Y13, Y9, Y9
+	VPXOR Y9, Y5, Y5
+	VPSRLD $7, Y5, Y7
+	VPSLLD $25, Y5, Y5
+	VPOR Y7, Y5, Y5
+	VPADDD Y6, Y2, Y2
+	VPADDD 544(SP), Y2, Y2
+	VPXOR Y2, Y14, Y14
+	VPSHUFB ·blake3Rot16<>(SB), Y14, Y14
+	VPADDD Y14, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPSRLD $12, Y6, Y7
+	VPSLLD $20, Y6, Y6
+	VPOR Y7, Y6, Y6
+	VPADDD Y6, Y2, Y2
+	VPADDD 32(BX), Y2, Y2
+	VPXOR Y2, Y14, Y14
+	VPSHUFB ·blake3Rot8<>(SB), Y14, Y14
+	VPADDD Y14, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPSRLD $7, Y6, Y7
+	VPSLLD $25, Y6, Y6
+	VPOR Y7, Y6, Y6
+	VMOVDQU Y4, 128(SP)
+	VMOVDQU 224(SP), Y7
+	VPADDD Y7, Y3, Y3
+	VPADDD (BX), Y3, Y3
+	VPXOR Y3, Y15, Y15
+	VPSHUFB ·blake3Rot16<>(SB), Y15, Y15
+	VPADDD Y15, Y11, Y11
+	VPXOR Y11, Y7, Y7
+	VPSRLD $12, Y7, Y4
+	VPSLLD $20, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y7, Y3, Y3
+	VPADDD 704(SP), Y3, Y3
+	VPXOR Y3, Y15, Y15
+	VPSHUFB ·blake3Rot8<>(SB), Y15, Y15
+	VPADDD Y15, Y11, Y11
+	VPXOR Y11, Y7, Y7
+	VPSRLD $7, Y7, Y4
+	VPSLLD $25, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y5, Y0, Y0
+	VPXOR Y0, Y15, Y15
+	VPSHUFB ·blake3Rot16<>(SB), Y15, Y15
+	VPADDD Y15, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPSRLD $12, Y5, Y4
+	VPSLLD $20, Y5, Y5
+	VPOR Y4, Y5, Y5
+	VPADDD Y5, Y0, Y0
+	VPXOR Y0, Y15, Y15
+	VPSHUFB ·blake3Rot8<>(SB), Y15, Y15
+	VPADDD Y15, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPSRLD $7, Y5, Y4
+	VPSLLD $25, Y5, Y5
+	VPOR Y4, Y5, Y5
+	VPADDD Y6, Y1, Y1
+	VPADDD 576(SP), Y1, Y1
+	VPXOR Y1, Y12, Y12
+	VPSHUFB ·blake3Rot16<>(SB), Y12, Y12
+	VPADDD Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPSRLD $12, Y6, Y4
+	VPSLLD $20, Y6, Y6
+	VPOR Y4, Y6, Y6
+	VPADDD Y6, Y1, Y1
+	VPXOR Y1, Y12, Y12
+	VPSHUFB ·blake3Rot8<>(SB), Y12, Y12
+	VPADDD Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPSRLD $7, Y6, Y4
+	VPSLLD $25, Y6, Y6
+	VPOR Y4, Y6, Y6
+	VPADDD Y7, Y2, Y2
+	VPADDD 608(SP), Y2, Y2
+	VPXOR Y2, Y13, Y13
+	VPSHUFB ·blake3Rot16<>(SB), Y13, Y13
+	VPADDD Y13, Y8, Y8
+	VPXOR Y8, Y7, Y7
+	VPSRLD $12, Y7, Y4
+	VPSLLD $20, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y7, Y2, Y2
+	VPADDD 640(SP), Y2, Y2
+	VPXOR Y2, Y13, Y13
+	VPSHUFB ·blake3Rot8<>(SB), Y13, Y13
+	VPADDD Y13, Y8, Y8
+	VPXOR Y8, Y7, Y7
+	VPSRLD $7, Y7, Y4
+	VPSLLD $25, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VMOVDQU Y0, 0(SP)
+	VMOVDQU 128(SP), Y4
+	VPADDD Y4, Y3, Y3
+	VPADDD 736(SP), Y3, Y3
+	VPXOR Y3, Y14, Y14
+	VPSHUFB ·blake3Rot16<>(SB), Y14, Y14
+	VPADDD Y14, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPSRLD $12, Y4, Y0
+	VPSLLD $20, Y4, Y4
+	VPOR Y0, Y4, Y4
+	VPADDD Y4, Y3, Y3
+	VPXOR Y3, Y14, Y14
+	VPSHUFB ·blake3Rot8<>(SB), Y14, Y14
+	VPADDD Y14, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPSRLD $7, Y4, Y0
+	VPSLLD $25, Y4, Y4
+	VPOR Y0, Y4, Y4
+	VMOVDQU 0(SP), Y0
+	VPXOR Y8, Y0, Y0
+	VMOVDQU Y0, 512(SP)
+	VPXOR Y9, Y1, Y1
+	VMOVDQU Y1, 544(SP)
+	VPXOR Y10, Y2, Y2
+	VMOVDQU Y2, 576(SP)
+	VPXOR Y11, Y3, Y3
+	VMOVDQU Y3, 608(SP)
+	VPXOR Y12, Y4, Y4
+	VMOVDQU Y4, 640(SP)
+	VPXOR Y13, Y5, Y5
+	VMOVDQU Y5, 672(SP)
+	VPXOR Y14, Y6, Y6
+	VMOVDQU Y6, 704(SP)
+	VPXOR Y15, Y7, Y7
+	VMOVDQU Y7, 736(SP)
+	TESTQ CX, CX
+	JZ   done
+loop:
+	VPBROADCASTD ·blake3IV<>+0x00(SB), Y0
+	VPBROADCASTD ·blake3IV<>+0x04(SB), Y1
+	VPBROADCASTD ·blake3IV<>+0x08(SB), Y2
+	VPBROADCASTD ·blake3IV<>+0x0c(SB), Y3
+	VPBROADCASTD ·blake3IV<>+0x10(SB), Y4
+	VPBROADCASTD ·blake3IV<>+0x14(SB), Y5
+	VPBROADCASTD ·blake3IV<>+0x18(SB), Y6
+	VPBROADCASTD ·blake3IV<>+0x1c(SB), Y7
+	VMOVDQA Y0, Y8
+	VMOVDQA Y1, Y9
+	VMOVDQA Y2, Y10
+	VMOVDQA Y3, Y11
+	VPXOR Y12, Y12, Y12
+	VPXOR Y13, Y13, Y13
+	VPBROADCASTD ·blake3Params<>+0x04(SB), Y14
+	VPBROADCASTD ·blake3Params<>+0x08(SB), Y15
+	VMOVDQU Y3, 96(SP)
+	VPADDD Y4, Y0, Y0
+	VPADDD 512(SP), Y0, Y0
+	VPXOR Y0, Y12, Y12
+	VPSHUFB ·blake3Rot16<>(SB), Y12, Y12
+	VPADDD Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPSRLD $12, Y4, Y3
+	VPSLLD $20, Y4, Y4
+	VPOR Y3, Y4, Y4
+	VPADDD Y4, Y0, Y0
+	VPADDD 544(SP), Y0, Y0
+	VPXOR Y0, Y12, Y12
+	VPSHUFB ·blake3Rot8<>(SB), Y12, Y12
+	VPADDD Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPSRLD $7, Y4, Y3
+	VPSLLD $25, Y4, Y4
+	VPOR Y3, Y4, Y4
+	VPADDD Y5, Y1, Y1
+	VPADDD 576(SP), Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPSHUFB ·blake3Rot16<>(SB), Y13, Y13
+	VPADDD Y13, Y9, Y9
+	VPXOR Y9, Y5, Y5
+	VPSRLD $12, Y5, Y3
+	VPSLLD $20, Y5, Y5
+	VPOR Y3, Y5, Y5
+	VPADDD Y5, Y1, Y1
+	VPADDD 608(SP), Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPSHUFB ·blake3Rot8<>(SB), Y13, Y13
+	VPADDD Y13, Y9, Y9
+	VPXOR Y9, Y5, Y5
+	VPSRLD $7, Y5, Y3
+	VPSLLD $25, Y5, Y5
+	VPOR Y3, Y5, Y5
+	VPADDD Y6, Y2, Y2
+	VPADDD 640(SP), Y2, Y2
+	VPXOR Y2, Y14, Y14
+	VPSHUFB ·blake3Rot16<>(SB), Y14, Y14
+	VPADDD Y14, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPSRLD $12, Y6, Y3
+	VPSLLD $20, Y6, Y6
+	VPOR Y3, Y6, Y6
+	VPADDD Y6, Y2, Y2
+	VPADDD 672(SP), Y2, Y2
+	VPXOR Y2, Y14, Y14
+	VPSHUFB ·blake3Rot8<>(SB), Y14, Y14
+	VPADDD Y14, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPSRLD $7, Y6, Y3
+	VPSLLD $25, Y6, Y6
+	VPOR Y3, Y6, Y6
+	VMOVDQU Y4, 128(SP)
+	VMOVDQU 96(SP), Y3
+	VPADDD Y7, Y3, Y3
+	VPADDD 704(SP), Y3, Y3
+	VPXOR Y3, Y15, Y15
+	VPSHUFB ·blake3Rot16<>(SB), Y15, Y15
+	VPADDD Y15, Y11, Y11
+	VPXOR Y11, Y7, Y7
+	VPSRLD $12, Y7, Y4
+	VPSLLD $20, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y7, Y3, Y3
+	VPADDD 736(SP), Y3, Y3
+	VPXOR Y3, Y15, Y15
+	VPSHUFB ·blake3Rot8<>(SB), Y15, Y15
+	VPADDD Y15, Y11, Y11
+	VPXOR Y11, Y7, Y7
+	VPSRLD $7, Y7, Y4
+	VPSLLD $25, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y5, Y0, Y0
+	VPXOR Y0, Y15, Y15
+	VPSHUFB ·blake3Rot16<>(SB), Y15, Y15
+	VPADDD Y15, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPSRLD $12, Y5, Y4
+	VPSLLD $20, Y5, Y5
+	VPOR Y4, Y5, Y5
+	VPADDD Y5, Y0, Y0
+	VPXOR Y0, Y15, Y15
+	VPSHUFB ·blake3Rot8<>(SB), Y15, Y15
+	VPADDD Y15, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPSRLD $7, Y5, Y4
+	VPSLLD $25, Y5, Y5
+	VPOR Y4, Y5, Y5
+	VPADDD Y6, Y1, Y1
+	VPXOR Y1, Y12, Y12
+	VPSHUFB ·blake3Rot16<>(SB), Y12, Y12
+	VPADDD Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPSRLD $12, Y6, Y4
+	VPSLLD $20, Y6, Y6
+	VPOR Y4, Y6, Y6
+	VPADDD Y6, Y1, Y1
+	VPXOR Y1, Y12, Y12
+	VPSHUFB ·blake3Rot8<>(SB), Y12, Y12
+	VPADDD Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPSRLD $7, Y6, Y4
+	VPSLLD $25, Y6, Y6
+	VPOR Y4, Y6, Y6
+	VPADDD Y7, Y2, Y2
+	VPXOR Y2, Y13, Y13
+	VPSHUFB ·blake3Rot16<>(SB), Y13, Y13
+	VPADDD Y13, Y8, Y8
+	VPXOR Y8, Y7, Y7
+	VPSRLD $12, Y7, Y4
+	VPSLLD $20, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y7, Y2, Y2
+	VPXOR Y2, Y13, Y13
+	VPSHUFB ·blake3Rot8<>(SB), Y13, Y13
+	VPADDD Y13, Y8, Y8
+	VPXOR Y8, Y7, Y7
+	VPSRLD $7, Y7, Y4
+	VPSLLD $25, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VMOVDQU Y7, 224(SP)
+	VMOVDQU 128(SP), Y4
+	VPADDD Y4, Y3, Y3
+	VPXOR Y3, Y14, Y14
+	VPSHUFB ·blake3Rot16<>(SB), Y14, Y14
+	VPADDD Y14, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPSRLD $12, Y4, Y7
+	VPSLLD $20, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y3, Y3
+	VPXOR Y3, Y14, Y14
+	VPSHUFB ·blake3Rot8<>(SB), Y14, Y14
+	VPADDD Y14, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPSRLD $7, Y4, Y7
+	VPSLLD $25, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y0, Y0
+	VPADDD 576(SP), Y0, Y0
+	VPXOR Y0, Y12, Y12
+	VPSHUFB ·blake3Rot16<>(SB), Y12, Y12
+	VPADDD Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPSRLD $12, Y4, Y7
+	VPSLLD $20, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y0, Y0
+	VPADDD 704(SP), Y0, Y0
+	VPXOR Y0, Y12, Y12
+	VPSHUFB ·blake3Rot8<>(SB), Y12, Y12
+	VPADDD Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPSRLD $7, Y4, Y7
+	VPSLLD $25, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y5, Y1, Y1
+	VPADDD 608(SP), Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPSHUFB ·blake3Rot16<>(SB), Y13, Y13
+	VPADDD Y13, Y9, Y9
+	VPXOR Y9, Y5, Y5
+	VPSRLD $12, Y5, Y7
+	VPSLLD $20, Y5, Y5
+	VPOR Y7, Y5, Y5
+	VPADDD Y5, Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPSHUFB ·blake3Rot8<>(SB), Y13, Y13
+	VPADDD Y13, Y9, Y9
+	VPXOR Y9, Y5, Y5
+	VPSRLD $7, Y5, Y7
+	VPSLLD $25, Y5, Y5
+	VPOR Y7, Y5, Y5
+	VPADDD Y6, Y2, Y2
+	VPADDD 736(SP), Y2, Y2
+	VPXOR Y2, Y14, Y14
+	VPSHUFB ·blake3Rot16<>(SB), Y14, Y14
+	VPADDD Y14, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPSRLD $12, Y6, Y7
+	VPSLLD $20, Y6, Y6
+	VPOR Y7, Y6, Y6
+	VPADDD Y6, Y2, Y2
+	VPADDD 512(SP), Y2, Y2
+	VPXOR Y2, Y14, Y14
+	VPSHUFB ·blake3Rot8<>(SB), Y14, Y14
+	VPADDD Y14, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPSRLD $7, Y6, Y7
+	VPSLLD $25, Y6, Y6
+	VPOR Y7, Y6, Y6
+	VMOVDQU Y4, 128(SP)
+	VMOVDQU 224(SP), Y7
+	VPADDD Y7, Y3, Y3
+	VPADDD 640(SP), Y3, Y3
+	VPXOR Y3, Y15, Y15
+	VPSHUFB ·blake3Rot16<>(SB), Y15, Y15
+	VPADDD Y15, Y11, Y11
+	VPXOR Y11, Y7, Y7
+	VPSRLD $12, Y7, Y4
+	VPSLLD $20, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y7, Y3, Y3
+	VPXOR Y3, Y15, Y15
+	VPSHUFB ·blake3Rot8<>(SB), Y15, Y15
+	VPADDD Y15, Y11, Y11
+	VPXOR Y11, Y7, Y7
+	VPSRLD $7, Y7, Y4
+	VPSLLD $25, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y5, Y0, Y0
+	VPADDD 544(SP), Y0, Y0
+	VPXOR Y0, Y15, Y15
+	VPSHUFB ·blake3Rot16<>(SB), Y15, Y15
+	VPADDD Y15, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPSRLD $12, Y5, Y4
+	VPSLLD $20, Y5, Y5
+	VPOR Y4, Y5, Y5
+	VPADDD Y5, Y0, Y0
+	VPXOR Y0, Y15, Y15
+	VPSHUFB ·blake3Rot8<>(SB), Y15, Y15
+	VPADDD Y15, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPSRLD $7, Y5, Y4
+	VPSLLD $25, Y5, Y5
+	VPOR Y4, Y5, Y5
+	VPADDD Y6, Y1, Y1
+	VPXOR Y1, Y12, Y12
+	VPSHUFB ·blake3Rot16<>(SB), Y12, Y12
+	VPADDD Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPSRLD $12, Y6, Y4
+	VPSLLD $20, Y6, Y6
+	VPOR Y4, Y6, Y6
+	VPADDD Y6, Y1, Y1
+	VPADDD 672(SP), Y1, Y1
+	VPXOR Y1, Y12, Y12
+	VPSHUFB ·blake3Rot8<>(SB), Y12, Y12
+	VPADDD Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPSRLD $7, Y6, Y4
+	VPSLLD $25, Y6, Y6
+	VPOR Y4, Y6, Y6
+	VPADDD Y7, Y2, Y2
+	VPXOR Y2, Y13, Y13
+	VPSHUFB ·blake3Rot16<>(SB), Y13, Y13
+	VPADDD Y13, Y8, Y8
+	VPXOR Y8, Y7, Y7
+	VPSRLD $12, Y7, Y4
+	VPSLLD $20, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y7, Y2, Y2
+	VPXOR Y2, Y13, Y13
+	VPSHUFB ·blake3Rot8<>(SB), Y13, Y13
+	VPADDD Y13, Y8, Y8
+	VPXOR Y8, Y7, Y7
+	VPSRLD $7, Y7, Y4
+	VPSLLD $25, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VMOVDQU Y7, 224(SP)
+	VMOVDQU 128(SP), Y4
+	VPADDD Y4, Y3, Y3
+	VPXOR Y3, Y14, Y14
+	VPSHUFB ·blake3Rot16<>(SB), Y14, Y14
+	VPADDD Y14, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPSRLD $12, Y4, Y7
+	VPSLLD $20, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y3, Y3
+	VPXOR Y3, Y14, Y14
+	VPSHUFB ·blake3Rot8<>(SB), Y14, Y14
+	VPADDD Y14, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPSRLD $7, Y4, Y7
+	VPSLLD $25, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y0, Y0
+	VPADDD 608(SP), Y0, Y0
+	VPXOR Y0, Y12, Y12
+	VPSHUFB ·blake3Rot16<>(SB), Y12, Y12
+	VPADDD Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPSRLD $12, Y4, Y7
+	VPSLLD $20, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y0, Y0
+	VPADDD 640(SP), Y0, Y0
+	VPXOR Y0, Y12, Y12
+	VPSHUFB ·blake3Rot8<>(SB), Y12, Y12
+	VPADDD Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPSRLD $7, Y4, Y7
+	VPSLLD $25, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y5, Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPSHUFB ·blake3Rot16<>(SB), Y13, Y13
+	VPADDD Y13, Y9, Y9
+	VPXOR Y9, Y5, Y5
+	VPSRLD $12, Y5, Y7
+	VPSLLD $20, Y5, Y5
+	VPOR Y7, Y5, Y5
+	VPADDD Y5, Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPSHUFB ·blake3Rot8<>(SB), Y13, Y13
+	VPADDD Y13, Y9, Y9
+	VPXOR Y9, Y5, Y5
+	VPSRLD $7, Y5, Y7
+	VPSLLD $25, Y5, Y5
+	VPOR Y7, Y5, Y5
+	VPADDD Y6, Y2, Y2
+	VPXOR Y2, Y14, Y14
+	VPSHUFB ·blake3Rot16<>(SB), Y14, Y14
+	VPADDD Y14, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPSRLD $12, Y6, Y7
+	VPSLLD $20, Y6, Y6
+	VPOR Y7, Y6, Y6
+	VPADDD Y6, Y2, Y2
+	VPADDD 576(SP), Y2, Y2
+	VPXOR Y2, Y14, Y14
+	VPSHUFB ·blake3Rot8<>(SB), Y14, Y14
+	VPADDD Y14, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPSRLD $7, Y6, Y7
+	VPSLLD $25, Y6, Y6
+	VPOR Y7, Y6, Y6
+	VMOVDQU Y4, 128(SP)
+	VMOVDQU 224(SP), Y7
+	VPADDD Y7, Y3, Y3
+	VPADDD 736(SP), Y3, Y3
+	VPXOR Y3, Y15, Y15
+	VPSHUFB ·blake3Rot16<>(SB), Y15, Y15
+	VPADDD Y15, Y11, Y11
+	VPXOR Y11, Y7, Y7
+	VPSRLD $12, Y7, Y4
+	VPSLLD $20, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y7, Y3, Y3
+	VPXOR Y3, Y15, Y15
+	VPSHUFB ·blake3Rot8<>(SB), Y15, Y15
+	VPADDD Y15, Y11, Y11
+	VPXOR Y11, Y7, Y7
+	VPSRLD $7, Y7, Y4
+	VPSLLD $25, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y5, Y0, Y0
+	VPADDD 704(SP), Y0, Y0
+	VPXOR Y0, Y15, Y15
+	VPSHUFB ·blake3Rot16<>(SB), Y15, Y15
+	VPADDD Y15, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPSRLD $12, Y5, Y4
+	VPSLLD $20, Y5, Y5
+	VPOR Y4, Y5, Y5
+	VPADDD Y5, Y0, Y0
+	VPADDD 672(SP), Y0, Y0
+	VPXOR Y0, Y15, Y15
+	VPSHUFB ·blake3Rot8<>(SB), Y15, Y15
+	VPADDD Y15, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPSRLD $7, Y5, Y4
+	VPSLLD $25, Y5, Y5
+	VPOR Y4, Y5, Y5
+	VPADDD Y6, Y1, Y1
+	VPXOR Y1, Y12, Y12
+	VPSHUFB ·blake3Rot16<>(SB), Y12, Y12
+	VPADDD Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPSRLD $12, Y6, Y4
+	VPSLLD $20, Y6, Y6
+	VPOR Y4, Y6, Y6
+	VPADDD Y6, Y1, Y1
+	VPADDD 512(SP), Y1, Y1
+	VPXOR Y1, Y12, Y12
+	VPSHUFB ·blake3Rot8<>(SB), Y12, Y12
+	VPADDD Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPSRLD $7, Y6, Y4
+	VPSLLD $25, Y6, Y6
+	VPOR Y4, Y6, Y6
+	VPADDD Y7, Y2, Y2
+	VPXOR Y2, Y13, Y13
+	VPSHUFB ·blake3Rot16<>(SB), Y13, Y13
+	VPADDD Y13, Y8, Y8
+	VPXOR Y8, Y7, Y7
+	VPSRLD $12, Y7, Y4
+	VPSLLD $20, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y7, Y2, Y2
+	VPXOR Y2, Y13, Y13
+	VPSHUFB ·blake3Rot8<>(SB), Y13, Y13
+	VPADDD Y13, Y8, Y8
+	VPXOR Y8, Y7, Y7
+	VPSRLD $7, Y7, Y4
+	VPSLLD $25, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VMOVDQU Y7, 224(SP)
+	VMOVDQU 128(SP), Y4
+	VPADDD Y4, Y3, Y3
+	VPXOR Y3, Y14, Y14
+	VPSHUFB ·blake3Rot16<>(SB), Y14, Y14
+	VPADDD Y14, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPSRLD $12, Y4, Y7
+	VPSLLD $20, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y3, Y3
+	VPADDD 544(SP), Y3, Y3
+	VPXOR Y3, Y14, Y14
+	VPSHUFB ·blake3Rot8<>(SB), Y14, Y14
+	VPADDD Y14, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPSRLD $7, Y4, Y7
+	VPSLLD $25, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y0, Y0
+	VPXOR Y0, Y12, Y12
+	VPSHUFB ·blake3Rot16<>(SB), Y12, Y12
+	VPADDD Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPSRLD $12, Y4, Y7
+	VPSLLD $20, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y0, Y0
+	VPADDD 736(SP), Y0, Y0
+	VPXOR Y0, Y12, Y12
+	VPSHUFB ·blake3Rot8<>(SB), Y12, Y12
+	VPADDD Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPSRLD $7, Y4, Y7
+	VPSLLD $25, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y5, Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPSHUFB ·blake3Rot16<>(SB), Y13, Y13
+	VPADDD Y13, Y9, Y9
+	VPXOR Y9, Y5, Y5
+	VPSRLD $12, Y5, Y7
+	VPSLLD $20, Y5, Y5
+	VPOR Y7, Y5, Y5
+	VPADDD Y5, Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPSHUFB ·blake3Rot8<>(SB), Y13, Y13
+	VPADDD Y13, Y9, Y9
+	VPXOR Y9, Y5, Y5
+	VPSRLD $7, Y5, Y7
+	VPSLLD $25, Y5, Y5
+	VPOR Y7, Y5, Y5
+	VPADDD Y6, Y2, Y2
+	VPXOR Y2, Y14, Y14
+	VPSHUFB ·blake3Rot16<>(SB), Y14, Y14
+	VPADDD Y14, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPSRLD $12, Y6, Y7
+	VPSLLD $20, Y6, Y6
+	VPOR Y7, Y6, Y6
+	VPADDD Y6, Y2, Y2
+	VPADDD 608(SP), Y2, Y2
+	VPXOR Y2, Y14, Y14
+	VPSHUFB ·blake3Rot8<>(SB), Y14, Y14
+	VPADDD Y14, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPSRLD $7, Y6, Y7
+	VPSLLD $25, Y6, Y6
+	VPOR Y7, Y6, Y6
+	VMOVDQU Y4, 128(SP)
+	VMOVDQU 224(SP), Y7
+	VPADDD Y7, Y3, Y3
+	VPXOR Y3, Y15, Y15
+	VPSHUFB ·blake3Rot16<>(SB), Y15, Y15
+	VPADDD Y15, Y11, Y11
+	VPXOR Y11, Y7, Y7
+	VPSRLD $12, Y7, Y4
+	VPSLLD $20, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y7, Y3, Y3
+	VPXOR Y3, Y15, Y15
+	VPSHUFB ·blake3Rot8<>(SB), Y15, Y15
+	VPADDD Y15, Y11, Y11
+	VPXOR Y11, Y7, Y7
+	VPSRLD $7, Y7, Y4
+	VPSLLD $25, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y5, Y0, Y0
+	VPADDD 640(SP), Y0, Y0
+	VPXOR Y0, Y15, Y15
+	VPSHUFB ·blake3Rot16<>(SB), Y15, Y15
+	VPADDD Y15, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPSRLD $12, Y5, Y4
+	VPSLLD $20, Y5, Y5
+	VPOR Y4, Y5, Y5
+	VPADDD Y5, Y0, Y0
+	VPADDD 512(SP), Y0, Y0
+	VPXOR Y0, Y15, Y15
+	VPSHUFB ·blake3Rot8<>(SB), Y15, Y15
+	VPADDD Y15, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPSRLD $7, Y5, Y4
+	VPSLLD $25, Y5, Y5
+	VPOR Y4, Y5, Y5
+	VPADDD Y6, Y1, Y1
+	VPXOR Y1, Y12, Y12
+	VPSHUFB ·blake3Rot16<>(SB), Y12, Y12
+	VPADDD Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPSRLD $12, Y6, Y4
+	VPSLLD $20, Y6, Y6
+	VPOR Y4, Y6, Y6
+	VPADDD Y6, Y1, Y1
+	VPADDD 576(SP), Y1, Y1
+	VPXOR Y1, Y12, Y12
+	VPSHUFB ·blake3Rot8<>(SB), Y12, Y12
+	VPADDD Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPSRLD $7, Y6, Y4
+	VPSLLD $25, Y6, Y6
+	VPOR Y4, Y6, Y6
+	VPADDD Y7, Y2, Y2
+	VPADDD 672(SP), Y2, Y2
+	VPXOR Y2, Y13, Y13
+	VPSHUFB ·blake3Rot16<>(SB), Y13, Y13
+	VPADDD Y13, Y8, Y8
+	VPXOR Y8, Y7, Y7
+	VPSRLD $12, Y7, Y4
+	VPSLLD $20, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y7, Y2, Y2
+	VPXOR Y2, Y13, Y13
+	VPSHUFB ·blake3Rot8<>(SB), Y13, Y13
+	VPADDD Y13, Y8, Y8
+	VPXOR Y8, Y7, Y7
+	VPSRLD $7, Y7, Y4
+	VPSLLD $25, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VMOVDQU Y7, 224(SP)
+	VMOVDQU 128(SP), Y4
+	VPADDD Y4, Y3, Y3
+	VPADDD 544(SP), Y3, Y3
+	VPXOR Y3, Y14, Y14
+	VPSHUFB ·blake3Rot16<>(SB), Y14, Y14
+	VPADDD Y14, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPSRLD $12, Y4, Y7
+	VPSLLD $20, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y3, Y3
+	VPADDD 704(SP), Y3, Y3
+	VPXOR Y3, Y14, Y14
+	VPSHUFB ·blake3Rot8<>(SB), Y14, Y14
+	VPADDD Y14, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPSRLD $7, Y4, Y7
+	VPSLLD $25, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y0, Y0
+	VPXOR Y0, Y12, Y12
+	VPSHUFB ·blake3Rot16<>(SB), Y12, Y12
+	VPADDD Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPSRLD $12, Y4, Y7
+	VPSLLD $20, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y0, Y0
+	VPXOR Y0, Y12, Y12
+	VPSHUFB ·blake3Rot8<>(SB), Y12, Y12
+	VPADDD Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPSRLD $7, Y4, Y7
+	VPSLLD $25, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y5, Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPSHUFB ·blake3Rot16<>(SB), Y13, Y13
+	VPADDD Y13, Y9, Y9
+	VPXOR Y9, Y5, Y5
+	VPSRLD $12, Y5, Y7
+	VPSLLD $20, Y5, Y5
+	VPOR Y7, Y5, Y5
+	VPADDD Y5, Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPSHUFB ·blake3Rot8<>(SB), Y13, Y13
+	VPADDD Y13, Y9, Y9
+	VPXOR Y9, Y5, Y5
+	VPSRLD $7, Y5, Y7
+	VPSLLD $25, Y5, Y5
+	VPOR Y7, Y5, Y5
+	VPADDD Y6, Y2, Y2
+	VPXOR Y2, Y14, Y14
+	VPSHUFB ·blake3Rot16<>(SB), Y14, Y14
+	VPADDD Y14, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPSRLD $12, Y6, Y7
+	VPSLLD $20, Y6, Y6
+	VPOR Y7, Y6, Y6
+	VPADDD Y6, Y2, Y2
+	VPXOR Y2, Y14, Y14
+	VPSHUFB ·blake3Rot8<>(SB), Y14, Y14
+	VPADDD Y14, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPSRLD $7, Y6, Y7
+	VPSLLD $25, Y6, Y6
+	VPOR Y7, Y6, Y6
+	VMOVDQU Y4, 128(SP)
+	VMOVDQU 224(SP), Y7
+	VPADDD Y7, Y3, Y3
+	VPXOR Y3, Y15, Y15
+	VPSHUFB ·blake3Rot16<>(SB), Y15, Y15
+	VPADDD Y15, Y11, Y11
+	VPXOR Y11, Y7, Y7
+	VPSRLD $12, Y7, Y4
+	VPSLLD $20, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y7, Y3, Y3
+	VPXOR Y3, Y15, Y15
+	VPSHUFB ·blake3Rot8<>(SB), Y15, Y15
+	VPADDD Y15, Y11, Y11
+	VPXOR Y11, Y7, Y7
+	VPSRLD $7, Y7, Y4
+	VPSLLD $25, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y5, Y0, Y0
+	VPADDD 736(SP), Y0, Y0
+	VPXOR Y0, Y15, Y15
+	VPSHUFB ·blake3Rot16<>(SB), Y15, Y15
+	VPADDD Y15, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPSRLD $12, Y5, Y4
+	VPSLLD $20, Y5, Y5
+	VPOR Y4, Y5, Y5
+	VPADDD Y5, Y0, Y0
+	VPADDD 576(SP), Y0, Y0
+	VPXOR Y0, Y15, Y15
+	VPSHUFB ·blake3Rot8<>(SB), Y15, Y15
+	VPADDD Y15, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPSRLD $7, Y5, Y4
+	VPSLLD $25, Y5, Y5
+	VPOR Y4, Y5, Y5
+	VPADDD Y6, Y1, Y1
+	VPADDD 672(SP), Y1, Y1
+	VPXOR Y1, Y12, Y12
+	VPSHUFB ·blake3Rot16<>(SB), Y12, Y12
+	VPADDD Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPSRLD $12, Y6, Y4
+	VPSLLD $20, Y6, Y6
+	VPOR Y4, Y6, Y6
+	VPADDD Y6, Y1, Y1
+	VPADDD 608(SP), Y1, Y1
+	VPXOR Y1, Y12, Y12
+	VPSHUFB ·blake3Rot8<>(SB), Y12, Y12
+	VPADDD Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPSRLD $7, Y6, Y4
+	VPSLLD $25, Y6, Y6
+	VPOR Y4, Y6, Y6
+	VPADDD Y7, Y2, Y2
+	VPADDD 512(SP), Y2, Y2
+	VPXOR Y2, Y13, Y13
+	VPSHUFB ·blake3Rot16<>(SB), Y13, Y13
+	VPADDD Y13, Y8, Y8
+	VPXOR Y8, Y7, Y7
+	VPSRLD $12, Y7, Y4
+	VPSLLD $20, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y7, Y2, Y2
+	VPADDD 544(SP), Y2, Y2
+	VPXOR Y2, Y13, Y13
+	VPSHUFB ·blake3Rot8<>(SB), Y13, Y13
+	VPADDD Y13, Y8, Y8
+	VPXOR Y8, Y7, Y7
+	VPSRLD $7, Y7, Y4
+	VPSLLD $25, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VMOVDQU Y7, 224(SP)
+	VMOVDQU 128(SP), Y4
+	VPADDD Y4, Y3, Y3
+	VPADDD 704(SP), Y3, Y3
+	VPXOR Y3, Y14, Y14
+	VPSHUFB ·blake3Rot16<>(SB), Y14, Y14
+	VPADDD Y14, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPSRLD $12, Y4, Y7
+	VPSLLD $20, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y3, Y3
+	VPADDD 640(SP), Y3, Y3
+	VPXOR Y3, Y14, Y14
+	VPSHUFB ·blake3Rot8<>(SB), Y14, Y14
+	VPADDD Y14, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPSRLD $7, Y4, Y7
+	VPSLLD $25, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y0, Y0
+	VPXOR Y0, Y12, Y12
+	VPSHUFB ·blake3Rot16<>(SB), Y12, Y12
+	VPADDD Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPSRLD $12, Y4, Y7
+	VPSLLD $20, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y0, Y0
+	VPXOR Y0, Y12, Y12
+	VPSHUFB ·blake3Rot8<>(SB), Y12, Y12
+	VPADDD Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPSRLD $7, Y4, Y7
+	VPSLLD $25, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y5, Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPSHUFB ·blake3Rot16<>(SB), Y13, Y13
+	VPADDD Y13, Y9, Y9
+	VPXOR Y9, Y5, Y5
+	VPSRLD $12, Y5, Y7
+	VPSLLD $20, Y5, Y5
+	VPOR Y7, Y5, Y5
+	VPADDD Y5, Y1, Y1
+	VPADDD 672(SP), Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPSHUFB ·blake3Rot8<>(SB), Y13, Y13
+	VPADDD Y13, Y9, Y9
+	VPXOR Y9, Y5, Y5
+	VPSRLD $7, Y5, Y7
+	VPSLLD $25, Y5, Y5
+	VPOR Y7, Y5, Y5
+	VPADDD Y6, Y2, Y2
+	VPXOR Y2, Y14, Y14
+	VPSHUFB ·blake3Rot16<>(SB), Y14, Y14
+	VPADDD Y14, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPSRLD $12, Y6, Y7
+	VPSLLD $20, Y6, Y6
+	VPOR Y7, Y6, Y6
+	VPADDD Y6, Y2, Y2
+	VPXOR Y2, Y14, Y14
+	VPSHUFB ·blake3Rot8<>(SB), Y14, Y14
+	VPADDD Y14, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPSRLD $7, Y6, Y7
+	VPSLLD $25, Y6, Y6
+	VPOR Y7, Y6, Y6
+	VMOVDQU Y4, 128(SP)
+	VMOVDQU 224(SP), Y7
+	VPADDD Y7, Y3, Y3
+	VPXOR Y3, Y15, Y15
+	VPSHUFB ·blake3Rot16<>(SB), Y15, Y15
+	VPADDD Y15, Y11, Y11
+	VPXOR Y11, Y7, Y7
+	VPSRLD $12, Y7, Y4
+	VPSLLD $20, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y7, Y3, Y3
+	VPADDD 544(SP), Y3, Y3
+	VPXOR Y3, Y15, Y15
+	VPSHUFB ·blake3Rot8<>(SB), Y15, Y15
+	VPADDD Y15, Y11, Y11
+	VPXOR Y11, Y7, Y7
+	VPSRLD $7, Y7, Y4
+	VPSLLD $25, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y5, Y0, Y0
+	VPXOR Y0, Y15, Y15
+	VPSHUFB ·blake3Rot16<>(SB), Y15, Y15
+	VPADDD Y15, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPSRLD $12, Y5, Y4
+	VPSLLD $20, Y5, Y5
+	VPOR Y4, Y5, Y5
+	VPADDD Y5, Y0, Y0
+	VPADDD 608(SP), Y0, Y0
+	VPXOR Y0, Y15, Y15
+	VPSHUFB ·blake3Rot8<>(SB), Y15, Y15
+	VPADDD Y15, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPSRLD $7, Y5, Y4
+	VPSLLD $25, Y5, Y5
+	VPOR Y4, Y5, Y5
+	VPADDD Y6, Y1, Y1
+	VPADDD 512(SP), Y1, Y1
+	VPXOR Y1, Y12, Y12
+	VPSHUFB ·blake3Rot16<>(SB), Y12, Y12
+	VPADDD Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPSRLD $12, Y6, Y4
+	VPSLLD $20, Y6, Y6
+	VPOR Y4, Y6, Y6
+	VPADDD Y6, Y1, Y1
+	VPXOR Y1, Y12, Y12
+	VPSHUFB ·blake3Rot8<>(SB), Y12, Y12
+	VPADDD Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPSRLD $7, Y6, Y4
+	VPSLLD $25, Y6, Y6
+	VPOR Y4, Y6, Y6
+	VPADDD Y7, Y2, Y2
+	VPADDD 576(SP), Y2, Y2
+	VPXOR Y2, Y13, Y13
+	VPSHUFB ·blake3Rot16<>(SB), Y13, Y13
+	VPADDD Y13, Y8, Y8
+	VPXOR Y8, Y7, Y7
+	VPSRLD $12, Y7, Y4
+	VPSLLD $20, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y7, Y2, Y2
+	VPADDD 704(SP), Y2, Y2
+	VPXOR Y2, Y13, Y13
+	VPSHUFB ·blake3Rot8<>(SB), Y13, Y13
+	VPADDD Y13, Y8, Y8
+	VPXOR Y8, Y7, Y7
+	VPSRLD $7, Y7, Y4
+	VPSLLD $25, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VMOVDQU Y7, 224(SP)
+	VMOVDQU 128(SP), Y4
+	VPADDD Y4, Y3, Y3
+	VPADDD 640(SP), Y3, Y3
+	VPXOR Y3, Y14, Y14
+	VPSHUFB ·blake3Rot16<>(SB), Y14, Y14
+	VPADDD Y14, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPSRLD $12, Y4, Y7
+	VPSLLD $20, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y3, Y3
+	VPADDD 736(SP), Y3, Y3
+	VPXOR Y3, Y14, Y14
+	VPSHUFB ·blake3Rot8<>(SB), Y14, Y14
+	VPADDD Y14, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPSRLD $7, Y4, Y7
+	VPSLLD $25, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y0, Y0
+	VPXOR Y0, Y12, Y12
+	VPSHUFB ·blake3Rot16<>(SB), Y12, Y12
+	VPADDD Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPSRLD $12, Y4, Y7
+	VPSLLD $20, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y4, Y0, Y0
+	VPXOR Y0, Y12, Y12
+	VPSHUFB ·blake3Rot8<>(SB), Y12, Y12
+	VPADDD Y12, Y8, Y8
+	VPXOR Y8, Y4, Y4
+	VPSRLD $7, Y4, Y7
+	VPSLLD $25, Y4, Y4
+	VPOR Y7, Y4, Y4
+	VPADDD Y5, Y1, Y1
+	VPADDD 672(SP), Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPSHUFB ·blake3Rot16<>(SB), Y13, Y13
+	VPADDD Y13, Y9, Y9
+	VPXOR Y9, Y5, Y5
+	VPSRLD $12, Y5, Y7
+	VPSLLD $20, Y5, Y5
+	VPOR Y7, Y5, Y5
+	VPADDD Y5, Y1, Y1
+	VPADDD 512(SP), Y1, Y1
+	VPXOR Y1, Y13, Y13
+	VPSHUFB ·blake3Rot8<>(SB), Y13, Y13
+	VPADDD Y13, Y9, Y9
+	VPXOR Y9, Y5, Y5
+	VPSRLD $7, Y5, Y7
+	VPSLLD $25, Y5, Y5
+	VPOR Y7, Y5, Y5
+	VPADDD Y6, Y2, Y2
+	VPADDD 544(SP), Y2, Y2
+	VPXOR Y2, Y14, Y14
+	VPSHUFB ·blake3Rot16<>(SB), Y14, Y14
+	VPADDD Y14, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPSRLD $12, Y6, Y7
+	VPSLLD $20, Y6, Y6
+	VPOR Y7, Y6, Y6
+	VPADDD Y6, Y2, Y2
+	VPXOR Y2, Y14, Y14
+	VPSHUFB ·blake3Rot8<>(SB), Y14, Y14
+	VPADDD Y14, Y10, Y10
+	VPXOR Y10, Y6, Y6
+	VPSRLD $7, Y6, Y7
+	VPSLLD $25, Y6, Y6
+	VPOR Y7, Y6, Y6
+	VMOVDQU Y4, 128(SP)
+	VMOVDQU 224(SP), Y7
+	VPADDD Y7, Y3, Y3
+	VPXOR Y3, Y15, Y15
+	VPSHUFB ·blake3Rot16<>(SB), Y15, Y15
+	VPADDD Y15, Y11, Y11
+	VPXOR Y11, Y7, Y7
+	VPSRLD $12, Y7, Y4
+	VPSLLD $20, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y7, Y3, Y3
+	VPADDD 704(SP), Y3, Y3
+	VPXOR Y3, Y15, Y15
+	VPSHUFB ·blake3Rot8<>(SB), Y15, Y15
+	VPADDD Y15, Y11, Y11
+	VPXOR Y11, Y7, Y7
+	VPSRLD $7, Y7, Y4
+	VPSLLD $25, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y5, Y0, Y0
+	VPXOR Y0, Y15, Y15
+	VPSHUFB ·blake3Rot16<>(SB), Y15, Y15
+	VPADDD Y15, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPSRLD $12, Y5, Y4
+	VPSLLD $20, Y5, Y5
+	VPOR Y4, Y5, Y5
+	VPADDD Y5, Y0, Y0
+	VPXOR Y0, Y15, Y15
+	VPSHUFB ·blake3Rot8<>(SB), Y15, Y15
+	VPADDD Y15, Y10, Y10
+	VPXOR Y10, Y5, Y5
+	VPSRLD $7, Y5, Y4
+	VPSLLD $25, Y5, Y5
+	VPOR Y4, Y5, Y5
+	VPADDD Y6, Y1, Y1
+	VPADDD 576(SP), Y1, Y1
+	VPXOR Y1, Y12, Y12
+	VPSHUFB ·blake3Rot16<>(SB), Y12, Y12
+	VPADDD Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPSRLD $12, Y6, Y4
+	VPSLLD $20, Y6, Y6
+	VPOR Y4, Y6, Y6
+	VPADDD Y6, Y1, Y1
+	VPXOR Y1, Y12, Y12
+	VPSHUFB ·blake3Rot8<>(SB), Y12, Y12
+	VPADDD Y12, Y11, Y11
+	VPXOR Y11, Y6, Y6
+	VPSRLD $7, Y6, Y4
+	VPSLLD $25, Y6, Y6
+	VPOR Y4, Y6, Y6
+	VPADDD Y7, Y2, Y2
+	VPADDD 608(SP), Y2, Y2
+	VPXOR Y2, Y13, Y13
+	VPSHUFB ·blake3Rot16<>(SB), Y13, Y13
+	VPADDD Y13, Y8, Y8
+	VPXOR Y8, Y7, Y7
+	VPSRLD $12, Y7, Y4
+	VPSLLD $20, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VPADDD Y7, Y2, Y2
+	VPADDD 640(SP), Y2, Y2
+	VPXOR Y2, Y13, Y13
+	VPSHUFB ·blake3Rot8<>(SB), Y13, Y13
+	VPADDD Y13, Y8, Y8
+	VPXOR Y8, Y7, Y7
+	VPSRLD $7, Y7, Y4
+	VPSLLD $25, Y7, Y7
+	VPOR Y4, Y7, Y7
+	VMOVDQU Y0, 0(SP)
+	VMOVDQU 128(SP), Y4
+	VPADDD Y4, Y3, Y3
+	VPADDD 736(SP), Y3, Y3
+	VPXOR Y3, Y14, Y14
+	VPSHUFB ·blake3Rot16<>(SB), Y14, Y14
+	VPADDD Y14, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPSRLD $12, Y4, Y0
+	VPSLLD $20, Y4, Y4
+	VPOR Y0, Y4, Y4
+	VPADDD Y4, Y3, Y3
+	VPXOR Y3, Y14, Y14
+	VPSHUFB ·blake3Rot8<>(SB), Y14, Y14
+	VPADDD Y14, Y9, Y9
+	VPXOR Y9, Y4, Y4
+	VPSRLD $7, Y4, Y0
+	VPSLLD $25, Y4, Y4
+	VPOR Y0, Y4, Y4
+	VMOVDQU 0(SP), Y0
+	VPXOR Y8, Y0, Y0
+	VMOVDQU Y0, 512(SP)
+	VPXOR Y9, Y1, Y1
+	VMOVDQU Y1, 544(SP)
+	VPXOR Y10, Y2, Y2
+	VMOVDQU Y2, 576(SP)
+	VPXOR Y11, Y3, Y3
+	VMOVDQU Y3, 608(SP)
+	VPXOR Y12, Y4, Y4
+	VMOVDQU Y4, 640(SP)
+	VPXOR Y13, Y5, Y5
+	VMOVDQU Y5, 672(SP)
+	VPXOR Y14, Y6, Y6
+	VMOVDQU Y6, 704(SP)
+	VPXOR Y15, Y7, Y7
+	VMOVDQU Y7, 736(SP)
+	DECQ CX
+	JNZ  loop
+done:
+	VMOVDQU Y0, 0(DX)
+	VMOVDQU Y1, 32(DX)
+	VMOVDQU Y2, 64(DX)
+	VMOVDQU Y3, 96(DX)
+	VMOVDQU Y4, 128(DX)
+	VMOVDQU Y5, 160(DX)
+	VMOVDQU Y6, 192(DX)
+	VMOVDQU Y7, 224(DX)
+	VZEROUPPER
+	RET
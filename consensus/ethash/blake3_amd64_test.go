@@ -0,0 +1,46 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+//go:build amd64 && !gccgo
+// +build amd64,!gccgo
+
+package ethash
+
+import "testing"
+
+// Tests every vectorised kernel supported by the CPU, and the fallback.
+func TestBlake3BatchKernels(t *testing.T) {
+	defer func(avx512, avx2 bool) { useAVX512, useAVX2 = avx512, avx2 }(useAVX512, useAVX2)
+
+	kernels := []struct {
+		name         string
+		avx512, avx2 bool
+		supported    bool
+	}{
+		{"AVX512", true, false, useAVX512},
+		{"AVX2", false, true, useAVX2},
+		{"Fallback", false, false, true},
+	}
+	for _, k := range kernels {
+		if !k.supported {
+			t.Logf("%s: not supported by the CPU", k.name)
+			continue
+		}
+		useAVX512, useAVX2 = k.avx512, k.avx2
+		for _, iters := range []uint64{0, 1, 100} {
+			checkBlake3Batch(t, iters)
+		}
+	}
+}
@@ -0,0 +1,340 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build ignore
+// +build ignore
+
+// This program generates blake3_amd64.s, the vectorised Blake3 digest chains.
+// Every vector lane computes the chain of a different nonce, so the kernels are
+// straight transcriptions of the scalar compression in blake3.go, with each word
+// of the state held in its own vector register.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+)
+
+// schedule is the order the message words are consumed in by each round.
+var schedule = [7][16]int{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	{2, 6, 3, 10, 7, 0, 4, 13, 1, 11, 12, 5, 9, 14, 15, 8},
+	{3, 4, 10, 12, 13, 2, 7, 14, 6, 5, 9, 0, 11, 15, 8, 1},
+	{10, 7, 12, 9, 14, 3, 13, 15, 4, 0, 11, 2, 5, 8, 1, 6},
+	{12, 13, 9, 11, 15, 10, 14, 8, 7, 2, 5, 3, 0, 1, 6, 4},
+	{9, 14, 11, 5, 8, 12, 15, 1, 13, 3, 0, 10, 2, 6, 4, 7},
+	{11, 15, 5, 0, 1, 9, 8, 6, 14, 10, 2, 12, 3, 4, 7, 13},
+}
+
+// quarters are the state words mixed by the quarter rounds of a round, columns
+// first and diagonals second.
+var quarters = [8][4]int{
+	{0, 4, 8, 12}, {1, 5, 9, 13}, {2, 6, 10, 14}, {3, 7, 11, 15},
+	{0, 5, 10, 15}, {1, 6, 11, 12}, {2, 7, 8, 13}, {3, 4, 9, 14},
+}
+
+// mixes lists the quarter rounds of a whole compression in execution order.
+var mixes = func() (mixes [][4]int) {
+	for r := 0; r < len(schedule); r++ {
+		mixes = append(mixes, quarters[:]...)
+	}
+	return mixes
+}()
+
+var out bytes.Buffer
+
+func emit(format string, args ...interface{}) {
+	fmt.Fprintf(&out, "\t"+format+"\n", args...)
+}
+
+func label(name string) {
+	fmt.Fprintf(&out, "%s:\n", name)
+}
+
+func main() {
+	fmt.Fprintln(&out, "// Code generated by blake3_gen.go. DO NOT EDIT.")
+	fmt.Fprintln(&out)
+	fmt.Fprintln(&out, "//go:build amd64 && !gccgo")
+	fmt.Fprintln(&out, "// +build amd64,!gccgo")
+	fmt.Fprintln(&out)
+	fmt.Fprintln(&out, "#include \"textflag.h\"")
+	fmt.Fprintln(&out)
+	constants()
+	avx512()
+	avx2()
+
+	if err := os.WriteFile("blake3_amd64.s", out.Bytes(), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func constants() {
+	iv := []uint32{0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a, 0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19}
+	for i, w := range iv {
+		fmt.Fprintf(&out, "DATA ·blake3IV<>+0x%02x(SB)/4, $0x%08x\n", 4*i, w)
+	}
+	fmt.Fprintln(&out, "GLOBL ·blake3IV<>(SB), (NOPTR+RODATA), $32")
+	fmt.Fprintln(&out)
+
+	// Block lengths of the seed and of the chained rounds, and the domain flags
+	fmt.Fprintln(&out, "DATA ·blake3Params<>+0x00(SB)/4, $40")
+	fmt.Fprintln(&out, "DATA ·blake3Params<>+0x04(SB)/4, $32")
+	fmt.Fprintln(&out, "DATA ·blake3Params<>+0x08(SB)/4, $11")
+	fmt.Fprintln(&out, "GLOBL ·blake3Params<>(SB), (NOPTR+RODATA), $12")
+	fmt.Fprintln(&out)
+
+	// Byte shuffles rotating every 32 bit word right by 16 and by 8 bits
+	for i := 0; i < 2; i++ {
+		fmt.Fprintf(&out, "DATA ·blake3Rot16<>+0x%02x(SB)/8, $0x0504070601000302\n", 16*i)
+		fmt.Fprintf(&out, "DATA ·blake3Rot16<>+0x%02x(SB)/8, $0x0d0c0f0e09080b0a\n", 16*i+8)
+	}
+	fmt.Fprintln(&out, "GLOBL ·blake3Rot16<>(SB), (NOPTR+RODATA), $32")
+	fmt.Fprintln(&out)
+	for i := 0; i < 2; i++ {
+		fmt.Fprintf(&out, "DATA ·blake3Rot8<>+0x%02x(SB)/8, $0x0407060500030201\n", 16*i)
+		fmt.Fprintf(&out, "DATA ·blake3Rot8<>+0x%02x(SB)/8, $0x0c0f0e0d080b0a09\n", 16*i+8)
+	}
+	fmt.Fprintln(&out, "GLOBL ·blake3Rot8<>(SB), (NOPTR+RODATA), $32")
+	fmt.Fprintln(&out)
+}
+
+// chain emits the digest chain shared by both kernels: the compression of the
+// seed, followed by a loop compressing the previous digest iters times. The seed
+// consumes the message words 0-9, the chained rounds the words 0-7 only, all the
+// others being zero.
+func chain(compress func(length int, words int)) {
+	compress(0x00, 10)
+	emit("TESTQ CX, CX")
+	emit("JZ   done")
+	label("loop")
+	compress(0x04, 8)
+	emit("DECQ CX")
+	emit("JNZ  loop")
+	label("done")
+}
+
+// avx512 emits the 16 lane kernel. The state lives in Z0-Z15, the message words
+// in Z16-Z25, so no memory is touched besides the constants.
+//
+//	func blake3ChainAVX512(hash *blake3Words, nonces *[2][16]uint32, iters uint64, out *[8][16]uint32)
+func avx512() {
+	fmt.Fprintln(&out, "// func blake3ChainAVX512(hash *blake3Words, nonces *[2][16]uint32, iters uint64, out *[8][16]uint32)")
+	fmt.Fprintln(&out, "// Requires: AVX512F")
+	fmt.Fprintln(&out, "TEXT ·blake3ChainAVX512(SB), NOSPLIT, $0-32")
+	emit("MOVQ hash+0(FP), AX")
+	emit("MOVQ nonces+8(FP), BX")
+	emit("MOVQ iters+16(FP), CX")
+	emit("MOVQ out+24(FP), DX")
+	for i := 0; i < 8; i++ {
+		emit("VPBROADCASTD %d(AX), Z%d", 4*i, 16+i)
+	}
+	emit("VMOVDQU32 (BX), Z24")
+	emit("VMOVDQU32 64(BX), Z25")
+
+	chain(func(length int, words int) {
+		for i := 0; i < 8; i++ {
+			emit("VPBROADCASTD ·blake3IV<>+0x%02x(SB), Z%d", 4*i, i)
+		}
+		for i := 0; i < 4; i++ {
+			emit("VMOVDQA32 Z%d, Z%d", i, 8+i)
+		}
+		emit("VPXORD Z12, Z12, Z12")
+		emit("VPXORD Z13, Z13, Z13")
+		emit("VPBROADCASTD ·blake3Params<>+0x%02x(SB), Z14", length)
+		emit("VPBROADCASTD ·blake3Params<>+0x08(SB), Z15")
+
+		// The quarter rounds of a half round are independent, interleave them
+		for r := range schedule {
+			for half := 0; half < 2; half++ {
+				var (
+					ops [4][]string
+					n   int
+				)
+				for j := 0; j < 4; j++ {
+					q := quarters[4*half+j]
+					mx, my := schedule[r][8*half+2*j], schedule[r][8*half+2*j+1]
+					if ops[j] = mix512(q, mx, my, words); len(ops[j]) > n {
+						n = len(ops[j])
+					}
+				}
+				for k := 0; k < n; k++ {
+					for j := range ops {
+						if k < len(ops[j]) {
+							emit("%s", ops[j][k])
+						}
+					}
+				}
+			}
+		}
+		for i := 0; i < 8; i++ {
+			emit("VPXORD Z%d, Z%d, Z%d", 8+i, i, 16+i)
+		}
+	})
+	for i := 0; i < 8; i++ {
+		emit("VMOVDQU32 Z%d, %d(DX)", 16+i, 64*i)
+	}
+	emit("VZEROUPPER")
+	emit("RET")
+	fmt.Fprintln(&out)
+}
+
+// mix512 returns the instructions of a quarter round of the 16 lane kernel,
+// skipping the additions of the message words which are known to be zero.
+func mix512(q [4]int, mx, my int, words int) []string {
+	a, b, c, d := q[0], q[1], q[2], q[3]
+	ops := []string{fmt.Sprintf("VPADDD Z%d, Z%d, Z%d", b, a, a)}
+	if mx < words {
+		ops = append(ops, fmt.Sprintf("VPADDD Z%d, Z%d, Z%d", 16+mx, a, a))
+	}
+	ops = append(ops,
+		fmt.Sprintf("VPXORD Z%d, Z%d, Z%d", a, d, d),
+		fmt.Sprintf("VPRORD $16, Z%d, Z%d", d, d),
+		fmt.Sprintf("VPADDD Z%d, Z%d, Z%d", d, c, c),
+		fmt.Sprintf("VPXORD Z%d, Z%d, Z%d", c, b, b),
+		fmt.Sprintf("VPRORD $12, Z%d, Z%d", b, b),
+		fmt.Sprintf("VPADDD Z%d, Z%d, Z%d", b, a, a),
+	)
+	if my < words {
+		ops = append(ops, fmt.Sprintf("VPADDD Z%d, Z%d, Z%d", 16+my, a, a))
+	}
+	return append(ops,
+		fmt.Sprintf("VPXORD Z%d, Z%d, Z%d", a, d, d),
+		fmt.Sprintf("VPRORD $8, Z%d, Z%d", d, d),
+		fmt.Sprintf("VPADDD Z%d, Z%d, Z%d", d, c, c),
+		fmt.Sprintf("VPXORD Z%d, Z%d, Z%d", c, b, b),
+		fmt.Sprintf("VPRORD $7, Z%d, Z%d", b, b),
+	)
+}
+
+// avx2 emits the 8 lane kernel. The state occupies all sixteen of Y0-Y15, so
+// the message words are kept on the stack, and the rotations which can't be done
+// by byte shuffles borrow the register of a state word spilled for the while.
+// The spilled word is the one whose next use is the furthest away.
+//
+//	func blake3ChainAVX2(hash *blake3Words, nonces *[2][8]uint32, iters uint64, out *[8][8]uint32)
+func avx2() {
+	const (
+		stateSlots = 0   // Stack offset of the spilled state words
+		msgSlots   = 512 // Stack offset of the message words 0-7
+	)
+	msg := func(i int) string {
+		switch i {
+		case 8:
+			return "(BX)"
+		case 9:
+			return "32(BX)"
+		}
+		return fmt.Sprintf("%d(SP)", msgSlots+32*i)
+	}
+	fmt.Fprintln(&out, "// func blake3ChainAVX2(hash *blake3Words, nonces *[2][8]uint32, iters uint64, out *[8][8]uint32)")
+	fmt.Fprintln(&out, "// Requires: AVX2")
+	fmt.Fprintln(&out, "TEXT ·blake3ChainAVX2(SB), 0, $768-32")
+	emit("MOVQ hash+0(FP), AX")
+	emit("MOVQ nonces+8(FP), BX")
+	emit("MOVQ iters+16(FP), CX")
+	emit("MOVQ out+24(FP), DX")
+	for i := 0; i < 8; i++ {
+		emit("VPBROADCASTD %d(AX), Y0", 4*i)
+		emit("VMOVDQU Y0, %s", msg(i))
+	}
+	chain(func(length int, words int) {
+		for i := 0; i < 8; i++ {
+			emit("VPBROADCASTD ·blake3IV<>+0x%02x(SB), Y%d", 4*i, i)
+		}
+		for i := 0; i < 4; i++ {
+			emit("VMOVDQA Y%d, Y%d", i, 8+i)
+		}
+		emit("VPXOR Y12, Y12, Y12")
+		emit("VPXOR Y13, Y13, Y13")
+		emit("VPBROADCASTD ·blake3Params<>+0x%02x(SB), Y14", length)
+		emit("VPBROADCASTD ·blake3Params<>+0x08(SB), Y15")
+
+		// nextUse returns the index of the quarter round using the state word
+		// next, the finalisation counting as a use of all of them.
+		nextUse := func(from int, word int) int {
+			for k := from; k < len(mixes); k++ {
+				for _, w := range mixes[k] {
+					if w == word {
+						return k
+					}
+				}
+			}
+			return len(mixes)
+		}
+		spilled := -1
+		for k, q := range mixes {
+			uses := func(w int) bool { return w == q[0] || w == q[1] || w == q[2] || w == q[3] }
+			if spilled == -1 || uses(spilled) {
+				victim, furthest := -1, -1
+				for w := 0; w < 16; w++ {
+					if uses(w) || w == spilled {
+						continue
+					}
+					if next := nextUse(k, w); next > furthest {
+						victim, furthest = w, next
+					}
+				}
+				emit("VMOVDQU Y%d, %d(SP)", victim, stateSlots+32*victim)
+				if spilled != -1 {
+					emit("VMOVDQU %d(SP), Y%d", stateSlots+32*spilled, spilled)
+				}
+				spilled = victim
+			}
+			r, j := k/8, k%8
+			mix2(q, msg, schedule[r][2*j], schedule[r][2*j+1], words, spilled)
+		}
+		emit("VMOVDQU %d(SP), Y%d", stateSlots+32*spilled, spilled)
+		for i := 0; i < 8; i++ {
+			emit("VPXOR Y%d, Y%d, Y%d", 8+i, i, i)
+			emit("VMOVDQU Y%d, %s", i, msg(i))
+		}
+	})
+	for i := 0; i < 8; i++ {
+		emit("VMOVDQU Y%d, %d(DX)", i, 32*i)
+	}
+	emit("VZEROUPPER")
+	emit("RET")
+}
+
+// mix2 emits a quarter round of the 8 lane kernel, using the register of the
+// given state word as temporary.
+func mix2(q [4]int, msg func(int) string, mx, my int, words int, tmp int) {
+	a, b, c, d := q[0], q[1], q[2], q[3]
+	emit("VPADDD Y%d, Y%d, Y%d", b, a, a)
+	if mx < words {
+		emit("VPADDD %s, Y%d, Y%d", msg(mx), a, a)
+	}
+	emit("VPXOR Y%d, Y%d, Y%d", a, d, d)
+	emit("VPSHUFB ·blake3Rot16<>(SB), Y%d, Y%d", d, d)
+	emit("VPADDD Y%d, Y%d, Y%d", d, c, c)
+	emit("VPXOR Y%d, Y%d, Y%d", c, b, b)
+	emit("VPSRLD $12, Y%d, Y%d", b, tmp)
+	emit("VPSLLD $20, Y%d, Y%d", b, b)
+	emit("VPOR Y%d, Y%d, Y%d", tmp, b, b)
+	emit("VPADDD Y%d, Y%d, Y%d", b, a, a)
+	if my < words {
+		emit("VPADDD %s, Y%d, Y%d", msg(my), a, a)
+	}
+	emit("VPXOR Y%d, Y%d, Y%d", a, d, d)
+	emit("VPSHUFB ·blake3Rot8<>(SB), Y%d, Y%d", d, d)
+	emit("VPADDD Y%d, Y%d, Y%d", d, c, c)
+	emit("VPXOR Y%d, Y%d, Y%d", c, b, b)
+	emit("VPSRLD $7, Y%d, Y%d", b, tmp)
+	emit("VPSLLD $25, Y%d, Y%d", b, b)
+	emit("VPOR Y%d, Y%d, Y%d", tmp, b, b)
+}
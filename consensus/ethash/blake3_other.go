@@ -0,0 +1,28 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !amd64 || gccgo
+// +build !amd64 gccgo
+
+package ethash
+
+// blake3ChainBatch computes the proof-of-work digests of consecutive nonces,
+// starting at the given one, as many at once as the vector units of the CPU
+// allow. The number of digests written to out is returned.
+func blake3ChainBatch(hash *blake3Words, nonce uint64, iters uint64, out *[blake3MaxLanes]blake3Words) int {
+	out[0] = blake3Chain(hash, nonce, iters)
+	return 1
+}
@@ -0,0 +1,142 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+package ethash
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// checkBlake3Batch verifies the digests of a batch of nonces against the plain
+// Blake3 implementation.
+func checkBlake3Batch(t *testing.T, iters uint64) {
+	t.Helper()
+
+	sealHash := common.HexToHash("0x5a3c1f0e7d9b2a4c6e8f1a3b5c7d9e0f2a4b6c8d0e1f3a5b7c9d0e2f4a6b8c0d")
+	hash := toWords(sealHash[:])
+
+	// Start right below a 32 bit boundary, so the lanes straddle a carry into the
+	// high word of the nonce
+	var digests [blake3MaxLanes]blake3Words
+	for _, start := range []uint64{0, 1<<32 - 3, ^uint64(0) - 20} {
+		n := blake3ChainBatch(&hash, start, iters, &digests)
+		for l := 0; l < n; l++ {
+			have := make([]byte, 32)
+			fromWords(&digests[l], have)
+			if want := hashimotoBlake3(sealHash[:], start+uint64(l), iters); !bytes.Equal(have, want) {
+				t.Errorf("iters %d, nonce %#x: digest mismatch: have %x, want %x", iters, start+uint64(l), have, want)
+			}
+		}
+	}
+}
+
+// Tests that the Blake3 kernels compute the same digests as the blake3 package.
+func TestBlake3Kernels(t *testing.T) {
+	sealHash := common.HexToHash("0xdeadbeef")
+	hash := toWords(sealHash[:])
+
+	for _, iters := range []uint64{0, 1, 2, 100} {
+		for nonce := uint64(0); nonce < 4; nonce++ {
+			have := make([]byte, 32)
+			digest := blake3Chain(&hash, nonce, iters)
+			fromWords(&digest, have)
+			if want := hashimotoBlake3(sealHash[:], nonce, iters); !bytes.Equal(have, want) {
+				t.Errorf("iters %d, nonce %d: scalar digest mismatch: have %x, want %x", iters, nonce, have, want)
+			}
+		}
+		checkBlake3Batch(t, iters)
+	}
+}
+
+// Tests that the batched search finds the first nonce meeting the target and
+// accounts for all the attempts made.
+func TestSearchBlake3(t *testing.T) {
+	var (
+		sealHash = common.HexToHash("0x1234")
+		target   = new(big.Int).Div(two256, big.NewInt(100))
+		attempts int64
+	)
+	nonce, digest, ok := searchBlake3(sealHash, 10, target, 0, 1<<15, nil, func(n int64) { attempts += n })
+	if !ok {
+		t.Fatalf("no nonce found")
+	}
+	for i := uint64(0); i < nonce; i++ {
+		if Blake3Digest(sealHash, i, 10).Big().Cmp(target) <= 0 {
+			t.Fatalf("nonce %d skipped", i)
+		}
+	}
+	if want := Blake3Digest(sealHash, nonce, 10); digest != want {
+		t.Fatalf("digest mismatch: have %x, want %x", digest, want)
+	}
+	if attempts != int64(nonce)+1 {
+		t.Fatalf("attempts mismatch: have %d, want %d", attempts, nonce+1)
+	}
+	// Difficulty 1 is met by any digest, including the ones above 2^255
+	if nonce, _, ok := searchBlake3(sealHash, 10, two256, 7, 1, nil, func(int64) {}); !ok || nonce != 7 {
+		t.Fatalf("minimal difficulty search mismatch: have %d, want 7", nonce)
+	}
+	abort := make(chan struct{})
+	close(abort)
+	if _, _, ok := searchBlake3(sealHash, 10, big.NewInt(0), 0, 1, abort, func(int64) {}); ok {
+		t.Fatalf("aborted search succeeded")
+	}
+}
+
+// Benchmarks the nonce search using the blake3 package for every nonce, the way
+// the sealer used to, against the batched kernels.
+func BenchmarkBlake3Search(b *testing.B) {
+	const iters = 1000
+	var (
+		sealHash = common.HexToHash("0x1234")
+		target   = big.NewInt(0) // Unreachable, every nonce is searched
+	)
+	b.Run("Sum256", func(b *testing.B) {
+		hash := sealHash.Bytes()
+		pow := func(nonce uint64) ([]byte, []byte) {
+			digest := hashimotoBlake3(hash, nonce, iters)
+			return digest, digest
+		}
+		benchmarkSearch(b, func(abort chan struct{}, mark func(int64)) {
+			search(pow, target, 0, 1, abort, mark)
+		})
+	})
+	b.Run("Batched", func(b *testing.B) {
+		benchmarkSearch(b, func(abort chan struct{}, mark func(int64)) {
+			searchBlake3(sealHash, iters, target, 0, 1, abort, mark)
+		})
+	})
+}
+
+// benchmarkSearch runs a nonce search until b.N nonces are hashed, reporting the
+// hashrate.
+func benchmarkSearch(b *testing.B, run func(abort chan struct{}, mark func(int64))) {
+	var (
+		abort    = make(chan struct{})
+		attempts int64
+	)
+	b.ReportAllocs()
+	b.ResetTimer()
+	run(abort, func(n int64) {
+		if attempts += n; attempts >= int64(b.N) && abort != nil {
+			close(abort)
+			abort = nil
+		}
+	})
+	b.ReportMetric(float64(attempts)/b.Elapsed().Seconds(), "hashes/s")
+}
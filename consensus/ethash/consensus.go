@@ -20,10 +20,8 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"log"
 	"math/big"
 	"runtime"
-	"strings"
 	"time"
 
 	mapset "github.com/deckarep/golang-set/v2"
@@ -349,6 +347,8 @@ func (ethash *Ethash) CalcDifficulty(chain consensus.ChainHeaderReader, time uin
 func CalcDifficulty(config *params.ChainConfig, time uint64, parent *types.Header) *big.Int {
 	next := new(big.Int).Add(parent.Number, big1)
 	switch {
+	case config.LibertySchedule().IsLibertyDifficulty(next):
+		return calcDifficultyLiberty(config.LibertySchedule(), time, parent)
 	case config.IsGrayGlacier(next):
 		return calcDifficultyEip5133(time, parent)
 	case config.IsArrowGlacier(next):
@@ -524,56 +524,222 @@ func calcDifficultyFrontier(time uint64, parent *types.Header) *big.Int {
 	return diff
 }
 
+// Fixed point parameters of the exponential in the Liberty difficulty adjustment.
+const (
+	asertFracBits = 16
+	asertRadix    = 1 << asertFracBits
+)
+
+var (
+	asertPoly1   = big.NewInt(195766423245049) // Coefficients of the cubic approximating 2^x-1 on [0, 1)
+	asertPoly2   = big.NewInt(971821376)
+	asertPoly3   = big.NewInt(5127)
+	asertPolyRnd = new(big.Int).Lsh(big1, 47)
+)
+
+// calcDifficultyLiberty is the difficulty adjustment algorithm of the Liberty
+// network. It is a relative, per-block variant of ASERT (absolutely scheduled
+// exponentially rising targets): every block scales its parent's difficulty by
+//
+//	2^((target - (time - parent.time)) / (target * window))
+//
+// Unlike the absolute form, the exponent is not measured against an anchor
+// block but against each parent. Every step is evaluated in fixed point and
+// rounded down, the exponent as well as the power of two and the product, so
+// the factors of consecutive blocks only approximately multiply up. The errors
+// accumulate along the chain, biasing the difficulty slightly downwards, and
+// the difficulty at any height depends on the individual block times, not only
+// on how far the chain runs ahead of or behind its schedule. Clamping to the
+// minimum difficulty loses the history as well. There is no difficulty bomb
+// and uncles do not influence the adjustment.
+func calcDifficultyLiberty(config *params.LibertyConfig, time uint64, parent *types.Header) *big.Int {
+	// Timestamps are strictly increasing, which keeps the exponent below one.
+	// Enforce it for callers not verifying the header beforehand.
+	solvetime := uint64(1)
+	if time > parent.Time {
+		solvetime = time - parent.Time
+	}
+	target := new(big.Int).SetUint64(config.BlockTime())
+
+	// exponent = (target - solvetime) / (target * window), in 16.16 fixed point
+	exponent := new(big.Int).Sub(target, new(big.Int).SetUint64(solvetime))
+	exponent.Lsh(exponent, asertFracBits)
+	exponent.Div(exponent, new(big.Int).Mul(target, new(big.Int).SetUint64(config.Window())))
+
+	// Split the exponent into whole shifts and the fractional remainder, the
+	// latter approximated by a cubic polynomial
+	shifts := new(big.Int).Rsh(exponent, asertFracBits)
+	frac := new(big.Int).Sub(exponent, new(big.Int).Lsh(shifts, asertFracBits))
+
+	factor := new(big.Int).Mul(asertPoly1, frac)
+	x := new(big.Int).Mul(frac, frac)
+	factor.Add(factor, x.Mul(x, asertPoly2))
+	x.Mul(frac, frac)
+	x.Mul(x, frac)
+	factor.Add(factor, x.Mul(x, asertPoly3))
+	factor.Add(factor, asertPolyRnd)
+	factor.Rsh(factor, 48)
+	factor.Add(factor, big.NewInt(asertRadix))
+
+	diff := new(big.Int).Mul(parent.Difficulty, factor)
+	diff.Rsh(diff, asertFracBits)
+
+	if shifts.Neg(shifts); shifts.Cmp(big.NewInt(int64(diff.BitLen()))) >= 0 {
+		diff.SetUint64(0)
+	} else {
+		diff.Rsh(diff, uint(shifts.Uint64()))
+	}
+	if diff.Cmp(params.MinimumDifficulty) < 0 {
+		diff.Set(params.MinimumDifficulty)
+	}
+	return diff
+}
+
 // Exported for fuzzing
 var FrontierDifficultyCalculator = calcDifficultyFrontier
 var HomesteadDifficultyCalculator = calcDifficultyHomestead
 var DynamicDifficultyCalculator = makeDifficultyCalculator
 
+// verifySeal checks whether a block satisfies the PoW difficulty requirements,
+// using the Blake3 iteration count scheduled for the block in the chain config.
+// Engines running in legacy mode verify the original Ethash seal instead.
 func (ethash *Ethash) verifySeal(chain consensus.ChainHeaderReader, header *types.Header, fulldag bool) error {
-	// Check if the complexity is correct
+	// If we're running a fake PoW, accept any seal as valid
+	if ethash.config.PowMode == ModeFake || ethash.config.PowMode == ModeFullFake {
+		time.Sleep(ethash.fakeDelay)
+		if ethash.fakeFail == header.Number.Uint64() {
+			return errInvalidPoW
+		}
+		return nil
+	}
+	// If we're running a shared PoW, delegate verification to it
+	if ethash.shared != nil {
+		return ethash.shared.verifySeal(chain, header, fulldag)
+	}
+	defer sealVerifyTimer.UpdateSince(time.Now())
+
+	if ethash.config.PowMode.legacy() {
+		return ethash.verifyLegacySeal(header, fulldag)
+	}
+	return ethash.verifyBlake3Seal(header, powIterations(chain, header.Number))
+}
+
+// verifyLegacySeal checks whether a block satisfies the original Ethash PoW
+// difficulty requirements, either using the usual ethash cache for it, or
+// alternatively using a full DAG to make remote mining fast.
+func (ethash *Ethash) verifyLegacySeal(header *types.Header, fulldag bool) error {
+	// Ensure that we have a valid difficulty for the block
 	if header.Difficulty.Sign() <= 0 {
 		return errInvalidDifficulty
 	}
+	// Recompute the digest and PoW values
+	number := header.Number.Uint64()
 
-	// Get SealHash (header without Nonce and MixDigest)
-	sealHash := ethash.SealHash(header).Bytes()
-
-	ethash.config.Log.Debug("SealHash in verifySeal", "sealHash", common.Bytes2Hex(sealHash))
+	var (
+		digest []byte
+		result []byte
+	)
+	// If fast-but-heavy PoW verification was requested, use an ethash dataset
+	if fulldag {
+		dataset := ethash.dataset(number, true)
+		if dataset.generated() {
+			digest, result = hashimotoFull(dataset.dataset, ethash.SealHash(header).Bytes(), header.Nonce.Uint64())
+
+			// Datasets are unmapped in a finalizer. Ensure that the dataset stays alive
+			// until after the call to hashimotoFull so it's not unmapped while being used.
+			runtime.KeepAlive(dataset)
+		} else {
+			// Dataset not yet generated, don't hang, use a cache instead
+			fulldag = false
+		}
+	}
+	// If slow-but-light PoW verification was requested (or DAG not yet ready), use an ethash cache
+	if !fulldag {
+		cache := ethash.cache(number)
 
-	// Concatenate SealHash and Nonce
-	var buffer bytes.Buffer
-	buffer.Write(sealHash)
-	buffer.Write(header.Nonce[:])
+		size := datasetSize(number)
+		if ethash.config.PowMode == ModeLegacyTest {
+			size = 32 * 1024
+		}
+		digest, result = hashimotoLight(size, cache.cache, ethash.SealHash(header).Bytes(), header.Nonce.Uint64())
 
-	ethash.config.Log.Debug("Nonce in verifySeal", "nonce", header.Nonce)
+		// Caches are unmapped in a finalizer. Ensure that the cache stays alive
+		// until after the call to hashimotoLight so it's not unmapped while being used.
+		runtime.KeepAlive(cache)
+	}
+	// Verify the calculated values against the ones provided in the header
+	if !bytes.Equal(header.MixDigest[:], digest) {
+		return errInvalidMixDigest
+	}
+	target := new(big.Int).Div(two256, header.Difficulty)
+	if new(big.Int).SetBytes(result).Cmp(target) > 0 {
+		return errInvalidPoW
+	}
+	return nil
+}
 
-	hashResult := blake3.Sum256(buffer.Bytes())
-	for i := 0; i < 312688; i++ {
-		if i == 0 {
+// sealCacheKey identifies a seal that already passed verification. The seal
+// hash commits to the difficulty, so a cached seal also meets its target.
+type sealCacheKey struct {
+	sealHash  common.Hash
+	nonce     types.BlockNonce
+	mixDigest common.Hash
+	iters     uint64
+}
 
-			ethash.config.Log.Debug("First iteration of Blake3 hashing in verifySeal", "iteration", i, "hashResult", common.Bytes2Hex(hashResult[:]))
-		}
-		hashResult = blake3.Sum256(hashResult[:])
+// verifyBlake3Seal checks whether a block satisfies the PoW difficulty
+// requirements with the given number of Blake3 iterations. Successfully
+// verified seals are cached, so re-verifying them is free.
+func (ethash *Ethash) verifyBlake3Seal(header *types.Header, iters uint64) error {
+	// Check if the complexity is correct
+	if header.Difficulty.Sign() <= 0 {
+		return errInvalidDifficulty
+	}
+	// Skip the expensive rounds if the seal was already verified
+	key := sealCacheKey{
+		sealHash:  ethash.SealHash(header),
+		nonce:     header.Nonce,
+		mixDigest: header.MixDigest,
+		iters:     iters,
+	}
+	if ethash.seals != nil && ethash.seals.Contains(key) {
+		return nil
 	}
+	// Recompute the digest from the seal hash (header without nonce and mix digest)
+	sealHash := key.sealHash.Bytes()
+	digest := hashimotoBlake3(sealHash, header.Nonce.Uint64(), iters)
 
-	ethash.config.Log.Debug("Final MixDigest in verifySeal", "mixDigest", common.Bytes2Hex(hashResult[:]))
+	ethash.config.Log.Debug("Computed Blake3 digest in verifySeal", "sealHash", common.Bytes2Hex(sealHash), "nonce", header.Nonce, "iterations", iters, "mixDigest", common.Bytes2Hex(digest))
 
-	if !bytes.Equal(header.MixDigest[:], hashResult[:]) {
-		ethash.config.Log.Warn("MixDigest mismatch in verifySeal", "expectedMixDigest", common.Bytes2Hex(header.MixDigest[:]), "calculatedMixDigest", common.Bytes2Hex(hashResult[:]))
+	if !bytes.Equal(header.MixDigest[:], digest) {
+		ethash.config.Log.Warn("MixDigest mismatch in verifySeal", "expectedMixDigest", common.Bytes2Hex(header.MixDigest[:]), "calculatedMixDigest", common.Bytes2Hex(digest))
 		return errInvalidMixDigest
 	}
 
 	target := new(big.Int).Div(two256, header.Difficulty)
-	if new(big.Int).SetBytes(hashResult[:]).Cmp(target) > 0 {
-		ethash.config.Log.Warn("Proof-of-work is invalid in verifySeal", "target", target, "calculatedHash", common.Bytes2Hex(hashResult[:]))
+	if new(big.Int).SetBytes(digest).Cmp(target) > 0 {
+		ethash.config.Log.Warn("Proof-of-work is invalid in verifySeal", "target", target, "calculatedHash", common.Bytes2Hex(digest))
 		return errInvalidPoW
 	}
 
-	ethash.config.Log.Debug("Successfully verified seal", "sealHash", common.Bytes2Hex(sealHash), "nonce", header.Nonce, "mixDigest", common.Bytes2Hex(hashResult[:]))
+	ethash.config.Log.Debug("Successfully verified seal", "sealHash", common.Bytes2Hex(sealHash), "nonce", header.Nonce, "mixDigest", common.Bytes2Hex(digest))
 
+	if ethash.seals != nil {
+		ethash.seals.Add(key, struct{}{})
+	}
 	return nil
 }
 
+// powIterations returns the number of Blake3 rounds the seal of the block with
+// the given number is computed with. Without a chain to consult, the default
+// iteration count is assumed.
+func powIterations(chain consensus.ChainHeaderReader, number *big.Int) uint64 {
+	if chain == nil {
+		return params.DefaultBlake3Iterations
+	}
+	return chain.Config().Ethash.Blake3Iterations(number)
+}
+
 // Prepare implements consensus.Engine, initializing the difficulty field of a
 // header to conform to the ethash protocol. The changes are done inline.
 func (ethash *Ethash) Prepare(chain consensus.ChainHeaderReader, header *types.Header) error {
@@ -585,11 +751,10 @@ func (ethash *Ethash) Prepare(chain consensus.ChainHeaderReader, header *types.H
 	return nil
 }
 
+// Finalize implements consensus.Engine, accumulating the block and uncle rewards.
 func (ethash *Ethash) Finalize(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header, withdrawals []*types.Withdrawal) {
-
-	log.Printf("Liberty Project: Finalizing rewards for block %d", header.Number.Uint64())
-
-	ethash.accumulateRewards(header, state, &txs, uncles)
+	ethash.config.Log.Trace("Finalizing block rewards", "number", header.Number)
+	ethash.accumulateRewards(chain.Config(), header, state, &txs, uncles)
 }
 
 // FinalizeAndAssemble implements consensus.Engine, accumulating the block and
@@ -607,7 +772,7 @@ func (ethash *Ethash) FinalizeAndAssemble(
 		return nil, errors.New("ethash does not support withdrawals")
 	}
 
-	ethash.accumulateRewards(header, state, &txs, uncles)
+	ethash.accumulateRewards(chain.Config(), header, state, &txs, uncles)
 
 	header.Root = state.IntermediateRoot(chain.Config().IsEIP158(header.Number))
 
@@ -646,60 +811,100 @@ func (ethash *Ethash) SealHash(header *types.Header) (hash common.Hash) {
 
 // Some weird constants to avoid constant memory allocs for them.
 var (
-	big8  = big.NewInt(8)
-	big32 = big.NewInt(32)
+	big8 = big.NewInt(8)
+)
+
+// Kinds of balance credits issued by a block.
+const (
+	RewardMiner   = "miner"   // Block reward of the coinbase, including any nephew bonus
+	RewardUncle   = "uncle"   // Reward of an included uncle's coinbase
+	RewardDevFund = "devFund" // Share of a dev-fund recipient
+	RewardStaking = "staking" // Share of a staking recipient
 )
 
-func (ethash *Ethash) accumulateRewards(header *types.Header, state *state.StateDB, txs *[]*types.Transaction, uncles []*types.Header) {
+// Reward is a single balance credit issued by a block.
+type Reward struct {
+	Kind    string
+	Address common.Address
+	Amount  *big.Int
+}
+
+// BlockRewards returns the balance credits issued by a block, in the order they
+// are applied to the state. The coinbase of the block earns the miner reward of
+// the epoch configured for the block in the chain config, and the dev-fund and
+// staking shares are split between the recipients active at the block. After
+// the uncle reward fork, the coinbase of each included uncle is rewarded too,
+// scaled down by its depth, and the including block earns a nephew bonus per
+// uncle.
+func BlockRewards(config *params.ChainConfig, header *types.Header, uncles []*types.Header) []Reward {
 	blockNumber := header.Number.Uint64()
-	epoch := shared.GetCurrentEpoch(blockNumber)
-
-	// Get addresses from shared package
-	developerAddresses := shared.GetDeveloperAddresses()
-	stakingAddresses := shared.GetStakingAddresses()
-
-	// Miner reward allocation
-	state.AddBalance(header.Coinbase, epoch.MinerReward)
-
-	// Developer reward distribution with remainder handling
-	if len(developerAddresses) > 0 {
-		share := new(big.Int).Div(epoch.DevFund, big.NewInt(int64(len(developerAddresses))))
-		remainder := new(big.Int).Mod(epoch.DevFund, big.NewInt(int64(len(developerAddresses))))
-
-		for i, addr := range developerAddresses {
-			// Add remainder to the first wallet
-			if i == 0 {
-				state.AddBalance(addr, new(big.Int).Add(share, remainder))
-			} else {
-				state.AddBalance(addr, share)
-			}
+	epoch := shared.GetCurrentEpoch(config, blockNumber)
+
+	// Get the recipients active at this height from the chain config
+	recipients := shared.GetRecipients(config, blockNumber)
+
+	// Accumulate the rewards for the miner and any included uncles
+	var rewards []Reward
+
+	reward := new(big.Int).Set(epoch.MinerReward)
+	if schedule := config.LibertySchedule(); schedule.IsUncleRewards(header.Number) {
+		nephewDivisor := new(big.Int).SetUint64(schedule.NephewDivisor())
+
+		for _, uncle := range uncles {
+			r := new(big.Int).Add(uncle.Number, big8)
+			r.Sub(r, header.Number)
+			r.Mul(r, epoch.MinerReward)
+			r.Div(r, big8)
+			rewards = append(rewards, Reward{Kind: RewardUncle, Address: uncle.Coinbase, Amount: r})
+
+			reward.Add(reward, new(big.Int).Div(epoch.MinerReward, nephewDivisor))
 		}
 	}
+	rewards = append(rewards, Reward{Kind: RewardMiner, Address: header.Coinbase, Amount: reward})
 
-	// Staking reward distribution with remainder handling
-	if epoch.StakingReward.Cmp(big.NewInt(0)) > 0 && len(stakingAddresses) > 0 {
-		share := new(big.Int).Div(epoch.StakingReward, big.NewInt(int64(len(stakingAddresses))))
-		remainder := new(big.Int).Mod(epoch.StakingReward, big.NewInt(int64(len(stakingAddresses))))
-
-		for i, addr := range stakingAddresses {
-			// Add remainder to the first wallet
-			if i == 0 {
-				state.AddBalance(addr, new(big.Int).Add(share, remainder))
-			} else {
-				state.AddBalance(addr, share)
-			}
+	// Developer reward distribution by recipient weight
+	for _, payout := range shared.DistributeReward(epoch.DevFund, recipients.DevFund) {
+		rewards = append(rewards, Reward{Kind: RewardDevFund, Address: payout.Address, Amount: payout.Amount})
+	}
+	// Staking reward distribution by recipient weight
+	if epoch.StakingReward.Sign() > 0 {
+		for _, payout := range shared.DistributeReward(epoch.StakingReward, recipients.Staking) {
+			rewards = append(rewards, Reward{Kind: RewardStaking, Address: payout.Address, Amount: payout.Amount})
 		}
 	}
+	return rewards
+}
+
+// BlockReward returns the total of the balance credits issued by a block, as
+// returned by BlockRewards. It satisfies core.RewardFunc.
+func BlockReward(config *params.ChainConfig, header *types.Header, uncles []*types.Header) *big.Int {
+	total := new(big.Int)
+	for _, reward := range BlockRewards(config, header, uncles) {
+		total.Add(total, reward.Amount)
+	}
+	return total
+}
 
-	// Reward logging for monitoring
+// accumulateRewards credits the rewards issued by the given block, as returned
+// by BlockRewards, to the state.
+func (ethash *Ethash) accumulateRewards(config *params.ChainConfig, header *types.Header, state *state.StateDB, txs *[]*types.Transaction, uncles []*types.Header) {
+	for _, reward := range BlockRewards(config, header, uncles) {
+		state.AddBalance(reward.Address, reward.Amount)
+	}
+	blockNumber := header.Number.Uint64()
+	epoch := shared.GetCurrentEpoch(config, blockNumber)
+
+	// Export the rewards of the active epoch for monitoring
 	minerReward := weiToCoins(epoch.MinerReward)
 	devFund := weiToCoins(epoch.DevFund)
 	stakingReward := weiToCoins(epoch.StakingReward)
 
-	timestamp := time.Now().Format("01-02|15:04:05.000")
-	line := fmt.Sprintf("Epoch: %s | Total Miner Reward: %.4f | Developer Fund: %.4f | Staking Reward: %.4f",
-		strings.ToUpper(epoch.Name), minerReward, devFund, stakingReward)
-	fmt.Printf("INFO [%s] %s\n", timestamp, line)
+	minerRewardGauge.Update(minerReward)
+	devFundRewardGauge.Update(devFund)
+	stakingRewardGauge.Update(stakingReward)
+
+	ethash.config.Log.Debug("Accumulated block rewards", "number", blockNumber, "epoch", epoch.Name,
+		"miner", minerReward, "devfund", devFund, "staking", stakingReward, "uncles", len(uncles))
 }
 
 func decodeAddresses(encodedAddresses []string) []common.Address {
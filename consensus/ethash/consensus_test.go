@@ -28,6 +28,8 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/params"
 )
@@ -139,6 +141,101 @@ func TestDifficultyCalculators(t *testing.T) {
 	}
 }
 
+// Tests that block, uncle and nephew rewards are accounted according to the
+// reward epoch of the including block, across epoch boundaries and the uncle
+// reward fork.
+func TestAccumulateRewards(t *testing.T) {
+	// lbt returns the given amount of tenths of a coin in wei
+	lbt := func(tenths int64) *big.Int {
+		return new(big.Int).Mul(big.NewInt(tenths), big.NewInt(params.Ether/10))
+	}
+	var (
+		config     = *params.AllEthashProtocolChanges
+		recipients = params.DefaultLibertyConfig.Recipients[0]
+
+		miner  = common.Address{0xaa}
+		uncle1 = common.Address{0xbb}
+		uncle2 = common.Address{0xcc}
+	)
+	config.Liberty = &params.LibertyConfig{
+		Epochs:           params.DefaultLibertyConfig.Epochs,
+		UncleRewardBlock: big.NewInt(500001),
+	}
+	uncle := func(number int64, coinbase common.Address) *types.Header {
+		return &types.Header{Number: big.NewInt(number), Coinbase: coinbase}
+	}
+	tests := []struct {
+		number  int64
+		uncles  []*types.Header
+		miner   *big.Int
+		uncle1  *big.Int
+		uncle2  *big.Int
+		devFund *big.Int // per dev-fund recipient
+		staking *big.Int // per staking recipient
+	}{
+		// Last block of the Freedom epoch, before the uncle reward fork
+		{
+			number: 500000, uncles: []*types.Header{uncle(499999, uncle1)},
+			miner: lbt(75), uncle1: new(big.Int), uncle2: new(big.Int), devFund: lbt(5), staking: new(big.Int),
+		},
+		// First block of the Unity epoch, uncles are rewarded from the including block's epoch
+		{
+			number: 500001, uncles: []*types.Header{uncle(499999, uncle1)},
+			miner:  new(big.Int).Add(lbt(58), new(big.Int).Div(lbt(58), big.NewInt(32))),
+			uncle1: new(big.Int).Div(new(big.Int).Mul(lbt(58), big.NewInt(6)), big8), uncle2: new(big.Int),
+			devFund: lbt(5), staking: new(big.Int).Div(lbt(17), big.NewInt(2)),
+		},
+		// Two uncles at the minimum and maximum depth
+		{
+			number: 500010, uncles: []*types.Header{uncle(500009, uncle1), uncle(500004, uncle2)},
+			miner:   new(big.Int).Add(lbt(58), new(big.Int).Div(lbt(58), big.NewInt(16))),
+			uncle1:  new(big.Int).Div(new(big.Int).Mul(lbt(58), big.NewInt(7)), big8),
+			uncle2:  new(big.Int).Div(new(big.Int).Mul(lbt(58), big.NewInt(2)), big8),
+			devFund: lbt(5), staking: new(big.Int).Div(lbt(17), big.NewInt(2)),
+		},
+		// Last block of the Unity epoch and first block of the Justice epoch
+		{
+			number: 1050000, miner: lbt(58), uncle1: new(big.Int), uncle2: new(big.Int),
+			devFund: lbt(5), staking: new(big.Int).Div(lbt(17), big.NewInt(2)),
+		},
+		{
+			number: 1050001, miner: lbt(51), uncle1: new(big.Int), uncle2: new(big.Int),
+			devFund: lbt(4), staking: lbt(11),
+		},
+	}
+	ethash := NewFaker()
+	for i, tt := range tests {
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		header := &types.Header{Number: big.NewInt(tt.number), Coinbase: miner}
+
+		ethash.accumulateRewards(&config, header, statedb, nil, tt.uncles)
+
+		for _, check := range []struct {
+			name string
+			addr common.Address
+			want *big.Int
+		}{
+			{"miner", miner, tt.miner},
+			{"uncle1", uncle1, tt.uncle1},
+			{"uncle2", uncle2, tt.uncle2},
+		} {
+			if have := statedb.GetBalance(check.addr); have.Cmp(check.want) != 0 {
+				t.Errorf("test %d: %s balance mismatch: have %v, want %v", i, check.name, have, check.want)
+			}
+		}
+		for _, recipient := range recipients.DevFund {
+			if have := statedb.GetBalance(recipient.Address); have.Cmp(tt.devFund) != 0 {
+				t.Errorf("test %d: dev-fund %v balance mismatch: have %v, want %v", i, recipient.Address, have, tt.devFund)
+			}
+		}
+		for _, recipient := range recipients.Staking {
+			if have := statedb.GetBalance(recipient.Address); have.Cmp(tt.staking) != 0 {
+				t.Errorf("test %d: staking %v balance mismatch: have %v, want %v", i, recipient.Address, have, tt.staking)
+			}
+		}
+	}
+}
+
 func BenchmarkDifficultyCalculator(b *testing.B) {
 	x1 := makeDifficultyCalculator(big.NewInt(1000000))
 	x2 := MakeDifficultyCalculatorU256(big.NewInt(1000000))
@@ -186,3 +283,106 @@ func BenchmarkDifficultyCalculator(b *testing.B) {
 		}
 	})
 }
+
+// Tests the basic properties of the Liberty difficulty adjustment and its
+// activation at the configured fork block.
+func TestLibertyDifficulty(t *testing.T) {
+	config := *params.AllEthashProtocolChanges
+	config.Liberty = &params.LibertyConfig{
+		Epochs:           params.DefaultLibertyConfig.Epochs,
+		DifficultyBlock:  big.NewInt(100),
+		TargetBlockTime:  10,
+		DifficultyWindow: 100,
+	}
+	parent := func(number int64, difficulty int64) *types.Header {
+		return &types.Header{Number: big.NewInt(number), Time: 1000, Difficulty: big.NewInt(difficulty), UncleHash: types.EmptyUncleHash}
+	}
+	// Before the fork the Ethereum rules apply, from the fork onwards the Liberty ones
+	if have, want := CalcDifficulty(&config, 1010, parent(98, 1e9)), calcDifficultyEip5133(1010, parent(98, 1e9)); have.Cmp(want) != 0 {
+		t.Errorf("pre-fork difficulty mismatch: have %v, want %v", have, want)
+	}
+	if have, want := CalcDifficulty(&config, 1010, parent(99, 1e9)), calcDifficultyLiberty(config.Liberty, 1010, parent(99, 1e9)); have.Cmp(want) != 0 {
+		t.Errorf("fork difficulty mismatch: have %v, want %v", have, want)
+	}
+	tests := []struct {
+		parent int64
+		time   uint64
+		want   int64
+	}{
+		{1e9, 1010, 1e9},        // On target, unchanged
+		{1e9, 1000, 1006257823}, // Non-increasing timestamp, treated as a one second block
+		{1e9, 1005, 1003471748}, // Fast block, 2^(5/1000) times harder
+		{1e9, 1020, 993092495},  // Hundredth of a window late, 2^-0.01
+		{1e9, 1060, 965936328},  // Twentieth of a window late, 2^-0.05
+		{1e9, 1260, 840896415},  // Quarter of a window late, 2^-0.25
+		{1e9, 1510, 707106781},  // Half a window late, 2^-0.5
+		{1e9, 2010, 500000000},  // A whole window late, halved
+		{1e9, 11010, 976562},    // Ten windows late, 2^-10
+		{1e9, 100000, 131072},   // Clamped to the minimum difficulty
+		{131072, 1010, 131072},  // Never below the minimum
+		{1e18, 1010, 1e18},      // No difficulty bomb, however high the block
+	}
+	for i, tt := range tests {
+		have := calcDifficultyLiberty(config.Liberty, tt.time, &types.Header{Number: big.NewInt(10_000_000), Time: 1000, Difficulty: big.NewInt(tt.parent)})
+		// The cubic approximation of the exponential is accurate to about 0.01%
+		diff := new(big.Int).Sub(have, big.NewInt(tt.want))
+		if diff.Abs(diff).Cmp(new(big.Int).Div(big.NewInt(tt.want), big.NewInt(10000))) > 0 {
+			t.Errorf("test %d: difficulty mismatch: have %v, want %v", i, have, tt.want)
+		}
+	}
+}
+
+// Tests that the Liberty difficulty adjustment keeps the block time on target
+// while the network hashrate swings wildly.
+func TestLibertyDifficultySimulation(t *testing.T) {
+	config := &params.LibertyConfig{TargetBlockTime: 13, DifficultyWindow: 36}
+
+	phases := []struct {
+		hashrate float64 // Hashes per second
+		blocks   int
+	}{
+		{1e6, 3000},   // Steady state
+		{1e7, 3000},   // Hashrate jumps tenfold
+		{5e5, 3000},   // Hashrate collapses to a twentieth
+		{2e6, 3000},   // Partial recovery
+		{2.5e5, 3000}, // Another collapse
+	}
+	var (
+		rng    = rand.New(rand.NewSource(1))
+		header = &types.Header{Number: big.NewInt(0), Time: 0, Difficulty: big.NewInt(13e6)}
+		total  uint64
+		blocks int
+	)
+	for i, phase := range phases {
+		var settled uint64
+		for j := 0; j < phase.blocks; j++ {
+			// Sample the time it takes to find the block at the current difficulty
+			expected, _ := new(big.Float).Quo(new(big.Float).SetInt(header.Difficulty), big.NewFloat(phase.hashrate)).Float64()
+			solvetime := uint64(rng.ExpFloat64()*expected + 0.5)
+			if solvetime == 0 {
+				solvetime = 1
+			}
+			next := &types.Header{
+				Number: new(big.Int).Add(header.Number, big1),
+				Time:   header.Time + solvetime,
+			}
+			next.Difficulty = calcDifficultyLiberty(config, next.Time, header)
+			header = next
+
+			// Only measure the second half of the phase, once the difficulty settled
+			if j >= phase.blocks/2 {
+				settled += solvetime
+			}
+			total += solvetime
+			blocks++
+		}
+		avg := float64(settled) / float64(phase.blocks-phase.blocks/2)
+		if avg < 13*0.9 || avg > 13*1.1 {
+			t.Errorf("phase %d: average block time %.2fs, want 13s±10%%", i, avg)
+		}
+	}
+	// Over the whole run, the chain must be on schedule despite the swings
+	if avg := float64(total) / float64(blocks); avg < 13*0.95 || avg > 13*1.05 {
+		t.Errorf("overall average block time %.2fs, want 13s±5%%", avg)
+	}
+}
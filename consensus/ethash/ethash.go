@@ -59,11 +59,15 @@ var (
 	dumpMagic = []uint32{0xbaddcafe, 0xfee1dead}
 )
 
+const (
+	// sealCacheSize is the number of verified seals to remember, enough to cover
+	// reorged side chains and blocks submitted through the remote sealer.
+	sealCacheSize = 4096
+)
+
 func init() {
 	sharedConfig := Config{
-		PowMode:       ModeNormal,
-		CachesInMem:   3,
-		DatasetsInMem: 1,
+		PowMode: ModeNormal,
 	}
 	keystorePath := "./shared_keystore"
 	sharedEthash = New(sharedConfig, nil, false, keystorePath)
@@ -421,13 +425,21 @@ func MakeDataset(block uint64, dir string) {
 type Mode uint
 
 const (
-	ModeNormal Mode = iota
+	ModeNormal Mode = iota // Blake3 proof-of-work, no caches or datasets needed
 	ModeShared
 	ModeTest
 	ModeFake
 	ModeFullFake
+	ModeLegacy     // Original DAG based Ethash proof-of-work
+	ModeLegacyTest // Original Ethash with tiny caches and datasets, for test chains
 )
 
+// legacy returns whether the mode runs the original DAG based Ethash algorithm,
+// requiring verification caches and mining datasets.
+func (mode Mode) legacy() bool {
+	return mode == ModeLegacy || mode == ModeLegacyTest
+}
+
 // Config are the configuration parameters of the ethash.
 type Config struct {
 	CacheDir         string
@@ -452,15 +464,21 @@ type Config struct {
 type Ethash struct {
 	config Config
 
-	caches   *lru[*cache]   // In memory caches to avoid regenerating too often
-	datasets *lru[*dataset] // In memory datasets to avoid regenerating too often
+	caches   *lru[*cache]   // In memory caches to avoid regenerating too often (legacy mode only)
+	datasets *lru[*dataset] // In memory datasets to avoid regenerating too often (legacy mode only)
+
+	seals *lrupkg.Cache[sealCacheKey, struct{}] // Seals already verified, to avoid redoing the Blake3 rounds
 
 	// Mining related fields
 	rand     *rand.Rand    // Properly seeded random source for nonces
 	threads  int           // Number of threads to mine on if mining
 	update   chan struct{} // Notification channel to update mining parameters
+	quit     chan struct{} // Closed on engine shutdown to abort the local search threads
 	hashrate metrics.Meter // Meter tracking the average hashrate
 	remote   *remoteSealer
+	payouts  PayoutBuilder // Assembles per-address work packages for remote miners
+	stratum  *stratumServer
+	shares   ShareRecorder // Optional accounting of the shares accepted by the stratum server
 
 	// The fields below are hooks for testing
 	shared    *Ethash       // Shared PoW verifier to avoid cache regeneration
@@ -469,6 +487,7 @@ type Ethash struct {
 
 	lock      sync.Mutex // Ensures thread safety for the in-memory caches and mining fields
 	closeOnce sync.Once  // Ensures exit channel will not be closed twice.
+	quitOnce  sync.Once  // Ensures quit channel will not be closed twice.
 
 	minerAddresses           map[common.Hash]common.Address
 	minerLock                sync.RWMutex
@@ -484,7 +503,7 @@ func New(config Config, notify []string, noverify bool, keystorePath string) *Et
 	if config.Log == nil {
 		config.Log = log.Root()
 	}
-	if config.CachesInMem <= 0 {
+	if config.PowMode.legacy() && config.CachesInMem <= 0 {
 		config.Log.Warn("One ethash cache must always be in memory", "requested", config.CachesInMem)
 		config.CachesInMem = 1
 	}
@@ -496,9 +515,9 @@ func New(config Config, notify []string, noverify bool, keystorePath string) *Et
 
 	ethash := &Ethash{
 		config:                   config,
-		caches:                   newlru(config.CachesInMem, newCache),
-		datasets:                 newlru(config.DatasetsInMem, newDataset),
+		seals:                    lrupkg.NewCache[sealCacheKey, struct{}](sealCacheSize),
 		update:                   make(chan struct{}),
+		quit:                     make(chan struct{}),
 		hashrate:                 metrics.NewMeterForced(),
 		minerAddresses:           make(map[common.Hash]common.Address),
 		minerLock:                sync.RWMutex{},
@@ -506,6 +525,10 @@ func New(config Config, notify []string, noverify bool, keystorePath string) *Et
 		keystore:                 ks,
 	}
 
+	if config.PowMode.legacy() {
+		ethash.caches = newlru(config.CachesInMem, newCache)
+		ethash.datasets = newlru(config.DatasetsInMem, newDataset)
+	}
 	if config.PowMode == ModeShared {
 		ethash.shared = sharedEthash
 	}
@@ -520,6 +543,13 @@ func NewTester(notify []string, noverify bool) *Ethash {
 	return New(Config{PowMode: ModeTest}, notify, noverify, keystorePath)
 }
 
+// NewLegacyTester creates a small sized original Ethash PoW scheme, backed by
+// tiny verification caches and mining datasets, useful only for testing purposes.
+func NewLegacyTester(notify []string, noverify bool) *Ethash {
+	keystorePath := "./keystore"
+	return New(Config{PowMode: ModeLegacyTest}, notify, noverify, keystorePath)
+}
+
 // NewFaker creates a ethash consensus engine with a fake PoW scheme that accepts
 // all blocks' seal as valid, though they still have to conform to the Ethereum
 // consensus rules.
@@ -577,6 +607,18 @@ func NewShared() *Ethash {
 
 // Close closes the exit channel to notify all backend threads exiting.
 func (ethash *Ethash) Close() error {
+	ethash.lock.Lock()
+	stratum := ethash.stratum
+	ethash.lock.Unlock()
+
+	if stratum != nil {
+		stratum.close()
+	}
+	ethash.quitOnce.Do(func() {
+		if ethash.quit != nil {
+			close(ethash.quit)
+		}
+	})
 	return ethash.StopRemoteSealer()
 }
 
@@ -601,11 +643,11 @@ func (ethash *Ethash) cache(block uint64) *cache {
 	current, future := ethash.caches.get(epoch)
 
 	// Wait for generation finish.
-	current.generate(ethash.config.CacheDir, ethash.config.CachesOnDisk, ethash.config.CachesLockMmap, ethash.config.PowMode == ModeTest)
+	current.generate(ethash.config.CacheDir, ethash.config.CachesOnDisk, ethash.config.CachesLockMmap, ethash.config.PowMode == ModeLegacyTest)
 
 	// If we need a new future cache, now's a good time to regenerate it.
 	if future != nil {
-		go future.generate(ethash.config.CacheDir, ethash.config.CachesOnDisk, ethash.config.CachesLockMmap, ethash.config.PowMode == ModeTest)
+		go future.generate(ethash.config.CacheDir, ethash.config.CachesOnDisk, ethash.config.CachesLockMmap, ethash.config.PowMode == ModeLegacyTest)
 	}
 	return current
 }
@@ -624,21 +666,65 @@ func (ethash *Ethash) dataset(block uint64, async bool) *dataset {
 	// If async is specified, generate everything in a background thread
 	if async && !current.generated() {
 		go func() {
-			current.generate(ethash.config.DatasetDir, ethash.config.DatasetsOnDisk, ethash.config.DatasetsLockMmap, ethash.config.PowMode == ModeTest)
+			current.generate(ethash.config.DatasetDir, ethash.config.DatasetsOnDisk, ethash.config.DatasetsLockMmap, ethash.config.PowMode == ModeLegacyTest)
 			if future != nil {
-				future.generate(ethash.config.DatasetDir, ethash.config.DatasetsOnDisk, ethash.config.DatasetsLockMmap, ethash.config.PowMode == ModeTest)
+				future.generate(ethash.config.DatasetDir, ethash.config.DatasetsOnDisk, ethash.config.DatasetsLockMmap, ethash.config.PowMode == ModeLegacyTest)
 			}
 		}()
 	} else {
 		// Either blocking generation was requested, or already done
-		current.generate(ethash.config.DatasetDir, ethash.config.DatasetsOnDisk, ethash.config.DatasetsLockMmap, ethash.config.PowMode == ModeTest)
+		current.generate(ethash.config.DatasetDir, ethash.config.DatasetsOnDisk, ethash.config.DatasetsLockMmap, ethash.config.PowMode == ModeLegacyTest)
 		if future != nil {
-			go future.generate(ethash.config.DatasetDir, ethash.config.DatasetsOnDisk, ethash.config.DatasetsLockMmap, ethash.config.PowMode == ModeTest)
+			go future.generate(ethash.config.DatasetDir, ethash.config.DatasetsOnDisk, ethash.config.DatasetsLockMmap, ethash.config.PowMode == ModeLegacyTest)
 		}
 	}
 	return current
 }
 
+// SetPayoutBuilder installs the callback used to assemble blocks paying remote
+// miners directly, enabling per-address work packages via eth_getWorkFor.
+func (ethash *Ethash) SetPayoutBuilder(builder PayoutBuilder) {
+	ethash.lock.Lock()
+	defer ethash.lock.Unlock()
+
+	ethash.payouts = builder
+}
+
+// StartStratum starts serving the remote sealer's work to miners over
+// stratum+tcp on the given listen address, starting at the given share
+// difficulty (in raw hashes).
+func (ethash *Ethash) StartStratum(addr string, difficulty uint64) error {
+	ethash.lock.Lock()
+	defer ethash.lock.Unlock()
+
+	if ethash.stratum != nil {
+		return errStratumRunning
+	}
+	stratum, err := startStratumServer(ethash, addr, difficulty)
+	if err != nil {
+		return err
+	}
+	ethash.stratum = stratum
+	return nil
+}
+
+// SetShareRecorder installs the recorder notified of every share accepted by
+// the stratum server, enabling pool accounting of the miners' work.
+func (ethash *Ethash) SetShareRecorder(recorder ShareRecorder) {
+	ethash.lock.Lock()
+	defer ethash.lock.Unlock()
+
+	ethash.shares = recorder
+}
+
+// shareRecorder returns the installed share recorder, if any.
+func (ethash *Ethash) shareRecorder() ShareRecorder {
+	ethash.lock.Lock()
+	defer ethash.lock.Unlock()
+
+	return ethash.shares
+}
+
 // Threads returns the number of mining threads currently enabled. This doesn't
 // necessarily mean that mining is running!
 func (ethash *Ethash) Threads() int {
@@ -675,8 +761,8 @@ func (ethash *Ethash) SetThreads(threads int) {
 // Note the returned hashrate includes local hashrate, but also includes the total
 // hashrate of all remote miner.
 func (ethash *Ethash) Hashrate() float64 {
-	// Short circuit if we are run the ethash in normal/test mode.
-	if ethash.config.PowMode != ModeNormal && ethash.config.PowMode != ModeTest {
+	// Short circuit if we are not running the ethash in normal/test/legacy mode.
+	if ethash.config.PowMode != ModeNormal && ethash.config.PowMode != ModeTest && !ethash.config.PowMode.legacy() {
 		return ethash.hashrate.Rate1()
 	}
 	var res = make(chan uint64, 1)
@@ -696,7 +782,7 @@ func (ethash *Ethash) Hashrate() float64 {
 func (ethash *Ethash) APIs(chain consensus.ChainHeaderReader) []rpc.API {
 	// In order to ensure backward compatibility, we exposes ethash RPC APIs
 	// to both eth and ethash namespaces.
-	return []rpc.API{
+	apis := []rpc.API{
 		{
 			Namespace: "eth",
 			Service:   &API{ethash},
@@ -706,6 +792,28 @@ func (ethash *Ethash) APIs(chain consensus.ChainHeaderReader) []rpc.API {
 			Service:   &API{ethash},
 		},
 	}
+	// The reward schedule is served under its own namespace, as well as the
+	// eth one explorers and wallets already talk to.
+	if chain != nil {
+		apis = append(apis, []rpc.API{
+			{
+				Namespace: "eth",
+				Service:   &RewardAPI{chain},
+			},
+			{
+				Namespace: "liberty",
+				Service:   &RewardAPI{chain},
+			},
+		}...)
+	}
+	// Network statistics need the uncles, only available with full blocks
+	if chain, ok := chain.(consensus.ChainReader); ok {
+		apis = append(apis, rpc.API{
+			Namespace: "ethash",
+			Service:   &NetworkAPI{chain},
+		})
+	}
+	return apis
 }
 
 // SeedHash is the seed to use for generating a verification cache and the mining
@@ -26,7 +26,9 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	lrupkg "github.com/ethereum/go-ethereum/common/lru"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 // Tests that ethash works correctly in test mode.
@@ -53,6 +55,225 @@ func TestTestMode(t *testing.T) {
 	}
 }
 
+// Tests that the original Ethash algorithm is still usable in legacy test mode.
+func TestLegacyTestMode(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(100)}
+
+	ethash := NewLegacyTester(nil, false)
+	defer ethash.Close()
+
+	results := make(chan *types.Block)
+	err := ethash.Seal(nil, types.NewBlockWithHeader(header), results, nil)
+	if err != nil {
+		t.Fatalf("failed to seal block: %v", err)
+	}
+	select {
+	case block := <-results:
+		header.Nonce = types.EncodeNonce(block.Nonce())
+		header.MixDigest = block.MixDigest()
+		if err := ethash.verifySeal(nil, header, false); err != nil {
+			t.Fatalf("unexpected verification error: %v", err)
+		}
+		// The Blake3 verifier must not accept a legacy seal
+		if err := ethash.verifyBlake3Seal(header, params.DefaultBlake3Iterations); err == nil {
+			t.Fatalf("legacy seal accepted by Blake3 verification")
+		}
+	case <-time.NewTimer(4 * time.Second).C:
+		t.Error("sealing result timeout")
+	}
+}
+
+// testChainReader is a header reader with no headers, only serving the chain
+// configuration to the engine.
+type testChainReader struct {
+	config *params.ChainConfig
+}
+
+func (cr *testChainReader) Config() *params.ChainConfig                             { return cr.config }
+func (cr *testChainReader) CurrentHeader() *types.Header                            { return nil }
+func (cr *testChainReader) GetHeader(hash common.Hash, number uint64) *types.Header { return nil }
+func (cr *testChainReader) GetHeaderByNumber(number uint64) *types.Header           { return nil }
+func (cr *testChainReader) GetHeaderByHash(hash common.Hash) *types.Header          { return nil }
+func (cr *testChainReader) GetTd(hash common.Hash, number uint64) *big.Int          { return nil }
+
+// Tests that seals are verified against the Blake3 iteration count scheduled
+// for the block's height in the chain config.
+func TestIterationSchedule(t *testing.T) {
+	config := *params.AllEthashProtocolChanges
+	config.Ethash = &params.EthashConfig{
+		Iterations: []params.EthashIterations{{Block: 10, Iterations: 16}},
+	}
+	chain := &testChainReader{config: &config}
+
+	ethash := NewTester(nil, false)
+	defer ethash.Close()
+
+	for i, tt := range []struct {
+		number uint64
+		iters  uint64
+		valid  bool
+	}{
+		{number: 10, iters: 16, valid: true},
+		{number: 11, iters: 16, valid: true},
+		{number: 10, iters: 17, valid: false},
+		{number: 9, iters: 16, valid: false},
+	} {
+		header := &types.Header{Number: new(big.Int).SetUint64(tt.number), Difficulty: big.NewInt(1), Nonce: types.EncodeNonce(uint64(i))}
+		header.MixDigest = common.BytesToHash(hashimotoBlake3(ethash.SealHash(header).Bytes(), header.Nonce.Uint64(), tt.iters))
+
+		if err := ethash.verifySeal(chain, header, false); (err == nil) != tt.valid {
+			t.Errorf("test %d: verification mismatch: have %v, want valid %v", i, err, tt.valid)
+		}
+	}
+	if have, want := config.Ethash.Blake3Iterations(big.NewInt(9)), params.DefaultBlake3Iterations; have != want {
+		t.Errorf("pre-fork iterations mismatch: have %d, want %d", have, want)
+	}
+}
+
+// Tests that verified seals are cached and that failed ones are not.
+func TestSealCache(t *testing.T) {
+	ethash := NewFaker()
+	ethash.seals = lrupkg.NewCache[sealCacheKey, struct{}](sealCacheSize)
+
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1)}
+	header.MixDigest = common.BytesToHash(hashimotoBlake3(ethash.SealHash(header).Bytes(), header.Nonce.Uint64(), 16))
+
+	if err := ethash.verifyBlake3Seal(header, 16); err != nil {
+		t.Fatalf("failed to verify seal: %v", err)
+	}
+	if ethash.seals.Len() != 1 {
+		t.Fatalf("verified seal not cached")
+	}
+	// A cached seal must not be accepted under a different iteration count
+	if err := ethash.verifyBlake3Seal(header, 17); err != errInvalidMixDigest {
+		t.Fatalf("iteration mismatch: have %v, want %v", err, errInvalidMixDigest)
+	}
+	if ethash.seals.Len() != 1 {
+		t.Fatalf("invalid seal cached")
+	}
+	// A tampered mix digest must miss the cache and fail verification
+	header.MixDigest[0] ^= 0xff
+	if err := ethash.verifyBlake3Seal(header, 16); err != errInvalidMixDigest {
+		t.Fatalf("tampered digest: have %v, want %v", err, errInvalidMixDigest)
+	}
+}
+
+// testHeaderReader is a header reader serving a fixed set of headers.
+type testHeaderReader struct {
+	testChainReader
+	headers map[common.Hash]*types.Header
+}
+
+func (cr *testHeaderReader) GetHeader(hash common.Hash, number uint64) *types.Header {
+	return cr.headers[hash]
+}
+
+// makeSealedHeaders creates a chain of n headers with valid seals on top of the
+// given parent, tagged with the given extra-data to tell side chains apart.
+func makeSealedHeaders(chain *testHeaderReader, parent *types.Header, n int, extra byte) []*types.Header {
+	headers := make([]*types.Header, n)
+	for i := range headers {
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     new(big.Int).Add(parent.Number, common.Big1),
+			Time:       parent.Time + chain.config.LibertySchedule().BlockTime(),
+			GasLimit:   parent.GasLimit,
+			Extra:      []byte{extra},
+		}
+		header.Difficulty = CalcDifficulty(chain.config, header.Time, parent)
+
+		target := new(big.Int).Div(two256, header.Difficulty)
+		for nonce := uint64(0); ; nonce++ {
+			digest := hashimotoBlake3(NewFaker().SealHash(header).Bytes(), nonce, powIterations(chain, header.Number))
+			if new(big.Int).SetBytes(digest).Cmp(target) <= 0 {
+				header.Nonce, header.MixDigest = types.EncodeNonce(nonce), common.BytesToHash(digest)
+				break
+			}
+		}
+		chain.headers[header.Hash()] = header
+		headers[i], parent = header, header
+	}
+	return headers
+}
+
+// Benchmarks sync header throughput through VerifyHeaders, without the seal
+// cache (as before it existed) and with it. The cold batch has every seal
+// recomputed, as on first sync. The side chain batch is verified again, as when
+// a reorg or a re-announced fork brings back headers seen before.
+func BenchmarkVerifyHeaders(b *testing.B) {
+	const batch = 32
+
+	// Lift the difficulty floor, so that seals at the full iteration count are
+	// found on the first nonce and the batch can be made in reasonable time.
+	defer func(floor *big.Int) { params.MinimumDifficulty = floor }(params.MinimumDifficulty)
+	params.MinimumDifficulty = big.NewInt(1)
+
+	chain := &testHeaderReader{
+		testChainReader: testChainReader{config: &params.ChainConfig{ChainID: big.NewInt(1)}},
+		headers:         make(map[common.Hash]*types.Header),
+	}
+	genesis := &types.Header{
+		Number:     new(big.Int),
+		Time:       uint64(time.Now().Unix()) - 2*batch*chain.config.LibertySchedule().BlockTime(),
+		GasLimit:   params.GenesisGasLimit,
+		Difficulty: big.NewInt(1),
+	}
+	chain.headers[genesis.Hash()] = genesis
+
+	var (
+		canonical = makeSealedHeaders(chain, genesis, batch, 0)
+		side      = makeSealedHeaders(chain, genesis, batch, 1)
+	)
+	seals := make([]bool, batch)
+	for i := range seals {
+		seals[i] = true
+	}
+	run := func(b *testing.B, cached bool, headers []*types.Header, repeated bool) {
+		ethash := NewTester(nil, false)
+		defer ethash.Close()
+
+		reset := func() {
+			ethash.seals = nil
+			if cached {
+				ethash.seals = lrupkg.NewCache[sealCacheKey, struct{}](sealCacheSize)
+			}
+		}
+		verify := func() time.Duration {
+			start := time.Now()
+			abort, results := ethash.VerifyHeaders(chain, headers, seals)
+			defer close(abort)
+
+			for range headers {
+				if err := <-results; err != nil {
+					b.Fatalf("failed to verify header: %v", err)
+				}
+			}
+			return time.Since(start)
+		}
+		// Repeated batches are verified once up front, as when first seen
+		reset()
+		if repeated {
+			verify()
+		}
+		b.ResetTimer()
+
+		var elapsed time.Duration
+		for i := 0; i < b.N; i++ {
+			if !repeated {
+				b.StopTimer()
+				reset()
+				b.StartTimer()
+			}
+			elapsed += verify()
+		}
+		b.ReportMetric(float64(b.N*batch)/elapsed.Seconds(), "headers/s")
+	}
+	b.Run("cold/uncached", func(b *testing.B) { run(b, false, canonical, false) })
+	b.Run("cold/cached", func(b *testing.B) { run(b, true, canonical, false) })
+	b.Run("sidechain/uncached", func(b *testing.B) { run(b, false, side, true) })
+	b.Run("sidechain/cached", func(b *testing.B) { run(b, true, side, true) })
+}
+
 // This test checks that cache lru logic doesn't crash under load.
 // It reproduces https://github.com/ethereum/go-ethereum/issues/14943
 // func TestCacheFileEvict(t *testing.T) {
@@ -101,7 +322,7 @@ func TestCacheFileEvict(t *testing.T) {
 		CachesInMem:  3,
 		CachesOnDisk: 10,
 		CacheDir:     tmpdir,
-		PowMode:      ModeTest,
+		PowMode:      ModeLegacyTest,
 	}
 	e := New(config, nil, false, keystorePath) //
 	defer e.Close()
@@ -0,0 +1,359 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethash
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// The work packages issued to remote miners and the solutions they submit live
+// in their own table of the chain database, using the keys below. Only the seal
+// hash and header fields of the work are kept, not the blocks themselves, so the
+// history survives restarts but the work issued before can't be sealed anymore.
+var (
+	historyTablePrefix = "ethash-" // Prefix of the work history table within the chain database
+
+	workPrefix       = []byte("w") // workPrefix + num (uint64 big endian) + sealhash -> issued work package
+	submissionPrefix = []byte("s") // submissionPrefix + num (uint64 big endian) + sealhash + nonce -> submitted solution
+)
+
+const (
+	// workHistoryDepth is the number of blocks the issued work packages and the
+	// submitted solutions are retained for.
+	workHistoryDepth = 128
+
+	// workHistoryLimit is the maximum number of work packages, and separately
+	// of submitted solutions, retained regardless of their depth.
+	workHistoryLimit = 4096
+
+	// maxRejectedRecords is the number of rejected solutions recorded while
+	// the sealer works on the same block height, protecting the database
+	// against miners flooding it with garbage.
+	maxRejectedRecords = 64
+)
+
+// Outcomes of a solution submitted by a remote miner.
+const (
+	SubmissionAccepted = "accepted" // Valid solution handed to the miner
+	SubmissionStale    = "stale"    // Solution for unknown or outdated work
+	SubmissionInvalid  = "invalid"  // Solution failing verification
+)
+
+var errNoWorkHistory = errors.New("work history not persisted")
+
+// workRecord is a work package issued to remote miners.
+type workRecord struct {
+	Number     uint64
+	SealHash   common.Hash
+	ParentHash common.Hash
+	Coinbase   common.Address
+	Difficulty *big.Int
+	Iterations uint64
+	Time       uint64 // Unix time the package was issued at
+}
+
+// submissionRecord is a solution submitted by a remote miner, together with the
+// verdict of the sealer.
+type submissionRecord struct {
+	Number    uint64 // Height of the work, the current height if the work is unknown
+	SealHash  common.Hash
+	Nonce     types.BlockNonce
+	MixDigest common.Hash
+	Payout    common.Address // Payout address requested by the miner, if any
+	Status    string
+	Reason    string // Cause of the rejection, empty if accepted
+	Time      uint64 // Unix time the solution was submitted at
+}
+
+// historyRequest is a request for the work history of the given number of most
+// recent blocks, zero requesting all retained ones.
+type historyRequest struct {
+	depth uint64
+	errc  chan error
+	res   chan *workHistory
+}
+
+// workHistory is the persisted record of the work issued for a range of blocks.
+type workHistory struct {
+	works       []*workRecord
+	submissions []*submissionRecord
+}
+
+func historyKey(prefix []byte, number uint64, hash common.Hash) []byte {
+	key := make([]byte, len(prefix)+8+common.HashLength)
+	copy(key, prefix)
+	binary.BigEndian.PutUint64(key[len(prefix):], number)
+	copy(key[len(prefix)+8:], hash.Bytes())
+	return key
+}
+
+func submissionKey(number uint64, hash common.Hash, nonce types.BlockNonce) []byte {
+	return append(historyKey(submissionPrefix, number, hash), nonce[:]...)
+}
+
+// writeWork stores a work package issued to remote miners.
+func writeWork(db ethdb.KeyValueWriter, w *workRecord) {
+	blob, err := rlp.EncodeToBytes(w)
+	if err != nil {
+		log.Crit("Failed to encode work package", "err", err)
+	}
+	if err := db.Put(historyKey(workPrefix, w.Number, w.SealHash), blob); err != nil {
+		log.Crit("Failed to store work package", "err", err)
+	}
+}
+
+// readWork retrieves an issued work package.
+func readWork(db ethdb.KeyValueReader, number uint64, hash common.Hash) *workRecord {
+	blob, _ := db.Get(historyKey(workPrefix, number, hash))
+	if len(blob) == 0 {
+		return nil
+	}
+	w := new(workRecord)
+	if err := rlp.DecodeBytes(blob, w); err != nil {
+		log.Error("Invalid work package", "number", number, "sealhash", hash, "err", err)
+		return nil
+	}
+	return w
+}
+
+// writeSubmission stores a solution submitted by a remote miner.
+func writeSubmission(db ethdb.KeyValueWriter, s *submissionRecord) {
+	blob, err := rlp.EncodeToBytes(s)
+	if err != nil {
+		log.Crit("Failed to encode work submission", "err", err)
+	}
+	if err := db.Put(submissionKey(s.Number, s.SealHash, s.Nonce), blob); err != nil {
+		log.Crit("Failed to store work submission", "err", err)
+	}
+}
+
+// readWorks retrieves the work packages issued for the blocks from the given
+// number onwards, ordered by number.
+func readWorks(db ethdb.Iteratee, from uint64) []*workRecord {
+	it := db.NewIterator(workPrefix, encodeNumber(from))
+	defer it.Release()
+
+	var works []*workRecord
+	for it.Next() {
+		w := new(workRecord)
+		if err := rlp.DecodeBytes(it.Value(), w); err != nil {
+			log.Error("Invalid work package", "key", it.Key(), "err", err)
+			continue
+		}
+		works = append(works, w)
+	}
+	return works
+}
+
+// readSubmissions retrieves the solutions submitted for the blocks from the
+// given number onwards, ordered by number.
+func readSubmissions(db ethdb.Iteratee, from uint64) []*submissionRecord {
+	it := db.NewIterator(submissionPrefix, encodeNumber(from))
+	defer it.Release()
+
+	var submissions []*submissionRecord
+	for it.Next() {
+		s := new(submissionRecord)
+		if err := rlp.DecodeBytes(it.Value(), s); err != nil {
+			log.Error("Invalid work submission", "key", it.Key(), "err", err)
+			continue
+		}
+		submissions = append(submissions, s)
+	}
+	return submissions
+}
+
+// countHistory returns the number of entries with the given prefix.
+func countHistory(db ethdb.Iteratee, prefix []byte) int {
+	it := db.NewIterator(prefix, nil)
+	defer it.Release()
+
+	var count int
+	for it.Next() {
+		if len(it.Key()) >= len(prefix)+8 {
+			count++
+		}
+	}
+	return count
+}
+
+// pruneHistory removes the entries with the given prefix whose block number is
+// below the limit, as well as the oldest ones exceeding the maximum count, given
+// the number of entries currently stored. Only the removed entries are iterated
+// over. The number of entries left is returned.
+func pruneHistory(db ethdb.KeyValueStore, prefix []byte, limit uint64, count int, entries int) int {
+	it := db.NewIterator(prefix, nil)
+	defer it.Release()
+
+	batch := db.NewBatch()
+	for entries > 0 && it.Next() {
+		key := it.Key()
+		if len(key) < len(prefix)+8 {
+			continue
+		}
+		if entries <= count && binary.BigEndian.Uint64(key[len(prefix):]) >= limit {
+			break
+		}
+		batch.Delete(common.CopyBytes(key))
+		entries--
+	}
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to prune work history", "err", err)
+	}
+	return entries
+}
+
+func encodeNumber(number uint64) []byte {
+	enc := make([]byte, 8)
+	binary.BigEndian.PutUint64(enc, number)
+	return enc
+}
+
+// SetWorkHistory enables persisting the work packages issued to remote miners
+// and the solutions they submit in the given database.
+func (ethash *Ethash) SetWorkHistory(db ethdb.Database) {
+	if ethash.remote == nil {
+		return
+	}
+	select {
+	case ethash.remote.historyCh <- rawdb.NewTable(db, historyTablePrefix):
+	case <-ethash.remote.exitCh:
+	}
+}
+
+// workHistory retrieves the work issued for the given number of most recent
+// blocks and the solutions submitted for it.
+func (ethash *Ethash) workHistory(depth uint64) (*workHistory, error) {
+	if ethash.remote == nil {
+		return nil, errNoWorkHistory
+	}
+	req := &historyRequest{depth: depth, errc: make(chan error, 1), res: make(chan *workHistory, 1)}
+	select {
+	case ethash.remote.fetchHistoryCh <- req:
+	case <-ethash.remote.exitCh:
+		return nil, errEthashStopped
+	}
+	select {
+	case history := <-req.res:
+		return history, nil
+	case err := <-req.errc:
+		return nil, err
+	}
+}
+
+// openHistory starts persisting the work history into the given database,
+// pruning whatever exceeds the retention limits since the last run.
+func (s *remoteSealer) openHistory(db ethdb.Database) {
+	s.db = db
+	s.workRecords = pruneHistory(db, workPrefix, 0, workHistoryLimit, countHistory(db, workPrefix))
+	s.submissionRecords = pruneHistory(db, submissionPrefix, 0, workHistoryLimit, countHistory(db, submissionPrefix))
+}
+
+// recordWork persists a work package issued to remote miners.
+func (s *remoteSealer) recordWork(hash common.Hash, work *sealTask) {
+	if s.db == nil {
+		return
+	}
+	block := work.block
+	writeWork(s.db, &workRecord{
+		Number:     block.NumberU64(),
+		SealHash:   hash,
+		ParentHash: block.ParentHash(),
+		Coinbase:   block.Coinbase(),
+		Difficulty: block.Difficulty(),
+		Iterations: work.iterations,
+		Time:       uint64(time.Now().Unix()),
+	})
+	if s.workRecords++; s.workRecords > workHistoryLimit {
+		s.workRecords = pruneHistory(s.db, workPrefix, 0, workHistoryLimit, s.workRecords)
+	}
+}
+
+// recordSubmission persists a solution submitted by a remote miner for the work
+// of the given height. Only the first few rejected solutions are recorded while
+// the sealer works on the same height, the rest is merely counted.
+func (s *remoteSealer) recordSubmission(number uint64, result *mineResult, status string, reason string) {
+	if s.db == nil {
+		return
+	}
+	if status != SubmissionAccepted {
+		var head uint64
+		if s.currentBlock != nil {
+			head = s.currentBlock.NumberU64()
+		}
+		if head != s.rejectedHead {
+			s.rejectedHead, s.rejectedRecords = head, 0
+		}
+		if s.rejectedRecords >= maxRejectedRecords {
+			workUnrecordedMeter.Mark(1)
+			return
+		}
+		s.rejectedRecords++
+	}
+	writeSubmission(s.db, &submissionRecord{
+		Number:    number,
+		SealHash:  result.hash,
+		Nonce:     result.nonce,
+		MixDigest: result.mixDigest,
+		Payout:    result.minerAddress,
+		Status:    status,
+		Reason:    reason,
+		Time:      uint64(time.Now().Unix()),
+	})
+	if s.submissionRecords++; s.submissionRecords > workHistoryLimit {
+		s.submissionRecords = pruneHistory(s.db, submissionPrefix, 0, workHistoryLimit, s.submissionRecords)
+	}
+}
+
+// pruneHistory drops the work history which is older than the retention depth,
+// once per new block height.
+func (s *remoteSealer) pruneHistory() {
+	if s.db == nil || s.currentBlock == nil {
+		return
+	}
+	if number := s.currentBlock.NumberU64(); number > workHistoryDepth && number-workHistoryDepth > s.prunedHistory {
+		s.workRecords = pruneHistory(s.db, workPrefix, number-workHistoryDepth, workHistoryLimit, s.workRecords)
+		s.submissionRecords = pruneHistory(s.db, submissionPrefix, number-workHistoryDepth, workHistoryLimit, s.submissionRecords)
+		s.prunedHistory = number - workHistoryDepth
+	}
+}
+
+// handleHistoryRequest serves the persisted work history of a block range.
+func (s *remoteSealer) handleHistoryRequest(req *historyRequest) {
+	if s.db == nil {
+		req.errc <- errNoWorkHistory
+		return
+	}
+	var from uint64
+	if s.currentBlock != nil && req.depth > 0 && s.currentBlock.NumberU64() >= req.depth {
+		from = s.currentBlock.NumberU64() - req.depth + 1
+	}
+	req.res <- &workHistory{
+		works:       readWorks(s.db, from),
+		submissions: readSubmissions(s.db, from),
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethash
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Tests that the work issued to remote miners and the solutions they submit are
+// recorded, and that the history is retained across restarts.
+func TestWorkHistory(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+
+	ethash := NewTester(nil, true)
+	defer ethash.Close()
+	api := &API{ethash}
+
+	if _, err := api.GetWorkHistory(nil); err != errNoWorkHistory {
+		t.Fatalf("missing history error mismatch: have %v, want %v", err, errNoWorkHistory)
+	}
+	ethash.SetWorkHistory(db)
+
+	var (
+		results = make(chan *types.Block, 16)
+		digest  = common.HexToHash("deadbeef")
+		first   = &types.Header{ParentHash: common.HexToHash("0xa"), Number: big.NewInt(1), Difficulty: big.NewInt(100000000)}
+		second  = &types.Header{ParentHash: common.HexToHash("0xb"), Number: big.NewInt(2), Difficulty: big.NewInt(100000000)}
+	)
+	ethash.Seal(nil, types.NewBlockWithHeader(first), results, nil)
+
+	if !api.SubmitWork(types.BlockNonce{0x01}, ethash.SealHash(first), digest) {
+		t.Fatalf("valid solution rejected")
+	}
+	<-results
+	if api.SubmitWorkFor(types.BlockNonce{0x02}, ethash.SealHash(first), digest, common.Address{0x01}) {
+		t.Fatalf("solution for a different payout accepted")
+	}
+	if api.SubmitWork(types.BlockNonce{0x03}, common.Hash{0xff}, digest) {
+		t.Fatalf("solution for unknown work accepted")
+	}
+	checkHistory := func(history *RPCWorkHistory) {
+		t.Helper()
+
+		if len(history.Works) != 1 || history.Works[0].SealHash != ethash.SealHash(first) {
+			t.Fatalf("work mismatch: have %+v", history.Works)
+		}
+		submissions := history.Works[0].Submissions
+		if len(submissions) != 2 {
+			t.Fatalf("submission count mismatch: have %d, want 2", len(submissions))
+		}
+		for _, s := range submissions {
+			switch s.Nonce {
+			case types.BlockNonce{0x01}:
+				if s.Status != SubmissionAccepted || s.Reason != "" {
+					t.Errorf("accepted submission mismatch: have %s (%s)", s.Status, s.Reason)
+				}
+			case types.BlockNonce{0x02}:
+				if s.Status != SubmissionInvalid || s.Payout == nil || *s.Payout != (common.Address{0x01}) {
+					t.Errorf("invalid submission mismatch: have %s (%s)", s.Status, s.Reason)
+				}
+			default:
+				t.Errorf("unexpected submission %x", s.Nonce)
+			}
+		}
+		if len(history.Unmatched) != 1 || history.Unmatched[0].Status != SubmissionStale || history.Unmatched[0].Number != 1 {
+			t.Fatalf("unmatched submissions mismatch: have %+v", history.Unmatched)
+		}
+	}
+	history, err := api.GetWorkHistory(nil)
+	if err != nil {
+		t.Fatalf("failed to retrieve work history: %v", err)
+	}
+	checkHistory(history)
+	ethash.Close()
+
+	// Restart the sealer, the history must be retained but the work of the first
+	// block can't be sealed anymore
+	ethash = NewTester(nil, true)
+	defer ethash.Close()
+	api = &API{ethash}
+
+	ethash.SetWorkHistory(db)
+	if history, err = api.GetWorkHistory(nil); err != nil {
+		t.Fatalf("failed to retrieve work history: %v", err)
+	}
+	checkHistory(history)
+
+	ethash.Seal(nil, types.NewBlockWithHeader(second), results, nil)
+	if api.SubmitWork(types.BlockNonce{0x04}, ethash.SealHash(first), digest) {
+		t.Fatalf("solution for work issued before the restart accepted")
+	}
+	select {
+	case block := <-results:
+		t.Fatalf("block sealed from work issued before the restart: #%d", block.NumberU64())
+	case <-time.After(100 * time.Millisecond):
+	}
+	// Only the work of the most recent block must be returned when limited
+	depth := hexutil.Uint64(1)
+	if history, err = api.GetWorkHistory(&depth); err != nil {
+		t.Fatalf("failed to retrieve work history: %v", err)
+	}
+	if len(history.Works) != 1 || history.Works[0].SealHash != ethash.SealHash(second) {
+		t.Fatalf("limited work history mismatch: have %+v", history.Works)
+	}
+}
+
+// Tests that pruning drops the work history of old blocks only, and the oldest
+// entries beyond the maximum count.
+func TestPruneWorkHistory(t *testing.T) {
+	tests := []struct {
+		limit uint64
+		count int
+		first uint64
+	}{
+		{6, 10, 6}, // Depth limit only
+		{0, 3, 8},  // Count limit only
+		{6, 3, 8},  // Count limit stricter than the depth
+		{8, 5, 8},  // Depth limit stricter than the count
+	}
+	for i, tt := range tests {
+		db := rawdb.NewMemoryDatabase()
+		for i := uint64(1); i <= 10; i++ {
+			writeWork(db, &workRecord{Number: i, SealHash: common.Hash{byte(i)}, Difficulty: big.NewInt(1)})
+		}
+		left := pruneHistory(db, workPrefix, tt.limit, tt.count, countHistory(db, workPrefix))
+
+		works := readWorks(db, 0)
+		if len(works) != left || len(works) != int(11-tt.first) {
+			t.Fatalf("test %d: retained work count mismatch: have %d (reported %d), want %d", i, len(works), left, 11-tt.first)
+		}
+		for j, work := range works {
+			if work.Number != tt.first+uint64(j) {
+				t.Errorf("test %d: work %d: number mismatch: have %d, want %d", i, j, work.Number, tt.first+uint64(j))
+			}
+		}
+	}
+}
+
+// Tests that miners flooding the sealer with bad solutions can't flood the work
+// history too.
+func TestWorkHistoryRejectedLimit(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+
+	ethash := NewTester(nil, true)
+	defer ethash.Close()
+	api := &API{ethash}
+	ethash.SetWorkHistory(db)
+
+	results := make(chan *types.Block, 16)
+	for number := int64(1); number <= 2; number++ {
+		header := &types.Header{Number: big.NewInt(number), Difficulty: big.NewInt(100000000)}
+		ethash.Seal(nil, types.NewBlockWithHeader(header), results, nil)
+
+		for i := 0; i < 2*maxRejectedRecords; i++ {
+			api.SubmitWork(types.BlockNonce{byte(i)}, common.Hash{byte(number), byte(i)}, common.Hash{})
+		}
+	}
+	history, err := api.GetWorkHistory(nil)
+	if err != nil {
+		t.Fatalf("failed to retrieve work history: %v", err)
+	}
+	if len(history.Unmatched) != 2*maxRejectedRecords {
+		t.Fatalf("recorded rejection count mismatch: have %d, want %d", len(history.Unmatched), 2*maxRejectedRecords)
+	}
+}
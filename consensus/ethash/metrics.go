@@ -0,0 +1,45 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Contains the metrics collected by the ethash engine.
+
+package ethash
+
+import (
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	// Rewards of the epoch active at the last finalized block, in coins
+	minerRewardGauge   = metrics.NewRegisteredGaugeFloat64("ethash/reward/miner", nil)
+	stakingRewardGauge = metrics.NewRegisteredGaugeFloat64("ethash/reward/staking", nil)
+	devFundRewardGauge = metrics.NewRegisteredGaugeFloat64("ethash/reward/devfund", nil)
+
+	// Solutions submitted through the remote sealer (getWork/submitWork)
+	workAcceptedMeter = metrics.NewRegisteredMeter("ethash/work/accepted", nil)
+	workRejectedMeter = metrics.NewRegisteredMeter("ethash/work/rejected", nil)
+	workStaleMeter    = metrics.NewRegisteredMeter("ethash/work/stale", nil)
+
+	// Rejected solutions left out of the work history
+	workUnrecordedMeter = metrics.NewRegisteredMeter("ethash/work/unrecorded", nil)
+
+	// Shares submitted by stratum miners
+	shareAcceptedMeter = metrics.NewRegisteredMeter("ethash/stratum/shares/accepted", nil)
+	shareRejectedMeter = metrics.NewRegisteredMeter("ethash/stratum/shares/rejected", nil)
+	shareStaleMeter    = metrics.NewRegisteredMeter("ethash/stratum/shares/stale", nil)
+
+	sealVerifyTimer = metrics.NewRegisteredTimer("ethash/seal/verify", nil)
+)
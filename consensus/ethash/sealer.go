@@ -20,39 +20,49 @@ import (
 	"bytes"
 	"context"
 	crand "crypto/rand"
-	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"math"
 	"math/big"
 	"math/rand"
 	"net/http"
 	"runtime"
-	"strings"
 	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	lrupkg "github.com/ethereum/go-ethereum/common/lru"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core/types"
-
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
 	// "github.com/ethereum/go-ethereum/shared"
-	"github.com/zeebo/blake3"
 )
 
 const (
 	// staleThreshold is the maximum depth of the acceptable stale but valid ethash solution.
 	staleThreshold = 7
+
+	// maxPayoutWorks is the maximum number of per-address work packages kept for
+	// the current parent. Beyond it, the least recently requested one is dropped.
+	maxPayoutWorks = 128
 )
 
 var (
 	errNoMiningWork      = errors.New("no mining work available yet")
 	errInvalidSealResult = errors.New("invalid or stale proof-of-work solution")
+	errNoPayoutBuilder   = errors.New("per-address work packages not supported")
+	errMiningWorkChanged = errors.New("mining work changed, retry")
 )
 
+// PayoutBuilder assembles a sealing block on top of the given parent with the
+// given timestamp, crediting the block rewards and fees to the given coinbase.
+// The returned block must be retained by the builder, so that a solution found
+// for it by a remote miner can be written to the chain.
+type PayoutBuilder func(parent common.Hash, timestamp uint64, coinbase common.Address) (*types.Block, error)
+
 // Seal implements consensus.Engine, attempting to find a nonce that satisfies
 // the block's difficulty requirements.
 func (ethash *Ethash) Seal(chain consensus.ChainHeaderReader, block *types.Block, results chan<- *types.Block, stop <-chan struct{}) error {
@@ -91,9 +101,12 @@ func (ethash *Ethash) Seal(chain consensus.ChainHeaderReader, block *types.Block
 	if threads < 0 {
 		threads = 0 // Allows disabling local mining without extra logic around local/remote
 	}
+	// Resolve the proof-of-work parameters the block must be sealed with
+	iters := powIterations(chain, block.Number())
+
 	// Push new work to remote sealer
 	if ethash.remote != nil {
-		ethash.remote.workCh <- &sealTask{block: block, results: results}
+		ethash.remote.workCh <- &sealTask{block: block, iterations: iters, results: results}
 	}
 	var (
 		pend   sync.WaitGroup
@@ -103,7 +116,7 @@ func (ethash *Ethash) Seal(chain consensus.ChainHeaderReader, block *types.Block
 		pend.Add(1)
 		go func(id int, nonce uint64) {
 			defer pend.Done()
-			ethash.mine(block, id, nonce, abort, locals)
+			ethash.mine(block, id, nonce, iters, abort, locals)
 		}(i, uint64(ethash.rand.Int63()))
 	}
 	// Wait until sealing is terminated or a nonce is found
@@ -113,6 +126,9 @@ func (ethash *Ethash) Seal(chain consensus.ChainHeaderReader, block *types.Block
 		case <-stop:
 			// Outside abort, stop all miner threads
 			close(abort)
+		case <-ethash.quit:
+			// Engine shut down, stop all miner threads
+			close(abort)
 		case result = <-locals:
 			// One of the threads found a block, abort all others
 			select {
@@ -134,76 +150,167 @@ func (ethash *Ethash) Seal(chain consensus.ChainHeaderReader, block *types.Block
 	return nil
 }
 
-func (ethash *Ethash) mine(block *types.Block, id int, seed uint64, abort chan struct{}, found chan *types.Block) {
+// mine is the actual proof-of-work miner that searches for a nonce starting from
+// seed that results in correct final block difficulty.
+func (ethash *Ethash) mine(block *types.Block, id int, seed uint64, iters uint64, abort chan struct{}, found chan *types.Block) {
+	// Extract some data from the header
+	var (
+		header = block.Header()
+		hash   = ethash.SealHash(header)
+		target = new(big.Int).Div(two256, header.Difficulty)
+		algo   = "Blake3"
+		pow    func(nonce uint64) ([]byte, []byte) // Generic search, nil for the batched Blake3 one
+	)
+	if ethash.config.PowMode.legacy() {
+		dataset := ethash.dataset(header.Number.Uint64(), false)
+		sealHash := hash.Bytes()
+		algo, pow = "Ethash", func(nonce uint64) ([]byte, []byte) {
+			return hashimotoFull(dataset.dataset, sealHash, nonce)
+		}
+		// Datasets are unmapped in a finalizer. Ensure that the dataset stays live
+		// during sealing so it's not unmapped while being read.
+		defer runtime.KeepAlive(dataset)
+	}
+	// Start generating random nonces until we abort or find a good one
+	logger := ethash.config.Log.New("miner", id, "algo", algo)
+	logger.Trace("Started search for new nonces", "seed", seed)
+
+	var (
+		nonce  uint64
+		digest []byte
+		ok     bool
+	)
+	if pow != nil {
+		nonce, digest, ok = search(pow, target, seed, 1<<15, abort, ethash.hashrate.Mark)
+	} else {
+		var mix common.Hash
+		nonce, mix, ok = searchBlake3(hash, iters, target, seed, 1<<15, abort, ethash.hashrate.Mark)
+		digest = mix.Bytes()
+	}
+	if !ok {
+		// Mining terminated, abort
+		logger.Trace("Nonce search aborted", "attempts", nonce-seed)
+		return
+	}
+	// Correct nonce found, create a new header with it
+	header = types.CopyHeader(header)
+	header.Nonce = types.EncodeNonce(nonce)
+	header.MixDigest = common.BytesToHash(digest)
+
+	logger.Debug("Found valid block", "nonce", nonce, "mixDigest", hex.EncodeToString(digest), "sealHash", hex.EncodeToString(hash.Bytes()))
+
+	// Seal and return a block (if still needed)
+	select {
+	case found <- block.WithSeal(header):
+		logger.Trace("Nonce found and reported", "attempts", nonce-seed, "nonce", nonce)
+	case <-abort:
+		logger.Trace("Nonce found but discarded", "attempts", nonce-seed, "nonce", nonce)
+	}
+}
+
+// search iterates the nonces from seed onwards until pow yields a result meeting
+// the target, returning the nonce and its digest, or until abort is closed. The
+// number of attempts is reported via mark every interval nonces and when the
+// search ends.
+func search(pow func(nonce uint64) ([]byte, []byte), target *big.Int, seed uint64, interval int64, abort <-chan struct{}, mark func(int64)) (uint64, []byte, bool) {
 	var (
-		header    = block.Header()
-		target    = new(big.Int).Div(two256, header.Difficulty)
 		attempts  = int64(0)
 		nonce     = seed
 		powBuffer = new(big.Int)
-		iterCount = 312688 // Number of hashing iterations
 	)
-	logger := ethash.config.Log.New("miner", id)
-	logger.Trace("Started Blake3 search for new nonces", "seed", seed)
-
-search:
 	for {
 		select {
 		case <-abort:
-			logger.Trace("Blake3 nonce search aborted", "attempts", nonce-seed)
-			ethash.hashrate.Mark(attempts)
-			break search
+			mark(attempts)
+			return nonce, nil, false
 		default:
+			// We don't have to update hash rate on every nonce, so update after after 2^X nonces
 			attempts++
-			if (attempts % (1 << 15)) == 0 {
-				ethash.hashrate.Mark(attempts)
+			if attempts%interval == 0 {
+				mark(attempts)
 				attempts = 0
 			}
-
-			// Get SealHash (header without Nonce and MixDigest)
-			sealHash := ethash.SealHash(header).Bytes()
-
-			// Create buffer for storage SealHash + Nonce
-			var buffer bytes.Buffer
-			buffer.Write(sealHash)
-			nonceBytes := make([]byte, 8)
-			binary.BigEndian.PutUint64(nonceBytes, nonce)
-			buffer.Write(nonceBytes)
-
-			// Perform multiple hashing
-			hashResult := blake3.Sum256(buffer.Bytes())
-			for i := 0; i < iterCount; i++ {
-				hashResult = blake3.Sum256(hashResult[:])
+			// Compute the PoW value of this nonce
+			digest, result := pow(nonce)
+			if powBuffer.SetBytes(result).Cmp(target) <= 0 {
+				mark(attempts)
+				return nonce, digest, true
 			}
+			nonce++
+		}
+	}
+}
 
-			// Convert hash to a number for comparison with target
-			powBuffer.SetBytes(hashResult[:])
-			if powBuffer.Cmp(target) <= 0 {
-
-				header = types.CopyHeader(header)
-				header.Nonce = types.EncodeNonce(nonce)
-				header.MixDigest = common.BytesToHash(hashResult[:])
-
-				logger.Debug("Found valid block", "nonce", nonce, "mixDigest", hex.EncodeToString(hashResult[:]), "sealHash", hex.EncodeToString(sealHash))
-
-				select {
-				case found <- block.WithSeal(header):
-					logger.Trace("Blake3 nonce found and reported", "attempts", nonce-seed, "nonce", nonce)
-				case <-abort:
-					logger.Trace("Blake3 nonce found but discarded", "attempts", nonce-seed, "nonce", nonce)
+// searchBlake3 is search specialised to the Blake3 proof-of-work. The seal hash
+// is converted once, and the nonces are hashed in batches by the vectorised
+// kernel, without any allocations per nonce. The attempts are reported via mark
+// every interval nonces, rounded up to whole batches, and when the search ends.
+func searchBlake3(sealHash common.Hash, iters uint64, target *big.Int, seed uint64, interval int64, abort <-chan struct{}, mark func(int64)) (uint64, common.Hash, bool) {
+	var (
+		hash     = toWords(sealHash[:])
+		limit    common.Hash
+		digests  [blake3MaxLanes]blake3Words
+		digest   common.Hash
+		attempts = int64(0)
+		nonce    = seed
+	)
+	// Digests are compared as big endian numbers, a target beyond 256 bits is
+	// met by all of them.
+	if target.BitLen() > 256 {
+		for i := range limit {
+			limit[i] = 0xff
+		}
+	} else {
+		target.FillBytes(limit[:])
+	}
+	for {
+		select {
+		case <-abort:
+			mark(attempts)
+			return nonce, common.Hash{}, false
+		default:
+			n := blake3ChainBatch(&hash, nonce, iters, &digests)
+			for l := 0; l < n; l++ {
+				fromWords(&digests[l], digest[:])
+				if bytes.Compare(digest[:], limit[:]) <= 0 {
+					mark(attempts + int64(l) + 1)
+					return nonce + uint64(l), digest, true
 				}
-				break search
 			}
-			nonce++
+			// Update the hash rate every interval nonces only, not every batch
+			if attempts += int64(n); attempts >= interval {
+				mark(attempts)
+				attempts = 0
+			}
+			nonce += uint64(n)
 		}
 	}
 }
 
+// SearchBlake3 runs the nonce search of the local sealer for standalone miners.
+// Starting from seed, it looks for a nonce whose digest, computed with the given
+// number of Blake3 rounds over the seal hash, meets the target. Every attempt is
+// reported via mark. False is returned if abort is closed before a nonce is found.
+func SearchBlake3(sealHash common.Hash, iters uint64, target *big.Int, seed uint64, abort <-chan struct{}, mark func(attempts int64)) (types.BlockNonce, common.Hash, bool) {
+	nonce, digest, ok := searchBlake3(sealHash, iters, target, seed, 1, abort, mark)
+	if !ok {
+		return types.BlockNonce{}, common.Hash{}, false
+	}
+	return types.EncodeNonce(nonce), digest, true
+}
+
+// Blake3Digest returns the proof-of-work digest of the seal hash and nonce,
+// computed with the given number of Blake3 rounds.
+func Blake3Digest(sealHash common.Hash, nonce uint64, iters uint64) common.Hash {
+	return common.BytesToHash(hashimotoBlake3(sealHash.Bytes(), nonce, iters))
+}
+
 // This is the timeout for HTTP requests to notify external miners.
 const remoteSealerTimeout = 1 * time.Second
 
 type remoteSealer struct {
-	works        map[common.Hash]*types.Block
+	works        map[common.Hash]*sealTask
+	payouts      lrupkg.BasicLRU[common.Address, *sealTask] // Per-address work built on the current block's parent
 	rates        map[common.Hash]hashrate
 	currentBlock *types.Block
 	currentWork  [4]string
@@ -217,17 +324,30 @@ type remoteSealer struct {
 	results      chan<- *types.Block
 	workCh       chan *sealTask   // Notification channel to push new work and relative result channel to remote sealer
 	fetchWorkCh  chan *sealWork   // Channel used for remote sealer to fetch mining work
+	payoutCh     chan *payoutWork // Channel used for remote sealer to fetch or add per-address mining work
 	submitWorkCh chan *mineResult // Channel used for remote sealer to submit their mining result
 	fetchRateCh  chan chan uint64 // Channel used to gather submitted hash rate for local or remote sealer.
 	submitRateCh chan *hashrate   // Channel used for remote sealer to submit their mining hashrate
 	requestExit  chan struct{}
 	exitCh       chan struct{}
+	workFeed     event.Feed // Feed of new work packages, consumed by the stratum server
+
+	db                ethdb.Database // Work history table, nil if the history is not persisted
+	workRecords       int            // Number of work packages in the history
+	submissionRecords int            // Number of submitted solutions in the history
+	rejectedRecords   int            // Rejected solutions recorded at the current height
+	rejectedHead      uint64         // Height the rejected solutions are counted at
+	prunedHistory     uint64         // Block number the history was last pruned below
+
+	historyCh      chan ethdb.Database  // Channel used to enable persisting the work history
+	fetchHistoryCh chan *historyRequest // Channel used to fetch the persisted work history
 }
 
 // sealTask wraps a seal block with relative result channel for remote sealer thread.
 type sealTask struct {
-	block   *types.Block
-	results chan<- *types.Block
+	block      *types.Block
+	iterations uint64 // Blake3 rounds the block's seal must be computed with
+	results    chan<- *types.Block
 }
 
 // mineResult wraps the pow solution parameters for the specified block.
@@ -254,6 +374,18 @@ type sealWork struct {
 	res  chan [4]string
 }
 
+// payoutWork wraps a request for a work package paying a specific address. If
+// task is nil, the cached package is requested, otherwise task is added to the
+// pending works. When no package is cached yet, the current task is returned
+// via base, for the requester to build the payout block upon.
+type payoutWork struct {
+	coinbase common.Address
+	task     *sealTask
+	base     chan *sealTask
+	errc     chan error
+	res      chan [4]string
+}
+
 func startRemoteSealer(ethash *Ethash, urls []string, noverify bool) *remoteSealer {
 	ctx, cancel := context.WithCancel(context.Background())
 	s := &remoteSealer{
@@ -262,15 +394,20 @@ func startRemoteSealer(ethash *Ethash, urls []string, noverify bool) *remoteSeal
 		notifyURLs:   urls,
 		notifyCtx:    ctx,
 		cancelNotify: cancel,
-		works:        make(map[common.Hash]*types.Block),
+		works:        make(map[common.Hash]*sealTask),
+		payouts:      lrupkg.NewBasicLRU[common.Address, *sealTask](maxPayoutWorks),
 		rates:        make(map[common.Hash]hashrate),
 		workCh:       make(chan *sealTask),
 		fetchWorkCh:  make(chan *sealWork),
+		payoutCh:     make(chan *payoutWork),
 		submitWorkCh: make(chan *mineResult),
 		fetchRateCh:  make(chan chan uint64),
 		submitRateCh: make(chan *hashrate),
 		requestExit:  make(chan struct{}),
 		exitCh:       make(chan struct{}),
+
+		historyCh:      make(chan ethdb.Database),
+		fetchHistoryCh: make(chan *historyRequest),
 	}
 	go s.loop()
 	return s
@@ -290,51 +427,47 @@ func (s *remoteSealer) loop() {
 	for {
 		select {
 		case work := <-s.workCh:
-			// Logging receipt of new mining task
-			// log.Printf("Liberty Project: New mining work received - BlockNumber=%d, BlockHash=%s", work.block.NumberU64(), work.block.Hash().Hex())
-
 			// Update current work with new block
 			s.results = work.results
-			s.makeWork(work.block)
+			s.makeWork(work)
 			s.notifyWork()
-			// log.Printf("Liberty Project: Current work updated - BlockNumber=%d", s.currentBlock.NumberU64())
+			s.workFeed.Send(s.currentWork)
 
 		case work := <-s.fetchWorkCh:
-			// Logging request to get current work
-			// log.Printf("Liberty Project: Fetching current mining work")
-
 			// Return current work to remote miner
 			if s.currentBlock == nil {
 				work.errc <- errNoMiningWork
-				// log.Printf("Liberty Project: No current mining work available")
 			} else {
 				work.res <- s.currentWork
-				// log.Printf("Liberty Project: Provided current work - BlockNumber=%d", s.currentBlock.NumberU64())
 			}
 
-		case result := <-s.submitWorkCh:
-			// log.Printf("Liberty Projec: Received submitted work - MinerAddress=%s, Nonce=%d, Hash=%s", result.minerAddress.Hex(), result.nonce, result.hash.Hex())
+		case req := <-s.payoutCh:
+			s.handlePayoutWork(req)
 
-			if s.submitWork(result.nonce, result.mixDigest, result.hash, result.minerAddress) {
+		case result := <-s.submitWorkCh:
+			// Verify submitted PoW solution based on maintained mining blocks.
+			if s.submitWork(result) {
 				result.errc <- nil
-				// log.Printf("Liberty Project: Work submission successful - BlockNumber=%d, SealHash=%s", s.currentBlock.NumberU64(), result.hash.Hex())
 			} else {
 				result.errc <- errInvalidSealResult
-				// log.Printf("Liberty Project: Work submission failed - BlockNumber=%d, SealHash=%s", s.currentBlock.NumberU64(), result.hash.Hex())
 			}
 
+		case db := <-s.historyCh:
+			s.openHistory(db)
+
+		case req := <-s.fetchHistoryCh:
+			s.handleHistoryRequest(req)
+
 		case result := <-s.submitRateCh:
-			// log.Printf("Liberty Project: Hash rate submission received - ID=%s, Rate=%d", result.id, result.rate)
 			s.rates[result.id] = hashrate{rate: result.rate, ping: time.Now()}
 			close(result.done)
 
 		case req := <-s.fetchRateCh:
-			// Logging total hash rate retrieval
+			// Gather all hash rate submitted by remote sealer.
 			var total uint64
 			for _, rate := range s.rates {
 				total += rate.rate
 			}
-			// log.Printf("Liberty Project: Total hash rate fetched - TotalRate=%d", total)
 			req <- total
 
 		case <-ticker.C:
@@ -342,21 +475,19 @@ func (s *remoteSealer) loop() {
 			for id, rate := range s.rates {
 				if time.Since(rate.ping) > 10*time.Second {
 					delete(s.rates, id)
-					// log.Printf("Liberty Project: Stale rate cleared - RateID=%s", id)
 				}
 			}
 			// Clean up outdated blocks from queue
 			if s.currentBlock != nil {
-				for hash, block := range s.works {
-					if block.NumberU64()+staleThreshold <= s.currentBlock.NumberU64() {
+				for hash, work := range s.works {
+					if work.block.NumberU64()+staleThreshold <= s.currentBlock.NumberU64() {
 						delete(s.works, hash)
-						// log.Printf("Liberty Project: Stale block cleared - BlockNumber=%d, BlockHash=%s", block.NumberU64(), hash.Hex())
 					}
 				}
 			}
+			s.pruneHistory()
 
 		case <-s.requestExit:
-			// log.Printf("Liberty Project: Remote sealer exiting")
 			return
 		}
 	}
@@ -364,22 +495,132 @@ func (s *remoteSealer) loop() {
 
 // makeWork creates a work package for external miner.
 //
-// The work package consists of 3 strings:
+// The work package consists of 4 strings:
 //
 //	result[0], 32 bytes hex encoded current block header pow-hash
-//	result[1], 32 bytes hex encoded seed hash used for DAG
+//	result[1], hex encoded number of Blake3 iterations (seed hash used for DAG in legacy mode)
 //	result[2], 32 bytes hex encoded boundary condition ("target"), 2^256/difficulty
 //	result[3], hex encoded block number
-func (s *remoteSealer) makeWork(block *types.Block) {
-	hash := s.ethash.SealHash(block.Header())
-	s.currentWork[0] = hash.Hex()
-	s.currentWork[1] = common.BytesToHash(SeedHash(block.NumberU64())).Hex()
-	s.currentWork[2] = common.BytesToHash(new(big.Int).Div(two256, block.Difficulty()).Bytes()).Hex()
-	s.currentWork[3] = hexutil.EncodeBig(block.Number())
+func (s *remoteSealer) makeWork(work *sealTask) {
+	// Per-address work stays valid as long as it builds on the same parent
+	if s.currentBlock != nil && s.currentBlock.ParentHash() != work.block.ParentHash() {
+		s.payouts.Purge()
+	}
+	hash := s.ethash.SealHash(work.block.Header())
+	s.currentWork = s.workPackage(work)
 
 	// Trace the seal work fetched by remote sealer.
-	s.currentBlock = block
-	s.works[hash] = block
+	s.currentBlock = work.block
+	s.works[hash] = work
+	s.recordWork(hash, work)
+}
+
+// workPackage assembles the work package handed to external miners for the
+// given seal task.
+func (s *remoteSealer) workPackage(work *sealTask) [4]string {
+	var (
+		block = work.block
+		res   [4]string
+	)
+	res[0] = s.ethash.SealHash(block.Header()).Hex()
+	if s.ethash.config.PowMode.legacy() {
+		res[1] = common.BytesToHash(SeedHash(block.NumberU64())).Hex()
+	} else {
+		res[1] = hexutil.EncodeUint64(work.iterations)
+	}
+	res[2] = common.BytesToHash(new(big.Int).Div(two256, block.Difficulty()).Bytes()).Hex()
+	res[3] = hexutil.EncodeBig(block.Number())
+	return res
+}
+
+// handlePayoutWork serves a request for a work package paying a specific
+// address, either from the cached per-address work or by registering newly
+// built work.
+func (s *remoteSealer) handlePayoutWork(req *payoutWork) {
+	if s.currentBlock == nil {
+		req.errc <- errNoMiningWork
+		return
+	}
+	// Adding newly built work, reject it if the chain moved on meanwhile
+	if req.task != nil {
+		if req.task.block.ParentHash() != s.currentBlock.ParentHash() {
+			req.errc <- errMiningWorkChanged
+			return
+		}
+		// Make room for the new work, dropping the least recently requested one
+		// so that cycling through addresses can't pile up blocks without bound.
+		if !s.payouts.Contains(req.coinbase) && s.payouts.Len() >= maxPayoutWorks {
+			if _, old, ok := s.payouts.RemoveOldest(); ok {
+				delete(s.works, s.ethash.SealHash(old.block.Header()))
+			}
+		}
+		hash := s.ethash.SealHash(req.task.block.Header())
+		s.payouts.Add(req.coinbase, req.task)
+		s.works[hash] = req.task
+		s.recordWork(hash, req.task)
+		req.res <- s.workPackage(req.task)
+		return
+	}
+	// Fetching work, serve the node's own or a cached package if available
+	if s.currentBlock.Coinbase() == req.coinbase {
+		req.res <- s.currentWork
+		return
+	}
+	if work, ok := s.payouts.Get(req.coinbase); ok {
+		req.res <- s.workPackage(work)
+		return
+	}
+	req.base <- s.works[s.ethash.SealHash(s.currentBlock.Header())]
+}
+
+// payoutWork returns a work package whose block credits the rewards to the given
+// coinbase, assembling a new block through the payout builder if needed.
+func (ethash *Ethash) payoutWork(coinbase common.Address) ([4]string, error) {
+	ethash.lock.Lock()
+	builder := ethash.payouts
+	ethash.lock.Unlock()
+
+	if ethash.remote == nil || builder == nil {
+		return [4]string{}, errNoPayoutBuilder
+	}
+	req := &payoutWork{
+		coinbase: coinbase,
+		base:     make(chan *sealTask, 1),
+		errc:     make(chan error, 1),
+		res:      make(chan [4]string, 1),
+	}
+	var base *sealTask
+	select {
+	case ethash.remote.payoutCh <- req:
+	case <-ethash.remote.exitCh:
+		return [4]string{}, errEthashStopped
+	}
+	select {
+	case work := <-req.res:
+		return work, nil
+	case err := <-req.errc:
+		return [4]string{}, err
+	case base = <-req.base:
+	}
+	// No work for this address yet, build it outside of the sealer loop, since
+	// the builder may itself be waiting on the loop to accept new work.
+	block, err := builder(base.block.ParentHash(), base.block.Time(), coinbase)
+	if err != nil {
+		return [4]string{}, err
+	}
+	req.task = &sealTask{block: block, iterations: base.iterations}
+
+	select {
+	case ethash.remote.payoutCh <- req:
+	case <-ethash.remote.exitCh:
+		return [4]string{}, errEthashStopped
+	}
+	select {
+	case work := <-req.res:
+		return work, nil
+	case err := <-req.errc:
+		return [4]string{}, err
+	}
 }
 
 // notifyWork notifies all the specified mining endpoints of the availability of
@@ -424,77 +665,82 @@ func (s *remoteSealer) sendNotification(ctx context.Context, url string, json []
 	}
 }
 
-// logWithTimestamp logs a message with timestamp in a consistent format
-func logWithTimestamp(level string, message string, fields map[string]interface{}) {
-	timestamp := time.Now().Format("01-02|15:04:05.000")
-	fieldStrings := []string{}
-	for key, value := range fields {
-		fieldStrings = append(fieldStrings, fmt.Sprintf("%s=%v", key, value))
+// submitWork verifies a solution submitted by a remote miner, handing the
+// sealed block to the miner if it is valid and recording the verdict in the
+// work history.
+func (s *remoteSealer) submitWork(result *mineResult) bool {
+	var (
+		nonce        = result.nonce
+		mixDigest    = result.mixDigest
+		sealhash     = result.hash
+		minerAddress = result.minerAddress
+	)
+	if s.currentBlock == nil {
+		s.ethash.config.Log.Error("Pending work without block", "sealhash", sealhash)
+		s.recordSubmission(0, result, SubmissionStale, "no work issued yet")
+		workRejectedMeter.Mark(1)
+		return false
 	}
-	fmt.Printf("%s [%s] %s: %s %s\n", level, timestamp, "Liberty-Node", message, strings.Join(fieldStrings, " "))
-}
-
-func (s *remoteSealer) submitWork(nonce types.BlockNonce, mixDigest common.Hash, sealhash common.Hash, minerAddress common.Address) bool {
-	// Check if work with this sealhash exists
-	block := s.works[sealhash]
-	if block == nil {
-		logWithTimestamp("WARN", "Work submitted but none pending", map[string]interface{}{
-			"sealhash":  sealhash.Hex(),
-			"curnumber": s.currentBlock.NumberU64(),
-		})
+	// Make sure the work submitted is present
+	work := s.works[sealhash]
+	if work == nil {
+		s.ethash.config.Log.Warn("Work submitted but none pending", "sealhash", sealhash, "curnumber", s.currentBlock.NumberU64())
+		s.recordSubmission(s.currentBlock.NumberU64(), result, SubmissionStale, "unknown work")
+		workStaleMeter.Mark(1)
 		return false
 	}
-
-	// Prepare header with received nonce and mixDigest
+	// Reject solutions for work paying somebody else than the submitter asked for
+	if minerAddress != (common.Address{}) && work.block.Coinbase() != minerAddress {
+		s.ethash.config.Log.Warn("Work submitted for a different payout address", "sealhash", sealhash, "payout", minerAddress, "coinbase", work.block.Coinbase())
+		s.recordSubmission(work.block.NumberU64(), result, SubmissionInvalid, "payout address mismatch")
+		workRejectedMeter.Mark(1)
+		return false
+	}
+	// Verify the correctness of submitted result.
+	block := work.block
 	header := block.Header()
 	header.Nonce = nonce
 	header.MixDigest = mixDigest
-	logWithTimestamp("INFO", "Prepared header", map[string]interface{}{
-		"nonce":     fmt.Sprintf("%x", nonce),
-		"mixDigest": mixDigest.Hex(),
-		"sealhash":  sealhash.Hex(),
-	})
 
 	start := time.Now()
 	if !s.noverify {
-		// Verify PoW with original coinbase, verification by externalMinerAddress
-		if err := s.ethash.verifySeal(nil, header, true); err != nil {
-			logWithTimestamp("WARN", "Invalid proof-of-work submitted", map[string]interface{}{
-				"sealhash": sealhash.Hex(),
-				"elapsed":  common.PrettyDuration(time.Since(start)),
-				"err":      err.Error(),
-			})
+		var err error
+		if s.ethash.config.PowMode.legacy() {
+			err = s.ethash.verifyLegacySeal(header, true)
+		} else {
+			err = s.ethash.verifyBlake3Seal(header, work.iterations)
+		}
+		sealVerifyTimer.UpdateSince(start)
+		if err != nil {
+			s.ethash.config.Log.Warn("Invalid proof-of-work submitted", "sealhash", sealhash, "elapsed", common.PrettyDuration(time.Since(start)), "err", err)
+			s.recordSubmission(block.NumberU64(), result, SubmissionInvalid, err.Error())
+			workRejectedMeter.Mark(1)
 			return false
 		}
-		logWithTimestamp("INFO", "Proof-of-work verification succeeded", map[string]interface{}{
-			"sealhash": sealhash.Hex(),
-		})
 	}
+	s.ethash.config.Log.Trace("Verified correct proof-of-work", "sealhash", sealhash, "elapsed", common.PrettyDuration(time.Since(start)))
 
-	// Solution is valid, prepare block for further processing
+	// Solutions seems to be valid, return to the miner and notify acceptance.
 	solution := block.WithSeal(header)
 
-	// Check for stale solution
+	// The submitted solution is within the scope of acceptance.
 	if solution.NumberU64()+staleThreshold > s.currentBlock.NumberU64() {
 		select {
 		case s.results <- solution:
-			logWithTimestamp("INFO", "Work accepted", map[string]interface{}{
-				"BlockNumber": solution.NumberU64(),
-				"SealHash":    sealhash.Hex(),
-			})
+			s.ethash.config.Log.Debug("Work submitted is acceptable", "number", solution.NumberU64(), "sealhash", sealhash, "hash", solution.Hash())
+			s.recordSubmission(block.NumberU64(), result, SubmissionAccepted, "")
+			workAcceptedMeter.Mark(1)
 			return true
 		default:
-			logWithTimestamp("WARN", "Sealing result not read by miner", map[string]interface{}{
-				"sealhash": sealhash.Hex(),
-			})
+			s.ethash.config.Log.Warn("Sealing result is not read by miner", "mode", "remote", "sealhash", sealhash)
+			s.recordSubmission(block.NumberU64(), result, SubmissionStale, "sealing result not read by miner")
+			workRejectedMeter.Mark(1)
 			return false
 		}
 	}
-
-	// If block is too old, log and reject
-	logWithTimestamp("WARN", "Work too old", map[string]interface{}{
-		"BlockNumber": solution.NumberU64(),
-		"SealHash":    sealhash.Hex(),
-	})
+	// The submitted block is too old to accept, drop it.
+	s.ethash.config.Log.Warn("Work submitted is too old", "number", solution.NumberU64(), "sealhash", sealhash, "hash", solution.Hash())
+	s.recordSubmission(block.NumberU64(), result, SubmissionStale, "work too old")
+	workStaleMeter.Mark(1)
 	return false
 }
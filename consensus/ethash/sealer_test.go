@@ -28,9 +28,11 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/internal/testlog"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 // Tests whether remote HTTP servers are correctly notified of new work.
@@ -64,8 +66,8 @@ func TestRemoteNotify(t *testing.T) {
 		if want := ethash.SealHash(header).Hex(); work[0] != want {
 			t.Errorf("work packet hash mismatch: have %s, want %s", work[0], want)
 		}
-		if want := common.BytesToHash(SeedHash(header.Number.Uint64())).Hex(); work[1] != want {
-			t.Errorf("work packet seed mismatch: have %s, want %s", work[1], want)
+		if want := hexutil.EncodeUint64(params.DefaultBlake3Iterations); work[1] != want {
+			t.Errorf("work packet iterations mismatch: have %s, want %s", work[1], want)
 		}
 		target := new(big.Int).Div(new(big.Int).Lsh(big.NewInt(1), 256), header.Difficulty)
 		if want := common.BytesToHash(target.Bytes()).Hex(); work[2] != want {
@@ -397,3 +399,104 @@ func TestStaleSubmission(t *testing.T) {
 		}
 	}
 }
+
+// Tests that remote miners can request and submit work packages paying their
+// own address instead of the node's coinbase.
+func TestPayoutWork(t *testing.T) {
+	ethash := NewTester(nil, true)
+	defer ethash.Close()
+	api := &API{ethash}
+
+	var (
+		node   = common.HexToAddress("0x01")
+		payout = common.HexToAddress("0x02")
+		header = &types.Header{ParentHash: common.HexToHash("0xa"), Number: big.NewInt(1), Difficulty: big.NewInt(100000000), Coinbase: node, Time: 10}
+	)
+	if _, err := api.GetWorkFor(payout); err != errNoPayoutBuilder {
+		t.Fatalf("missing builder error mismatch: have %v, want %v", err, errNoPayoutBuilder)
+	}
+	var built int
+	ethash.SetPayoutBuilder(func(parent common.Hash, timestamp uint64, coinbase common.Address) (*types.Block, error) {
+		built++
+		h := types.CopyHeader(header)
+		h.ParentHash, h.Time, h.Coinbase = parent, timestamp, coinbase
+		return types.NewBlockWithHeader(h), nil
+	})
+	if _, err := api.GetWorkFor(payout); err != errNoMiningWork {
+		t.Fatalf("missing work error mismatch: have %v, want %v", err, errNoMiningWork)
+	}
+	results := make(chan *types.Block, 1)
+	ethash.Seal(nil, types.NewBlockWithHeader(header), results, nil)
+
+	// The node's own coinbase is served the regular work
+	work, err := api.GetWork()
+	if err != nil {
+		t.Fatalf("failed to get work: %v", err)
+	}
+	if own, err := api.GetWorkFor(node); err != nil || own != work {
+		t.Fatalf("own work mismatch: have %v (%v), want %v", own, err, work)
+	}
+	// Other addresses get work with their coinbase baked into the seal hash
+	payoutHeader := types.CopyHeader(header)
+	payoutHeader.Coinbase = payout
+
+	for i := 0; i < 2; i++ {
+		work, err := api.GetWorkFor(payout)
+		if err != nil {
+			t.Fatalf("failed to get payout work: %v", err)
+		}
+		if want := ethash.SealHash(payoutHeader).Hex(); work[0] != want {
+			t.Fatalf("payout work hash mismatch: have %s, want %s", work[0], want)
+		}
+	}
+	if built != 1 {
+		t.Fatalf("payout block built %d times, want once", built)
+	}
+	nonce, digest := types.BlockNonce{0x01}, common.HexToHash("deadbeef")
+	if api.SubmitWorkFor(nonce, ethash.SealHash(payoutHeader), digest, common.HexToAddress("0x03")) {
+		t.Fatalf("solution accepted for wrong payout address")
+	}
+	if !api.SubmitWorkFor(nonce, ethash.SealHash(payoutHeader), digest, payout) {
+		t.Fatalf("payout solution rejected")
+	}
+	select {
+	case block := <-results:
+		if block.Coinbase() != payout {
+			t.Errorf("sealed block coinbase mismatch: have %x, want %x", block.Coinbase(), payout)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("sealing result timeout")
+	}
+}
+
+// Tests that per-address work packages are capped per parent, dropping the
+// least recently requested one when new addresses keep coming.
+func TestPayoutWorkLimit(t *testing.T) {
+	ethash := NewTester(nil, true)
+	defer ethash.Close()
+	api := &API{ethash}
+
+	header := &types.Header{ParentHash: common.HexToHash("0xa"), Number: big.NewInt(1), Difficulty: big.NewInt(100000000), Time: 10}
+	ethash.SetPayoutBuilder(func(parent common.Hash, timestamp uint64, coinbase common.Address) (*types.Block, error) {
+		h := types.CopyHeader(header)
+		h.ParentHash, h.Time, h.Coinbase = parent, timestamp, coinbase
+		return types.NewBlockWithHeader(h), nil
+	})
+	ethash.Seal(nil, types.NewBlockWithHeader(header), make(chan *types.Block, 1), nil)
+
+	works := make([][4]string, maxPayoutWorks+1)
+	for i := range works {
+		work, err := api.GetWorkFor(common.BigToAddress(big.NewInt(int64(i + 1))))
+		if err != nil {
+			t.Fatalf("failed to get payout work %d: %v", i, err)
+		}
+		works[i] = work
+	}
+	nonce, digest := types.BlockNonce{0x01}, common.HexToHash("deadbeef")
+	if api.SubmitWorkFor(nonce, common.HexToHash(works[0][0]), digest, common.BigToAddress(big.NewInt(1))) {
+		t.Fatalf("solution accepted for evicted payout work")
+	}
+	if !api.SubmitWorkFor(nonce, common.HexToHash(works[1][0]), digest, common.BigToAddress(big.NewInt(2))) {
+		t.Fatalf("solution rejected for retained payout work")
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+package ethash
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// DefaultStatsWindow is the number of recent blocks the network statistics
+	// are estimated over if no window is requested.
+	DefaultStatsWindow = 100
+
+	// maxStatsWindow is the largest window the statistics may be requested for,
+	// bounding the number of blocks loaded per request.
+	maxStatsWindow = 10000
+)
+
+var errStatsWindow = fmt.Errorf("stats window must be between 1 and %d blocks", maxStatsWindow)
+
+// NetworkStats are the mining statistics of the network, estimated from a window
+// of consecutive canonical blocks.
+type NetworkStats struct {
+	From       uint64      `json:"from"`       // First block of the window
+	To         uint64      `json:"to"`         // Last block of the window
+	Hashrate   *big.Int    `json:"hashrate"`   // Hashes per second needed to seal the blocks at the rate they were
+	Difficulty *big.Int    `json:"difficulty"` // Average difficulty of the blocks
+	BlockTime  float64     `json:"blockTime"`  // Average seconds between blocks
+	Uncles     uint64      `json:"uncles"`     // Uncles included by the blocks
+	UncleRate  float64     `json:"uncleRate"`  // Uncles per block, the share of sealed blocks which got orphaned
+	Miners     []MinerStat `json:"miners"`     // Coinbases of the blocks, most blocks first
+}
+
+// MinerStat is the share of the blocks of a window sealed by a coinbase.
+type MinerStat struct {
+	Coinbase common.Address `json:"coinbase"`
+	Blocks   uint64         `json:"blocks"`
+	Share    float64        `json:"share"`
+}
+
+// EstimateNetworkStats derives the network statistics from the given number of
+// canonical headers up to head, retrieved via getHeader. The uncles are only
+// retrieved via getUncles for the headers including any.
+//
+// The hashrate is the difficulty accumulated after the first block of the window
+// divided by the time it took to seal it, as the difficulty is the expected
+// number of hashes to find a block. Work spent on uncles is not accounted for.
+func EstimateNetworkStats(head uint64, window uint64, getHeader func(number uint64) *types.Header, getUncles func(hash common.Hash, number uint64) []*types.Header) (*NetworkStats, error) {
+	if window == 0 || window > maxStatsWindow {
+		return nil, errStatsWindow
+	}
+	if window > head+1 {
+		window = head + 1
+	}
+	var (
+		stats = &NetworkStats{From: head - window + 1, To: head}
+		total = new(big.Int)
+		work  = new(big.Int) // Difficulty accumulated after the first block
+		first *types.Header
+		last  *types.Header
+		mined = make(map[common.Address]uint64)
+	)
+	for number := stats.From; number <= stats.To; number++ {
+		header := getHeader(number)
+		if header == nil {
+			return nil, fmt.Errorf("header #%d not found", number)
+		}
+		if first == nil {
+			first = header
+		} else {
+			if header.ParentHash != last.Hash() {
+				return nil, errors.New("chain reorganised during estimation")
+			}
+			work.Add(work, header.Difficulty)
+		}
+		last = header
+
+		total.Add(total, header.Difficulty)
+		if header.UncleHash != types.EmptyUncleHash {
+			uncles := getUncles(header.Hash(), number)
+			if len(uncles) == 0 {
+				return nil, fmt.Errorf("uncles of block #%d not found", number)
+			}
+			stats.Uncles += uint64(len(uncles))
+		}
+		mined[header.Coinbase]++
+	}
+	stats.Difficulty = total.Div(total, new(big.Int).SetUint64(window))
+	stats.UncleRate = float64(stats.Uncles) / float64(window)
+
+	stats.Hashrate = new(big.Int)
+	if span := last.Time - first.Time; span > 0 {
+		stats.Hashrate.Div(work, new(big.Int).SetUint64(span))
+		stats.BlockTime = float64(span) / float64(window-1)
+	}
+	stats.Miners = make([]MinerStat, 0, len(mined))
+	for coinbase, blocks := range mined {
+		stats.Miners = append(stats.Miners, MinerStat{
+			Coinbase: coinbase,
+			Blocks:   blocks,
+			Share:    float64(blocks) / float64(window),
+		})
+	}
+	sort.Slice(stats.Miners, func(i, j int) bool {
+		if stats.Miners[i].Blocks != stats.Miners[j].Blocks {
+			return stats.Miners[i].Blocks > stats.Miners[j].Blocks
+		}
+		return bytes.Compare(stats.Miners[i].Coinbase[:], stats.Miners[j].Coinbase[:]) < 0
+	})
+	return stats, nil
+}
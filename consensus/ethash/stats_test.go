@@ -0,0 +1,150 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+package ethash
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// Tests that the network statistics are estimated from the recent blocks of the
+// canonical chain.
+func TestNetworkStats(t *testing.T) {
+	chain := &testBlockReader{
+		testChainReader: testChainReader{config: params.AllEthashProtocolChanges},
+		blocks:          make(map[uint64]*types.Block),
+		head:            20,
+	}
+	// Seal a block every 12 seconds at a difficulty of 1200, 2 out of 3 of them
+	// by the same miner, with a pair of uncles every fifth block.
+	var parent common.Hash
+	for i := uint64(0); i <= chain.head; i++ {
+		header := &types.Header{
+			ParentHash: parent,
+			Number:     new(big.Int).SetUint64(i),
+			Time:       12 * i,
+			Difficulty: big.NewInt(1200),
+			Coinbase:   common.Address{0xaa},
+		}
+		if i%3 == 0 {
+			header.Coinbase = common.Address{0xbb}
+		}
+		var uncles []*types.Header
+		if i%5 == 0 {
+			uncles = []*types.Header{{Number: big.NewInt(1)}, {Number: big.NewInt(2)}}
+		}
+		block := types.NewBlock(header, nil, uncles, nil, trie.NewStackTrie(nil))
+		chain.blocks[i] = block
+		parent = block.Hash()
+	}
+	api := &NetworkAPI{chain}
+
+	window := hexutil.Uint64(15)
+	stats, err := api.NetworkStats(&window)
+	if err != nil {
+		t.Fatalf("failed to estimate network stats: %v", err)
+	}
+	if stats.From != 6 || stats.To != 20 {
+		t.Errorf("window mismatch: have [%d, %d], want [6, 20]", stats.From, stats.To)
+	}
+	if stats.Hashrate.ToInt().Cmp(big.NewInt(100)) != 0 {
+		t.Errorf("hashrate mismatch: have %v, want 100", stats.Hashrate)
+	}
+	if stats.Difficulty.ToInt().Cmp(big.NewInt(1200)) != 0 {
+		t.Errorf("difficulty mismatch: have %v, want 1200", stats.Difficulty)
+	}
+	if stats.BlockTime != 12 {
+		t.Errorf("block time mismatch: have %v, want 12", stats.BlockTime)
+	}
+	if stats.Uncles != 6 || stats.UncleRate != 0.4 {
+		t.Errorf("uncles mismatch: have %d (rate %v), want 6 (rate 0.4)", stats.Uncles, stats.UncleRate)
+	}
+	want := []RPCMinerStat{
+		{Coinbase: common.Address{0xaa}, Blocks: 10, Share: 10.0 / 15},
+		{Coinbase: common.Address{0xbb}, Blocks: 5, Share: 5.0 / 15},
+	}
+	if len(stats.Miners) != len(want) {
+		t.Fatalf("miner count mismatch: have %d, want %d", len(stats.Miners), len(want))
+	}
+	for i := range want {
+		if stats.Miners[i] != want[i] {
+			t.Errorf("miner %d: mismatch: have %+v, want %+v", i, stats.Miners[i], want[i])
+		}
+	}
+	// The window must be capped by the chain length, and defaulted if omitted
+	window = 1000
+	if stats, err = api.NetworkStats(&window); err != nil {
+		t.Errorf("failed to estimate oversized window: %v", err)
+	} else if stats.From != 0 {
+		t.Errorf("oversized window mismatch: have from %d, want 0", stats.From)
+	}
+	if stats, err = api.NetworkStats(nil); err != nil {
+		t.Errorf("failed to estimate default window: %v", err)
+	} else if stats.From != 0 || stats.To != 20 {
+		t.Errorf("default window mismatch: have [%d, %d], want [0, 20]", stats.From, stats.To)
+	}
+	for _, window := range []hexutil.Uint64{0, maxStatsWindow + 1} {
+		if _, err := api.NetworkStats(&window); err != errStatsWindow {
+			t.Errorf("window %d: error mismatch: have %v, want %v", window, err, errStatsWindow)
+		}
+	}
+}
+
+// Tests that the network statistics are estimated from headers, only loading the
+// uncles of the blocks including any.
+func TestNetworkStatsHeaders(t *testing.T) {
+	var (
+		headers = make(map[uint64]*types.Header)
+		uncles  = make(map[common.Hash][]*types.Header)
+		parent  common.Hash
+	)
+	for i := uint64(0); i < 10; i++ {
+		header := &types.Header{ParentHash: parent, Number: new(big.Int).SetUint64(i), Time: 10 * i, Difficulty: big.NewInt(100), UncleHash: types.EmptyUncleHash}
+		if i == 4 {
+			header.UncleHash = common.Hash{0x01} // Uncle contents are irrelevant for the estimation
+		}
+		headers[i], parent = header, header.Hash()
+		if i == 4 {
+			uncles[parent] = []*types.Header{{Number: big.NewInt(3)}}
+		}
+	}
+	var loaded []uint64
+	stats, err := EstimateNetworkStats(9, 10, func(number uint64) *types.Header {
+		return headers[number]
+	}, func(hash common.Hash, number uint64) []*types.Header {
+		loaded = append(loaded, number)
+		return uncles[hash]
+	})
+	if err != nil {
+		t.Fatalf("failed to estimate network stats: %v", err)
+	}
+	if stats.Uncles != 1 {
+		t.Errorf("uncle count mismatch: have %d, want 1", stats.Uncles)
+	}
+	if len(loaded) != 1 || loaded[0] != 4 {
+		t.Errorf("uncles loaded for blocks %v, want [4]", loaded)
+	}
+	// Blocks whose uncles are unavailable must fail the estimation
+	if _, err := EstimateNetworkStats(9, 10, func(number uint64) *types.Header { return headers[number] }, func(common.Hash, uint64) []*types.Header { return nil }); err == nil {
+		t.Errorf("estimation succeeded without uncles")
+	}
+}
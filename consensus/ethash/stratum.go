@@ -0,0 +1,839 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethash
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// stratumProtocol is the protocol version announced to subscribing miners.
+	stratumProtocol = "EthereumStratum/1.0.0"
+
+	// stratumExtranonceSize is the number of leading nonce bytes assigned to each
+	// session, so that miners never search overlapping nonce ranges.
+	stratumExtranonceSize = 2
+
+	// stratumMaxJobs is the number of recent jobs shares are still accepted for.
+	stratumMaxJobs = 8
+
+	// stratumMaxLineSize is the maximum length of a single request line.
+	stratumMaxLineSize = 4096
+
+	// stratumWriteTimeout is the deadline for delivering a message to a miner.
+	stratumWriteTimeout = 5 * time.Second
+
+	// stratumHandshakeTimeout is the time a new connection has to subscribe and
+	// authorize, stratumIdleTimeout the longest an authorized miner may stay
+	// silent before it is disconnected.
+	stratumHandshakeTimeout = 30 * time.Second
+	stratumIdleTimeout      = 10 * time.Minute
+
+	// stratumMaxHostSessions is the maximum number of sessions connected from a
+	// single address, so that one host can't use up all the extranonces.
+	stratumMaxHostSessions = 32
+
+	// stratumShareTime is the share interval vardiff aims for on every session.
+	stratumShareTime = 10 * time.Second
+
+	// stratumRetargetTime is the minimum interval between difficulty retargets.
+	stratumRetargetTime = 30 * time.Second
+
+	// stratumDefaultDifficulty is the initial share difficulty if none is configured.
+	stratumDefaultDifficulty = 256
+
+	// stratumSubmitRate and stratumSubmitBurst limit the shares a session may
+	// submit, each of which costs a full Blake3 chain to check. Vardiff aims for
+	// a share every stratumShareTime, so honest miners stay far below.
+	stratumSubmitRate  = 5
+	stratumSubmitBurst = 20
+
+	// stratumMaxBadShares is the number of consecutive invalid or rate limited
+	// shares after which a session is dropped and its address banned for
+	// stratumBanTime.
+	stratumMaxBadShares = 16
+	stratumBanTime      = 10 * time.Minute
+)
+
+// Stratum error codes, as used by common pool software.
+const (
+	stratumErrOther         = 20
+	stratumErrJobNotFound   = 21
+	stratumErrDuplicate     = 22
+	stratumErrLowDifficulty = 23
+	stratumErrUnauthorized  = 24
+	stratumErrNotSubscribed = 25
+	stratumErrRateLimited   = 26
+)
+
+var (
+	errStratumLegacy  = errors.New("stratum server not supported in legacy mode")
+	errStratumRunning = errors.New("stratum server already running")
+)
+
+// ShareRecorder is notified about every valid share accepted by the stratum
+// server. The difficulty is the share difficulty the miner was working against,
+// number the height of the block the share was mined for.
+//
+// RecordSolution is called with the seal hash of every share that also meets
+// the block target, before it is handed to the sealer. It allows telling the
+// blocks found by stratum miners apart from the ones sealed by other means.
+type ShareRecorder interface {
+	RecordShare(worker string, difficulty uint64, number uint64)
+	RecordSolution(sealhash common.Hash)
+}
+
+// stratumRequest is a line-delimited JSON-RPC request sent by a miner.
+type stratumRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+// stratumResponse is a reply to a miner's request.
+type stratumResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result interface{}     `json:"result"`
+	Error  interface{}     `json:"error"`
+}
+
+// stratumNotification is a message pushed by the server to a miner.
+type stratumNotification struct {
+	ID     interface{}   `json:"id"`
+	Method string        `json:"method"`
+	Params []interface{} `json:"params"`
+}
+
+// stratumError builds the [code, message, traceback] error triple.
+func stratumError(code int, message string) []interface{} {
+	return []interface{}{code, message, nil}
+}
+
+// stratumJob is a work package handed out to the stratum miners.
+type stratumJob struct {
+	id       string
+	work     [4]string
+	sealHash common.Hash
+	iters    uint64
+	target   *big.Int            // Block target, 2^256/difficulty
+	shares   map[uint64]struct{} // Nonces already submitted, to reject duplicates
+}
+
+// newStratumJob parses a remote sealer work package into a stratum job.
+func newStratumJob(id string, work [4]string) (*stratumJob, error) {
+	iters, err := hexutil.DecodeUint64(work[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid iteration count %q: %v", work[1], err)
+	}
+	return &stratumJob{
+		id:       id,
+		work:     work,
+		sealHash: common.HexToHash(work[0]),
+		iters:    iters,
+		target:   new(big.Int).SetBytes(common.FromHex(work[2])),
+		shares:   make(map[uint64]struct{}),
+	}, nil
+}
+
+// stratumServer is a Stratum V1 (EthereumStratum/1.0.0) TCP server, handing out
+// the remote sealer's work to connected miners and feeding their solutions back.
+//
+// Share difficulties are expressed in raw hashes: a share of difficulty d must
+// have a digest no larger than 2^256/d, the same rule that applies to blocks.
+type stratumServer struct {
+	ethash     *Ethash
+	listener   net.Listener
+	difficulty uint64 // Initial and lowest share difficulty of the sessions
+
+	handshakeTimeout time.Duration // Time new sessions have to authorize
+	idleTimeout      time.Duration // Time authorized sessions may stay silent
+
+	workCh  chan [4]string
+	workSub event.Subscription
+
+	lock        sync.Mutex
+	sessions    map[*stratumSession]struct{}
+	extranonces map[uint16]struct{}  // Extranonces assigned to the connected sessions
+	hosts       map[string]int       // Number of connected sessions per miner address
+	bans        map[string]time.Time // Expiry of the bans of misbehaving miner addresses
+	jobs        map[string]*stratumJob
+	jobOrder    []string
+	current     *stratumJob
+	jobCounter  uint64
+	extranonce  uint16
+
+	closeOnce sync.Once
+	quit      chan struct{}
+	wg        sync.WaitGroup
+	log       log.Logger
+}
+
+// startStratumServer starts listening for stratum miners on the given address.
+func startStratumServer(ethash *Ethash, addr string, difficulty uint64) (*stratumServer, error) {
+	if ethash.config.PowMode.legacy() {
+		return nil, errStratumLegacy
+	}
+	if ethash.remote == nil {
+		return nil, errors.New("remote sealer not running")
+	}
+	if difficulty == 0 {
+		difficulty = stratumDefaultDifficulty
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s := &stratumServer{
+		ethash:      ethash,
+		listener:    listener,
+		difficulty:  difficulty,
+		workCh:      make(chan [4]string, 16),
+		sessions:    make(map[*stratumSession]struct{}),
+		extranonces: make(map[uint16]struct{}),
+		hosts:       make(map[string]int),
+		bans:        make(map[string]time.Time),
+		jobs:        make(map[string]*stratumJob),
+		quit:        make(chan struct{}),
+		log:         ethash.config.Log.New("stratum", listener.Addr()),
+
+		handshakeTimeout: stratumHandshakeTimeout,
+		idleTimeout:      stratumIdleTimeout,
+	}
+	s.workSub = ethash.remote.workFeed.Subscribe(s.workCh)
+
+	s.wg.Add(2)
+	go s.acceptLoop()
+	go s.workLoop()
+
+	s.log.Info("Stratum server started", "difficulty", difficulty)
+	return s, nil
+}
+
+// close stops accepting miners, disconnects the connected ones and waits for
+// all background goroutines to exit.
+func (s *stratumServer) close() {
+	s.closeOnce.Do(func() {
+		close(s.quit)
+		s.workSub.Unsubscribe()
+		s.listener.Close()
+
+		s.lock.Lock()
+		for session := range s.sessions {
+			session.conn.Close()
+		}
+		s.lock.Unlock()
+
+		s.wg.Wait()
+		s.log.Info("Stratum server stopped")
+	})
+}
+
+// acceptLoop accepts incoming miner connections until the server is closed.
+func (s *stratumServer) acceptLoop() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			select {
+			case <-s.quit:
+				return
+			default:
+			}
+			s.log.Warn("Failed to accept stratum connection", "err", err)
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+		s.lock.Lock()
+		select {
+		case <-s.quit:
+			// Closed while accepting, the session was missed by close
+			s.lock.Unlock()
+			conn.Close()
+			return
+		default:
+		}
+		session, err := s.newSession(conn)
+		if err != nil {
+			s.lock.Unlock()
+			s.log.Debug("Refused stratum connection", "remote", conn.RemoteAddr(), "err", err)
+			conn.Close()
+			continue
+		}
+		s.sessions[session] = struct{}{}
+		s.lock.Unlock()
+
+		s.wg.Add(1)
+		go session.serve()
+	}
+}
+
+// workLoop turns new work packages of the remote sealer into jobs and pushes
+// them to all authorized sessions.
+func (s *stratumServer) workLoop() {
+	defer s.wg.Done()
+
+	// Pick up the work that's already pending, if any
+	if work, err := (&API{s.ethash}).GetWork(); err == nil {
+		s.newJob(work)
+	}
+	for {
+		select {
+		case work := <-s.workCh:
+			s.newJob(work)
+		case <-s.workSub.Err():
+			return
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// newJob registers a new job, evicting the oldest one if too many are tracked,
+// and notifies all authorized sessions about it.
+func (s *stratumServer) newJob(work [4]string) {
+	s.lock.Lock()
+	if s.current != nil && s.current.work == work {
+		s.lock.Unlock()
+		return
+	}
+	s.jobCounter++
+	job, err := newStratumJob(strconv.FormatUint(s.jobCounter, 16), work)
+	if err != nil {
+		s.lock.Unlock()
+		s.log.Warn("Failed to create stratum job", "err", err)
+		return
+	}
+	// Jobs of a new block height invalidate everything that came before
+	clean := s.current == nil || s.current.work[3] != work[3]
+
+	s.jobs[job.id] = job
+	s.jobOrder = append(s.jobOrder, job.id)
+	if len(s.jobOrder) > stratumMaxJobs {
+		delete(s.jobs, s.jobOrder[0])
+		s.jobOrder = s.jobOrder[1:]
+	}
+	s.current = job
+
+	sessions := make([]*stratumSession, 0, len(s.sessions))
+	for session := range s.sessions {
+		sessions = append(sessions, session)
+	}
+	s.lock.Unlock()
+
+	for _, session := range sessions {
+		session.notify(job, clean)
+	}
+}
+
+// job returns the tracked job with the given id, or the current one if the id
+// is empty.
+func (s *stratumServer) job(id string) *stratumJob {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if id == "" {
+		return s.current
+	}
+	return s.jobs[id]
+}
+
+// recordShare marks the nonce as submitted for the job, returning false if it
+// was submitted before.
+func (s *stratumServer) recordShare(job *stratumJob, nonce uint64) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := job.shares[nonce]; ok {
+		return false
+	}
+	job.shares[nonce] = struct{}{}
+	return true
+}
+
+// submitBlock hands a block solution found by a stratum miner to the remote
+// sealer, returning whether it was accepted.
+func (s *stratumServer) submitBlock(job *stratumJob, nonce uint64, digest common.Hash) bool {
+	errc := make(chan error, 1)
+	select {
+	case s.ethash.remote.submitWorkCh <- &mineResult{
+		nonce:     types.EncodeNonce(nonce),
+		mixDigest: digest,
+		hash:      job.sealHash,
+		errc:      errc,
+	}:
+	case <-s.ethash.remote.exitCh:
+		return false
+	}
+	return <-errc == nil
+}
+
+// submitRate reports the estimated hash rate of a session to the remote sealer.
+func (s *stratumServer) submitRate(id common.Hash, rate uint64) {
+	done := make(chan struct{}, 1)
+	select {
+	case s.ethash.remote.submitRateCh <- &hashrate{done: done, rate: rate, id: id}:
+		<-done
+	case <-s.ethash.remote.exitCh:
+	}
+}
+
+// newSession allocates an extranonce not used by any connected session and
+// tracks state for a new miner connection, unless the miner's address is banned
+// or already has too many sessions.
+//
+// Note, this method assumes the server lock is held!
+func (s *stratumServer) newSession(conn net.Conn) (*stratumSession, error) {
+	host := stratumHost(conn.RemoteAddr())
+	if expiry, ok := s.bans[host]; ok {
+		if time.Now().Before(expiry) {
+			return nil, errors.New("banned")
+		}
+		delete(s.bans, host)
+	}
+	if s.hosts[host] >= stratumMaxHostSessions {
+		return nil, errors.New("too many sessions from host")
+	}
+	if len(s.extranonces) > math.MaxUint16 {
+		return nil, errors.New("too many sessions")
+	}
+	for {
+		s.extranonce++
+		if _, ok := s.extranonces[s.extranonce]; !ok {
+			break
+		}
+	}
+	extranonce := s.extranonce
+	s.extranonces[extranonce] = struct{}{}
+	s.hosts[host]++
+
+	return &stratumSession{
+		server:       s,
+		conn:         conn,
+		host:         host,
+		id:           crypto.Keccak256Hash([]byte(conn.RemoteAddr().String()), []byte{byte(extranonce >> 8), byte(extranonce)}),
+		extranonce:   extranonce,
+		limiter:      rate.NewLimiter(stratumSubmitRate, stratumSubmitBurst),
+		difficulty:   s.difficulty,
+		minimum:      s.difficulty,
+		lastRetarget: time.Now(),
+		handshake:    time.Now().Add(s.handshakeTimeout),
+		idleTimeout:  s.idleTimeout,
+		log:          s.log.New("remote", conn.RemoteAddr()),
+	}, nil
+}
+
+// releaseSession frees the extranonce and the host slot of a disconnected
+// session.
+//
+// Note, this method assumes the server lock is held!
+func (s *stratumServer) releaseSession(sess *stratumSession) {
+	delete(s.sessions, sess)
+	delete(s.extranonces, sess.extranonce)
+	if s.hosts[sess.host] > 1 {
+		s.hosts[sess.host]--
+	} else {
+		delete(s.hosts, sess.host)
+	}
+}
+
+// ban refuses new connections from the given miner address for stratumBanTime,
+// dropping expired bans along the way.
+func (s *stratumServer) ban(host string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := time.Now()
+	for banned, expiry := range s.bans {
+		if now.After(expiry) {
+			delete(s.bans, banned)
+		}
+	}
+	s.bans[host] = now.Add(stratumBanTime)
+}
+
+// stratumHost returns the IP address of a miner connection, the unit bans are
+// applied to.
+func stratumHost(addr net.Addr) string {
+	if host, _, err := net.SplitHostPort(addr.String()); err == nil {
+		return host
+	}
+	return addr.String()
+}
+
+// stratumSession is the state of a single connected stratum miner.
+type stratumSession struct {
+	server     *stratumServer
+	conn       net.Conn
+	host       string      // Address of the miner, bans apply to it
+	id         common.Hash // Identifier for hash rate reporting
+	extranonce uint16
+	limiter    *rate.Limiter // Limits the share submissions of the miner
+	log        log.Logger
+
+	handshake   time.Time     // Deadline for the miner to subscribe and authorize
+	idleTimeout time.Duration // Longest silence of the authorized miner
+
+	writeLock sync.Mutex // Serializes writes to the connection
+
+	lock         sync.Mutex
+	subscribed   bool
+	authorized   bool
+	worker       string
+	difficulty   uint64    // Current share difficulty
+	minimum      uint64    // Lowest difficulty accepted until the next job, covering in-flight shares after a retarget
+	shares       uint64    // Accepted shares since the last retarget
+	badShares    int       // Consecutive invalid or rate limited shares, the session is dropped once too many
+	lastRetarget time.Time // Time of the last difficulty retarget
+}
+
+// serve reads and handles the miner's requests until the connection drops.
+func (sess *stratumSession) serve() {
+	defer sess.server.wg.Done()
+	defer func() {
+		sess.conn.Close()
+
+		sess.server.lock.Lock()
+		sess.server.releaseSession(sess)
+		sess.server.lock.Unlock()
+		sess.log.Debug("Stratum miner disconnected")
+	}()
+	sess.log.Debug("Stratum miner connected")
+
+	scanner := bufio.NewScanner(sess.conn)
+	scanner.Buffer(make([]byte, 0, stratumMaxLineSize), stratumMaxLineSize)
+	for {
+		// Miners that don't authorize in time or go silent are dropped
+		sess.conn.SetReadDeadline(sess.readDeadline(time.Now()))
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				sess.log.Debug("Stratum connection failed", "err", err)
+			}
+			return
+		}
+		line := scanner.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+		var req stratumRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			sess.log.Debug("Invalid stratum request", "err", err)
+			return
+		}
+		sess.handle(&req)
+	}
+}
+
+// readDeadline returns the time the next request of the miner must arrive by.
+func (sess *stratumSession) readDeadline(now time.Time) time.Time {
+	sess.lock.Lock()
+	defer sess.lock.Unlock()
+
+	if !sess.authorized {
+		return sess.handshake
+	}
+	return now.Add(sess.idleTimeout)
+}
+
+// handle dispatches a single miner request.
+func (sess *stratumSession) handle(req *stratumRequest) {
+	switch req.Method {
+	case "mining.subscribe":
+		sess.lock.Lock()
+		sess.subscribed = true
+		sess.lock.Unlock()
+
+		extranonce := make([]byte, stratumExtranonceSize)
+		binary.BigEndian.PutUint16(extranonce, sess.extranonce)
+		sess.reply(req.ID, []interface{}{
+			[]interface{}{"mining.notify", hex.EncodeToString(sess.id[:8]), stratumProtocol},
+			hex.EncodeToString(extranonce),
+		}, nil)
+
+	case "mining.extranonce.subscribe":
+		// Extranonces never change during a session, nothing to track
+		sess.reply(req.ID, true, nil)
+
+	case "mining.authorize":
+		var worker string
+		if len(req.Params) > 0 {
+			json.Unmarshal(req.Params[0], &worker)
+		}
+		sess.lock.Lock()
+		if !sess.subscribed {
+			sess.lock.Unlock()
+			sess.reply(req.ID, nil, stratumError(stratumErrNotSubscribed, "not subscribed"))
+			return
+		}
+		sess.authorized, sess.worker = true, worker
+		sess.lock.Unlock()
+
+		sess.reply(req.ID, true, nil)
+		sess.log.Debug("Stratum miner authorized", "worker", worker)
+		if job := sess.server.job(""); job != nil {
+			sess.notify(job, true)
+		}
+
+	case "mining.submit":
+		sess.submit(req)
+
+	default:
+		sess.reply(req.ID, nil, stratumError(stratumErrOther, "unsupported method "+req.Method))
+	}
+}
+
+// submit validates a share submitted by the miner, forwarding it to the remote
+// sealer if it also satisfies the block difficulty.
+func (sess *stratumSession) submit(req *stratumRequest) {
+	sess.lock.Lock()
+	authorized, worker, difficulty, minimum := sess.authorized, sess.worker, sess.difficulty, sess.minimum
+	sess.lock.Unlock()
+
+	if !authorized {
+		sess.reject(req.ID, stratumErrUnauthorized, "unauthorized worker", true)
+		return
+	}
+	// Checking a share is expensive, refuse floods before doing any work. Floods
+	// that go on get the miner banned like invalid shares do.
+	if !sess.limiter.Allow() {
+		sess.reject(req.ID, stratumErrRateLimited, "rate limited", true)
+		return
+	}
+	var params []string
+	for _, raw := range req.Params {
+		var param string
+		if err := json.Unmarshal(raw, &param); err != nil {
+			sess.reject(req.ID, stratumErrOther, "invalid parameters", true)
+			return
+		}
+		params = append(params, param)
+	}
+	if len(params) < 3 {
+		sess.reject(req.ID, stratumErrOther, "invalid parameters", true)
+		return
+	}
+	job := sess.server.job(params[1])
+	if job == nil {
+		sess.reply(req.ID, nil, stratumError(stratumErrJobNotFound, "job not found"))
+		shareStaleMeter.Mark(1)
+		return
+	}
+	nonce, err := sess.nonce(params[2])
+	if err != nil {
+		sess.reject(req.ID, stratumErrOther, err.Error(), true)
+		return
+	}
+	if !sess.server.recordShare(job, nonce) {
+		sess.reject(req.ID, stratumErrDuplicate, "duplicate share", true)
+		return
+	}
+	digest := hashimotoBlake3(job.sealHash.Bytes(), nonce, job.iters)
+	result := new(big.Int).SetBytes(digest)
+
+	if result.Cmp(new(big.Int).Div(two256, new(big.Int).SetUint64(minimum))) > 0 {
+		sess.reject(req.ID, stratumErrLowDifficulty, "low difficulty share", true)
+		return
+	}
+	// Shares still in flight from before a retarget only count at the old difficulty
+	if result.Cmp(new(big.Int).Div(two256, new(big.Int).SetUint64(difficulty))) > 0 {
+		difficulty = minimum
+	}
+	if result.Cmp(job.target) <= 0 {
+		if recorder := sess.server.ethash.shareRecorder(); recorder != nil {
+			recorder.RecordSolution(job.sealHash)
+		}
+		if sess.server.submitBlock(job, nonce, common.BytesToHash(digest)) {
+			sess.log.Info("Stratum miner found block", "worker", sess.worker, "number", job.work[3], "sealhash", job.sealHash)
+		} else {
+			sess.log.Warn("Stratum block solution rejected", "worker", sess.worker, "number", job.work[3], "sealhash", job.sealHash)
+		}
+	}
+	sess.reply(req.ID, true, nil)
+	shareAcceptedMeter.Mark(1)
+
+	sess.lock.Lock()
+	sess.shares++
+	sess.badShares = 0
+	sess.lock.Unlock()
+
+	if recorder := sess.server.ethash.shareRecorder(); recorder != nil {
+		number, _ := hexutil.DecodeUint64(job.work[3])
+		recorder.RecordShare(worker, difficulty, number)
+	}
+	sess.retarget(time.Now())
+}
+
+// reject refuses a share with the given error. Invalid and rate limited shares
+// count against the session, which is dropped and its address banned once it
+// sent too many of them in a row.
+func (sess *stratumSession) reject(id json.RawMessage, code int, message string, invalid bool) {
+	sess.reply(id, nil, stratumError(code, message))
+	shareRejectedMeter.Mark(1)
+	if !invalid {
+		return
+	}
+	sess.lock.Lock()
+	sess.badShares++
+	drop := sess.badShares >= stratumMaxBadShares
+	sess.lock.Unlock()
+
+	if drop {
+		sess.log.Warn("Banning misbehaving stratum miner", "worker", sess.worker, "shares", stratumMaxBadShares, "duration", stratumBanTime)
+		sess.server.ban(sess.host)
+		sess.conn.Close()
+	}
+}
+
+// nonce assembles the full nonce from the session's extranonce and the hex
+// encoded suffix searched by the miner.
+func (sess *stratumSession) nonce(suffix string) (uint64, error) {
+	suffix = strings.TrimPrefix(suffix, "0x")
+	if len(suffix) != 2*(8-stratumExtranonceSize) {
+		return 0, fmt.Errorf("invalid nonce length %d", len(suffix))
+	}
+	blob, err := hex.DecodeString(suffix)
+	if err != nil {
+		return 0, fmt.Errorf("invalid nonce: %v", err)
+	}
+	var nonce [8]byte
+	binary.BigEndian.PutUint16(nonce[:], sess.extranonce)
+	copy(nonce[stratumExtranonceSize:], blob)
+	return binary.BigEndian.Uint64(nonce[:]), nil
+}
+
+// retarget adjusts the session's share difficulty to the observed share rate
+// once enough time passed since the last adjustment, and reports the estimated
+// hash rate of the miner.
+func (sess *stratumSession) retarget(now time.Time) {
+	sess.lock.Lock()
+	elapsed := now.Sub(sess.lastRetarget)
+	if elapsed < stratumRetargetTime {
+		sess.lock.Unlock()
+		return
+	}
+	old, shares := sess.difficulty, sess.shares
+	sess.difficulty = stratumRetarget(old, shares, elapsed, sess.server.difficulty)
+	if sess.difficulty < sess.minimum {
+		sess.minimum = sess.difficulty
+	}
+	sess.shares, sess.lastRetarget = 0, now
+	changed := sess.difficulty != old
+	difficulty := sess.difficulty
+	sess.lock.Unlock()
+
+	sess.server.submitRate(sess.id, saturateUint64(float64(old)*float64(shares)/elapsed.Seconds()))
+	if changed {
+		sess.log.Debug("Retargeted stratum difficulty", "worker", sess.worker, "old", old, "new", difficulty)
+		sess.send(&stratumNotification{Method: "mining.set_difficulty", Params: []interface{}{difficulty}})
+	}
+}
+
+// stratumRetarget calculates the share difficulty that yields one share every
+// stratumShareTime, given the number of shares found at the current difficulty
+// over the elapsed time. Adjustments are capped at a factor of four, and the
+// difficulty never drops below the floor: every share costs a full Blake3 chain
+// to check, so trivial shares would let a miner burn the node's CPU.
+func stratumRetarget(difficulty uint64, shares uint64, elapsed time.Duration, floor uint64) uint64 {
+	next := saturateUint64(float64(difficulty) * float64(shares) * stratumShareTime.Seconds() / elapsed.Seconds())
+	if next < difficulty/4 {
+		next = difficulty / 4
+	}
+	if difficulty <= math.MaxUint64/4 && next > difficulty*4 {
+		next = difficulty * 4
+	}
+	if next < floor {
+		next = floor
+	}
+	return next
+}
+
+// saturateUint64 converts a non-negative float to an integer, capped at the
+// largest uint64 instead of overflowing.
+func saturateUint64(f float64) uint64 {
+	if f >= math.MaxUint64 {
+		return math.MaxUint64
+	}
+	return uint64(f)
+}
+
+// notify pushes a job to the miner, preceded by its current share difficulty.
+func (sess *stratumSession) notify(job *stratumJob, clean bool) {
+	sess.lock.Lock()
+	authorized := sess.authorized
+	sess.lock.Unlock()
+	if !authorized {
+		return
+	}
+	// Idle miners never submit, so retarget them on new work too
+	sess.retarget(time.Now())
+
+	sess.lock.Lock()
+	sess.minimum = sess.difficulty
+	difficulty := sess.difficulty
+	sess.lock.Unlock()
+
+	sess.send(&stratumNotification{Method: "mining.set_difficulty", Params: []interface{}{difficulty}})
+	sess.send(&stratumNotification{Method: "mining.notify", Params: []interface{}{
+		job.id, job.work[1], strings.TrimPrefix(job.work[0], "0x"), clean,
+	}})
+}
+
+// reply sends the response to a miner's request.
+func (sess *stratumSession) reply(id json.RawMessage, result interface{}, err interface{}) {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	sess.send(&stratumResponse{ID: id, Result: result, Error: err})
+}
+
+// send writes a single message to the miner, dropping the connection if it
+// cannot be delivered in time.
+func (sess *stratumSession) send(msg interface{}) {
+	blob, err := json.Marshal(msg)
+	if err != nil {
+		sess.log.Error("Failed to encode stratum message", "err", err)
+		return
+	}
+	sess.writeLock.Lock()
+	defer sess.writeLock.Unlock()
+
+	sess.conn.SetWriteDeadline(time.Now().Add(stratumWriteTimeout))
+	if _, err := sess.conn.Write(append(blob, '\n')); err != nil {
+		sess.log.Debug("Failed to send stratum message", "err", err)
+		sess.conn.Close()
+	}
+}
@@ -0,0 +1,417 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethash
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/internal/testlog"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// stratumTestClient is a minimal in-process stratum miner.
+type stratumTestClient struct {
+	t      *testing.T
+	conn   net.Conn
+	reader *bufio.Reader
+	nextID int
+}
+
+// stratumTestMessage is any message received from the server.
+type stratumTestMessage struct {
+	ID     *int              `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+	Result json.RawMessage   `json:"result"`
+	Error  []interface{}     `json:"error"`
+}
+
+func newStratumTestClient(t *testing.T, addr net.Addr) *stratumTestClient {
+	conn, err := net.Dial("tcp", addr.String())
+	if err != nil {
+		t.Fatalf("failed to dial stratum server: %v", err)
+	}
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	return &stratumTestClient{t: t, conn: conn, reader: bufio.NewReader(conn)}
+}
+
+// read returns the next message sent by the server.
+func (c *stratumTestClient) read() *stratumTestMessage {
+	line, err := c.reader.ReadBytes('\n')
+	if err != nil {
+		c.t.Fatalf("failed to read stratum message: %v", err)
+	}
+	var msg stratumTestMessage
+	if err := json.Unmarshal(line, &msg); err != nil {
+		c.t.Fatalf("failed to decode stratum message %q: %v", line, err)
+	}
+	return &msg
+}
+
+// call sends a request and returns the response, skipping notifications.
+func (c *stratumTestClient) call(method string, params ...interface{}) *stratumTestMessage {
+	c.nextID++
+	blob, _ := json.Marshal(map[string]interface{}{"id": c.nextID, "method": method, "params": params})
+	if _, err := c.conn.Write(append(blob, '\n')); err != nil {
+		c.t.Fatalf("failed to send stratum request: %v", err)
+	}
+	for {
+		if msg := c.read(); msg.ID != nil && *msg.ID == c.nextID {
+			return msg
+		}
+	}
+}
+
+// expect reads the next message and checks it's a notification of the method.
+func (c *stratumTestClient) expect(method string) []json.RawMessage {
+	msg := c.read()
+	if msg.Method != method {
+		c.t.Fatalf("unexpected message: have %q, want %q", msg.Method, method)
+	}
+	return msg.Params
+}
+
+// Tests the subscribe/authorize/notify/submit flow of the stratum server,
+// including share validation and forwarding of block solutions.
+func TestStratumServer(t *testing.T) {
+	config := Config{
+		PowMode: ModeTest,
+		Log:     testlog.Logger(t, log.LvlWarn),
+	}
+	ethash := New(config, nil, false, t.TempDir())
+	defer ethash.Close()
+	ethash.SetThreads(-1)
+
+	if err := ethash.StartStratum("127.0.0.1:0", 1); err != nil {
+		t.Fatalf("failed to start stratum server: %v", err)
+	}
+
+	if err := ethash.StartStratum("127.0.0.1:0", 1); err != errStratumRunning {
+		t.Fatalf("restart error mismatch: have %v, want %v", err, errStratumRunning)
+	}
+	client := newStratumTestClient(t, ethash.stratum.listener.Addr())
+	defer client.conn.Close()
+
+	// Submitting or authorizing before subscribing must fail
+	if msg := client.call("mining.submit", "worker", "1", "000000000000"); msg.Error == nil || msg.Error[0].(float64) != stratumErrUnauthorized {
+		t.Fatalf("unauthorized submit error mismatch: %v", msg.Error)
+	}
+	if msg := client.call("mining.authorize", "worker", "x"); msg.Error == nil || msg.Error[0].(float64) != stratumErrNotSubscribed {
+		t.Fatalf("unsubscribed authorize error mismatch: %v", msg.Error)
+	}
+	// Subscribe and check the assigned extranonce
+	var subscription []interface{}
+	if err := json.Unmarshal(client.call("mining.subscribe", "test-miner", stratumProtocol).Result, &subscription); err != nil {
+		t.Fatalf("invalid subscribe result: %v", err)
+	}
+	extranonce, err := hex.DecodeString(subscription[1].(string))
+	if err != nil || len(extranonce) != stratumExtranonceSize {
+		t.Fatalf("invalid extranonce %v: %v", subscription[1], err)
+	}
+	if msg := client.call("mining.extranonce.subscribe"); string(msg.Result) != "true" {
+		t.Fatalf("extranonce subscription failed: %v", msg.Error)
+	}
+	if msg := client.call("mining.authorize", "worker", "x"); string(msg.Result) != "true" {
+		t.Fatalf("authorization failed: %v", msg.Error)
+	}
+	// Push new work and wait for the job to arrive
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(4)}
+	results := make(chan *types.Block, 1)
+	if err := ethash.Seal(nil, types.NewBlockWithHeader(header), results, nil); err != nil {
+		t.Fatalf("failed to seal: %v", err)
+	}
+	var difficulty uint64
+	json.Unmarshal(client.expect("mining.set_difficulty")[0], &difficulty)
+	if difficulty != 1 {
+		t.Fatalf("share difficulty mismatch: have %d, want 1", difficulty)
+	}
+	var (
+		job   = client.expect("mining.notify")
+		jobID string
+		iters string
+		hash  string
+	)
+	json.Unmarshal(job[0], &jobID)
+	json.Unmarshal(job[1], &iters)
+	json.Unmarshal(job[2], &hash)
+	if want := hexutil.EncodeUint64(params.DefaultBlake3Iterations); iters != want {
+		t.Fatalf("job iterations mismatch: have %s, want %s", iters, want)
+	}
+	if want := ethash.SealHash(header).Hex()[2:]; hash != want {
+		t.Fatalf("job header hash mismatch: have %s, want %s", hash, want)
+	}
+	// Submit shares until one of them solves the block as well
+	target := new(big.Int).Div(two256, header.Difficulty)
+	for suffix := uint64(0); ; suffix++ {
+		nonceSuffix := fmt.Sprintf("%012x", suffix)
+		if msg := client.call("mining.submit", "worker", jobID, nonceSuffix); string(msg.Result) != "true" {
+			t.Fatalf("share %d rejected: %v", suffix, msg.Error)
+		}
+		var nonce [8]byte
+		copy(nonce[:], extranonce)
+		blob, _ := hex.DecodeString(nonceSuffix)
+		copy(nonce[stratumExtranonceSize:], blob)
+
+		digest := hashimotoBlake3(ethash.SealHash(header).Bytes(), binary.BigEndian.Uint64(nonce[:]), params.DefaultBlake3Iterations)
+		if new(big.Int).SetBytes(digest).Cmp(target) <= 0 {
+			select {
+			case block := <-results:
+				if block.Nonce() != binary.BigEndian.Uint64(nonce[:]) {
+					t.Fatalf("sealed nonce mismatch: have %x, want %x", block.Nonce(), nonce)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("block solution not forwarded to the sealer")
+			}
+			// Resubmitting the same share must be rejected
+			if msg := client.call("mining.submit", "worker", jobID, nonceSuffix); msg.Error == nil || msg.Error[0].(float64) != stratumErrDuplicate {
+				t.Fatalf("duplicate share error mismatch: %v", msg.Error)
+			}
+			break
+		}
+	}
+	// Unknown jobs and malformed nonces must be rejected
+	if msg := client.call("mining.submit", "worker", "ffff", "000000000000"); msg.Error == nil || msg.Error[0].(float64) != stratumErrJobNotFound {
+		t.Fatalf("unknown job error mismatch: %v", msg.Error)
+	}
+	if msg := client.call("mining.submit", "worker", jobID, "00"); msg.Error == nil || msg.Error[0].(float64) != stratumErrOther {
+		t.Fatalf("malformed nonce error mismatch: %v", msg.Error)
+	}
+}
+
+// Tests that the stratum server refuses shares above the session difficulty.
+func TestStratumLowDifficultyShare(t *testing.T) {
+	config := Config{
+		PowMode: ModeTest,
+		Log:     testlog.Logger(t, log.LvlWarn),
+	}
+	ethash := New(config, nil, false, t.TempDir())
+	defer ethash.Close()
+	ethash.SetThreads(-1)
+
+	if err := ethash.StartStratum("127.0.0.1:0", 1<<62); err != nil {
+		t.Fatalf("failed to start stratum server: %v", err)
+	}
+
+	client := newStratumTestClient(t, ethash.stratum.listener.Addr())
+	defer client.conn.Close()
+
+	client.call("mining.subscribe", "test-miner", stratumProtocol)
+	client.call("mining.authorize", "worker", "x")
+
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1 << 62)}
+	ethash.Seal(nil, types.NewBlockWithHeader(header), make(chan *types.Block, 1), nil)
+
+	client.expect("mining.set_difficulty")
+	var jobID string
+	json.Unmarshal(client.expect("mining.notify")[0], &jobID)
+
+	if msg := client.call("mining.submit", "worker", jobID, "000000000000"); msg.Error == nil || msg.Error[0].(float64) != stratumErrLowDifficulty {
+		t.Fatalf("low difficulty share error mismatch: %v", msg.Error)
+	}
+}
+
+// Tests the vardiff retargeting towards the configured share time.
+func TestStratumRetarget(t *testing.T) {
+	tests := []struct {
+		difficulty uint64
+		shares     uint64
+		elapsed    time.Duration
+		floor      uint64
+		want       uint64
+	}{
+		{1000, 3, 30 * time.Second, 1, 1000},  // On target
+		{1000, 6, 30 * time.Second, 1, 2000},  // Shares twice as fast
+		{1000, 60, 30 * time.Second, 1, 4000}, // Capped upwards
+		{1000, 0, 30 * time.Second, 1, 250},   // Idle, capped downwards
+		{2, 0, time.Minute, 1, 1},             // Never below one
+		{1000, 0, 30 * time.Second, 500, 500}, // Never below the configured difficulty
+
+		{math.MaxUint64 / 2, 60, 30 * time.Second, 1, math.MaxUint64}, // Saturated instead of overflowing
+	}
+	for i, tt := range tests {
+		if have := stratumRetarget(tt.difficulty, tt.shares, tt.elapsed, tt.floor); have != tt.want {
+			t.Errorf("test %d: difficulty mismatch: have %d, want %d", i, have, tt.want)
+		}
+	}
+}
+
+// Tests that extranonces are never shared by connected sessions, even after the
+// counter wrapped around.
+func TestStratumExtranonceReuse(t *testing.T) {
+	s := &stratumServer{
+		extranonces: make(map[uint16]struct{}),
+		hosts:       make(map[string]int),
+		bans:        make(map[string]time.Time),
+		extranonce:  math.MaxUint16 - 1,
+		log:         log.Root(),
+	}
+	s.extranonces[0], s.extranonces[1] = struct{}{}, struct{}{}
+
+	conn, _ := net.Pipe()
+	for _, want := range []uint16{math.MaxUint16, 2, 3} {
+		session, err := s.newSession(conn)
+		if err != nil {
+			t.Fatalf("failed to create session: %v", err)
+		}
+		if session.extranonce != want {
+			t.Fatalf("extranonce mismatch: have %d, want %d", session.extranonce, want)
+		}
+	}
+}
+
+// Tests that a single address can't hold more than stratumMaxHostSessions
+// sessions, and that closed sessions free their slot.
+func TestStratumHostLimit(t *testing.T) {
+	s := &stratumServer{
+		extranonces: make(map[uint16]struct{}),
+		hosts:       make(map[string]int),
+		bans:        make(map[string]time.Time),
+		log:         log.Root(),
+	}
+	conn, _ := net.Pipe()
+
+	var last *stratumSession
+	for i := 0; i < stratumMaxHostSessions; i++ {
+		session, err := s.newSession(conn)
+		if err != nil {
+			t.Fatalf("session %d: failed to create session: %v", i, err)
+		}
+		last = session
+	}
+	if _, err := s.newSession(conn); err == nil {
+		t.Fatalf("session above the host limit accepted")
+	}
+	s.releaseSession(last)
+	if _, err := s.newSession(conn); err != nil {
+		t.Fatalf("failed to create session after release: %v", err)
+	}
+}
+
+// Tests that connections which don't authorize in time are dropped.
+func TestStratumHandshakeTimeout(t *testing.T) {
+	config := Config{
+		PowMode: ModeTest,
+		Log:     testlog.Logger(t, log.LvlError),
+	}
+	ethash := New(config, nil, false, t.TempDir())
+	defer ethash.Close()
+	ethash.SetThreads(-1)
+
+	if err := ethash.StartStratum("127.0.0.1:0", 1); err != nil {
+		t.Fatalf("failed to start stratum server: %v", err)
+	}
+	ethash.stratum.lock.Lock()
+	ethash.stratum.handshakeTimeout = 100 * time.Millisecond
+	ethash.stratum.lock.Unlock()
+
+	// Subscribing alone doesn't keep the connection open
+	client := newStratumTestClient(t, ethash.stratum.listener.Addr())
+	defer client.conn.Close()
+	client.call("mining.subscribe", "test-miner", stratumProtocol)
+
+	client.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		if _, err := client.reader.ReadBytes('\n'); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("unauthorized miner not disconnected: %v", err)
+		}
+	}
+}
+
+// Tests that share floods are rate limited, and that miners sending too many
+// invalid or rate limited shares are disconnected and banned.
+func TestStratumMisbehaviour(t *testing.T) {
+	config := Config{
+		PowMode: ModeTest,
+		Log:     testlog.Logger(t, log.LvlError),
+	}
+	ethash := New(config, nil, false, t.TempDir())
+	defer ethash.Close()
+	ethash.SetThreads(-1)
+
+	if err := ethash.StartStratum("127.0.0.1:0", 1<<62); err != nil {
+		t.Fatalf("failed to start stratum server: %v", err)
+	}
+	addr := ethash.stratum.listener.Addr()
+
+	// Stale shares are harmless, but still limited in rate, and miners that keep
+	// flooding get banned
+	client := newStratumTestClient(t, addr)
+	client.call("mining.subscribe", "test-miner", stratumProtocol)
+	client.call("mining.authorize", "worker", "x")
+
+	var limited int
+	for i := 0; i < 4*stratumSubmitBurst && limited < stratumMaxBadShares; i++ {
+		msg := client.call("mining.submit", "worker", "ffff", "000000000000")
+		if msg.Error != nil && msg.Error[0].(float64) == stratumErrRateLimited {
+			limited++
+		}
+	}
+	if limited < stratumMaxBadShares {
+		t.Fatalf("share flood not rate limited: %d limited shares", limited)
+	}
+	client.conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := client.reader.ReadByte(); err == nil {
+		t.Fatalf("flooding miner not disconnected")
+	}
+	client.conn.Close()
+
+	ethash.stratum.lock.Lock()
+	if _, ok := ethash.stratum.bans["127.0.0.1"]; !ok {
+		t.Fatalf("flooding miner not banned")
+	}
+	delete(ethash.stratum.bans, "127.0.0.1")
+	ethash.stratum.lock.Unlock()
+
+	// Invalid shares get the miner banned
+	client = newStratumTestClient(t, addr)
+	defer client.conn.Close()
+	client.call("mining.subscribe", "test-miner", stratumProtocol)
+	client.call("mining.authorize", "worker", "x")
+
+	for i := 0; i < stratumMaxBadShares; i++ {
+		client.call("mining.submit", "worker")
+	}
+	client.conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := client.reader.ReadByte(); err == nil {
+		t.Fatalf("misbehaving miner not disconnected")
+	}
+	conn, err := net.Dial("tcp", addr.String())
+	if err != nil {
+		t.Fatalf("failed to dial stratum server: %v", err)
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := conn.Read(make([]byte, 1)); err != io.EOF {
+		t.Fatalf("banned miner connection error mismatch: have %v, want %v", err, io.EOF)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package contract
+
+import (
+	_ "embed"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+//go:embed staking.bin-runtime
+var runtimeHex string
+
+// lockPeriodSlot is the storage slot holding the lock period of the deposits.
+var lockPeriodSlot = common.BigToHash(big.NewInt(3))
+
+// RuntimeCode returns the code of a deployed distributor.
+func RuntimeCode() []byte {
+	return common.FromHex(runtimeHex)
+}
+
+// GenesisStorage returns the storage of a distributor predeployed in a genesis
+// block, deposits being locked for the given number of blocks.
+func GenesisStorage(lockPeriod uint64) map[common.Hash]common.Hash {
+	return map[common.Hash]common.Hash{lockPeriodSlot: common.BigToHash(new(big.Int).SetUint64(lockPeriod))}
+}
@@ -0,0 +1,83 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package staking is an on-chain distributor of the staking block rewards.
+//
+// The contract is meant to be the only staking recipient of the Liberty reward
+// schedule. Depositors lock coins in it and claim the staking rewards credited
+// to it by every block, proportionally to their stake. New networks predeploy
+// it in the genesis block at SystemAddress with GenesisAccount. Existing ones can
+// deploy it with a transaction and switch their staking recipients to it with a
+// new recipient set.
+package staking
+
+//go:generate sh -c "go run ../../cmd/evm compile contract/staking.easm | tr -d '\\n' > contract/staking.bin-runtime"
+//go:generate sh -c "go run ../../cmd/evm compile contract/deploy.easm | tr -d '\\n' | cat - contract/staking.bin-runtime > contract/staking.bin"
+//go:generate go run ../../cmd/abigen --abi contract/staking.abi --bin contract/staking.bin --pkg contract --type Staking --out contract/staking.go
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/contracts/staking/contract"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// SystemAddress is the address the distributor is predeployed at in the genesis
+// block of new networks.
+var SystemAddress = params.LibertyStakingAddress
+
+// RuntimeCode returns the code of a deployed distributor.
+func RuntimeCode() []byte {
+	return contract.RuntimeCode()
+}
+
+// GenesisAccount returns the genesis allocation of a predeployed distributor,
+// deposits being locked for the given number of blocks.
+func GenesisAccount(lockPeriod uint64) core.GenesisAccount {
+	return core.GenesisAccount{
+		Code:    contract.RuntimeCode(),
+		Storage: contract.GenesisStorage(lockPeriod),
+		Balance: new(big.Int),
+	}
+}
+
+// Staking is a Go wrapper around an on-chain staking reward distributor.
+type Staking struct {
+	address  common.Address
+	contract *contract.Staking
+}
+
+// NewStaking binds the distributor deployed at the given address.
+func NewStaking(contractAddr common.Address, backend bind.ContractBackend) (*Staking, error) {
+	c, err := contract.NewStaking(contractAddr, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Staking{address: contractAddr, contract: c}, nil
+}
+
+// ContractAddr returns the address of contract.
+func (s *Staking) ContractAddr() common.Address {
+	return s.address
+}
+
+// Contract returns the underlying contract instance.
+func (s *Staking) Contract() *contract.Staking {
+	return s.contract
+}
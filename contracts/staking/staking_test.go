@@ -0,0 +1,377 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package staking
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/contracts/staking/contract"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/asm"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var ether = big.NewInt(params.Ether)
+
+func coins(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), ether)
+}
+
+// testAccount is a funded account of the simulated backend.
+type testAccount struct {
+	key  *ecdsa.PrivateKey
+	addr common.Address
+	opts *bind.TransactOpts
+}
+
+// testEnv is a distributor deployed on a simulated backend.
+type testEnv struct {
+	backend  *backends.SimulatedBackend
+	staking  *Staking
+	accounts []*testAccount
+}
+
+func newTestEnv(t *testing.T, lockPeriod uint64) *testEnv {
+	var (
+		accounts []*testAccount
+		alloc    = make(core.GenesisAlloc)
+	)
+	for i := 0; i < 3; i++ {
+		key, _ := crypto.GenerateKey()
+		opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+		account := &testAccount{key: key, addr: crypto.PubkeyToAddress(key.PublicKey), opts: opts}
+		accounts = append(accounts, account)
+		alloc[account.addr] = core.GenesisAccount{Balance: coins(1000)}
+	}
+	backend := backends.NewSimulatedBackend(alloc, 10000000)
+	t.Cleanup(func() { backend.Close() })
+
+	addr, _, _, err := contract.DeployStaking(accounts[0].opts, backend, new(big.Int).SetUint64(lockPeriod))
+	if err != nil {
+		t.Fatalf("failed to deploy contract: %v", err)
+	}
+	backend.Commit()
+
+	staking, err := NewStaking(addr, backend)
+	if err != nil {
+		t.Fatalf("failed to bind contract: %v", err)
+	}
+	return &testEnv{backend: backend, staking: staking, accounts: accounts}
+}
+
+// transact executes a transaction in a new block, failing the test if it can't
+// be executed successfully.
+func (env *testEnv) transact(t *testing.T, send func() (*types.Transaction, error)) *types.Receipt {
+	t.Helper()
+
+	tx, err := send()
+	if err != nil {
+		t.Fatalf("failed to send transaction: %v", err)
+	}
+	env.backend.Commit()
+
+	receipt, err := env.backend.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		t.Fatalf("failed to retrieve receipt: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("transaction failed")
+	}
+	return receipt
+}
+
+// reward credits the distributor the way block rewards do.
+func (env *testEnv) reward(t *testing.T, amount *big.Int) {
+	t.Helper()
+
+	opts := *env.accounts[2].opts
+	opts.Value = amount
+	env.transact(t, func() (*types.Transaction, error) { return env.staking.Contract().Receive(&opts) })
+}
+
+func (env *testEnv) deposit(t *testing.T, account *testAccount, amount *big.Int) *types.Receipt {
+	t.Helper()
+
+	opts := *account.opts
+	opts.Value = amount
+	return env.transact(t, func() (*types.Transaction, error) { return env.staking.Contract().Deposit(&opts) })
+}
+
+func (env *testEnv) assertUint(t *testing.T, name string, have *big.Int, err error, want *big.Int) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("failed to retrieve %s: %v", name, err)
+	}
+	if have.Cmp(want) != 0 {
+		t.Fatalf("%s mismatch: have %v, want %v", name, have, want)
+	}
+}
+
+func TestDeposit(t *testing.T) {
+	env := newTestEnv(t, 10)
+	c, alice, bob := env.staking.Contract(), env.accounts[0], env.accounts[1]
+
+	period, err := c.LockPeriod(nil)
+	env.assertUint(t, "lock period", period, err, big.NewInt(10))
+
+	// Deposits without value must be rejected
+	if _, err := c.Deposit(alice.opts); err == nil {
+		t.Fatalf("empty deposit accepted")
+	}
+	receipt := env.deposit(t, alice, coins(1))
+	env.deposit(t, bob, coins(3))
+	env.deposit(t, alice, coins(2))
+
+	stake, err := c.StakeOf(nil, alice.addr)
+	env.assertUint(t, "alice stake", stake, err, coins(3))
+	stake, err = c.StakeOf(nil, bob.addr)
+	env.assertUint(t, "bob stake", stake, err, coins(3))
+	total, err := c.TotalStaked(nil)
+	env.assertUint(t, "total stake", total, err, coins(6))
+
+	// The lock is extended by every deposit
+	head := env.backend.Blockchain().CurrentBlock().Number
+	unlock, err := c.UnlockBlock(nil, alice.addr)
+	env.assertUint(t, "unlock block", unlock, err, new(big.Int).Add(head, big.NewInt(10)))
+
+	if len(receipt.Logs) != 1 {
+		t.Fatalf("log count mismatch: have %d, want 1", len(receipt.Logs))
+	}
+	event, err := c.ParseDeposited(*receipt.Logs[0])
+	if err != nil {
+		t.Fatalf("failed to parse deposit event: %v", err)
+	}
+	if event.Account != alice.addr || event.Amount.Cmp(coins(1)) != 0 {
+		t.Fatalf("deposit event mismatch: have %x/%v, want %x/%v", event.Account, event.Amount, alice.addr, coins(1))
+	}
+}
+
+func TestClaim(t *testing.T) {
+	env := newTestEnv(t, 0)
+	c, alice, bob := env.staking.Contract(), env.accounts[0], env.accounts[1]
+
+	// Rewards credited before anyone staked go to the first stakers
+	env.reward(t, coins(2))
+	env.deposit(t, alice, coins(1))
+	env.deposit(t, bob, coins(3))
+
+	pending, err := c.PendingReward(nil, alice.addr)
+	env.assertUint(t, "alice reward", pending, err, coins(2))
+
+	// New rewards are split pro-rata
+	env.reward(t, coins(4))
+	pending, err = c.PendingReward(nil, alice.addr)
+	env.assertUint(t, "alice reward", pending, err, coins(3))
+	pending, err = c.PendingReward(nil, bob.addr)
+	env.assertUint(t, "bob reward", pending, err, coins(3))
+
+	before, _ := env.backend.BalanceAt(context.Background(), alice.addr, nil)
+	receipt := env.transact(t, func() (*types.Transaction, error) { return c.Claim(alice.opts) })
+	after, _ := env.backend.BalanceAt(context.Background(), alice.addr, nil)
+
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+	if have := new(big.Int).Sub(after, before); have.Cmp(new(big.Int).Sub(coins(3), fee)) != 0 {
+		t.Fatalf("claimed amount mismatch: have %v, want %v", have, new(big.Int).Sub(coins(3), fee))
+	}
+	event, err := c.ParseClaimed(*receipt.Logs[0])
+	if err != nil || event.Account != alice.addr || event.Amount.Cmp(coins(3)) != 0 {
+		t.Fatalf("claim event mismatch: %v, %v", event, err)
+	}
+	// Nothing left to claim, but the stake keeps earning
+	pending, err = c.PendingReward(nil, alice.addr)
+	env.assertUint(t, "alice reward", pending, err, new(big.Int))
+	if _, err := c.Claim(alice.opts); err == nil {
+		t.Fatalf("empty claim accepted")
+	}
+	env.reward(t, coins(8))
+	pending, err = c.PendingReward(nil, alice.addr)
+	env.assertUint(t, "alice reward", pending, err, coins(2))
+	pending, err = c.PendingReward(nil, bob.addr)
+	env.assertUint(t, "bob reward", pending, err, coins(9))
+
+	// Stakes must stay fully backed after the claims
+	env.transact(t, func() (*types.Transaction, error) { return c.Claim(bob.opts) })
+	env.transact(t, func() (*types.Transaction, error) { return c.Claim(alice.opts) })
+
+	balance, err := env.backend.BalanceAt(context.Background(), env.staking.ContractAddr(), nil)
+	env.assertUint(t, "contract balance", balance, err, coins(4))
+}
+
+func TestWithdraw(t *testing.T) {
+	env := newTestEnv(t, 5)
+	c, alice, bob := env.staking.Contract(), env.accounts[0], env.accounts[1]
+
+	env.deposit(t, alice, coins(4))
+	env.deposit(t, bob, coins(4))
+
+	// Locked stakes can't be withdrawn
+	if _, err := c.Withdraw(alice.opts, coins(1)); err == nil {
+		t.Fatalf("locked stake withdrawn")
+	}
+	for i := 0; i < 5; i++ {
+		env.backend.Commit()
+	}
+	if _, err := c.Withdraw(alice.opts, coins(5)); err == nil {
+		t.Fatalf("withdrawal beyond the stake accepted")
+	}
+	if _, err := c.Withdraw(alice.opts, new(big.Int)); err == nil {
+		t.Fatalf("empty withdrawal accepted")
+	}
+	env.reward(t, coins(2))
+
+	before, _ := env.backend.BalanceAt(context.Background(), alice.addr, nil)
+	receipt := env.transact(t, func() (*types.Transaction, error) { return c.Withdraw(alice.opts, coins(3)) })
+	after, _ := env.backend.BalanceAt(context.Background(), alice.addr, nil)
+
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+	if have := new(big.Int).Sub(after, before); have.Cmp(new(big.Int).Sub(coins(3), fee)) != 0 {
+		t.Fatalf("withdrawn amount mismatch: have %v, want %v", have, new(big.Int).Sub(coins(3), fee))
+	}
+	event, err := c.ParseWithdrawn(*receipt.Logs[0])
+	if err != nil || event.Account != alice.addr || event.Amount.Cmp(coins(3)) != 0 {
+		t.Fatalf("withdraw event mismatch: %v, %v", event, err)
+	}
+	stake, err := c.StakeOf(nil, alice.addr)
+	env.assertUint(t, "alice stake", stake, err, coins(1))
+	total, err := c.TotalStaked(nil)
+	env.assertUint(t, "total stake", total, err, coins(5))
+
+	// Rewards earned before the withdrawal are kept, later ones follow the new stakes
+	env.reward(t, coins(5))
+	pending, err := c.PendingReward(nil, alice.addr)
+	env.assertUint(t, "alice reward", pending, err, coins(2))
+	pending, err = c.PendingReward(nil, bob.addr)
+	env.assertUint(t, "bob reward", pending, err, coins(5))
+}
+
+// Tests that a predeployed distributor collects the staking share of the block
+// rewards and hands it out to the stakers.
+func TestGenesisPredeploy(t *testing.T) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr    = crypto.PubkeyToAddress(key.PublicKey)
+		config  = *params.TestChainConfig
+		staking = coins(2)
+	)
+	config.Liberty = &params.LibertyConfig{
+		Epochs: []params.LibertyEpoch{
+			{Name: "Test", StartBlock: 1, MinerReward: coins(5), StakingReward: staking, DevFund: coins(1)},
+		},
+		Recipients: []params.LibertyRecipientSet{{
+			DevFund: []params.LibertyRecipient{{Address: common.Address{0xde, 0xf}, Weight: 1}},
+			Staking: []params.LibertyRecipient{{Address: SystemAddress, Weight: 1}},
+		}},
+	}
+	gspec := &core.Genesis{
+		Config:  &config,
+		BaseFee: big.NewInt(params.InitialBaseFee),
+		Alloc: core.GenesisAlloc{
+			addr:          {Balance: coins(1000)},
+			SystemAddress: GenesisAccount(2),
+		},
+	}
+	parsed, _ := abi.JSON(strings.NewReader(contract.StakingMetaData.ABI))
+	deposit, _ := parsed.Pack("deposit")
+	claim, _ := parsed.Pack("claim")
+
+	signer := types.LatestSigner(&config)
+	call := func(gen *core.BlockGen, data []byte, value *big.Int) {
+		tx, _ := types.SignTx(types.NewTransaction(gen.TxNonce(addr), SystemAddress, value, 200000, gen.BaseFee(), data), signer, key)
+		gen.AddTx(tx)
+	}
+	_, blocks, receipts := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), 6, func(i int, gen *core.BlockGen) {
+		switch i {
+		case 0:
+			call(gen, deposit, coins(10))
+		case 5:
+			call(gen, claim, new(big.Int))
+		}
+	})
+	db := rawdb.NewMemoryDatabase()
+	chain, _ := core.NewBlockChain(db, nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	defer chain.Stop()
+
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert block %d: %v", n, err)
+	}
+	// The staker gets the staking rewards of the first five blocks, the last
+	// one being credited after the claim
+	if receipts[5][0].Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("claim failed")
+	}
+	filterer, _ := contract.NewStakingFilterer(SystemAddress, nil)
+	event, err := filterer.ParseClaimed(*receipts[5][0].Logs[0])
+	if err != nil {
+		t.Fatalf("failed to parse claim event: %v", err)
+	}
+	if want := new(big.Int).Mul(staking, big.NewInt(5)); event.Amount.Cmp(want) != 0 {
+		t.Fatalf("claimed reward mismatch: have %v, want %v", event.Amount, want)
+	}
+	statedb, _ := chain.State()
+	if have, want := statedb.GetBalance(SystemAddress), new(big.Int).Add(coins(10), staking); have.Cmp(want) != 0 {
+		t.Fatalf("contract balance mismatch: have %v, want %v", have, want)
+	}
+}
+
+// assemble compiles the given EVM assembly source into hex encoded bytecode.
+func assemble(t *testing.T, path string) string {
+	t.Helper()
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	compiler := asm.NewCompiler(false)
+	compiler.Feed(asm.Lex(src, false))
+
+	bin, errs := compiler.Compile()
+	if len(errs) > 0 {
+		t.Fatalf("failed to assemble %s: %v", path, errs)
+	}
+	return bin
+}
+
+// Tests that the committed bytecode, predeployed in genesis blocks and used by
+// the bindings, is the one assembled from the contract sources.
+func TestCompiledCode(t *testing.T) {
+	runtime := assemble(t, "contract/staking.easm")
+	if have := common.Bytes2Hex(RuntimeCode()); have != runtime {
+		t.Errorf("runtime code mismatch, run go generate:\nhave %s\nwant %s", have, runtime)
+	}
+	if have := common.Bytes2Hex(GenesisAccount(1).Code); have != runtime {
+		t.Errorf("predeployed code mismatch:\nhave %s\nwant %s", have, runtime)
+	}
+	deploy := assemble(t, "contract/deploy.easm") + runtime
+	if have := strings.TrimPrefix(contract.StakingMetaData.Bin, "0x"); have != deploy {
+		t.Errorf("binding code mismatch, run go generate:\nhave %s\nwant %s", have, deploy)
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	// ErrCheckpointSigners is returned if a finality checkpoint is submitted
+	// while no checkpoint signers are configured.
+	ErrCheckpointSigners = errors.New("no checkpoint signers configured")
+
+	// ErrCheckpointQuorum is returned if a finality checkpoint is not signed by
+	// enough of the checkpoint signers.
+	ErrCheckpointQuorum = errors.New("checkpoint signer quorum not reached")
+
+	// ErrStaleCheckpoint is returned if a finality checkpoint is not above the
+	// current one.
+	ErrStaleCheckpoint = errors.New("stale finality checkpoint")
+
+	errCheckpointConflict = errors.New("chain conflicts with the finality checkpoint")
+	errReorgTooDeep       = errors.New("reorg exceeds the maximum depth")
+)
+
+// FinalityConfig are the options protecting the chain from deep reorgs, which
+// proof-of-work chains with little hashrate are prone to.
+type FinalityConfig struct {
+	Oracle     *params.CheckpointOracleConfig // Signers of the finality checkpoints, nil to disable them
+	Checkpoint *params.TrustedCheckpoint      // Hardcoded checkpoint, the head of its section is final
+	MaxReorg   uint64                         // Maximum number of blocks a reorg may drop, 0 for no limit
+}
+
+// FinalityCheckpoint is a block declared final by a quorum of the checkpoint
+// signers. Chains which don't contain it are never switched to.
+//
+// Signed checkpoints are neither gossiped between peers nor read from the
+// checkpoint oracle contract: the votes stored on-chain sign the hashes of CHT
+// sections, which can't be mapped back to a block. A node only learns about
+// them through AddFinalityCheckpoint, exposed as the
+// liberty_submitFinalityCheckpoint RPC method, so operators need to submit each
+// checkpoint to each of their nodes. Without that, only the hardcoded trusted
+// checkpoint and the reorg depth limit protect the node.
+type FinalityCheckpoint struct {
+	Number     uint64
+	Hash       common.Hash
+	Signatures [][]byte // Signatures of the finality hash, with V being 27 or 28
+}
+
+// verify checks that the checkpoint is signed by a quorum of the signers of the
+// oracle, returning the distinct signers.
+func (c *FinalityCheckpoint) verify(oracle *params.CheckpointOracleConfig) ([]common.Address, error) {
+	var (
+		hash    = oracle.FinalityHash(c.Number, c.Hash)
+		signers []common.Address
+		checked = make(map[common.Address]bool)
+	)
+	for _, sig := range c.Signatures {
+		if len(sig) != crypto.SignatureLength {
+			return nil, fmt.Errorf("invalid signature length %d", len(sig))
+		}
+		// Transform V from 27/28 to 0/1 according to the yellow paper
+		sig = common.CopyBytes(sig)
+		sig[crypto.RecoveryIDOffset] -= 27
+
+		pubkey, err := crypto.SigToPub(hash[:], sig)
+		if err != nil {
+			return nil, err
+		}
+		signer := crypto.PubkeyToAddress(*pubkey)
+		if checked[signer] {
+			continue
+		}
+		checked[signer] = true
+		for _, s := range oracle.Signers {
+			if s == signer {
+				signers = append(signers, signer)
+				break
+			}
+		}
+	}
+	if uint64(len(signers)) < oracle.Threshold || len(signers) == 0 {
+		return nil, ErrCheckpointQuorum
+	}
+	return signers, nil
+}
+
+// SetFinality configures the protection of the chain against deep reorgs. The
+// latest finality checkpoint persisted is restored if the checkpoint signers are
+// configured, unless the hardcoded one is more recent.
+func (bc *BlockChain) SetFinality(config *FinalityConfig) {
+	var checkpoint *FinalityCheckpoint
+	if blob := rawdb.ReadFinalityCheckpoint(bc.db); config.Oracle != nil && len(blob) > 0 {
+		checkpoint = new(FinalityCheckpoint)
+		if err := rlp.DecodeBytes(blob, checkpoint); err != nil {
+			log.Error("Invalid finality checkpoint in database", "err", err)
+			checkpoint = nil
+		}
+	}
+	if config.Checkpoint != nil && !config.Checkpoint.Empty() {
+		number, hash := config.Checkpoint.FinalBlock()
+		if checkpoint == nil || checkpoint.Number < number {
+			checkpoint = &FinalityCheckpoint{Number: number, Hash: hash}
+		}
+	}
+	bc.finalityLock.Lock()
+	defer bc.finalityLock.Unlock()
+
+	bc.finality = config
+	bc.checkpoint = checkpoint
+	if checkpoint != nil {
+		log.Info("Enabled finality checkpoint", "number", checkpoint.Number, "hash", checkpoint.Hash, "maxreorg", config.MaxReorg)
+		bc.warnCheckpointConflict(checkpoint)
+	} else if config.MaxReorg > 0 {
+		log.Info("Enabled reorg depth limit", "maxreorg", config.MaxReorg)
+	}
+}
+
+// AddFinalityCheckpoint verifies a checkpoint signed by the checkpoint signers,
+// and if it supersedes the current one, persists it and refuses any reorg which
+// would drop its block from then on.
+func (bc *BlockChain) AddFinalityCheckpoint(checkpoint *FinalityCheckpoint) error {
+	bc.finalityLock.Lock()
+	defer bc.finalityLock.Unlock()
+
+	if bc.finality == nil || bc.finality.Oracle == nil {
+		return ErrCheckpointSigners
+	}
+	if bc.checkpoint != nil && checkpoint.Number <= bc.checkpoint.Number {
+		return ErrStaleCheckpoint
+	}
+	signers, err := checkpoint.verify(bc.finality.Oracle)
+	if err != nil {
+		return err
+	}
+	blob, err := rlp.EncodeToBytes(checkpoint)
+	if err != nil {
+		return err
+	}
+	rawdb.WriteFinalityCheckpoint(bc.db, blob)
+	bc.checkpoint = checkpoint
+
+	log.Info("Updated finality checkpoint", "number", checkpoint.Number, "hash", checkpoint.Hash, "signers", len(signers))
+	bc.warnCheckpointConflict(checkpoint)
+	return nil
+}
+
+// FinalityCheckpoint returns the latest finality checkpoint, nil if there is
+// none.
+func (bc *BlockChain) FinalityCheckpoint() *FinalityCheckpoint {
+	bc.finalityLock.RLock()
+	defer bc.finalityLock.RUnlock()
+
+	return bc.checkpoint
+}
+
+// warnCheckpointConflict warns if the canonical chain doesn't contain the block
+// of the checkpoint. Such a chain is not extended anymore, and any chain with
+// the checkpoint is switched to as soon as it is heavier.
+func (bc *BlockChain) warnCheckpointConflict(checkpoint *FinalityCheckpoint) {
+	if bc.CurrentBlock().Number.Uint64() < checkpoint.Number {
+		return
+	}
+	if hash := bc.GetCanonicalHash(checkpoint.Number); hash != checkpoint.Hash {
+		log.Warn("Canonical chain conflicts with finality checkpoint", "number", checkpoint.Number, "checkpoint", checkpoint.Hash, "canonical", hash)
+	}
+}
+
+// checkReorg checks whether switching from the current head to a new one would
+// revert the finality checkpoint, or drop more blocks than permitted.
+func (bc *BlockChain) checkReorg(current *types.Header, extern *types.Header) error {
+	bc.finalityLock.RLock()
+	var (
+		checkpoint = bc.checkpoint
+		limit      uint64
+	)
+	if bc.finality != nil {
+		limit = bc.finality.MaxReorg
+	}
+	bc.finalityLock.RUnlock()
+
+	if checkpoint == nil && limit == 0 {
+		return nil
+	}
+	// If the canonical chain conflicts with the checkpoint, it may be reorged
+	// away from at any depth, but never be extended.
+	var (
+		head        = current.Number.Uint64()
+		anchored    = checkpoint != nil && head >= checkpoint.Number && bc.GetCanonicalHash(checkpoint.Number) == checkpoint.Hash
+		conflicting = checkpoint != nil && head >= checkpoint.Number && !anchored
+	)
+	// Walk the new chain back until it joins the canonical one
+	header := extern
+	for {
+		number, hash := header.Number.Uint64(), header.Hash()
+		if bc.GetCanonicalHash(number) == hash {
+			break
+		}
+		if checkpoint != nil && number <= checkpoint.Number {
+			switch {
+			case number == checkpoint.Number && hash != checkpoint.Hash:
+				return errCheckpointConflict
+			case number == checkpoint.Number && (conflicting || limit == 0):
+				return nil // New chain contains the checkpoint, its depth doesn't matter
+			case anchored:
+				return errCheckpointConflict // New chain forks off below the checkpoint
+			}
+		}
+		// The common ancestor lies below this block, so the reorg drops at least
+		// head-number+1 blocks.
+		if limit > 0 && !conflicting && head >= number+limit {
+			return errReorgTooDeep
+		}
+		if header = bc.GetHeader(header.ParentHash, number-1); header == nil {
+			return consensus.ErrUnknownAncestor
+		}
+	}
+	if conflicting && header.Number.Uint64() >= checkpoint.Number {
+		return errCheckpointConflict
+	}
+	return nil
+}
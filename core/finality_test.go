@@ -0,0 +1,268 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+package core
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// finalityTester is a chain with its own database, to which competing chains
+// generated in a separate one are imported.
+type finalityTester struct {
+	t       *testing.T
+	genesis *Genesis
+	genDb   ethdb.Database
+	db      ethdb.Database
+	chain   *BlockChain
+}
+
+func newFinalityTester(t *testing.T, config *FinalityConfig) *finalityTester {
+	genesis := &Genesis{
+		BaseFee: big.NewInt(params.InitialBaseFee),
+		Config:  params.AllEthashProtocolChanges,
+	}
+	genDb, _, _ := GenerateChainWithGenesis(genesis, ethash.NewFaker(), 0, nil)
+	tester := &finalityTester{t: t, genesis: genesis, genDb: genDb, db: rawdb.NewMemoryDatabase()}
+	tester.restart(config)
+	t.Cleanup(func() { tester.chain.Stop() })
+	return tester
+}
+
+// restart reopens the chain on the same database with the given finality
+// options.
+func (ft *finalityTester) restart(config *FinalityConfig) {
+	if ft.chain != nil {
+		ft.chain.Stop()
+	}
+	chain, err := NewBlockChain(ft.db, nil, ft.genesis, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		ft.t.Fatalf("failed to create chain: %v", err)
+	}
+	chain.SetFinality(config)
+	ft.chain = chain
+}
+
+// fork generates n blocks on top of the given parent, the genesis if nil. The
+// coinbase differentiates competing chains.
+func (ft *finalityTester) fork(parent *types.Block, n int, coinbase byte) []*types.Block {
+	if parent == nil {
+		parent = ft.genesis.ToBlock()
+	}
+	blocks, _ := GenerateChain(ft.genesis.Config, parent, ethash.NewFaker(), ft.genDb, n, func(i int, b *BlockGen) {
+		b.SetCoinbase(common.Address{coinbase})
+	})
+	return blocks
+}
+
+// insert imports the blocks and checks that the head ends up at the given one.
+func (ft *finalityTester) insert(blocks []*types.Block, head *types.Block) {
+	ft.t.Helper()
+
+	if _, err := ft.chain.InsertChain(blocks); err != nil {
+		ft.t.Fatalf("failed to insert chain: %v", err)
+	}
+	if have := ft.chain.CurrentBlock(); have.Hash() != head.Hash() {
+		ft.t.Fatalf("head mismatch: have #%d [%x], want #%d [%x]", have.Number, have.Hash().Bytes()[:4], head.NumberU64(), head.Hash().Bytes()[:4])
+	}
+}
+
+// Tests that reorgs dropping more blocks than permitted are refused, leaving the
+// competing chain as a side chain.
+func TestFinalityMaxReorg(t *testing.T) {
+	tests := []struct {
+		limit  uint64
+		forkAt int // Number of the last block shared with the canonical chain
+		reorg  bool
+	}{
+		{0, 0, true},
+		{4, 6, true},
+		{4, 5, false},
+		{3, 6, false},
+		{3, 8, true},
+	}
+	for i, tt := range tests {
+		ft := newFinalityTester(t, &FinalityConfig{MaxReorg: tt.limit})
+		canon := ft.fork(nil, 10, 1)
+		ft.insert(canon, canon[9])
+
+		parent := ft.genesis.ToBlock()
+		if tt.forkAt > 0 {
+			parent = canon[tt.forkAt-1]
+		}
+		fork := ft.fork(parent, 10-tt.forkAt+2, 2)
+		if _, err := ft.chain.InsertChain(fork); err != nil {
+			t.Fatalf("test %d: failed to insert fork: %v", i, err)
+		}
+		want := canon[9]
+		if tt.reorg {
+			want = fork[len(fork)-1]
+		}
+		if have := ft.chain.CurrentBlock().Hash(); have != want.Hash() {
+			t.Errorf("test %d: head mismatch: have %x, want %x (reorg %v)", i, have, want.Hash(), tt.reorg)
+		}
+		if !ft.chain.HasBlock(fork[len(fork)-1].Hash(), fork[len(fork)-1].NumberU64()) {
+			t.Errorf("test %d: fork not retained", i)
+		}
+	}
+}
+
+// checkpointSigners returns the keys of the checkpoint signers and an oracle
+// config requiring two of them to sign.
+func checkpointSigners(t *testing.T, n int) ([]*ecdsa.PrivateKey, *params.CheckpointOracleConfig) {
+	oracle := &params.CheckpointOracleConfig{Address: common.HexToAddress("0x1234"), Threshold: 2}
+
+	var keys []*ecdsa.PrivateKey
+	for i := 0; i < n; i++ {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatalf("failed to generate key: %v", err)
+		}
+		keys = append(keys, key)
+		oracle.Signers = append(oracle.Signers, crypto.PubkeyToAddress(key.PublicKey))
+	}
+	return keys, oracle
+}
+
+// signCheckpoint creates a finality checkpoint for the block, signed by the keys.
+func signCheckpoint(t *testing.T, oracle *params.CheckpointOracleConfig, block *types.Block, keys ...*ecdsa.PrivateKey) *FinalityCheckpoint {
+	checkpoint := &FinalityCheckpoint{Number: block.NumberU64(), Hash: block.Hash()}
+
+	hash := oracle.FinalityHash(checkpoint.Number, checkpoint.Hash)
+	for _, key := range keys {
+		sig, err := crypto.Sign(hash[:], key)
+		if err != nil {
+			t.Fatalf("failed to sign checkpoint: %v", err)
+		}
+		sig[crypto.RecoveryIDOffset] += 27
+		checkpoint.Signatures = append(checkpoint.Signatures, sig)
+	}
+	return checkpoint
+}
+
+// Tests that signed finality checkpoints are only accepted with a quorum of the
+// signers, that reorgs below them are refused and that they survive restarts.
+func TestFinalityCheckpoint(t *testing.T) {
+	keys, oracle := checkpointSigners(t, 3)
+	outsider, _ := crypto.GenerateKey()
+
+	ft := newFinalityTester(t, &FinalityConfig{})
+	canon := ft.fork(nil, 10, 1)
+	ft.insert(canon, canon[9])
+
+	if err := ft.chain.AddFinalityCheckpoint(signCheckpoint(t, oracle, canon[5], keys[0], keys[1])); !errors.Is(err, ErrCheckpointSigners) {
+		t.Fatalf("checkpoint without signers error mismatch: have %v, want %v", err, ErrCheckpointSigners)
+	}
+	ft.restart(&FinalityConfig{Oracle: oracle})
+
+	for i, signers := range [][]*ecdsa.PrivateKey{
+		{keys[0]},
+		{keys[0], keys[0]},
+		{keys[0], outsider},
+	} {
+		if err := ft.chain.AddFinalityCheckpoint(signCheckpoint(t, oracle, canon[5], signers...)); !errors.Is(err, ErrCheckpointQuorum) {
+			t.Fatalf("test %d: quorum error mismatch: have %v, want %v", i, err, ErrCheckpointQuorum)
+		}
+	}
+	if err := ft.chain.AddFinalityCheckpoint(signCheckpoint(t, oracle, canon[5], keys[0], keys[2])); err != nil {
+		t.Fatalf("failed to add checkpoint: %v", err)
+	}
+	if err := ft.chain.AddFinalityCheckpoint(signCheckpoint(t, oracle, canon[4], keys...)); !errors.Is(err, ErrStaleCheckpoint) {
+		t.Fatalf("stale checkpoint error mismatch: have %v, want %v", err, ErrStaleCheckpoint)
+	}
+	// Forks dropping the block of the checkpoint must be refused, no matter how
+	// heavy, but those above it accepted
+	ft.insert(ft.fork(canon[4], 10, 2), canon[9])
+
+	fork := ft.fork(canon[5], 6, 3)
+	ft.insert(fork, fork[5])
+
+	// The checkpoint must be retained across restarts
+	ft.restart(&FinalityConfig{Oracle: oracle})
+	if checkpoint := ft.chain.FinalityCheckpoint(); checkpoint == nil || checkpoint.Hash != canon[5].Hash() {
+		t.Fatalf("restored checkpoint mismatch: have %+v, want block %x", checkpoint, canon[5].Hash())
+	}
+	ft.insert(ft.fork(nil, 20, 4), fork[5])
+
+	// Without the signers, the signed checkpoint must not apply anymore
+	ft.restart(&FinalityConfig{})
+	if checkpoint := ft.chain.FinalityCheckpoint(); checkpoint != nil {
+		t.Fatalf("checkpoint restored without signers: %+v", checkpoint)
+	}
+	reorg := ft.fork(nil, 30, 5)
+	ft.insert(reorg, reorg[29])
+}
+
+// Tests that a hardcoded trusted checkpoint makes the head of its section final,
+// unless a more recent signed checkpoint is known.
+func TestFinalityTrustedCheckpoint(t *testing.T) {
+	keys, oracle := checkpointSigners(t, 2)
+	trusted := &params.TrustedCheckpoint{
+		SectionIndex: 1,
+		SectionHead:  common.HexToHash("0x01"),
+		CHTRoot:      common.HexToHash("0x02"),
+		BloomRoot:    common.HexToHash("0x03"),
+	}
+
+	ft := newFinalityTester(t, &FinalityConfig{Oracle: oracle, Checkpoint: trusted})
+	checkpoint := ft.chain.FinalityCheckpoint()
+	if checkpoint == nil || checkpoint.Number != 2*params.CHTFrequency-1 || checkpoint.Hash != trusted.SectionHead {
+		t.Fatalf("trusted checkpoint mismatch: have %+v", checkpoint)
+	}
+	block := types.NewBlockWithHeader(&types.Header{Number: new(big.Int).SetUint64(params.CHTFrequency)})
+	if err := ft.chain.AddFinalityCheckpoint(signCheckpoint(t, oracle, block, keys...)); !errors.Is(err, ErrStaleCheckpoint) {
+		t.Fatalf("stale checkpoint error mismatch: have %v, want %v", err, ErrStaleCheckpoint)
+	}
+	block = types.NewBlockWithHeader(&types.Header{Number: new(big.Int).SetUint64(2 * params.CHTFrequency)})
+	if err := ft.chain.AddFinalityCheckpoint(signCheckpoint(t, oracle, block, keys...)); err != nil {
+		t.Fatalf("failed to add checkpoint: %v", err)
+	}
+	ft.restart(&FinalityConfig{Oracle: oracle, Checkpoint: trusted})
+	if checkpoint := ft.chain.FinalityCheckpoint(); checkpoint == nil || checkpoint.Hash != block.Hash() {
+		t.Fatalf("signed checkpoint not retained: have %+v", checkpoint)
+	}
+}
+
+// Tests that a canonical chain conflicting with the finality checkpoint is not
+// extended anymore, but abandoned for the checkpointed chain.
+func TestFinalityCheckpointConflict(t *testing.T) {
+	keys, oracle := checkpointSigners(t, 2)
+
+	ft := newFinalityTester(t, &FinalityConfig{Oracle: oracle, MaxReorg: 3})
+	canon := ft.fork(nil, 12, 1)
+	ft.insert(canon[:10], canon[9])
+
+	fork := ft.fork(nil, 14, 2)
+	if err := ft.chain.AddFinalityCheckpoint(signCheckpoint(t, oracle, fork[4], keys...)); err != nil {
+		t.Fatalf("failed to add checkpoint: %v", err)
+	}
+	ft.insert(canon[10:], canon[9])
+
+	// The reorg to the checkpointed chain is deeper than permitted, but the
+	// canonical chain can't be final
+	ft.insert(fork, fork[13])
+}
@@ -54,6 +54,10 @@ type ForkChoice struct {
 	// local td is equal to the extern one. It can be nil for light
 	// client
 	preserve func(header *types.Header) bool
+
+	// guard is an optional check a reorg deemed necessary by the total
+	// difficulty must pass to be applied, protecting finalized blocks.
+	guard func(current *types.Header, extern *types.Header) error
 }
 
 func NewForkChoice(chainReader ChainReader, preserve func(header *types.Header) bool) *ForkChoice {
@@ -91,7 +95,7 @@ func (f *ForkChoice) ReorgNeeded(current *types.Header, extern *types.Header) (b
 
 	// If the total difficulty is higher than our known, add it to the canonical chain
 	if diff := externTd.Cmp(localTD); diff > 0 {
-		return true, nil
+		return f.permitted(current, extern), nil
 	} else if diff < 0 {
 		return false, nil
 	}
@@ -109,5 +113,19 @@ func (f *ForkChoice) ReorgNeeded(current *types.Header, extern *types.Header) (b
 		}
 		reorg = !currentPreserve && (externPreserve || f.rand.Float64() < 0.5)
 	}
-	return reorg, nil
+	return reorg && f.permitted(current, extern), nil
+}
+
+// permitted reports whether the guard, if any, allows switching the head from
+// the current header to the external one.
+func (f *ForkChoice) permitted(current *types.Header, extern *types.Header) bool {
+	if f.guard == nil {
+		return true
+	}
+	if err := f.guard(current, extern); err != nil {
+		log.Warn("Refused chain reorg", "number", extern.Number, "hash", extern.Hash(), "head", current.Number, "err", err)
+		blockReorgRefusedMeter.Mark(1)
+		return false
+	}
+	return true
 }
@@ -270,6 +270,14 @@ func gatherForks(config *params.ChainConfig) ([]uint64, []uint64) {
 			}
 		}
 	}
+	// Retuning the proof-of-work and rotating the liberty reward recipients are
+	// consensus changes too, so make sure peers on either side are told apart.
+	if config.Ethash != nil {
+		forksByBlock = append(forksByBlock, config.Ethash.ForkBlocks()...)
+	}
+	if config.Liberty != nil {
+		forksByBlock = append(forksByBlock, config.Liberty.ForkBlocks()...)
+	}
 	sort.Slice(forksByBlock, func(i, j int) bool { return forksByBlock[i] < forksByBlock[j] })
 	sort.Slice(forksByTime, func(i, j int) bool { return forksByTime[i] < forksByTime[j] })
 
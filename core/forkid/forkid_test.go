@@ -137,6 +137,32 @@ func TestCreation(t *testing.T) {
 	}
 }
 
+// Tests that rotating the liberty reward recipients is treated as a fork, so
+// peers on different recipient sets split cleanly.
+func TestRecipientRotationFork(t *testing.T) {
+	config := *params.AllEthashProtocolChanges
+	config.Liberty = &params.LibertyConfig{
+		Epochs: params.DefaultLibertyConfig.Epochs,
+		Recipients: []params.LibertyRecipientSet{
+			params.DefaultLibertyConfig.Recipients[0],
+			{Block: 1000, DevFund: []params.LibertyRecipient{{Address: common.Address{0x01}, Weight: 1}}},
+		},
+	}
+	genesis := common.Hash{0xff}
+
+	before := NewID(&config, genesis, 999, 0)
+	if before.Next != 1000 {
+		t.Fatalf("next fork mismatch: have %d, want %d", before.Next, 1000)
+	}
+	after := NewID(&config, genesis, 1000, 0)
+	if after.Next != 0 {
+		t.Fatalf("next fork after rotation mismatch: have %d, want %d", after.Next, 0)
+	}
+	if before.Hash == after.Hash {
+		t.Fatalf("fork hash unchanged by recipient rotation: %x", after.Hash)
+	}
+}
+
 // TestValidation tests that a local peer correctly validates and accepts a remote
 // fork ID.
 func TestValidation(t *testing.T) {
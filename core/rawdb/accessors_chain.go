@@ -232,6 +232,21 @@ func WriteFinalizedBlockHash(db ethdb.KeyValueWriter, hash common.Hash) {
 	}
 }
 
+// ReadFinalityCheckpoint retrieves the RLP encoded latest signed finality
+// checkpoint.
+func ReadFinalityCheckpoint(db ethdb.KeyValueReader) []byte {
+	data, _ := db.Get(finalityCheckpointKey)
+	return data
+}
+
+// WriteFinalityCheckpoint stores the RLP encoded latest signed finality
+// checkpoint.
+func WriteFinalityCheckpoint(db ethdb.KeyValueWriter, checkpoint []byte) {
+	if err := db.Put(finalityCheckpointKey, checkpoint); err != nil {
+		log.Crit("Failed to store finality checkpoint", "err", err)
+	}
+}
+
 // ReadLastPivotNumber retrieves the number of the last pivot block. If the node
 // full synced, the last pivot will always be nil.
 func ReadLastPivotNumber(db ethdb.KeyValueReader) *uint64 {
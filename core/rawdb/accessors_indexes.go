@@ -175,3 +175,26 @@ func DeleteBloombits(db ethdb.Database, bit uint, from uint64, to uint64) {
 		log.Crit("Failed to delete bloom bits", "err", it.Error())
 	}
 }
+
+// ReadSupply retrieves the total supply at the end of the given section of the
+// supply index, or nil if the section wasn't indexed.
+func ReadSupply(db ethdb.KeyValueReader, section uint64, head common.Hash) *big.Int {
+	data, _ := db.Get(supplyKey(section, head))
+	if len(data) == 0 {
+		return nil
+	}
+	return new(big.Int).SetBytes(data)
+}
+
+// WriteSupply stores the total supply at the end of the given section of the
+// supply index.
+func WriteSupply(db ethdb.KeyValueWriter, section uint64, head common.Hash, supply *big.Int) {
+	// Encode zero as a single byte to tell it apart from a missing entry
+	data := supply.Bytes()
+	if len(data) == 0 {
+		data = []byte{0}
+	}
+	if err := db.Put(supplyKey(section, head), data); err != nil {
+		log.Crit("Failed to store supply checkpoint", "err", err)
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	// supplyThrottling is the time to wait between processing two consecutive
+	// supply sections. It's useful during chain upgrades to prevent disk overload.
+	supplyThrottling = 100 * time.Millisecond
+)
+
+var (
+	// errMissingSupplyCheckpoint is returned if the supply of the previous section
+	// is unavailable when starting a new one.
+	errMissingSupplyCheckpoint = errors.New("missing supply checkpoint")
+
+	// errSupplyIndexLagging is returned if the supply index is too far behind the
+	// requested block for the supply to be computed on the fly.
+	errSupplyIndexLagging = errors.New("supply index not yet available")
+)
+
+// RewardFunc returns the total block and uncle rewards credited by the consensus
+// engine for a proof-of-work block. It is provided by the engine in use, a nil
+// function meaning that blocks are not rewarded.
+type RewardFunc func(config *params.ChainConfig, header *types.Header, uncles []*types.Header) *big.Int
+
+// SupplyIndexer implements a core.ChainIndexer, accumulating the total coin
+// supply of the canonical chain and checkpointing it at the end of every section.
+type SupplyIndexer struct {
+	db      ethdb.Database      // database instance to read blocks from and write checkpoints into
+	config  *params.ChainConfig // chain configuration deciding the block rewards
+	reward  RewardFunc          // Block rewards of the consensus engine
+	section uint64              // Section is the section number being processed currently
+	head    common.Hash         // Head is the hash of the last header processed
+	supply  *big.Int            // Supply is the total supply after the last header processed
+}
+
+// NewSupplyIndexer returns a chain indexer that accumulates the total supply of
+// the canonical chain, the block rewards being computed by the given function.
+func NewSupplyIndexer(db ethdb.Database, config *params.ChainConfig, reward RewardFunc, size, confirms uint64) *ChainIndexer {
+	backend := &SupplyIndexer{
+		db:     db,
+		config: config,
+		reward: reward,
+	}
+	table := rawdb.NewTable(db, string(rawdb.SupplyIndexPrefix))
+
+	return NewChainIndexer(db, table, backend, size, confirms, supplyThrottling, "supply")
+}
+
+// Reset implements core.ChainIndexerBackend, starting a new supply section from
+// the checkpoint of the previous one.
+func (s *SupplyIndexer) Reset(ctx context.Context, section uint64, lastSectionHead common.Hash) error {
+	s.section, s.head = section, common.Hash{}
+	if section == 0 {
+		supply, err := GenesisSupply(s.db)
+		if err != nil {
+			return err
+		}
+		s.supply = supply
+		return nil
+	}
+	if s.supply = rawdb.ReadSupply(s.db, section-1, lastSectionHead); s.supply == nil {
+		return fmt.Errorf("%w: section %d, head %x", errMissingSupplyCheckpoint, section-1, lastSectionHead)
+	}
+	return nil
+}
+
+// Process implements core.ChainIndexerBackend, adding the issuance of a new
+// header to the supply.
+func (s *SupplyIndexer) Process(ctx context.Context, header *types.Header) error {
+	number, hash := header.Number.Uint64(), header.Hash()
+
+	body := rawdb.ReadBody(s.db, hash, number)
+	if body == nil {
+		return fmt.Errorf("missing body #%d [%x]", number, hash)
+	}
+	s.supply.Add(s.supply, BlockIssuance(s.config, s.reward, header, body))
+	s.head = hash
+	return nil
+}
+
+// Commit implements core.ChainIndexerBackend, checkpointing the supply at the
+// end of the section.
+func (s *SupplyIndexer) Commit() error {
+	rawdb.WriteSupply(s.db, s.section, s.head, s.supply)
+	return nil
+}
+
+// Prune returns an empty error since checkpoints are tiny and kept forever.
+func (s *SupplyIndexer) Prune(threshold uint64) error {
+	return nil
+}
+
+// BlockIssuance returns the change of the total supply caused by a block: the
+// block and uncle rewards plus the withdrawals, minus the base fee burned by
+// EIP-1559. The result may be negative if more is burned than issued.
+func BlockIssuance(config *params.ChainConfig, reward RewardFunc, header *types.Header, body *types.Body) *big.Int {
+	issuance := new(big.Int)
+
+	// Only proof-of-work blocks past genesis are rewarded
+	if reward != nil && header.Number.Sign() > 0 && header.Difficulty.Sign() > 0 {
+		issuance.Add(issuance, reward(config, header, body.Uncles))
+	}
+	for _, w := range body.Withdrawals {
+		amount := new(big.Int).SetUint64(w.Amount)
+		issuance.Add(issuance, amount.Mul(amount, big.NewInt(params.GWei)))
+	}
+	if header.BaseFee != nil {
+		burnt := new(big.Int).SetUint64(header.GasUsed)
+		issuance.Sub(issuance, burnt.Mul(burnt, header.BaseFee))
+	}
+	return issuance
+}
+
+// GenesisSupply returns the total balance allocated in the genesis block of the
+// chain stored in the database. The genesis specification is used if available,
+// otherwise the balances are summed up from the genesis state.
+func GenesisSupply(db ethdb.Database) (*big.Int, error) {
+	hash := rawdb.ReadCanonicalHash(db, 0)
+	if hash == (common.Hash{}) {
+		return nil, errors.New("genesis not found")
+	}
+	supply := new(big.Int)
+	if blob := rawdb.ReadGenesisStateSpec(db, hash); len(blob) != 0 {
+		var alloc GenesisAlloc
+		if err := alloc.UnmarshalJSON(blob); err != nil {
+			return nil, err
+		}
+		for _, account := range alloc {
+			if account.Balance != nil {
+				supply.Add(supply, account.Balance)
+			}
+		}
+		return supply, nil
+	}
+	header := rawdb.ReadHeader(db, hash, 0)
+	if header == nil {
+		return nil, errors.New("genesis header not found")
+	}
+	statedb, err := state.New(header.Root, state.NewDatabase(db), nil)
+	if err != nil {
+		return nil, fmt.Errorf("genesis state unavailable: %w", err)
+	}
+	statedb.DumpToCollector(&supplyCollector{supply: supply}, &state.DumpConfig{SkipCode: true, SkipStorage: true})
+	return supply, nil
+}
+
+// supplyCollector is a state.DumpCollector summing up the account balances.
+type supplyCollector struct {
+	supply *big.Int
+}
+
+func (c *supplyCollector) OnRoot(common.Hash) {}
+
+func (c *supplyCollector) OnAccount(addr common.Address, account state.DumpAccount) {
+	if balance, ok := new(big.Int).SetString(account.Balance, 10); ok {
+		c.supply.Add(c.supply, balance)
+	}
+}
+
+// SupplyAt returns the total supply after the given canonical block, resuming
+// from the closest checkpoint of the supply index.
+func SupplyAt(db ethdb.Database, config *params.ChainConfig, reward RewardFunc, indexer *ChainIndexer, number uint64) (*big.Int, error) {
+	var (
+		supply *big.Int
+		from   uint64
+	)
+	sections, _, _ := indexer.Sections()
+	if section := (number + 1) / indexer.sectionSize; section > 0 && sections > 0 {
+		if section > sections {
+			section = sections
+		}
+		supply = rawdb.ReadSupply(db, section-1, indexer.SectionHead(section-1))
+		from = section * indexer.sectionSize
+	}
+	if supply == nil {
+		genesis, err := GenesisSupply(db)
+		if err != nil {
+			return nil, err
+		}
+		supply, from = genesis, 1
+	}
+	// Avoid walking a large part of the chain while the index is catching up
+	if number >= from && number-from >= 2*indexer.sectionSize {
+		return nil, errSupplyIndexLagging
+	}
+	if err := AccumulateSupply(db, config, reward, supply, from, number); err != nil {
+		return nil, err
+	}
+	return supply, nil
+}
+
+// AccumulateSupply adds the issuance of the canonical blocks in the [from, to]
+// range to the supply.
+func AccumulateSupply(db ethdb.Reader, config *params.ChainConfig, reward RewardFunc, supply *big.Int, from, to uint64) error {
+	for number := from; number <= to; number++ {
+		hash := rawdb.ReadCanonicalHash(db, number)
+		if hash == (common.Hash{}) {
+			return fmt.Errorf("canonical block #%d not found", number)
+		}
+		header, body := rawdb.ReadHeader(db, hash, number), rawdb.ReadBody(db, hash, number)
+		if header == nil || body == nil {
+			return fmt.Errorf("missing block #%d [%x]", number, hash)
+		}
+		supply.Add(supply, BlockIssuance(config, reward, header, body))
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the supply index tracks the sum of all balances of the chain, with
+// rewards, uncles and burned base fees.
+func TestSupplyIndexer(t *testing.T) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr    = crypto.PubkeyToAddress(key.PublicKey)
+		config  = *params.TestChainConfig
+		engine  = ethash.NewFaker()
+		section = uint64(4)
+	)
+	config.Liberty = &params.LibertyConfig{
+		Epochs:           params.DefaultLibertyConfig.Epochs,
+		UncleRewardBlock: big.NewInt(1),
+	}
+	gspec := &Genesis{
+		Config:  &config,
+		BaseFee: big.NewInt(params.InitialBaseFee),
+		Alloc: GenesisAlloc{
+			addr:                      {Balance: big.NewInt(params.Ether)},
+			common.HexToAddress("aa"): {Balance: big.NewInt(12345)},
+		},
+	}
+	signer := types.LatestSigner(&config)
+	_, blocks, _ := GenerateChainWithGenesis(gspec, engine, 18, func(i int, gen *BlockGen) {
+		gen.SetCoinbase(common.Address{byte(i)})
+		if i%3 == 0 {
+			tx, _ := types.SignTx(types.NewTransaction(gen.TxNonce(addr), common.Address{0xff}, big.NewInt(1000), params.TxGas, gen.BaseFee(), nil), signer, key)
+			gen.AddTx(tx)
+		}
+		if i == 5 {
+			uncle := gen.PrevBlock(3).Header()
+			uncle.Extra = []byte("uncle")
+			gen.AddUncle(uncle)
+		}
+	})
+	db := rawdb.NewMemoryDatabase()
+	cacheConfig := *defaultCacheConfig
+	cacheConfig.TrieDirtyDisabled = true
+
+	chain, err := NewBlockChain(db, &cacheConfig, gspec, nil, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert block %d: %v", n, err)
+	}
+	indexer := NewSupplyIndexer(db, &config, ethash.BlockReward, section, 0)
+	defer indexer.Close()
+	indexer.Start(chain)
+
+	// Wait for all complete sections (19 blocks with genesis) to be indexed
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		if sections, _, _ := indexer.Sections(); sections == 4 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("supply index not generated in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	for number := uint64(0); number <= 18; number++ {
+		supply, err := SupplyAt(db, &config, ethash.BlockReward, indexer, number)
+		if err != nil {
+			t.Fatalf("block %d: failed to retrieve supply: %v", number, err)
+		}
+		statedb, _ := state.New(chain.GetHeaderByNumber(number).Root, chain.StateCache(), nil)
+		want := new(big.Int)
+		statedb.DumpToCollector(&supplyCollector{supply: want}, &state.DumpConfig{SkipCode: true, SkipStorage: true})
+		if supply.Cmp(want) != 0 {
+			t.Errorf("block %d: supply mismatch: have %v, want %v", number, supply, want)
+		}
+	}
+	// Checkpoints must match the supply recomputed from genesis
+	for s := uint64(0); s < 4; s++ {
+		head := (s+1)*section - 1
+		supply, _ := GenesisSupply(db)
+		if err := AccumulateSupply(db, &config, ethash.BlockReward, supply, 1, head); err != nil {
+			t.Fatalf("failed to recompute supply: %v", err)
+		}
+		if indexed := rawdb.ReadSupply(db, s, indexer.SectionHead(s)); indexed == nil || indexed.Cmp(supply) != 0 {
+			t.Errorf("section %d: checkpoint mismatch: have %v, want %v", s, indexed, supply)
+		}
+	}
+}
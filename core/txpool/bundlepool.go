@@ -0,0 +1,355 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	// BundleMaxTxs is the maximum number of transactions a single bundle can have.
+	BundleMaxTxs = 64
+
+	// bundlePoolSlots is the maximum number of bundles the pool keeps.
+	bundlePoolSlots = 4096
+
+	// bundleSenderSlots is the maximum number of bundles the pool keeps from a
+	// single sender, the signer of the first transaction of a bundle.
+	bundleSenderSlots = 16
+
+	// bundleMaxFutureBlocks is how many blocks above the current head a bundle
+	// may target.
+	bundleMaxFutureBlocks = 16
+)
+
+var (
+	// ErrBundleEmpty is returned if a bundle has no transactions.
+	ErrBundleEmpty = errors.New("bundle has no transactions")
+
+	// ErrBundleTooLarge is returned if a bundle has more transactions than the
+	// pool accepts in a single bundle.
+	ErrBundleTooLarge = errors.New("bundle has too many transactions")
+
+	// ErrBundleStale is returned if the block targeted by a bundle has already
+	// been mined.
+	ErrBundleStale = errors.New("bundle targets a past block")
+
+	// ErrBundleFuture is returned if the block targeted by a bundle is too far
+	// ahead of the current head.
+	ErrBundleFuture = errors.New("bundle targets a block too far in the future")
+
+	// ErrBundleTimestamp is returned if the timestamp range of a bundle is empty.
+	ErrBundleTimestamp = errors.New("bundle timestamp range is empty")
+
+	// ErrBundlePoolOverflow is returned if the bundle pool is full and the
+	// bundle doesn't pay more than any of the pooled ones.
+	ErrBundlePoolOverflow = errors.New("bundle pool is full")
+
+	// ErrBundleSenderLimit is returned if the sender of a bundle already has as
+	// many bundles pooled as a single sender is allowed.
+	ErrBundleSenderLimit = errors.New("bundle sender exceeds pool limit")
+)
+
+var (
+	bundleValidMeter   = metrics.NewRegisteredMeter("txpool/bundles/valid", nil)
+	bundleEvictMeter   = metrics.NewRegisteredMeter("txpool/bundles/evicted", nil)
+	bundleInvalidMeter = metrics.NewRegisteredMeter("txpool/bundles/invalid", nil)
+	bundleStaleMeter   = metrics.NewRegisteredMeter("txpool/bundles/stale", nil)
+	bundleGauge        = metrics.NewRegisteredGauge("txpool/bundles", nil)
+)
+
+// Bundle is a group of transactions to be included into a specific block
+// atomically: either all of them, in the given order, or none.
+type Bundle struct {
+	Txs               types.Transactions // Transactions of the bundle, in inclusion order
+	BlockNumber       uint64             // Number of the block the bundle targets
+	MinTimestamp      uint64             // Earliest block timestamp the bundle is valid at, 0 if unbounded
+	MaxTimestamp      uint64             // Latest block timestamp the bundle is valid at, 0 if unbounded
+	RevertingTxHashes []common.Hash      // Transactions allowed to revert without invalidating the bundle
+}
+
+// Hash returns the identifier of the bundle, the hash of its transaction hashes.
+func (b *Bundle) Hash() common.Hash {
+	hashes := make([]byte, 0, len(b.Txs)*common.HashLength)
+	for _, tx := range b.Txs {
+		hashes = append(hashes, tx.Hash().Bytes()...)
+	}
+	return crypto.Keccak256Hash(hashes)
+}
+
+// AllowsRevert reports whether the given transaction of the bundle may revert
+// without invalidating the whole bundle.
+func (b *Bundle) AllowsRevert(hash common.Hash) bool {
+	for _, allowed := range b.RevertingTxHashes {
+		if allowed == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// validAt reports whether the bundle may be included into a block with the
+// given timestamp.
+func (b *Bundle) validAt(timestamp uint64) bool {
+	if b.MinTimestamp != 0 && timestamp < b.MinTimestamp {
+		return false
+	}
+	if b.MaxTimestamp != 0 && timestamp > b.MaxTimestamp {
+		return false
+	}
+	return true
+}
+
+// bundlePrice returns the gas weighted average tip the transactions of a bundle
+// pay on top of the given base fee.
+func bundlePrice(bundle *Bundle, baseFee *big.Int) *big.Int {
+	var (
+		fees = new(big.Int)
+		gas  = new(big.Int)
+	)
+	for _, tx := range bundle.Txs {
+		limit := new(big.Int).SetUint64(tx.Gas())
+		fees.Add(fees, limit.Mul(limit, tx.EffectiveGasTipValue(baseFee)))
+		gas.Add(gas, new(big.Int).SetUint64(tx.Gas()))
+	}
+	if gas.Sign() == 0 {
+		return fees
+	}
+	return fees.Div(fees, gas)
+}
+
+// pooledBundle is a bundle kept by the pool, along with the details it was
+// admitted with.
+type pooledBundle struct {
+	bundle *Bundle
+	hash   common.Hash
+	sender common.Address // Signer of the first transaction of the bundle
+	price  *big.Int       // Price of the bundle at the head it was admitted on
+}
+
+// BundlePool keeps the transaction bundles submitted for upcoming blocks until
+// the worker picks them up. Bundles are not propagated to other peers, nor do
+// their transactions enter the transaction pool.
+type BundlePool struct {
+	signer types.Signer
+	chain  blockChain
+
+	bundles map[uint64][]*pooledBundle    // Bundles grouped by target block number, in arrival order
+	known   map[common.Hash]*pooledBundle // All the bundles in the pool, by hash
+	senders map[common.Address]int        // Number of bundles pooled from each sender
+	mu      sync.RWMutex
+}
+
+// NewBundlePool creates a new bundle pool accepting bundles on top of the given
+// chain.
+func NewBundlePool(chainconfig *params.ChainConfig, chain blockChain) *BundlePool {
+	return &BundlePool{
+		signer:  types.LatestSigner(chainconfig),
+		chain:   chain,
+		bundles: make(map[uint64][]*pooledBundle),
+		known:   make(map[common.Hash]*pooledBundle),
+		senders: make(map[common.Address]int),
+	}
+}
+
+// Add validates a bundle and adds it to the pool, returning the bundle hash.
+func (pool *BundlePool) Add(bundle *Bundle) (common.Hash, error) {
+	hash, err := pool.add(bundle)
+	if err != nil {
+		bundleInvalidMeter.Mark(1)
+		log.Trace("Discarding invalid bundle", "hash", hash, "err", err)
+		return hash, err
+	}
+	bundleValidMeter.Mark(1)
+	log.Trace("Pooled new bundle", "hash", hash, "block", bundle.BlockNumber, "txs", len(bundle.Txs))
+	return hash, nil
+}
+
+func (pool *BundlePool) add(bundle *Bundle) (common.Hash, error) {
+	hash := bundle.Hash()
+
+	// Make sure the bundle is sane and can still be included
+	if len(bundle.Txs) == 0 {
+		return hash, ErrBundleEmpty
+	}
+	if len(bundle.Txs) > BundleMaxTxs {
+		return hash, ErrBundleTooLarge
+	}
+	if bundle.MaxTimestamp != 0 && bundle.MinTimestamp > bundle.MaxTimestamp {
+		return hash, ErrBundleTimestamp
+	}
+	head := pool.chain.CurrentBlock()
+	if bundle.BlockNumber <= head.Number.Uint64() {
+		return hash, ErrBundleStale
+	}
+	if bundle.BlockNumber > head.Number.Uint64()+bundleMaxFutureBlocks {
+		return hash, ErrBundleFuture
+	}
+	// Make sure the senders can pay for their transactions, so that bundles of
+	// unfunded accounts don't take the place of real ones
+	statedb, err := pool.chain.StateAt(head.Root)
+	if err != nil {
+		return hash, err
+	}
+	var (
+		sender common.Address
+		costs  = make(map[common.Address]*big.Int)
+	)
+	for i, tx := range bundle.Txs {
+		from, err := types.Sender(pool.signer, tx)
+		if err != nil {
+			return hash, ErrInvalidSender
+		}
+		if i == 0 {
+			sender = from
+		}
+		if statedb.GetNonce(from) > tx.Nonce() {
+			return hash, core.ErrNonceTooLow
+		}
+		if costs[from] == nil {
+			costs[from] = new(big.Int)
+		}
+		if costs[from].Add(costs[from], tx.Cost()); statedb.GetBalance(from).Cmp(costs[from]) < 0 {
+			return hash, core.ErrInsufficientFunds
+		}
+	}
+	pooled := &pooledBundle{
+		bundle: bundle,
+		hash:   hash,
+		sender: sender,
+		price:  bundlePrice(bundle, head.BaseFee),
+	}
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	pool.prune(head.Number.Uint64() + 1)
+	if _, ok := pool.known[hash]; ok {
+		return hash, ErrAlreadyKnown
+	}
+	if pool.senders[sender] >= bundleSenderSlots {
+		return hash, ErrBundleSenderLimit
+	}
+	// If the pool is full, make room by evicting the cheapest bundle, provided
+	// the new one pays more
+	if len(pool.known) >= bundlePoolSlots {
+		var cheapest *pooledBundle
+		for _, b := range pool.known {
+			if cheapest == nil || b.price.Cmp(cheapest.price) < 0 {
+				cheapest = b
+			}
+		}
+		if pooled.price.Cmp(cheapest.price) <= 0 {
+			return hash, ErrBundlePoolOverflow
+		}
+		log.Trace("Evicting cheap bundle", "hash", cheapest.hash, "price", cheapest.price)
+		pool.remove(cheapest)
+		bundleEvictMeter.Mark(1)
+	}
+	pool.bundles[bundle.BlockNumber] = append(pool.bundles[bundle.BlockNumber], pooled)
+	pool.known[hash] = pooled
+	pool.senders[sender]++
+	bundleGauge.Update(int64(len(pool.known)))
+
+	return hash, nil
+}
+
+// Bundles returns the bundles that may be included into the block with the
+// given number and timestamp, the best paying first and otherwise in the order
+// they were submitted. Bundles targeting earlier blocks are dropped.
+func (pool *BundlePool) Bundles(number uint64, timestamp uint64) []*Bundle {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	pool.prune(number)
+
+	var pooled []*pooledBundle
+	for _, b := range pool.bundles[number] {
+		if b.bundle.validAt(timestamp) {
+			pooled = append(pooled, b)
+		}
+	}
+	sort.SliceStable(pooled, func(i, j int) bool {
+		return pooled[i].price.Cmp(pooled[j].price) > 0
+	})
+	bundles := make([]*Bundle, 0, len(pooled))
+	for _, b := range pooled {
+		bundles = append(bundles, b.bundle)
+	}
+	return bundles
+}
+
+// Len returns the number of bundles in the pool.
+func (pool *BundlePool) Len() int {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	return len(pool.known)
+}
+
+// prune drops all the bundles targeting blocks before the given number. The
+// caller must hold the pool lock.
+func (pool *BundlePool) prune(number uint64) {
+	for target, bundles := range pool.bundles {
+		if target >= number {
+			continue
+		}
+		for _, bundle := range bundles {
+			pool.release(bundle)
+		}
+		delete(pool.bundles, target)
+		bundleStaleMeter.Mark(int64(len(bundles)))
+	}
+	bundleGauge.Update(int64(len(pool.known)))
+}
+
+// remove drops a single bundle from the pool. The caller must hold the pool
+// lock.
+func (pool *BundlePool) remove(bundle *pooledBundle) {
+	target := bundle.bundle.BlockNumber
+	for i, b := range pool.bundles[target] {
+		if b == bundle {
+			pool.bundles[target] = append(pool.bundles[target][:i], pool.bundles[target][i+1:]...)
+			break
+		}
+	}
+	if len(pool.bundles[target]) == 0 {
+		delete(pool.bundles, target)
+	}
+	pool.release(bundle)
+}
+
+// release forgets a bundle dropped from its target block and frees the slot of
+// its sender. The caller must hold the pool lock.
+func (pool *BundlePool) release(bundle *pooledBundle) {
+	if pool.senders[bundle.sender] > 1 {
+		pool.senders[bundle.sender]--
+	} else {
+		delete(pool.senders, bundle.sender)
+	}
+	delete(pool.known, bundle.hash)
+}
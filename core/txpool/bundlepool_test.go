@@ -0,0 +1,220 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// newTestBundlePool creates a bundle pool on top of a chain in which the given
+// keys are funded.
+func newTestBundlePool(keys ...*ecdsa.PrivateKey) (*BundlePool, *state.StateDB) {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	for _, key := range keys {
+		statedb.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(params.Ether))
+	}
+	return NewBundlePool(params.TestChainConfig, newTestBlockChain(1000000, statedb, nil)), statedb
+}
+
+// Tests that invalid bundles are rejected by the bundle pool.
+func TestBundleValidation(t *testing.T) {
+	t.Parallel()
+
+	var (
+		key, _      = crypto.GenerateKey()
+		spent, _    = crypto.GenerateKey()
+		unfunded, _ = crypto.GenerateKey()
+	)
+	pool, statedb := newTestBundlePool(key, spent)
+	statedb.SetNonce(crypto.PubkeyToAddress(spent.PublicKey), 1)
+
+	foreign, _ := types.SignTx(types.NewTransaction(0, common.Address{}, big.NewInt(0), 100000, big.NewInt(1), nil), types.NewEIP155Signer(big.NewInt(1337)), key)
+	oversized := make(types.Transactions, BundleMaxTxs+1)
+	for i := range oversized {
+		oversized[i] = transaction(uint64(i), 100000, key)
+	}
+	tests := []struct {
+		bundle *Bundle
+		err    error
+	}{
+		{&Bundle{BlockNumber: 1}, ErrBundleEmpty},
+		{&Bundle{Txs: oversized, BlockNumber: 1}, ErrBundleTooLarge},
+		{&Bundle{Txs: types.Transactions{transaction(0, 100000, key)}, BlockNumber: 0}, ErrBundleStale},
+		{&Bundle{Txs: types.Transactions{transaction(0, 100000, key)}, BlockNumber: 1, MinTimestamp: 20, MaxTimestamp: 10}, ErrBundleTimestamp},
+		{&Bundle{Txs: types.Transactions{transaction(0, 100000, key)}, BlockNumber: bundleMaxFutureBlocks + 1}, ErrBundleFuture},
+		{&Bundle{Txs: types.Transactions{foreign}, BlockNumber: 1}, ErrInvalidSender},
+		{&Bundle{Txs: types.Transactions{transaction(0, 100000, spent)}, BlockNumber: 1}, core.ErrNonceTooLow},
+		{&Bundle{Txs: types.Transactions{transaction(0, 100000, unfunded)}, BlockNumber: 1}, core.ErrInsufficientFunds},
+		{&Bundle{Txs: types.Transactions{transaction(1, 100000, spent), pricedTransaction(2, 100000, big.NewInt(params.GWei*10000), spent)}, BlockNumber: 1}, core.ErrInsufficientFunds},
+		{&Bundle{Txs: types.Transactions{transaction(0, 100000, key)}, BlockNumber: 1}, nil},
+		{&Bundle{Txs: types.Transactions{transaction(0, 100000, key)}, BlockNumber: 2}, ErrAlreadyKnown},
+	}
+	for i, tt := range tests {
+		if _, err := pool.Add(tt.bundle); !errors.Is(err, tt.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+	if pool.Len() != 1 {
+		t.Errorf("pooled bundle count mismatch: have %d, want %d", pool.Len(), 1)
+	}
+}
+
+// Tests that a single sender can't pool more bundles than its quota, and that
+// the quota is released as the bundles go stale.
+func TestBundleSenderLimit(t *testing.T) {
+	t.Parallel()
+
+	var (
+		key, _   = crypto.GenerateKey()
+		other, _ = crypto.GenerateKey()
+		pool, _  = newTestBundlePool(key, other)
+	)
+	for i := 0; i < bundleSenderSlots; i++ {
+		if _, err := pool.Add(&Bundle{Txs: types.Transactions{transaction(uint64(i), 100000, key)}, BlockNumber: 1}); err != nil {
+			t.Fatalf("bundle %d: failed to add: %v", i, err)
+		}
+	}
+	// Only bundles led by the sender count towards its quota
+	overflow := &Bundle{Txs: types.Transactions{transaction(bundleSenderSlots, 100000, key)}, BlockNumber: 2}
+	if _, err := pool.Add(overflow); !errors.Is(err, ErrBundleSenderLimit) {
+		t.Fatalf("overflowing bundle error mismatch: have %v, want %v", err, ErrBundleSenderLimit)
+	}
+	trailing := &Bundle{Txs: types.Transactions{transaction(0, 100000, other), transaction(bundleSenderSlots, 100000, key)}, BlockNumber: 2}
+	if _, err := pool.Add(trailing); err != nil {
+		t.Fatalf("failed to add bundle of another sender: %v", err)
+	}
+	// Drop the stale bundles and check that the quota is released
+	pool.Bundles(2, 0)
+	if _, err := pool.Add(overflow); err != nil {
+		t.Fatalf("failed to add bundle after pruning: %v", err)
+	}
+}
+
+// Tests that the bundle pool returns the bundles valid for a block, and drops
+// the ones targeting earlier blocks.
+func TestBundleSelection(t *testing.T) {
+	t.Parallel()
+
+	key, _ := crypto.GenerateKey()
+	pool, _ := newTestBundlePool(key)
+
+	bundles := []*Bundle{
+		{Txs: types.Transactions{transaction(0, 100000, key)}, BlockNumber: 1},
+		{Txs: types.Transactions{transaction(1, 100000, key)}, BlockNumber: 2},
+		{Txs: types.Transactions{transaction(2, 100000, key)}, BlockNumber: 2, MinTimestamp: 100},
+		{Txs: types.Transactions{transaction(3, 100000, key)}, BlockNumber: 2, MaxTimestamp: 50},
+		{Txs: types.Transactions{transaction(4, 100000, key)}, BlockNumber: 3},
+	}
+	for i, bundle := range bundles {
+		if _, err := pool.Add(bundle); err != nil {
+			t.Fatalf("bundle %d: failed to add: %v", i, err)
+		}
+	}
+	tests := []struct {
+		number    uint64
+		timestamp uint64
+		want      []*Bundle
+		pooled    int
+	}{
+		{number: 1, timestamp: 10, want: bundles[:1], pooled: 5},
+		{number: 2, timestamp: 10, want: []*Bundle{bundles[1], bundles[3]}, pooled: 4},
+		{number: 2, timestamp: 100, want: []*Bundle{bundles[1], bundles[2]}, pooled: 4},
+		{number: 4, timestamp: 100, want: nil, pooled: 0},
+	}
+	for i, tt := range tests {
+		have := pool.Bundles(tt.number, tt.timestamp)
+		if len(have) != len(tt.want) {
+			t.Errorf("test %d: bundle count mismatch: have %d, want %d", i, len(have), len(tt.want))
+			continue
+		}
+		for j := range have {
+			if have[j].Hash() != tt.want[j].Hash() {
+				t.Errorf("test %d: bundle %d mismatch: have %x, want %x", i, j, have[j].Hash(), tt.want[j].Hash())
+			}
+		}
+		if pool.Len() != tt.pooled {
+			t.Errorf("test %d: pooled bundle count mismatch: have %d, want %d", i, pool.Len(), tt.pooled)
+		}
+	}
+}
+
+// Tests that bundles are returned by price, and that a full pool evicts its
+// cheapest bundle for a better paying one.
+func TestBundlePricing(t *testing.T) {
+	t.Parallel()
+
+	keys := make([]*ecdsa.PrivateKey, bundlePoolSlots/bundleSenderSlots+1)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	pool, _ := newTestBundlePool(keys...)
+
+	// Fill the pool, the first bundle being the cheapest and the second one the
+	// best paying
+	var bundles []*Bundle
+	for i := 0; i < bundlePoolSlots; i++ {
+		price := big.NewInt(2)
+		switch i {
+		case 0:
+			price = big.NewInt(1)
+		case 1:
+			price = big.NewInt(3)
+		}
+		key := keys[i/bundleSenderSlots]
+		bundle := &Bundle{Txs: types.Transactions{pricedTransaction(uint64(i%bundleSenderSlots), 100000, price, key)}, BlockNumber: 1}
+		if _, err := pool.Add(bundle); err != nil {
+			t.Fatalf("bundle %d: failed to add: %v", i, err)
+		}
+		bundles = append(bundles, bundle)
+	}
+	// Bundles not paying more than the cheapest one are refused
+	last := keys[len(keys)-1]
+	if _, err := pool.Add(&Bundle{Txs: types.Transactions{pricedTransaction(0, 100000, big.NewInt(1), last)}, BlockNumber: 1}); !errors.Is(err, ErrBundlePoolOverflow) {
+		t.Fatalf("cheap bundle error mismatch: have %v, want %v", err, ErrBundlePoolOverflow)
+	}
+	// Better paying bundles replace the cheapest one
+	better := &Bundle{Txs: types.Transactions{pricedTransaction(1, 100000, big.NewInt(4), last)}, BlockNumber: 1}
+	if _, err := pool.Add(better); err != nil {
+		t.Fatalf("failed to add better paying bundle: %v", err)
+	}
+	if pool.Len() != bundlePoolSlots {
+		t.Fatalf("pooled bundle count mismatch: have %d, want %d", pool.Len(), bundlePoolSlots)
+	}
+	have := pool.Bundles(1, 0)
+	if len(have) != bundlePoolSlots {
+		t.Fatalf("bundle count mismatch: have %d, want %d", len(have), bundlePoolSlots)
+	}
+	if have[0].Hash() != better.Hash() || have[1].Hash() != bundles[1].Hash() || have[2].Hash() != bundles[2].Hash() {
+		t.Errorf("bundles not ordered by price")
+	}
+	for _, bundle := range have {
+		if bundle.Hash() == bundles[0].Hash() {
+			t.Errorf("cheapest bundle not evicted")
+		}
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+	"golang.org/x/time/rate"
+)
+
+// policyPeerLimiters is the number of peers whose rate limiters are tracked.
+const policyPeerLimiters = 1024
+
+var (
+	// ErrPolicyDenied is returned if the sender or the recipient of a transaction
+	// is on the denylist of the admission policy.
+	ErrPolicyDenied = errors.New("address denied by txpool policy")
+
+	// ErrPolicyCreation is returned if a contract creation is rejected by the
+	// admission policy.
+	ErrPolicyCreation = errors.New("contract creation limited by txpool policy")
+
+	// ErrPolicyCalldata is returned if the calldata of a transaction exceeds the
+	// limit of the admission policy.
+	ErrPolicyCalldata = errors.New("calldata exceeds txpool policy limit")
+
+	// ErrPolicyTip is returned if the tip of a transaction is below the minimum
+	// the admission policy requires from its sender.
+	ErrPolicyTip = errors.New("tip below txpool policy minimum")
+
+	// ErrPolicyRateLimit is returned if the peer a transaction was received from
+	// exceeded the rate limit of the admission policy.
+	ErrPolicyRateLimit = errors.New("peer exceeds txpool policy rate limit")
+)
+
+var (
+	policyDeniedMeter    = metrics.NewRegisteredMeter("txpool/policy/denied", nil)
+	policyCreationMeter  = metrics.NewRegisteredMeter("txpool/policy/creation", nil)
+	policyCalldataMeter  = metrics.NewRegisteredMeter("txpool/policy/calldata", nil)
+	policyTipMeter       = metrics.NewRegisteredMeter("txpool/policy/tip", nil)
+	policyRateLimitMeter = metrics.NewRegisteredMeter("txpool/policy/ratelimit", nil)
+)
+
+// Policy is a set of admission rules operators impose on transactions entering
+// the pool, on top of the consensus and pool rules. The zero policy admits
+// everything.
+type Policy struct {
+	Deny         []common.Address `json:"deny,omitempty" toml:",omitempty"`         // Senders and recipients whose transactions are rejected
+	NoCreations  bool             `json:"noCreations,omitempty" toml:",omitempty"`  // Whether contract creations are rejected
+	MaxCreations uint64           `json:"maxCreations,omitempty" toml:",omitempty"` // Maximum number of pooled contract creations per sender (0 = unlimited)
+	MaxCalldata  uint64           `json:"maxCalldata,omitempty" toml:",omitempty"`  // Maximum calldata size of a transaction in bytes (0 = unlimited)
+	MinTip       uint64           `json:"minTip,omitempty" toml:",omitempty"`       // Minimum tip in wei of remote transactions from senders outside of any class
+	Classes      []SenderClass    `json:"classes,omitempty" toml:",omitempty"`      // Sender classes with their own minimum tip
+	PeerRate     float64          `json:"peerRate,omitempty" toml:",omitempty"`     // Transactions per second accepted from a single peer (0 = unlimited)
+	PeerBurst    int              `json:"peerBurst,omitempty" toml:",omitempty"`    // Transactions accepted from a single peer at once (defaults to the rate)
+}
+
+// SenderClass is a group of senders sharing a minimum tip. Senders listed in
+// several classes belong to the first.
+type SenderClass struct {
+	Name    string           `json:"name"`
+	Senders []common.Address `json:"senders"`
+	MinTip  uint64           `json:"minTip"` // Minimum tip in wei of remote transactions from the senders
+}
+
+// admission is the compiled form of a Policy, checking transactions against it.
+type admission struct {
+	policy Policy
+
+	deny   map[common.Address]struct{}
+	tips   map[common.Address]*big.Int // Minimum tip of the senders of a class
+	minTip *big.Int                    // Minimum tip of all other senders
+
+	limiters *lru.BasicLRU[string, *rate.Limiter] // Rate limiters of the recently seen peers
+	lock     sync.Mutex                           // Protects the rate limiters
+}
+
+// newAdmission compiles an admission policy.
+func newAdmission(policy Policy) (*admission, error) {
+	if policy.PeerRate < 0 || math.IsNaN(policy.PeerRate) || math.IsInf(policy.PeerRate, 0) {
+		return nil, fmt.Errorf("invalid peer rate %v", policy.PeerRate)
+	}
+	if policy.PeerBurst < 0 {
+		return nil, fmt.Errorf("invalid peer burst %d", policy.PeerBurst)
+	}
+	if policy.PeerRate > 0 && policy.PeerBurst == 0 {
+		policy.PeerBurst = int(math.Ceil(policy.PeerRate))
+	}
+	a := &admission{
+		policy: policy,
+		deny:   make(map[common.Address]struct{}, len(policy.Deny)),
+		tips:   make(map[common.Address]*big.Int),
+		minTip: new(big.Int).SetUint64(policy.MinTip),
+	}
+	for _, addr := range policy.Deny {
+		a.deny[addr] = struct{}{}
+	}
+	for _, class := range policy.Classes {
+		tip := new(big.Int).SetUint64(class.MinTip)
+		for _, addr := range class.Senders {
+			if _, ok := a.tips[addr]; !ok {
+				a.tips[addr] = tip
+			}
+		}
+	}
+	if policy.PeerRate > 0 {
+		limiters := lru.NewBasicLRU[string, *rate.Limiter](policyPeerLimiters)
+		a.limiters = &limiters
+	}
+	return a, nil
+}
+
+// validate checks a transaction against the stateless rules of the policy and
+// the number of contract creations the sender already has pooled. Local
+// transactions are exempt from the minimum tips, just like from the pool's
+// pricing rules.
+func (a *admission) validate(tx *types.Transaction, from common.Address, local bool, creations func() uint64) error {
+	if len(a.deny) > 0 {
+		if _, ok := a.deny[from]; ok {
+			policyDeniedMeter.Mark(1)
+			return fmt.Errorf("%w: sender %v", ErrPolicyDenied, from)
+		}
+		if to := tx.To(); to != nil {
+			if _, ok := a.deny[*to]; ok {
+				policyDeniedMeter.Mark(1)
+				return fmt.Errorf("%w: recipient %v", ErrPolicyDenied, *to)
+			}
+		}
+	}
+	if tx.To() == nil {
+		if a.policy.NoCreations {
+			policyCreationMeter.Mark(1)
+			return ErrPolicyCreation
+		}
+		if a.policy.MaxCreations > 0 && creations() >= a.policy.MaxCreations {
+			policyCreationMeter.Mark(1)
+			return fmt.Errorf("%w: sender %v has %d pooled", ErrPolicyCreation, from, a.policy.MaxCreations)
+		}
+	}
+	if a.policy.MaxCalldata > 0 && uint64(len(tx.Data())) > a.policy.MaxCalldata {
+		policyCalldataMeter.Mark(1)
+		return fmt.Errorf("%w: size %d, limit %d", ErrPolicyCalldata, len(tx.Data()), a.policy.MaxCalldata)
+	}
+	if !local {
+		minTip, ok := a.tips[from]
+		if !ok {
+			minTip = a.minTip
+		}
+		if tx.GasTipCapIntCmp(minTip) < 0 {
+			policyTipMeter.Mark(1)
+			return fmt.Errorf("%w: tip %v, minimum %v", ErrPolicyTip, tx.GasTipCap(), minTip)
+		}
+	}
+	return nil
+}
+
+// allow reports whether another transaction from the given peer fits into the
+// rate limit of the policy.
+func (a *admission) allow(peer string) bool {
+	if a.limiters == nil {
+		return true
+	}
+	a.lock.Lock()
+	limiter, ok := a.limiters.Get(peer)
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(a.policy.PeerRate), a.policy.PeerBurst)
+		a.limiters.Add(peer, limiter)
+	}
+	a.lock.Unlock()
+
+	if !limiter.Allow() {
+		policyRateLimitMeter.Mark(1)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,196 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Tests that transactions violating the admission policy are rejected with the
+// error of the violated rule.
+func TestPolicyAdmission(t *testing.T) {
+	t.Parallel()
+
+	var (
+		sender, _  = crypto.GenerateKey()
+		partner, _ = crypto.GenerateKey()
+
+		senderAddr  = crypto.PubkeyToAddress(sender.PublicKey)
+		partnerAddr = crypto.PubkeyToAddress(partner.PublicKey)
+	)
+	creation := func(nonce uint64, price int64) *types.Transaction {
+		tx, _ := types.SignTx(types.NewContractCreation(nonce, big.NewInt(0), 100000, big.NewInt(price), nil), types.HomesteadSigner{}, sender)
+		return tx
+	}
+	tests := []struct {
+		name   string
+		policy Policy
+		txs    []*types.Transaction // Added as remotes, all but the last must succeed
+		local  bool                 // Whether the last transaction is added as local
+		err    error
+	}{
+		{
+			name:   "denied-sender",
+			policy: Policy{Deny: []common.Address{senderAddr}},
+			txs:    []*types.Transaction{pricedTransaction(0, 100000, big.NewInt(1), sender)},
+			err:    ErrPolicyDenied,
+		},
+		{
+			name:   "denied-recipient",
+			policy: Policy{Deny: []common.Address{{}}},
+			txs:    []*types.Transaction{pricedTransaction(0, 100000, big.NewInt(1), sender)},
+			local:  true,
+			err:    ErrPolicyDenied,
+		},
+		{
+			name:   "no-creations",
+			policy: Policy{NoCreations: true},
+			txs:    []*types.Transaction{creation(0, 1)},
+			err:    ErrPolicyCreation,
+		},
+		{
+			name:   "max-creations",
+			policy: Policy{MaxCreations: 2},
+			txs:    []*types.Transaction{creation(0, 1), creation(1, 1), creation(2, 1)},
+			err:    ErrPolicyCreation,
+		},
+		{
+			name:   "max-creations-replacement",
+			policy: Policy{MaxCreations: 2},
+			txs:    []*types.Transaction{creation(0, 1), creation(1, 1), creation(1, 2)},
+		},
+		{
+			name:   "max-calldata",
+			policy: Policy{MaxCalldata: 64},
+			txs:    []*types.Transaction{pricedDataTransaction(0, 100000, big.NewInt(1), sender, 64), pricedDataTransaction(1, 100000, big.NewInt(1), sender, 65)},
+			err:    ErrPolicyCalldata,
+		},
+		{
+			name:   "min-tip",
+			policy: Policy{MinTip: 2},
+			txs:    []*types.Transaction{pricedTransaction(0, 100000, big.NewInt(1), sender)},
+			err:    ErrPolicyTip,
+		},
+		{
+			name:   "min-tip-local",
+			policy: Policy{MinTip: 2},
+			txs:    []*types.Transaction{pricedTransaction(0, 100000, big.NewInt(1), sender)},
+			local:  true,
+		},
+		{
+			name:   "min-tip-class",
+			policy: Policy{MinTip: 1, Classes: []SenderClass{{Name: "partners", Senders: []common.Address{partnerAddr}, MinTip: 3}}},
+			txs:    []*types.Transaction{pricedTransaction(0, 100000, big.NewInt(1), sender), pricedTransaction(0, 100000, big.NewInt(2), partner)},
+			err:    ErrPolicyTip,
+		},
+		{
+			name:   "min-tip-class-lowered",
+			policy: Policy{MinTip: 3, Classes: []SenderClass{{Name: "partners", Senders: []common.Address{partnerAddr}, MinTip: 1}}},
+			txs:    []*types.Transaction{pricedTransaction(0, 100000, big.NewInt(1), partner)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool, _ := setupPool()
+			defer pool.Stop()
+
+			testAddBalance(pool, senderAddr, big.NewInt(1000000000))
+			testAddBalance(pool, partnerAddr, big.NewInt(1000000000))
+
+			if err := pool.SetPolicy(tt.policy); err != nil {
+				t.Fatalf("failed to set policy: %v", err)
+			}
+			last := len(tt.txs) - 1
+			for i, tx := range tt.txs[:last] {
+				if err := pool.addRemoteSync(tx); err != nil {
+					t.Fatalf("transaction %d: failed to add: %v", i, err)
+				}
+			}
+			var err error
+			if tt.local {
+				err = pool.AddLocal(tt.txs[last])
+			} else {
+				err = pool.addRemoteSync(tt.txs[last])
+			}
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("error mismatch: have %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+// Tests that the admission policy can be replaced at runtime, and that invalid
+// policies are refused.
+func TestPolicyReload(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Stop()
+
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, addr, big.NewInt(1000000000))
+
+	if err := pool.SetPolicy(Policy{Deny: []common.Address{addr}}); err != nil {
+		t.Fatalf("failed to set policy: %v", err)
+	}
+	if err := pool.addRemoteSync(transaction(0, 100000, key)); !errors.Is(err, ErrPolicyDenied) {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrPolicyDenied)
+	}
+	if err := pool.SetPolicy(Policy{PeerRate: -1}); err == nil {
+		t.Fatalf("invalid policy accepted")
+	}
+	if deny := pool.Policy().Deny; len(deny) != 1 || deny[0] != addr {
+		t.Fatalf("policy replaced by invalid one: deny list %v", deny)
+	}
+	if err := pool.SetPolicy(Policy{}); err != nil {
+		t.Fatalf("failed to reset policy: %v", err)
+	}
+	if err := pool.addRemoteSync(transaction(0, 100000, key)); err != nil {
+		t.Fatalf("failed to add transaction after policy reset: %v", err)
+	}
+}
+
+// Tests that transactions from peers exceeding the rate limit of the admission
+// policy are rejected, without affecting other peers.
+func TestPolicyPeerRateLimit(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Stop()
+
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+
+	if err := pool.SetPolicy(Policy{PeerRate: 0.001, PeerBurst: 2}); err != nil {
+		t.Fatalf("failed to set policy: %v", err)
+	}
+	txs := []*types.Transaction{transaction(0, 100000, key), transaction(1, 100000, key), transaction(2, 100000, key)}
+	errs := pool.AddRemotesFrom("peer-a", txs)
+	for i, want := range []error{nil, nil, ErrPolicyRateLimit} {
+		if !errors.Is(errs[i], want) {
+			t.Errorf("transaction %d: error mismatch: have %v, want %v", i, errs[i], want)
+		}
+	}
+	if errs := pool.AddRemotesFrom("peer-b", txs[2:]); errs[0] != nil {
+		t.Errorf("transaction from other peer rejected: %v", errs[0])
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// remoteJournal is a snapshot of the executable remote transactions of the pool,
+// saved periodically and on shutdown, so that a restarted node doesn't have to
+// wait for the network to refill its pending set. Unlike the local journal, it
+// is not appended to between snapshots: the remote set changes too quickly for
+// that to be worth it.
+type remoteJournal struct {
+	path  string // Filesystem path to store the transactions at
+	limit uint64 // Maximum size of the snapshot, in bytes
+}
+
+// newRemoteJournal creates a new remote transaction journal, keeping at most
+// limit bytes of transactions.
+func newRemoteJournal(path string, limit uint64) *remoteJournal {
+	return &remoteJournal{
+		path:  path,
+		limit: limit,
+	}
+}
+
+// load parses a remote transaction snapshot from disk, loading its contents
+// into the pool. Transactions that fail to decode are skipped, and a damaged
+// snapshot is read up to the point of the damage, so that a corrupted file
+// never prevents the pool from starting.
+func (journal *remoteJournal) load(add func([]*types.Transaction) []error) error {
+	input, err := os.Open(journal.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		// Skip the parsing if the journal file doesn't exist at all
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	// Limit the stream to the file size, so a corrupted length prefix can't
+	// make the decoder allocate more than that
+	info, err := input.Stat()
+	if err != nil {
+		return err
+	}
+	stream := rlp.NewStream(bufio.NewReader(input), uint64(info.Size()))
+
+	var (
+		total, corrupt, dropped int
+
+		failure error
+		batch   types.Transactions
+	)
+	loadBatch := func(txs types.Transactions) {
+		for _, err := range add(txs) {
+			if err != nil {
+				log.Debug("Failed to add journaled remote transaction", "err", err)
+				dropped++
+			}
+		}
+	}
+	for {
+		// Read the next entry, the snapshot is unusable past a framing error
+		raw, err := stream.Raw()
+		if err != nil {
+			if err != io.EOF {
+				failure = err
+			}
+			break
+		}
+		tx := new(types.Transaction)
+		if err := rlp.DecodeBytes(raw, tx); err != nil {
+			corrupt++
+			continue
+		}
+		total++
+		if batch = append(batch, tx); batch.Len() > 1024 {
+			loadBatch(batch)
+			batch = batch[:0]
+		}
+	}
+	if batch.Len() > 0 {
+		loadBatch(batch)
+	}
+	log.Info("Loaded remote transaction journal", "transactions", total, "dropped", dropped, "corrupt", corrupt)
+
+	return failure
+}
+
+// save replaces the snapshot on disk with the given executable remote transactions,
+// sorted by nonce per account. If they don't fit into the size limit, accounts
+// paying the highest tip on their next transaction are kept first, and every
+// account keeps a prefix of its transactions, so their nonces stay gapless.
+func (journal *remoteJournal) save(all map[common.Address]types.Transactions) error {
+	addrs := make([]common.Address, 0, len(all))
+	for addr, txs := range all {
+		if len(txs) > 0 {
+			addrs = append(addrs, addr)
+		}
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		if cmp := all[addrs[i]][0].GasTipCapCmp(all[addrs[j]][0]); cmp != 0 {
+			return cmp > 0
+		}
+		return addrs[i].Hex() < addrs[j].Hex()
+	})
+	// Generate the new snapshot aside and replace the old one once complete
+	output, err := os.OpenFile(journal.path+".new", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	var (
+		writer = bufio.NewWriter(output)
+		size   uint64
+		saved  int
+	)
+	for _, addr := range addrs {
+		for _, tx := range all[addr] {
+			blob, err := rlp.EncodeToBytes(tx)
+			if err != nil {
+				output.Close()
+				return err
+			}
+			if size+uint64(len(blob)) > journal.limit {
+				break
+			}
+			if _, err := writer.Write(blob); err != nil {
+				output.Close()
+				return err
+			}
+			size += uint64(len(blob))
+			saved++
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		output.Close()
+		return err
+	}
+	if err := output.Sync(); err != nil {
+		output.Close()
+		return err
+	}
+	output.Close()
+
+	if err := os.Rename(journal.path+".new", journal.path); err != nil {
+		return err
+	}
+	log.Info("Saved remote transaction journal", "transactions", saved, "accounts", len(addrs), "size", common.StorageSize(size))
+	return nil
+}
@@ -0,0 +1,203 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// newRemoteJournalTester creates a pool persisting its remote transactions into
+// the given journal, on top of the given state.
+func newRemoteJournalTester(t *testing.T, statedb *state.StateDB, journal string, limit uint64) *TxPool {
+	config := testTxPoolConfig
+	config.RemoteJournal = journal
+	config.RemoteJournalSize = limit
+
+	return NewTxPool(config, params.TestChainConfig, newTestBlockChain(1000000, statedb, new(event.Feed)))
+}
+
+// Tests that executable remote transactions survive a restart of the pool,
+// revalidated against the state the new pool starts on, and that queued ones
+// are not journaled.
+func TestRemoteJournalRestore(t *testing.T) {
+	t.Parallel()
+
+	journal := filepath.Join(t.TempDir(), "remotes.rlp")
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+
+	keys := make([]*ecdsa.PrivateKey, 3)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		statedb.AddBalance(crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000000))
+	}
+	pool := newRemoteJournalTester(t, statedb, journal, DefaultConfig.RemoteJournalSize)
+
+	// Account 0 has two pending and one queued transaction, account 1 one
+	// pending and account 2 one pending transaction, which is made local
+	txs := []*types.Transaction{
+		pricedTransaction(0, 100000, big.NewInt(1), keys[0]),
+		pricedTransaction(1, 100000, big.NewInt(1), keys[0]),
+		pricedTransaction(3, 100000, big.NewInt(1), keys[0]),
+		pricedTransaction(0, 100000, big.NewInt(1), keys[1]),
+	}
+	for i, err := range pool.AddRemotesSync(txs) {
+		if err != nil {
+			t.Fatalf("failed to add remote transaction %d: %v", i, err)
+		}
+	}
+	if err := pool.AddLocal(pricedTransaction(0, 100000, big.NewInt(1), keys[2])); err != nil {
+		t.Fatalf("failed to add local transaction: %v", err)
+	}
+	pool.Stop()
+
+	// Include the first transaction of account 0 and restart the pool
+	statedb.SetNonce(crypto.PubkeyToAddress(keys[0].PublicKey), 1)
+
+	pool = newRemoteJournalTester(t, statedb, journal, DefaultConfig.RemoteJournalSize)
+	defer pool.Stop()
+
+	pending, queued := pool.Stats()
+	if pending != 2 {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, 2)
+	}
+	if queued != 0 {
+		t.Fatalf("queued transactions mismatched: have %d, want %d", queued, 0)
+	}
+	for i, tx := range txs {
+		if restored, want := pool.Get(tx.Hash()) != nil, i == 1 || i == 3; restored != want {
+			t.Errorf("transaction %d: restored mismatch: have %v, want %v", i, restored, want)
+		}
+	}
+	if locals := pool.local(); len(locals) != 0 {
+		t.Errorf("restored remote transactions marked local: %v", locals)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the remote journal keeps the accounts paying the highest tips when
+// the pool doesn't fit into it, without leaving nonce gaps.
+func TestRemoteJournalLimit(t *testing.T) {
+	t.Parallel()
+
+	journal := filepath.Join(t.TempDir(), "remotes.rlp")
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+
+	cheap, _ := crypto.GenerateKey()
+	costly, _ := crypto.GenerateKey()
+	statedb.AddBalance(crypto.PubkeyToAddress(cheap.PublicKey), big.NewInt(1000000000))
+	statedb.AddBalance(crypto.PubkeyToAddress(costly.PublicKey), big.NewInt(1000000000))
+
+	txs := []*types.Transaction{
+		pricedTransaction(0, 100000, big.NewInt(1), cheap),
+		pricedTransaction(1, 100000, big.NewInt(1), cheap),
+		pricedTransaction(0, 100000, big.NewInt(2), costly),
+		pricedTransaction(1, 100000, big.NewInt(2), costly),
+	}
+	// Make room for three transactions only
+	var limit uint64
+	for _, tx := range txs[:3] {
+		blob, _ := rlp.EncodeToBytes(tx)
+		limit += uint64(len(blob))
+	}
+	pool := newRemoteJournalTester(t, statedb, journal, limit)
+	for i, err := range pool.AddRemotesSync(txs) {
+		if err != nil {
+			t.Fatalf("failed to add remote transaction %d: %v", i, err)
+		}
+	}
+	pool.Stop()
+
+	pool = newRemoteJournalTester(t, statedb, journal, limit)
+	defer pool.Stop()
+
+	for i, tx := range txs {
+		if restored, want := pool.Get(tx.Hash()) != nil, i != 1; restored != want {
+			t.Errorf("transaction %d: restored mismatch: have %v, want %v", i, restored, want)
+		}
+	}
+}
+
+// Tests that a damaged remote journal is recovered up to the damage, and that
+// entries which don't decode are skipped.
+func TestRemoteJournalCorruption(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+
+	key, _ := crypto.GenerateKey()
+	statedb.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+
+	txs := make([]*types.Transaction, 3)
+	blobs := make([][]byte, len(txs))
+	for i := range txs {
+		txs[i] = pricedTransaction(uint64(i), 100000, big.NewInt(1), key)
+		blobs[i], _ = rlp.EncodeToBytes(txs[i])
+	}
+	garbage, _ := rlp.EncodeToBytes([]byte("not a transaction"))
+
+	tests := []struct {
+		name    string
+		content []byte
+		want    int // Number of leading transactions restored
+	}{
+		{"empty", nil, 0},
+		{"intact", concat(blobs[0], blobs[1], blobs[2]), 3},
+		{"truncated", concat(blobs[0], blobs[1], blobs[2][:len(blobs[2])/2]), 2},
+		{"garbled-entry", concat(blobs[0], garbage, blobs[1]), 2},
+		{"garbled-framing", concat(blobs[0], []byte{0xff, 0xff, 0xff, 0xff}, blobs[1]), 1},
+		{"garbage", []byte("definitely not rlp"), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			journal := filepath.Join(t.TempDir(), "remotes.rlp")
+			if err := os.WriteFile(journal, tt.content, 0644); err != nil {
+				t.Fatalf("failed to write journal: %v", err)
+			}
+			pool := newRemoteJournalTester(t, statedb.Copy(), journal, DefaultConfig.RemoteJournalSize)
+			defer pool.Stop()
+
+			for i, tx := range txs {
+				if restored, want := pool.Get(tx.Hash()) != nil, i < tt.want; restored != want {
+					t.Errorf("transaction %d: restored mismatch: have %v, want %v", i, restored, want)
+				}
+			}
+		})
+	}
+}
+
+func concat(blobs ...[]byte) []byte {
+	var out []byte
+	for _, blob := range blobs {
+		out = append(out, blob...)
+	}
+	return out
+}
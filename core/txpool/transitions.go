@@ -0,0 +1,164 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// txTransitionQueueSize is the number of pool updates whose transitions may wait
+// to be posted. Updates beyond it are not posted at all.
+const txTransitionQueueSize = 1024
+
+// transitionLossMeter counts the pool updates whose transitions were lost
+// because subscribers didn't keep up.
+var transitionLossMeter = metrics.NewRegisteredMeter("txpool/transitions/lost", nil)
+
+// TxTransitionType identifies the change a pooled transaction went through.
+type TxTransitionType string
+
+const (
+	TxPromoted TxTransitionType = "promoted" // Moved from the queue into the pending set
+	TxDemoted  TxTransitionType = "demoted"  // Moved from the pending set back into the queue
+	TxDropped  TxTransitionType = "dropped"  // Removed from the pool, the reason tells whether it was included
+)
+
+// TxDropReason is the reason code of a transaction dropped from the pool.
+type TxDropReason string
+
+const (
+	TxDropUnderpriced TxDropReason = "underpriced" // Evicted by better paying transactions or the price threshold
+	TxDropIncluded    TxDropReason = "included"    // Included into the chain the pool was reset to
+	TxDropNonceTooLow TxDropReason = "nonceTooLow" // Nonce used up on chain by another transaction
+	TxDropUnpayable   TxDropReason = "unpayable"   // Balance can't cover the cost, or gas above the block limit
+	TxDropTruncated   TxDropReason = "truncated"   // Exceeded the pending or queued slot limits
+	TxDropExpired     TxDropReason = "expired"     // Queued for longer than the configured lifetime
+	TxDropReplaced    TxDropReason = "replaced"    // Superseded by another transaction with the same nonce
+)
+
+// TxTransition describes a single change of a transaction in the pool.
+type TxTransition struct {
+	Hash  common.Hash
+	From  common.Address
+	Nonce uint64
+
+	Type       TxTransitionType
+	Reason     TxDropReason // Reason of the drop, only set for dropped transactions
+	ReplacedBy common.Hash  // Transaction superseding the dropped one, only set for replacements
+}
+
+// TxTransitionsEvent is posted with the transitions of a pool update, in the
+// order they happened.
+type TxTransitionsEvent struct {
+	Transitions []TxTransition
+}
+
+// noteTransition buffers a transition of the given transaction, to be posted
+// once the pool lock is released.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) noteTransition(tx *types.Transaction, typ TxTransitionType, reason TxDropReason, by common.Hash) {
+	from, _ := types.Sender(pool.signer, tx) // already validated during insertion
+	pool.transitions = append(pool.transitions, TxTransition{
+		Hash:       tx.Hash(),
+		From:       from,
+		Nonce:      tx.Nonce(),
+		Type:       typ,
+		Reason:     reason,
+		ReplacedBy: by,
+	})
+}
+
+// noteMoved buffers the promotion or demotion of the given transaction.
+func (pool *TxPool) noteMoved(tx *types.Transaction, typ TxTransitionType) {
+	pool.noteTransition(tx, typ, "", common.Hash{})
+}
+
+// noteDropped buffers the removal of the given transaction.
+func (pool *TxPool) noteDropped(tx *types.Transaction, reason TxDropReason) {
+	pool.noteTransition(tx, TxDropped, reason, common.Hash{})
+}
+
+// noteIncluded records the given transactions as included by the chain the pool
+// is being reset to, so that their removal is reported as such.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) noteIncluded(txs types.Transactions) {
+	pool.included = make(map[common.Hash]struct{}, len(txs))
+	for _, tx := range txs {
+		pool.included[tx.Hash()] = struct{}{}
+	}
+}
+
+// noteForwarded buffers the removal of the given transaction because its nonce
+// was used up on chain, either by the transaction itself or by another one.
+func (pool *TxPool) noteForwarded(tx *types.Transaction) {
+	if _, ok := pool.included[tx.Hash()]; ok {
+		pool.noteDropped(tx, TxDropIncluded)
+	} else {
+		pool.noteDropped(tx, TxDropNonceTooLow)
+	}
+}
+
+// noteReplaced buffers the removal of the given transaction in favour of the
+// one with the given hash.
+func (pool *TxPool) noteReplaced(tx *types.Transaction, by common.Hash) {
+	pool.noteTransition(tx, TxDropped, TxDropReplaced, by)
+}
+
+// unlockAndPost queues the transitions buffered while the pool lock was held for
+// posting, and releases the lock. Queueing under the lock keeps the transitions
+// in the order they happened, and never blocks: if subscribers are too slow to
+// drain the queue, the transitions are lost instead of stalling the pool.
+func (pool *TxPool) unlockAndPost() {
+	if transitions := pool.transitions; len(transitions) > 0 {
+		select {
+		case pool.transitionCh <- transitions:
+		default:
+			transitionLossMeter.Mark(1)
+			log.Debug("Transaction transitions lost, subscribers too slow", "transitions", len(transitions))
+		}
+		pool.transitions = nil
+	}
+	pool.mu.Unlock()
+}
+
+// transitionLoop posts the queued transitions to the subscribers, outside of the
+// pool lock, until the pool shuts down.
+func (pool *TxPool) transitionLoop() {
+	defer pool.wg.Done()
+
+	for {
+		select {
+		case transitions := <-pool.transitionCh:
+			pool.transitionFeed.Send(TxTransitionsEvent{Transitions: transitions})
+
+		case <-pool.reorgShutdownCh:
+			return
+		}
+	}
+}
+
+// SubscribeTxTransitionsEvent registers a subscription of TxTransitionsEvent
+// and starts sending event to the given channel.
+func (pool *TxPool) SubscribeTxTransitionsEvent(ch chan<- TxTransitionsEvent) event.Subscription {
+	return pool.scope.Track(pool.transitionFeed.Subscribe(ch))
+}
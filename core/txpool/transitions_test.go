@@ -0,0 +1,225 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// collectTransitions gathers the given number of transitions from the channel,
+// failing if they don't arrive in time or if any surplus ones are posted.
+func collectTransitions(t *testing.T, events chan TxTransitionsEvent, count int) []TxTransition {
+	t.Helper()
+
+	var received []TxTransition
+	for len(received) < count {
+		select {
+		case ev := <-events:
+			received = append(received, ev.Transitions...)
+		case <-time.After(time.Second):
+			t.Fatalf("transition #%d not posted", len(received))
+		}
+	}
+	select {
+	case ev := <-events:
+		received = append(received, ev.Transitions...)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if len(received) != count {
+		t.Fatalf("transition count mismatch: have %d, want %d: %v", len(received), count, received)
+	}
+	return received
+}
+
+// Tests that promotions, demotions and drops of pooled transactions are posted
+// with the reason of the drop, in the order they happened.
+func TestTxTransitions(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Stop()
+
+	events := make(chan TxTransitionsEvent, 32)
+	sub := pool.SubscribeTxTransitionsEvent(events)
+	defer sub.Unsubscribe()
+
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, account, big.NewInt(1000000))
+
+	var (
+		tx0  = pricedTransaction(0, 100000, big.NewInt(1), key)
+		tx1  = pricedTransaction(1, 100000, big.NewInt(1), key)
+		tx1b = pricedTransaction(1, 100000, big.NewInt(2), key)
+		tx2  = pricedTransaction(2, 100000, big.NewInt(1), key)
+	)
+	check := func(have TxTransition, want TxTransition) {
+		t.Helper()
+		want.From = account
+		if have != want {
+			t.Errorf("transition mismatch: have %+v, want %+v", have, want)
+		}
+	}
+	// Gapped transactions are promoted once the gap is filled
+	if err := pool.addRemoteSync(tx1); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(tx0); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	transitions := collectTransitions(t, events, 2)
+	check(transitions[0], TxTransition{Hash: tx0.Hash(), Nonce: 0, Type: TxPromoted})
+	check(transitions[1], TxTransition{Hash: tx1.Hash(), Nonce: 1, Type: TxPromoted})
+
+	// Replaced transactions are dropped in favour of the replacement
+	if err := pool.addRemoteSync(tx1b); err != nil {
+		t.Fatalf("failed to replace transaction: %v", err)
+	}
+	transitions = collectTransitions(t, events, 1)
+	check(transitions[0], TxTransition{Hash: tx1.Hash(), Nonce: 1, Type: TxDropped, Reason: TxDropReplaced, ReplacedBy: tx1b.Hash()})
+
+	// Transactions whose nonce got used up are dropped
+	testSetNonce(pool, account, 1)
+	<-pool.requestReset(nil, nil)
+
+	transitions = collectTransitions(t, events, 1)
+	check(transitions[0], TxTransition{Hash: tx0.Hash(), Nonce: 0, Type: TxDropped, Reason: TxDropNonceTooLow})
+
+	// Unpayable transactions are dropped, postponing the subsequent ones
+	if err := pool.addRemoteSync(tx2); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	transitions = collectTransitions(t, events, 1)
+	check(transitions[0], TxTransition{Hash: tx2.Hash(), Nonce: 2, Type: TxPromoted})
+
+	testAddBalance(pool, account, big.NewInt(-850000))
+	<-pool.requestReset(nil, nil)
+
+	transitions = collectTransitions(t, events, 2)
+	check(transitions[0], TxTransition{Hash: tx1b.Hash(), Nonce: 1, Type: TxDropped, Reason: TxDropUnpayable})
+	check(transitions[1], TxTransition{Hash: tx2.Hash(), Nonce: 2, Type: TxDemoted})
+
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// includingBlockChain is a test chain whose blocks all contain the given
+// transactions.
+type includingBlockChain struct {
+	*testBlockChain
+	txs types.Transactions
+}
+
+func (bc *includingBlockChain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	return types.NewBlock(bc.CurrentBlock(), bc.txs, nil, nil, trie.NewStackTrie(nil))
+}
+
+// Tests that transactions included into the new head are posted as such, and
+// distinguished from the ones whose nonce got used up by other transactions.
+func TestTxTransitionsIncluded(t *testing.T) {
+	t.Parallel()
+
+	var (
+		key, _     = crypto.GenerateKey()
+		account    = crypto.PubkeyToAddress(key.PublicKey)
+		statedb, _ = state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+
+		tx0  = pricedTransaction(0, 100000, big.NewInt(1), key)
+		tx1  = pricedTransaction(1, 100000, big.NewInt(1), key)
+		tx1b = pricedTransaction(1, 100000, big.NewInt(2), key)
+	)
+	chain := &includingBlockChain{
+		testBlockChain: newTestBlockChain(10000000, statedb, new(event.Feed)),
+		txs:            types.Transactions{tx0, tx1b},
+	}
+	pool := NewTxPool(testTxPoolConfig, params.TestChainConfig, chain)
+	defer pool.Stop()
+
+	events := make(chan TxTransitionsEvent, 32)
+	sub := pool.SubscribeTxTransitionsEvent(events)
+	defer sub.Unsubscribe()
+
+	testAddBalance(pool, account, big.NewInt(1000000))
+	for _, tx := range []*types.Transaction{tx0, tx1} {
+		if err := pool.addRemoteSync(tx); err != nil {
+			t.Fatalf("failed to add transaction: %v", err)
+		}
+	}
+	collectTransitions(t, events, 2)
+
+	// Include the first transaction and a replacement of the second one
+	testSetNonce(pool, account, 2)
+	parent := &types.Header{Number: big.NewInt(0)}
+	<-pool.requestReset(parent, &types.Header{ParentHash: parent.Hash(), Number: big.NewInt(1), BaseFee: common.Big1})
+
+	transitions := collectTransitions(t, events, 2)
+	for i, want := range []TxTransition{
+		{Hash: tx0.Hash(), From: account, Nonce: 0, Type: TxDropped, Reason: TxDropIncluded},
+		{Hash: tx1.Hash(), From: account, Nonce: 1, Type: TxDropped, Reason: TxDropNonceTooLow},
+	} {
+		if transitions[i] != want {
+			t.Errorf("transition %d mismatch: have %+v, want %+v", i, transitions[i], want)
+		}
+	}
+}
+
+// Tests that transactions evicted by the price threshold or the slot limits are
+// posted as dropped with the matching reason.
+func TestTxTransitionsEviction(t *testing.T) {
+	t.Parallel()
+
+	pool, _ := setupPool()
+	defer pool.Stop()
+
+	events := make(chan TxTransitionsEvent, 32)
+	sub := pool.SubscribeTxTransitionsEvent(events)
+	defer sub.Unsubscribe()
+
+	// Fill the queue beyond the per account limit of a single sender
+	key, _ := crypto.GenerateKey()
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+
+	for i := uint64(1); i <= pool.config.AccountQueue+1; i++ {
+		if err := pool.addRemoteSync(pricedTransaction(i, 100000, big.NewInt(1), key)); err != nil {
+			t.Fatalf("failed to add transaction %d: %v", i, err)
+		}
+	}
+	transitions := collectTransitions(t, events, 1)
+	if have := transitions[0]; have.Type != TxDropped || have.Reason != TxDropTruncated || have.Nonce != pool.config.AccountQueue+1 {
+		t.Errorf("truncation mismatch: have %+v", have)
+	}
+	// Raise the price threshold above the queued transactions
+	pool.SetGasPrice(big.NewInt(2))
+
+	transitions = collectTransitions(t, events, int(pool.config.AccountQueue))
+	for i, have := range transitions {
+		if have.Type != TxDropped || have.Reason != TxDropUnderpriced || have.ReplacedBy != (common.Hash{}) {
+			t.Errorf("transition %d: underpriced drop mismatch: have %+v", i, have)
+		}
+	}
+}
@@ -167,6 +167,10 @@ type Config struct {
 	Journal   string           // Journal of local transactions to survive node restarts
 	Rejournal time.Duration    // Time interval to regenerate the local transaction journal
 
+	RemoteJournal     string        // Journal of remote transactions to survive node restarts (disabled if empty)
+	RemoteJournalSize uint64        // Maximum size of the remote transaction journal, in bytes
+	RemoteRejournal   time.Duration // Time interval to regenerate the remote transaction journal
+
 	PriceLimit uint64 // Minimum gas price to enforce for acceptance into the pool
 	PriceBump  uint64 // Minimum price bump percentage to replace an already existing transaction (nonce)
 
@@ -176,6 +180,8 @@ type Config struct {
 	GlobalQueue  uint64 // Maximum number of non-executable transaction slots for all accounts
 
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
+
+	Policy Policy // Admission policy of the operator, replaceable at runtime
 }
 
 // DefaultConfig contains the default configurations for the transaction
@@ -184,6 +190,9 @@ var DefaultConfig = Config{
 	Journal:   "transactions.rlp",
 	Rejournal: time.Hour,
 
+	RemoteJournalSize: 32 * 1024 * 1024,
+	RemoteRejournal:   5 * time.Minute,
+
 	PriceLimit: 1,
 	PriceBump:  10,
 
@@ -203,6 +212,14 @@ func (config *Config) sanitize() Config {
 		log.Warn("Sanitizing invalid txpool journal time", "provided", conf.Rejournal, "updated", time.Second)
 		conf.Rejournal = time.Second
 	}
+	if conf.RemoteJournal != "" && conf.RemoteJournalSize < 1 {
+		log.Warn("Sanitizing invalid txpool remote journal size", "provided", conf.RemoteJournalSize, "updated", DefaultConfig.RemoteJournalSize)
+		conf.RemoteJournalSize = DefaultConfig.RemoteJournalSize
+	}
+	if conf.RemoteJournal != "" && conf.RemoteRejournal < time.Second {
+		log.Warn("Sanitizing invalid txpool remote journal time", "provided", conf.RemoteRejournal, "updated", time.Second)
+		conf.RemoteRejournal = time.Second
+	}
 	if conf.PriceLimit < 1 {
 		log.Warn("Sanitizing invalid txpool price limit", "provided", conf.PriceLimit, "updated", DefaultConfig.PriceLimit)
 		conf.PriceLimit = DefaultConfig.PriceLimit
@@ -231,6 +248,10 @@ func (config *Config) sanitize() Config {
 		log.Warn("Sanitizing invalid txpool lifetime", "provided", conf.Lifetime, "updated", DefaultConfig.Lifetime)
 		conf.Lifetime = DefaultConfig.Lifetime
 	}
+	if _, err := newAdmission(conf.Policy); err != nil {
+		log.Warn("Sanitizing invalid txpool policy", "err", err)
+		conf.Policy = Policy{}
+	}
 	return conf
 }
 
@@ -251,6 +272,11 @@ type TxPool struct {
 	signer      types.Signer
 	mu          sync.RWMutex
 
+	transitionFeed event.Feed               // Feed of dropped, promoted and demoted transactions
+	transitions    []TxTransition           // Transitions buffered under the pool lock, queued on release
+	transitionCh   chan []TxTransition      // Queue of transitions waiting to be posted
+	included       map[common.Hash]struct{} // Transactions included by the chain the pool is being reset to
+
 	istanbul atomic.Bool // Fork indicator whether we are in the istanbul stage.
 	eip2718  atomic.Bool // Fork indicator whether we are using EIP-2718 type transactions.
 	eip1559  atomic.Bool // Fork indicator whether we are using EIP-1559 type transactions.
@@ -260,9 +286,13 @@ type TxPool struct {
 	pendingNonces *noncer        // Pending state tracking virtual nonces
 	currentMaxGas atomic.Uint64  // Current gas limit for transaction caps
 
+	admission atomic.Pointer[admission] // Admission policy checked on top of the validation rules
+
 	locals  *accountSet // Set of local transaction to exempt from eviction rules
 	journal *journal    // Journal of local transaction to back up to disk
 
+	remoteJournal *remoteJournal // Snapshot of remote transactions to back up to disk
+
 	pending map[common.Address]*list     // All currently processable transactions
 	queue   map[common.Address]*list     // Queued but non-processable transactions
 	beats   map[common.Address]time.Time // Last heartbeat from each known account
@@ -308,6 +338,7 @@ func NewTxPool(config Config, chainconfig *params.ChainConfig, chain blockChain)
 		queueTxEventCh:  make(chan *types.Transaction),
 		reorgDoneCh:     make(chan chan struct{}),
 		reorgShutdownCh: make(chan struct{}),
+		transitionCh:    make(chan []TxTransition, txTransitionQueueSize),
 		initDoneCh:      make(chan struct{}),
 		gasPrice:        new(big.Int).SetUint64(config.PriceLimit),
 	}
@@ -319,9 +350,14 @@ func NewTxPool(config Config, chainconfig *params.ChainConfig, chain blockChain)
 	pool.priced = newPricedList(pool.all)
 	pool.reset(nil, chain.CurrentBlock())
 
-	// Start the reorg loop early so it can handle requests generated during journal loading.
-	pool.wg.Add(1)
+	admission, _ := newAdmission(config.Policy) // Sanitized already
+	pool.admission.Store(admission)
+
+	// Start the reorg and transition posting loops early so they can handle
+	// requests and transitions generated during journal loading.
+	pool.wg.Add(2)
 	go pool.scheduleReorgLoop()
+	go pool.transitionLoop()
 
 	// If local transactions and journaling is enabled, load from disk
 	if !config.NoLocals && config.Journal != "" {
@@ -334,6 +370,14 @@ func NewTxPool(config Config, chainconfig *params.ChainConfig, chain blockChain)
 			log.Warn("Failed to rotate transaction journal", "err", err)
 		}
 	}
+	// If remote journaling is enabled, restore the remote transactions too
+	if config.RemoteJournal != "" {
+		pool.remoteJournal = newRemoteJournal(config.RemoteJournal, config.RemoteJournalSize)
+
+		if err := pool.remoteJournal.load(pool.AddRemotesSync); err != nil {
+			log.Warn("Failed to load remote transaction journal", "err", err)
+		}
+	}
 
 	// Subscribe events from blockchain and start the main event loop.
 	pool.chainHeadSub = pool.chain.SubscribeChainHeadEvent(pool.chainHeadCh)
@@ -355,6 +399,8 @@ func (pool *TxPool) loop() {
 		report  = time.NewTicker(statsReportInterval)
 		evict   = time.NewTicker(evictionInterval)
 		journal = time.NewTicker(pool.config.Rejournal)
+		// Snapshot remote transactions on a separate, shorter interval
+		remoteRejournal <-chan time.Time // Remote snapshot ticks, nil if the remote journal is disabled
 		// Track the previous head headers for transaction reorgs
 		head = pool.chain.CurrentBlock()
 	)
@@ -362,6 +408,12 @@ func (pool *TxPool) loop() {
 	defer evict.Stop()
 	defer journal.Stop()
 
+	if pool.remoteJournal != nil {
+		ticker := time.NewTicker(pool.config.RemoteRejournal)
+		defer ticker.Stop()
+		remoteRejournal = ticker.C
+	}
+
 	// Notify tests that the init phase is done
 	close(pool.initDoneCh)
 	for {
@@ -402,12 +454,12 @@ func (pool *TxPool) loop() {
 				if time.Since(pool.beats[addr]) > pool.config.Lifetime {
 					list := pool.queue[addr].Flatten()
 					for _, tx := range list {
-						pool.removeTx(tx.Hash(), true)
+						pool.removeTx(tx.Hash(), true, TxDropExpired)
 					}
 					queuedEvictionMeter.Mark(int64(len(list)))
 				}
 			}
-			pool.mu.Unlock()
+			pool.unlockAndPost()
 
 		// Handle local transaction journal rotation
 		case <-journal.C:
@@ -418,6 +470,10 @@ func (pool *TxPool) loop() {
 				}
 				pool.mu.Unlock()
 			}
+
+		// Handle remote transaction snapshots
+		case <-remoteRejournal:
+			pool.saveRemotes()
 		}
 	}
 }
@@ -434,6 +490,9 @@ func (pool *TxPool) Stop() {
 	if pool.journal != nil {
 		pool.journal.close()
 	}
+	if pool.remoteJournal != nil {
+		pool.saveRemotes()
+	}
 	log.Info("Transaction pool stopped")
 }
 
@@ -455,7 +514,7 @@ func (pool *TxPool) GasPrice() *big.Int {
 // new transaction, and drops all transactions below this threshold.
 func (pool *TxPool) SetGasPrice(price *big.Int) {
 	pool.mu.Lock()
-	defer pool.mu.Unlock()
+	defer pool.unlockAndPost()
 
 	old := pool.gasPrice
 	pool.gasPrice = price
@@ -464,7 +523,7 @@ func (pool *TxPool) SetGasPrice(price *big.Int) {
 		// pool.priced is sorted by GasFeeCap, so we have to iterate through pool.all instead
 		drop := pool.all.RemotesBelowTip(price)
 		for _, tx := range drop {
-			pool.removeTx(tx.Hash(), false)
+			pool.removeTx(tx.Hash(), false, TxDropUnderpriced)
 		}
 		pool.priced.Removed(len(drop))
 	}
@@ -593,6 +652,32 @@ func (pool *TxPool) local() map[common.Address]types.Transactions {
 	return txs
 }
 
+// remotePending retrieves all currently executable remote transactions, grouped
+// by origin account and sorted by nonce. The returned transaction set is a copy
+// and can be freely modified by calling code.
+func (pool *TxPool) remotePending() map[common.Address]types.Transactions {
+	txs := make(map[common.Address]types.Transactions)
+	for addr, pending := range pool.pending {
+		if !pool.locals.contains(addr) {
+			txs[addr] = pending.Flatten()
+		}
+	}
+	return txs
+}
+
+// saveRemotes writes the current executable remote transactions into the remote
+// journal. Queued transactions are left out, they would mostly be dropped or
+// stay stuck after a restart anyway.
+func (pool *TxPool) saveRemotes() {
+	pool.mu.RLock()
+	remotes := pool.remotePending()
+	pool.mu.RUnlock()
+
+	if err := pool.remoteJournal.save(remotes); err != nil {
+		log.Warn("Failed to save remote transaction journal", "err", err)
+	}
+}
+
 // validateTxBasics checks whether a transaction is valid according to the consensus
 // rules, but does not check state-dependent validation such as sufficient balance.
 // This check is meant as an early check which only needs to be performed once,
@@ -658,6 +743,11 @@ func (pool *TxPool) validateTxBasics(tx *types.Transaction, local bool) error {
 func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	// Signature has been checked already, this cannot error.
 	from, _ := types.Sender(pool.signer, tx)
+	// Ensure the transaction is admitted by the policy of the operator
+	creations := func() uint64 { return pool.creations(from, tx.Nonce()) }
+	if err := pool.admission.Load().validate(tx, from, local, creations); err != nil {
+		return err
+	}
 	// Ensure the transaction adheres to nonce ordering
 	if pool.currentState.GetNonce(from) > tx.Nonce() {
 		return core.ErrNonceTooLow
@@ -685,6 +775,40 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	return nil
 }
 
+// creations returns the number of contract creations pooled from the given
+// sender, not counting the one the given nonce a new transaction may replace.
+func (pool *TxPool) creations(from common.Address, nonce uint64) uint64 {
+	var count uint64
+	for _, txs := range []*list{pool.pending[from], pool.queue[from]} {
+		if txs == nil {
+			continue
+		}
+		for _, tx := range txs.Flatten() {
+			if tx.To() == nil && tx.Nonce() != nonce {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// Policy returns the admission policy transactions are checked against.
+func (pool *TxPool) Policy() Policy {
+	return pool.admission.Load().policy
+}
+
+// SetPolicy replaces the admission policy transactions are checked against.
+// Transactions already in the pool are not affected.
+func (pool *TxPool) SetPolicy(policy Policy) error {
+	admission, err := newAdmission(policy)
+	if err != nil {
+		return err
+	}
+	pool.admission.Store(admission)
+	log.Info("Updated transaction pool policy", "deny", len(policy.Deny), "classes", len(policy.Classes))
+	return nil
+}
+
 // add validates a transaction and inserts it into the non-executable queue for later
 // pending promotion and execution. If the transaction is a replacement for an already
 // pending or queued one, it overwrites the previous transaction if its price is higher.
@@ -768,7 +892,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		for _, tx := range drop {
 			log.Trace("Discarding freshly underpriced transaction", "hash", tx.Hash(), "gasTipCap", tx.GasTipCap(), "gasFeeCap", tx.GasFeeCap())
 			underpricedTxMeter.Mark(1)
-			dropped := pool.removeTx(tx.Hash(), false)
+			dropped := pool.removeTx(tx.Hash(), false, TxDropUnderpriced)
 			pool.changesSinceReorg += dropped
 		}
 	}
@@ -786,6 +910,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 			pool.all.Remove(old.Hash())
 			pool.priced.Removed(1)
 			pendingReplaceMeter.Mark(1)
+			pool.noteReplaced(old, hash)
 		}
 		pool.all.Add(tx, isLocal)
 		pool.priced.Put(tx, isLocal)
@@ -859,6 +984,7 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction, local boo
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
 		queuedReplaceMeter.Mark(1)
+		pool.noteReplaced(old, hash)
 	} else {
 		// Nothing was replaced, bump the queued counter
 		queuedGauge.Inc(1)
@@ -908,6 +1034,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 		pool.all.Remove(hash)
 		pool.priced.Removed(1)
 		pendingDiscardMeter.Mark(1)
+		pool.noteReplaced(tx, list.txs.Get(tx.Nonce()).Hash())
 		return false
 	}
 	// Otherwise discard any previous transaction and mark this
@@ -915,6 +1042,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
 		pendingReplaceMeter.Mark(1)
+		pool.noteReplaced(old, hash)
 	} else {
 		// Nothing was replaced, bump the pending counter
 		pendingGauge.Inc(1)
@@ -952,6 +1080,29 @@ func (pool *TxPool) AddRemotes(txs []*types.Transaction) []error {
 	return pool.addTxs(txs, false, false)
 }
 
+// AddRemotesFrom is like AddRemotes, but for transactions received from the given
+// peer, which are subject to the per-peer rate limit of the admission policy.
+func (pool *TxPool) AddRemotesFrom(peer string, txs []*types.Transaction) []error {
+	var (
+		admission = pool.admission.Load()
+		errs      = make([]error, len(txs))
+		allowed   = make([]*types.Transaction, 0, len(txs))
+		indices   = make([]int, 0, len(txs))
+	)
+	for i, tx := range txs {
+		if !admission.allow(peer) {
+			errs[i] = ErrPolicyRateLimit
+			continue
+		}
+		allowed = append(allowed, tx)
+		indices = append(indices, i)
+	}
+	for i, err := range pool.AddRemotes(allowed) {
+		errs[indices[i]] = err
+	}
+	return errs
+}
+
 // AddRemotesSync is like AddRemotes, but waits for pool reorganization. Tests use this method.
 func (pool *TxPool) AddRemotesSync(txs []*types.Transaction) []error {
 	return pool.addTxs(txs, false, true)
@@ -1005,7 +1156,7 @@ func (pool *TxPool) addTxs(txs []*types.Transaction, local, sync bool) []error {
 	// Process all the new transaction and merge any errors into the original slice
 	pool.mu.Lock()
 	newErrs, dirtyAddrs := pool.addTxsLocked(news, local)
-	pool.mu.Unlock()
+	pool.unlockAndPost()
 
 	var nilSlot = 0
 	for _, err := range newErrs {
@@ -1073,10 +1224,10 @@ func (pool *TxPool) Has(hash common.Hash) bool {
 	return pool.all.Get(hash) != nil
 }
 
-// removeTx removes a single transaction from the queue, moving all subsequent
-// transactions back to the future queue.
+// removeTx removes a single transaction from the queue for the given reason,
+// moving all subsequent transactions back to the future queue.
 // Returns the number of transactions removed from the pending queue.
-func (pool *TxPool) removeTx(hash common.Hash, outofbound bool) int {
+func (pool *TxPool) removeTx(hash common.Hash, outofbound bool, reason TxDropReason) int {
 	// Fetch the transaction we wish to delete
 	tx := pool.all.Get(hash)
 	if tx == nil {
@@ -1086,6 +1237,7 @@ func (pool *TxPool) removeTx(hash common.Hash, outofbound bool) int {
 
 	// Remove it from the list of known transactions
 	pool.all.Remove(hash)
+	pool.noteDropped(tx, reason)
 	if outofbound {
 		pool.priced.Removed(1)
 	}
@@ -1103,6 +1255,7 @@ func (pool *TxPool) removeTx(hash common.Hash, outofbound bool) int {
 			for _, tx := range invalids {
 				// Internal shuffle shouldn't touch the lookup set.
 				pool.enqueueTx(tx.Hash(), tx, false, false)
+				pool.noteMoved(tx, TxDemoted)
 			}
 			// Update the account nonce if needed
 			pool.pendingNonces.setIfLower(addr, tx.Nonce())
@@ -1267,6 +1420,7 @@ func (pool *TxPool) runReorg(done chan struct{}, reset *txpoolResetRequest, dirt
 	// because of another transaction (e.g. higher gas price).
 	if reset != nil {
 		pool.demoteUnexecutables()
+		pool.included = nil
 		if reset.newHead != nil && pool.chainconfig.IsLondon(new(big.Int).Add(reset.newHead.Number, big.NewInt(1))) {
 			pendingBaseFee := misc.CalcBaseFee(pool.chainconfig, reset.newHead)
 			pool.priced.SetBaseFee(pendingBaseFee)
@@ -1285,7 +1439,7 @@ func (pool *TxPool) runReorg(done chan struct{}, reset *txpoolResetRequest, dirt
 
 	dropBetweenReorgHistogram.Update(int64(pool.changesSinceReorg))
 	pool.changesSinceReorg = 0 // Reset change counter
-	pool.mu.Unlock()
+	pool.unlockAndPost()
 
 	// Notify subsystems for newly added transactions
 	for _, tx := range promoted {
@@ -1367,8 +1521,14 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 					}
 				}
 				reinject = types.TxDifference(discarded, included)
+				pool.noteIncluded(included)
 			}
 		}
+	} else if oldHead != nil {
+		// The new head extends the old one, only its own transactions got included
+		if block := pool.chain.GetBlock(newHead.Hash(), newHead.Number.Uint64()); block != nil {
+			pool.noteIncluded(block.Transactions())
+		}
 	}
 	// Initialize the internal state to the current head
 	if newHead == nil {
@@ -1414,6 +1574,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 		for _, tx := range forwards {
 			hash := tx.Hash()
 			pool.all.Remove(hash)
+			pool.noteForwarded(tx)
 		}
 		log.Trace("Removed old queued transactions", "count", len(forwards))
 		// Drop all transactions that are too costly (low balance or out of gas)
@@ -1421,6 +1582,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 		for _, tx := range drops {
 			hash := tx.Hash()
 			pool.all.Remove(hash)
+			pool.noteDropped(tx, TxDropUnpayable)
 		}
 		log.Trace("Removed unpayable queued transactions", "count", len(drops))
 		queuedNofundsMeter.Mark(int64(len(drops)))
@@ -1431,6 +1593,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 			hash := tx.Hash()
 			if pool.promoteTx(addr, hash, tx) {
 				promoted = append(promoted, tx)
+				pool.noteMoved(tx, TxPromoted)
 			}
 		}
 		log.Trace("Promoted queued transactions", "count", len(promoted))
@@ -1443,6 +1606,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 			for _, tx := range caps {
 				hash := tx.Hash()
 				pool.all.Remove(hash)
+				pool.noteDropped(tx, TxDropTruncated)
 				log.Trace("Removed cap-exceeding queued transaction", "hash", hash)
 			}
 			queuedRateLimitMeter.Mark(int64(len(caps)))
@@ -1505,6 +1669,7 @@ func (pool *TxPool) truncatePending() {
 						// Drop the transaction from the global pools too
 						hash := tx.Hash()
 						pool.all.Remove(hash)
+						pool.noteDropped(tx, TxDropTruncated)
 
 						// Update the account nonce to the dropped transaction
 						pool.pendingNonces.setIfLower(offenders[i], tx.Nonce())
@@ -1532,6 +1697,7 @@ func (pool *TxPool) truncatePending() {
 					// Drop the transaction from the global pools too
 					hash := tx.Hash()
 					pool.all.Remove(hash)
+					pool.noteDropped(tx, TxDropTruncated)
 
 					// Update the account nonce to the dropped transaction
 					pool.pendingNonces.setIfLower(addr, tx.Nonce())
@@ -1578,7 +1744,7 @@ func (pool *TxPool) truncateQueue() {
 		// Drop all transactions if they are less than the overflow
 		if size := uint64(list.Len()); size <= drop {
 			for _, tx := range list.Flatten() {
-				pool.removeTx(tx.Hash(), true)
+				pool.removeTx(tx.Hash(), true, TxDropTruncated)
 			}
 			drop -= size
 			queuedRateLimitMeter.Mark(int64(size))
@@ -1587,7 +1753,7 @@ func (pool *TxPool) truncateQueue() {
 		// Otherwise drop only last few transactions
 		txs := list.Flatten()
 		for i := len(txs) - 1; i >= 0 && drop > 0; i-- {
-			pool.removeTx(txs[i].Hash(), true)
+			pool.removeTx(txs[i].Hash(), true, TxDropTruncated)
 			drop--
 			queuedRateLimitMeter.Mark(1)
 		}
@@ -1611,6 +1777,7 @@ func (pool *TxPool) demoteUnexecutables() {
 		for _, tx := range olds {
 			hash := tx.Hash()
 			pool.all.Remove(hash)
+			pool.noteForwarded(tx)
 			log.Trace("Removed old pending transaction", "hash", hash)
 		}
 		// Drop all transactions that are too costly (low balance or out of gas), and queue any invalids back for later
@@ -1619,6 +1786,7 @@ func (pool *TxPool) demoteUnexecutables() {
 			hash := tx.Hash()
 			log.Trace("Removed unpayable pending transaction", "hash", hash)
 			pool.all.Remove(hash)
+			pool.noteDropped(tx, TxDropUnpayable)
 		}
 		pendingNofundsMeter.Mark(int64(len(drops)))
 
@@ -1628,6 +1796,7 @@ func (pool *TxPool) demoteUnexecutables() {
 
 			// Internal shuffle shouldn't touch the lookup set.
 			pool.enqueueTx(hash, tx, false, false)
+			pool.noteMoved(tx, TxDemoted)
 		}
 		pendingGauge.Dec(int64(len(olds) + len(drops) + len(invalids)))
 		if pool.locals.contains(addr) {
@@ -1642,6 +1811,7 @@ func (pool *TxPool) demoteUnexecutables() {
 
 				// Internal shuffle shouldn't touch the lookup set.
 				pool.enqueueTx(hash, tx, false, false)
+				pool.noteMoved(tx, TxDemoted)
 			}
 			pendingGauge.Dec(int64(len(gapped)))
 		}
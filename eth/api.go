@@ -33,6 +33,7 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/log"
@@ -71,6 +72,95 @@ func (api *EthereumAPI) Mining() bool {
 	return api.e.IsMining()
 }
 
+// GetSupply returns the total coin supply after the given block: the genesis
+// allocations plus the rewards issued since, minus the burned base fees.
+func (api *EthereumAPI) GetSupply(ctx context.Context, blockNr rpc.BlockNumber) (*hexutil.Big, error) {
+	if blockNr == rpc.PendingBlockNumber {
+		return nil, errors.New("supply of the pending block is unknown")
+	}
+	header, err := api.e.APIBackend.HeaderByNumber(ctx, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, fmt.Errorf("block #%d not found", blockNr)
+	}
+	supply, err := core.SupplyAt(api.e.chainDb, api.e.blockchain.Config(), api.e.blockReward, api.e.supplyIndexer, header.Number.Uint64())
+	if err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(supply), nil
+}
+
+// LibertyAPI provides an API to the finality checkpoints protecting the chain
+// against deep reorgs.
+type LibertyAPI struct {
+	e *Ethereum
+}
+
+// NewLibertyAPI creates a new LibertyAPI instance.
+func NewLibertyAPI(e *Ethereum) *LibertyAPI {
+	return &LibertyAPI{e}
+}
+
+// FinalityCheckpoint is a block declared final by the checkpoint signers.
+type FinalityCheckpoint struct {
+	Number     hexutil.Uint64  `json:"number"`
+	Hash       common.Hash     `json:"hash"`
+	Signatures []hexutil.Bytes `json:"signatures"`
+}
+
+// GetFinalityCheckpoint returns the latest finality checkpoint, nil if there is
+// none. Checkpoints derived from the hardcoded trusted checkpoint are unsigned.
+func (api *LibertyAPI) GetFinalityCheckpoint() *FinalityCheckpoint {
+	checkpoint := api.e.blockchain.FinalityCheckpoint()
+	if checkpoint == nil {
+		return nil
+	}
+	res := &FinalityCheckpoint{
+		Number:     hexutil.Uint64(checkpoint.Number),
+		Hash:       checkpoint.Hash,
+		Signatures: make([]hexutil.Bytes, 0, len(checkpoint.Signatures)),
+	}
+	for _, sig := range checkpoint.Signatures {
+		res.Signatures = append(res.Signatures, sig)
+	}
+	return res
+}
+
+// SubmitFinalityCheckpoint submits a checkpoint signed by the checkpoint oracle
+// signers. Once accepted, the node refuses to switch to any chain without the
+// block of the checkpoint. This is the only way a signed checkpoint reaches the
+// node, they are not propagated over the network nor read from the oracle. The
+// node only accepts them with finality enabled and the oracle signers configured.
+func (api *LibertyAPI) SubmitFinalityCheckpoint(checkpoint FinalityCheckpoint) error {
+	sigs := make([][]byte, 0, len(checkpoint.Signatures))
+	for _, sig := range checkpoint.Signatures {
+		sigs = append(sigs, sig)
+	}
+	return api.e.blockchain.AddFinalityCheckpoint(&core.FinalityCheckpoint{
+		Number:     uint64(checkpoint.Number),
+		Hash:       checkpoint.Hash,
+		Signatures: sigs,
+	})
+}
+
+// TxPoolPolicyAPI provides read access to the admission policy of the transaction
+// pool. Replacing the policy is an admin operation, see AdminAPI.SetTxPoolPolicy.
+type TxPoolPolicyAPI struct {
+	e *Ethereum
+}
+
+// NewTxPoolPolicyAPI creates a new TxPoolPolicyAPI instance.
+func NewTxPoolPolicyAPI(e *Ethereum) *TxPoolPolicyAPI {
+	return &TxPoolPolicyAPI{e}
+}
+
+// Policy returns the admission policy of the transaction pool.
+func (api *TxPoolPolicyAPI) Policy() txpool.Policy {
+	return api.e.txPool.Policy()
+}
+
 // MinerAPI provides an API to control the miner.
 type MinerAPI struct {
 	e *Ethereum
@@ -243,6 +333,12 @@ func (api *AdminAPI) ImportChain(file string) (bool, error) {
 	return true, nil
 }
 
+// SetTxPoolPolicy replaces the admission policy of the transaction pool. Pooled
+// transactions are not affected, and the change doesn't persist across restarts.
+func (api *AdminAPI) SetTxPoolPolicy(policy txpool.Policy) error {
+	return api.eth.txPool.SetPolicy(policy)
+}
+
 // DebugAPI is the collection of Ethereum full node APIs for debugging the
 // protocol.
 type DebugAPI struct {
@@ -0,0 +1,287 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// BundleAPI provides an API to submit and simulate transaction bundles, groups
+// of transactions included atomically, in order, ahead of the regular ones.
+// The argument and result formats follow the Flashbots bundle API.
+type BundleAPI struct {
+	e *Ethereum
+}
+
+// NewBundleAPI creates a new BundleAPI instance.
+func NewBundleAPI(e *Ethereum) *BundleAPI {
+	return &BundleAPI{e}
+}
+
+// SendBundleArgs are the arguments of eth_sendBundle.
+type SendBundleArgs struct {
+	Txs               []hexutil.Bytes `json:"txs"`
+	BlockNumber       rpc.BlockNumber `json:"blockNumber"`
+	MinTimestamp      *uint64         `json:"minTimestamp"`
+	MaxTimestamp      *uint64         `json:"maxTimestamp"`
+	RevertingTxHashes []common.Hash   `json:"revertingTxHashes"`
+}
+
+// SendBundleResult is the result of eth_sendBundle.
+type SendBundleResult struct {
+	BundleHash common.Hash `json:"bundleHash"`
+}
+
+// SendBundle adds a bundle of signed transactions to the bundle pool, to be
+// included into the block of the given number.
+func (api *BundleAPI) SendBundle(ctx context.Context, args SendBundleArgs) (*SendBundleResult, error) {
+	if args.BlockNumber <= 0 {
+		return nil, errors.New("bundle target block number missing")
+	}
+	txs, err := decodeBundleTxs(args.Txs)
+	if err != nil {
+		return nil, err
+	}
+	bundle := &txpool.Bundle{
+		Txs:               txs,
+		BlockNumber:       uint64(args.BlockNumber),
+		RevertingTxHashes: args.RevertingTxHashes,
+	}
+	if args.MinTimestamp != nil {
+		bundle.MinTimestamp = *args.MinTimestamp
+	}
+	if args.MaxTimestamp != nil {
+		bundle.MaxTimestamp = *args.MaxTimestamp
+	}
+	hash, err := api.e.bundlePool.Add(bundle)
+	if err != nil {
+		return nil, err
+	}
+	return &SendBundleResult{BundleHash: hash}, nil
+}
+
+// CallBundleArgs are the arguments of eth_callBundle.
+type CallBundleArgs struct {
+	Txs              []hexutil.Bytes       `json:"txs"`
+	BlockNumber      rpc.BlockNumber       `json:"blockNumber"`
+	StateBlockNumber rpc.BlockNumberOrHash `json:"stateBlockNumber"`
+	Coinbase         *common.Address       `json:"coinbase"`
+	Timestamp        *uint64               `json:"timestamp"`
+	GasLimit         *uint64               `json:"gasLimit"`
+}
+
+// CallBundleTxResult is the outcome of a single transaction of a simulated bundle.
+type CallBundleTxResult struct {
+	TxHash       common.Hash     `json:"txHash"`
+	FromAddress  common.Address  `json:"fromAddress"`
+	ToAddress    *common.Address `json:"toAddress"`
+	GasUsed      hexutil.Uint64  `json:"gasUsed"`
+	GasPrice     *hexutil.Big    `json:"gasPrice"`
+	GasFees      *hexutil.Big    `json:"gasFees"`
+	CoinbaseDiff *hexutil.Big    `json:"coinbaseDiff"`
+	Value        hexutil.Bytes   `json:"value,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	Revert       hexutil.Bytes   `json:"revert,omitempty"`
+}
+
+// CallBundleResult is the result of eth_callBundle.
+type CallBundleResult struct {
+	BundleHash       common.Hash           `json:"bundleHash"`
+	BundleGasPrice   *hexutil.Big          `json:"bundleGasPrice"`
+	CoinbaseDiff     *hexutil.Big          `json:"coinbaseDiff"`
+	GasFees          *hexutil.Big          `json:"gasFees"`
+	TotalGasUsed     hexutil.Uint64        `json:"totalGasUsed"`
+	StateBlockNumber hexutil.Uint64        `json:"stateBlockNumber"`
+	Results          []*CallBundleTxResult `json:"results"`
+}
+
+// CallBundle simulates a bundle of signed transactions on top of the state of
+// the given block, as if they were included into the next one, without adding
+// it to the bundle pool. Reverting transactions are reported, failing ones
+// abort the simulation. The gas limit of the simulated block is capped by the
+// RPC gas cap and the simulation by the RPC EVM timeout, as for eth_call.
+func (api *BundleAPI) CallBundle(ctx context.Context, args CallBundleArgs) (*CallBundleResult, error) {
+	defer func(start time.Time) { log.Debug("Executing bundle call finished", "runtime", time.Since(start)) }(time.Now())
+
+	txs, err := decodeBundleTxs(args.Txs)
+	if err != nil {
+		return nil, err
+	}
+	stateBlock := args.StateBlockNumber
+	if stateBlock.BlockNumber == nil && stateBlock.BlockHash == nil {
+		stateBlock = rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	}
+	statedb, parent, err := api.e.APIBackend.StateAndHeaderByNumberOrHash(ctx, stateBlock)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	var (
+		chain    = api.e.blockchain
+		config   = chain.Config()
+		coinbase common.Address
+	)
+	if args.Coinbase != nil {
+		coinbase = *args.Coinbase
+	} else if etherbase, err := api.e.Etherbase(); err == nil {
+		coinbase = etherbase
+	}
+	// Assemble the header of the block the bundle is simulated in
+	timestamp := uint64(time.Now().Unix())
+	if args.Timestamp != nil {
+		timestamp = *args.Timestamp
+	} else if parent.Time >= timestamp {
+		timestamp = parent.Time + 1
+	}
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number, common.Big1),
+		GasLimit:   parent.GasLimit,
+		Time:       timestamp,
+		Difficulty: chain.Engine().CalcDifficulty(chain, timestamp, parent),
+		Coinbase:   coinbase,
+	}
+	if args.BlockNumber > 0 {
+		header.Number = big.NewInt(int64(args.BlockNumber))
+	}
+	if args.GasLimit != nil {
+		header.GasLimit = *args.GasLimit
+	}
+	if gasCap := api.e.APIBackend.RPCGasCap(); gasCap != 0 && header.GasLimit > gasCap {
+		log.Warn("Caller gas above allowance, capping", "requested", header.GasLimit, "cap", gasCap)
+		header.GasLimit = gasCap
+	}
+	if config.IsLondon(header.Number) {
+		header.BaseFee = misc.CalcBaseFee(config, parent)
+	}
+	// Setup context so it may be cancelled when the call has completed
+	// or, in case of unmetered gas, setup a context with a timeout.
+	var (
+		cancel  context.CancelFunc
+		timeout = api.e.APIBackend.RPCEVMTimeout()
+	)
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	// Execute the transactions one by one in the same EVM, collecting their
+	// outcomes. Cancel the EVM as soon as the context is done.
+	var (
+		signer = types.MakeSigner(config, header.Number)
+		gp     = new(core.GasPool).AddGas(header.GasLimit)
+		evm    = vm.NewEVM(core.NewEVMBlockContext(header, chain, &coinbase), vm.TxContext{}, statedb, config, vm.Config{})
+
+		balance = statedb.GetBalance(coinbase)
+		gasFees = new(big.Int)
+		gasUsed uint64
+		results = make([]*CallBundleTxResult, 0, len(txs))
+	)
+	go func() {
+		<-ctx.Done()
+		evm.Cancel()
+	}()
+	for i, tx := range txs {
+		msg, err := core.TransactionToMessage(tx, signer, header.BaseFee)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %x invalid: %w", tx.Hash(), err)
+		}
+		statedb.SetTxContext(tx.Hash(), i)
+
+		txBalance := statedb.GetBalance(coinbase)
+		evm.Reset(core.NewEVMTxContext(msg), statedb)
+		result, err := core.ApplyMessage(evm, msg, gp)
+		if evm.Cancelled() {
+			return nil, fmt.Errorf("execution aborted (timeout = %v)", timeout)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("transaction %x failed: %w", tx.Hash(), err)
+		}
+		statedb.Finalise(config.IsEIP158(header.Number))
+
+		tip := tx.EffectiveGasTipValue(header.BaseFee)
+		fees := new(big.Int).Mul(tip, new(big.Int).SetUint64(result.UsedGas))
+		gasFees.Add(gasFees, fees)
+		gasUsed += result.UsedGas
+
+		res := &CallBundleTxResult{
+			TxHash:       tx.Hash(),
+			FromAddress:  msg.From,
+			ToAddress:    tx.To(),
+			GasUsed:      hexutil.Uint64(result.UsedGas),
+			GasPrice:     (*hexutil.Big)(tip),
+			GasFees:      (*hexutil.Big)(fees),
+			CoinbaseDiff: (*hexutil.Big)(new(big.Int).Sub(statedb.GetBalance(coinbase), txBalance)),
+		}
+		if result.Err != nil {
+			res.Error = result.Err.Error()
+			res.Revert = result.Revert()
+		} else {
+			res.Value = result.Return()
+		}
+		results = append(results, res)
+	}
+	coinbaseDiff := new(big.Int).Sub(statedb.GetBalance(coinbase), balance)
+	bundleGasPrice := new(big.Int)
+	if gasUsed > 0 {
+		bundleGasPrice.Div(coinbaseDiff, new(big.Int).SetUint64(gasUsed))
+	}
+	return &CallBundleResult{
+		BundleHash:       (&txpool.Bundle{Txs: txs}).Hash(),
+		BundleGasPrice:   (*hexutil.Big)(bundleGasPrice),
+		CoinbaseDiff:     (*hexutil.Big)(coinbaseDiff),
+		GasFees:          (*hexutil.Big)(gasFees),
+		TotalGasUsed:     hexutil.Uint64(gasUsed),
+		StateBlockNumber: hexutil.Uint64(parent.Number.Uint64()),
+		Results:          results,
+	}, nil
+}
+
+// decodeBundleTxs decodes the signed transactions of a bundle, refusing bundles
+// larger than the bundle pool accepts.
+func decodeBundleTxs(encoded []hexutil.Bytes) (types.Transactions, error) {
+	if len(encoded) == 0 {
+		return nil, txpool.ErrBundleEmpty
+	}
+	if len(encoded) > txpool.BundleMaxTxs {
+		return nil, txpool.ErrBundleTooLarge
+	}
+	txs := make(types.Transactions, 0, len(encoded))
+	for i, input := range encoded {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(input); err != nil {
+			return nil, fmt.Errorf("bundle transaction %d invalid: %v", i, err)
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
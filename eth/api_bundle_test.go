@@ -0,0 +1,185 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// newTestEthereum creates a started in-memory node running the Ethereum
+// protocol on a chain in which the test account and the given ones are funded.
+func newTestEthereum(t *testing.T, config *ethconfig.Config, funded ...common.Address) *Ethereum {
+	t.Helper()
+
+	stack, err := node.New(&node.Config{})
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	t.Cleanup(func() { stack.Close() })
+
+	alloc := core.GenesisAlloc{testAddr: {Balance: big.NewInt(params.Ether)}}
+	for _, addr := range funded {
+		alloc[addr] = core.GenesisAccount{Balance: big.NewInt(params.Ether)}
+	}
+	config.Genesis = &core.Genesis{
+		Config:   params.AllEthashProtocolChanges,
+		Alloc:    alloc,
+		GasLimit: params.GenesisGasLimit,
+		BaseFee:  big.NewInt(params.InitialBaseFee),
+	}
+	config.Ethash.PowMode = ethash.ModeFake
+	ethereum, err := New(stack, config)
+	if err != nil {
+		t.Fatalf("failed to create ethereum service: %v", err)
+	}
+	if err := stack.Start(); err != nil {
+		t.Fatalf("failed to start node: %v", err)
+	}
+	return ethereum
+}
+
+// bundleTransfer creates a signed transfer from the given key, encoded as
+// expected by the bundle API.
+func bundleTransfer(t *testing.T, key *ecdsa.PrivateKey, nonce uint64) hexutil.Bytes {
+	t.Helper()
+
+	tx := types.MustSignNewTx(key, types.LatestSigner(params.AllEthashProtocolChanges), &types.LegacyTx{
+		Nonce:    nonce,
+		To:       &common.Address{0x01},
+		Value:    big.NewInt(1),
+		Gas:      params.TxGas,
+		GasPrice: big.NewInt(2 * params.InitialBaseFee),
+	})
+	blob, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to encode transaction: %v", err)
+	}
+	return blob
+}
+
+// Tests that eth_sendBundle pools valid bundles and refuses the ones the pool
+// can't accept.
+func TestSendBundle(t *testing.T) {
+	var (
+		ethereum    = newTestEthereum(t, &ethconfig.Config{})
+		api         = NewBundleAPI(ethereum)
+		unfunded, _ = crypto.GenerateKey()
+	)
+	oversized := make([]hexutil.Bytes, txpool.BundleMaxTxs+1)
+	for i := range oversized {
+		oversized[i] = bundleTransfer(t, testKey, uint64(i))
+	}
+	tests := []struct {
+		args SendBundleArgs
+		err  error
+	}{
+		{SendBundleArgs{Txs: []hexutil.Bytes{bundleTransfer(t, testKey, 0)}}, errors.New("bundle target block number missing")},
+		{SendBundleArgs{BlockNumber: 1}, txpool.ErrBundleEmpty},
+		{SendBundleArgs{Txs: oversized, BlockNumber: 1}, txpool.ErrBundleTooLarge},
+		{SendBundleArgs{Txs: []hexutil.Bytes{{0x01}}, BlockNumber: 1}, errors.New("bundle transaction 0 invalid: typed transaction too short")},
+		{SendBundleArgs{Txs: []hexutil.Bytes{bundleTransfer(t, unfunded, 0)}, BlockNumber: 1}, core.ErrInsufficientFunds},
+		{SendBundleArgs{Txs: []hexutil.Bytes{bundleTransfer(t, testKey, 0), bundleTransfer(t, testKey, 1)}, BlockNumber: 1}, nil},
+	}
+	for i, tt := range tests {
+		res, err := api.SendBundle(context.Background(), tt.args)
+		switch {
+		case tt.err == nil && err != nil:
+			t.Fatalf("test %d: failed to send bundle: %v", i, err)
+		case tt.err != nil && (err == nil || (!errors.Is(err, tt.err) && err.Error() != tt.err.Error())):
+			t.Fatalf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		case tt.err == nil:
+			txs, _ := decodeBundleTxs(tt.args.Txs)
+			if want := (&txpool.Bundle{Txs: txs}).Hash(); res.BundleHash != want {
+				t.Fatalf("test %d: bundle hash mismatch: have %x, want %x", i, res.BundleHash, want)
+			}
+		}
+	}
+	if bundles := ethereum.BundlePool().Bundles(1, 0); len(bundles) != 1 || len(bundles[0].Txs) != 2 {
+		t.Fatalf("pooled bundles mismatch: %v", bundles)
+	}
+}
+
+// Tests that eth_callBundle simulates bundles on top of the requested state,
+// within the RPC gas cap.
+func TestCallBundle(t *testing.T) {
+	var (
+		ethereum = newTestEthereum(t, &ethconfig.Config{RPCGasCap: 2 * params.TxGas})
+		api      = NewBundleAPI(ethereum)
+		coinbase = common.Address{0xc0}
+	)
+	args := CallBundleArgs{
+		Txs:      []hexutil.Bytes{bundleTransfer(t, testKey, 0), bundleTransfer(t, testKey, 1)},
+		Coinbase: &coinbase,
+	}
+	res, err := api.CallBundle(context.Background(), args)
+	if err != nil {
+		t.Fatalf("failed to call bundle: %v", err)
+	}
+	if len(res.Results) != 2 || res.TotalGasUsed != hexutil.Uint64(2*params.TxGas) || res.StateBlockNumber != 0 {
+		t.Fatalf("bundle result mismatch: %d results, %d gas used, state block %d", len(res.Results), res.TotalGasUsed, res.StateBlockNumber)
+	}
+	var (
+		genesis = ethereum.BlockChain().Genesis().Header()
+		baseFee = misc.CalcBaseFee(params.AllEthashProtocolChanges, genesis)
+		tip     = new(big.Int).Sub(big.NewInt(2*params.InitialBaseFee), baseFee)
+		fees    = new(big.Int).Mul(tip, big.NewInt(int64(2*params.TxGas)))
+	)
+	if (*big.Int)(res.GasFees).Cmp(fees) != 0 || (*big.Int)(res.CoinbaseDiff).Cmp(fees) != 0 {
+		t.Fatalf("bundle fees mismatch: have %v/%v, want %v", res.GasFees, res.CoinbaseDiff, fees)
+	}
+	for i, result := range res.Results {
+		if result.FromAddress != testAddr || result.Error != "" || (*big.Int)(result.GasPrice).Cmp(tip) != 0 {
+			t.Errorf("transaction %d: result mismatch: %+v", i, result)
+		}
+	}
+	// Bundles exceeding the RPC gas cap must fail
+	args.Txs = append(args.Txs, bundleTransfer(t, testKey, 2))
+	if _, err := api.CallBundle(context.Background(), args); !errors.Is(err, core.ErrGasLimitReached) {
+		t.Fatalf("gas cap error mismatch: have %v, want %v", err, core.ErrGasLimitReached)
+	}
+	// Bundles larger than the pool accepts must be refused
+	args.Txs = make([]hexutil.Bytes, txpool.BundleMaxTxs+1)
+	for i := range args.Txs {
+		args.Txs[i] = bundleTransfer(t, testKey, uint64(i))
+	}
+	if _, err := api.CallBundle(context.Background(), args); !errors.Is(err, txpool.ErrBundleTooLarge) {
+		t.Fatalf("oversized bundle error mismatch: have %v, want %v", err, txpool.ErrBundleTooLarge)
+	}
+	// Simulating on top of an unknown block must fail
+	args.Txs = args.Txs[:1]
+	args.StateBlockNumber = rpc.BlockNumberOrHashWithNumber(1)
+	if _, err := api.CallBundle(context.Background(), args); err == nil {
+		t.Fatalf("bundle simulated on an unknown block")
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// txTransitionChanSize is the size of channel listening to TxTransitionsEvent.
+const txTransitionChanSize = 128
+
+// TxPoolEventsAPI provides a subscription to the transitions of the transactions
+// in the pool, so that services can learn why a transaction left it.
+type TxPoolEventsAPI struct {
+	e *Ethereum
+}
+
+// NewTxPoolEventsAPI creates a new TxPoolEventsAPI instance.
+func NewTxPoolEventsAPI(e *Ethereum) *TxPoolEventsAPI {
+	return &TxPoolEventsAPI{e}
+}
+
+// RPCTxTransition is the notification format of a transaction pool transition.
+type RPCTxTransition struct {
+	Hash       common.Hash             `json:"hash"`
+	From       common.Address          `json:"from"`
+	Nonce      hexutil.Uint64          `json:"nonce"`
+	Type       txpool.TxTransitionType `json:"type"`
+	Reason     txpool.TxDropReason     `json:"reason,omitempty"`
+	ReplacedBy *common.Hash            `json:"replacedBy,omitempty"`
+}
+
+// newRPCTxTransition converts a transaction pool transition into its notification
+// format.
+func newRPCTxTransition(t *txpool.TxTransition) *RPCTxTransition {
+	result := &RPCTxTransition{
+		Hash:   t.Hash,
+		From:   t.From,
+		Nonce:  hexutil.Uint64(t.Nonce),
+		Type:   t.Type,
+		Reason: t.Reason,
+	}
+	if t.Reason == txpool.TxDropReplaced {
+		by := t.ReplacedBy
+		result.ReplacedBy = &by
+	}
+	return result
+}
+
+// TxpoolEvents creates a subscription that is triggered each time a transaction
+// is promoted to the pending set, demoted to the queue or dropped from the pool,
+// in the latter case along with the reason code. If addresses are given, only
+// the transitions of transactions sent from them are reported.
+func (api *TxPoolEventsAPI) TxpoolEvents(ctx context.Context, addresses *[]common.Address) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	var senders map[common.Address]struct{}
+	if addresses != nil {
+		senders = make(map[common.Address]struct{}, len(*addresses))
+		for _, addr := range *addresses {
+			senders[addr] = struct{}{}
+		}
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		events := make(chan txpool.TxTransitionsEvent, txTransitionChanSize)
+		eventSub := api.e.TxPool().SubscribeTxTransitionsEvent(events)
+		defer eventSub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-events:
+				for i := range ev.Transitions {
+					if senders != nil {
+						if _, ok := senders[ev.Transitions[i].From]; !ok {
+							continue
+						}
+					}
+					notifier.Notify(rpcSub.ID, newRPCTxTransition(&ev.Transitions[i]))
+				}
+			case <-eventSub.Err():
+				return
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
@@ -0,0 +1,153 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Tests that the txpoolEvents subscription reports the transitions of the pooled
+// transactions with their drop reasons, filtered by sender if requested.
+func TestTxpoolEventsSubscription(t *testing.T) {
+	var (
+		otherKey, _ = crypto.GenerateKey()
+		otherAddr   = crypto.PubkeyToAddress(otherKey.PublicKey)
+		ethereum    = newTestEthereum(t, &ethconfig.Config{}, otherAddr)
+		signer      = types.LatestSigner(params.AllEthashProtocolChanges)
+	)
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("eth", NewTxPoolEventsAPI(ethereum)); err != nil {
+		t.Fatalf("failed to register txpool events API: %v", err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	var (
+		all      = make(chan *RPCTxTransition, 16)
+		filtered = make(chan *RPCTxTransition, 16)
+	)
+	allSub, err := client.EthSubscribe(context.Background(), all, "txpoolEvents")
+	if err != nil {
+		t.Fatalf("failed to subscribe to all transitions: %v", err)
+	}
+	defer allSub.Unsubscribe()
+	filteredSub, err := client.EthSubscribe(context.Background(), filtered, "txpoolEvents", []common.Address{testAddr})
+	if err != nil {
+		t.Fatalf("failed to subscribe to filtered transitions: %v", err)
+	}
+	defer filteredSub.Unsubscribe()
+
+	transfer := func(key *ecdsa.PrivateKey, nonce uint64, to common.Address, price int64) *types.Transaction {
+		return types.MustSignNewTx(key, signer, &types.LegacyTx{
+			Nonce:    nonce,
+			To:       &to,
+			Value:    big.NewInt(1),
+			Gas:      params.TxGas,
+			GasPrice: big.NewInt(price),
+		})
+	}
+	var (
+		tx       = transfer(testKey, 0, common.Address{0x01}, 2*params.InitialBaseFee)
+		replaced = transfer(testKey, 0, common.Address{0x01}, 4*params.InitialBaseFee)
+		other    = transfer(otherKey, 0, common.Address{0x01}, 2*params.InitialBaseFee)
+		rival    = transfer(otherKey, 0, common.Address{0x02}, 2*params.InitialBaseFee)
+	)
+	// Pool the transactions of both senders, replacing the first one
+	pool := ethereum.TxPool()
+	for _, tx := range []*types.Transaction{tx, other, replaced} {
+		if err := pool.AddLocal(tx); err != nil {
+			t.Fatalf("failed to add transaction %x: %v", tx.Hash(), err)
+		}
+	}
+	// Include the replacement and a rival of the other transaction on chain
+	blocks, _ := core.GenerateChain(params.AllEthashProtocolChanges, ethereum.BlockChain().Genesis(), ethereum.Engine(), ethereum.ChainDb(), 1, func(i int, b *core.BlockGen) {
+		b.AddTx(replaced)
+		b.AddTx(rival)
+	})
+	if _, err := ethereum.BlockChain().InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert block: %v", err)
+	}
+	want := []*RPCTxTransition{
+		{Hash: tx.Hash(), From: testAddr, Type: txpool.TxPromoted},
+		{Hash: other.Hash(), From: otherAddr, Type: txpool.TxPromoted},
+		{Hash: tx.Hash(), From: testAddr, Type: txpool.TxDropped, Reason: txpool.TxDropReplaced, ReplacedBy: hashPtr(replaced.Hash())},
+		{Hash: replaced.Hash(), From: testAddr, Type: txpool.TxDropped, Reason: txpool.TxDropIncluded},
+		{Hash: other.Hash(), From: otherAddr, Type: txpool.TxDropped, Reason: txpool.TxDropNonceTooLow},
+	}
+	checkTransitions(t, "all", all, want)
+
+	var mine []*RPCTxTransition
+	for _, transition := range want {
+		if transition.From == testAddr {
+			mine = append(mine, transition)
+		}
+	}
+	checkTransitions(t, "filtered", filtered, mine)
+}
+
+func hashPtr(hash common.Hash) *common.Hash { return &hash }
+
+// checkTransitions verifies that the given subscription channel delivers the
+// expected transitions, and nothing else. Transitions of a single sender must
+// arrive in order, but may interleave with the ones of other senders, as a pool
+// reset walks the accounts in map order.
+func checkTransitions(t *testing.T, name string, ch chan *RPCTxTransition, want []*RPCTxTransition) {
+	t.Helper()
+
+	pending := append([]*RPCTxTransition{}, want...)
+	for len(pending) > 0 {
+		select {
+		case have := <-ch:
+			i := 0
+			for i < len(pending) && pending[i].From != have.From {
+				i++
+			}
+			if i == len(pending) {
+				t.Fatalf("%s unexpected transition: %+v", name, have)
+			}
+			w := pending[i]
+			if have.Hash != w.Hash || have.Type != w.Type || have.Reason != w.Reason {
+				t.Fatalf("%s transition mismatch: have %+v, want %+v", name, have, w)
+			}
+			if (have.ReplacedBy == nil) != (w.ReplacedBy == nil) || (have.ReplacedBy != nil && *have.ReplacedBy != *w.ReplacedBy) {
+				t.Fatalf("%s replacement mismatch: have %v, want %v", name, have.ReplacedBy, w.ReplacedBy)
+			}
+			pending = append(pending[:i], pending[i+1:]...)
+		case <-time.After(time.Second):
+			t.Fatalf("%s transitions missing: want %+v", name, pending)
+		}
+	}
+	select {
+	case have := <-ch:
+		t.Fatalf("%s unexpected transition: %+v", name, have)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
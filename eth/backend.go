@@ -31,6 +31,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/beacon"
 	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
 	"github.com/ethereum/go-ethereum/core/rawdb"
@@ -49,6 +50,7 @@ import (
 	"github.com/ethereum/go-ethereum/internal/shutdowncheck"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/miner"
+	"github.com/ethereum/go-ethereum/miner/pool"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/p2p/dnsdisc"
@@ -68,6 +70,7 @@ type Ethereum struct {
 
 	// Handlers
 	txPool             *txpool.TxPool
+	bundlePool         *txpool.BundlePool
 	blockchain         *core.BlockChain
 	handler            *handler
 	ethDialCandidates  enode.Iterator
@@ -83,11 +86,14 @@ type Ethereum struct {
 
 	bloomRequests     chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
 	bloomIndexer      *core.ChainIndexer             // Bloom indexer operating during block imports
+	supplyIndexer     *core.ChainIndexer             // Supply indexer operating during block imports
+	blockReward       core.RewardFunc                // Block rewards of the consensus engine, nil if unrewarded
 	closeBloomHandler chan struct{}
 
 	APIBackend *EthAPIBackend
 
 	miner     *miner.Miner
+	pool      *pool.Pool // Share accounting of stratum miners, nil if disabled
 	gasPrice  *big.Int
 	etherbase common.Address
 
@@ -202,19 +208,37 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if err != nil {
 		return nil, err
 	}
+	checkpoint := config.Checkpoint
+	if checkpoint == nil {
+		checkpoint = params.TrustedCheckpoints[eth.blockchain.Genesis().Hash()]
+	}
+	// Protect the chain against deep reorgs with the reorg depth limit, and with
+	// the finality checkpoints if opted in
+	finality := &core.FinalityConfig{MaxReorg: config.MaxReorg}
+	if config.Finality {
+		finality.Checkpoint = checkpoint
+		finality.Oracle = config.CheckpointOracle
+	}
+	eth.blockchain.SetFinality(finality)
 	eth.bloomIndexer.Start(eth.blockchain)
 
+	if eth.blockchain.Config().Clique == nil {
+		eth.blockReward = ethash.BlockReward
+	}
+	eth.supplyIndexer = core.NewSupplyIndexer(chainDb, eth.blockchain.Config(), eth.blockReward, params.SupplyIndexBlocks, params.SupplyIndexConfirms)
+	eth.supplyIndexer.Start(eth.blockchain)
+
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = stack.ResolvePath(config.TxPool.Journal)
 	}
+	if config.TxPool.RemoteJournal != "" {
+		config.TxPool.RemoteJournal = stack.ResolvePath(config.TxPool.RemoteJournal)
+	}
 	eth.txPool = txpool.NewTxPool(config.TxPool, eth.blockchain.Config(), eth.blockchain)
+	eth.bundlePool = txpool.NewBundlePool(eth.blockchain.Config(), eth.blockchain)
 
 	// Permit the downloader to use the trie cache allowance during fast sync
 	cacheLimit := cacheConfig.TrieCleanLimit + cacheConfig.TrieDirtyLimit + cacheConfig.SnapshotLimit
-	checkpoint := config.Checkpoint
-	if checkpoint == nil {
-		checkpoint = params.TrustedCheckpoints[eth.blockchain.Genesis().Hash()]
-	}
 	if eth.handler, err = newHandler(&handlerConfig{
 		Database:       chainDb,
 		Chain:          eth.blockchain,
@@ -233,6 +257,25 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	eth.miner = miner.New(eth, &config.Miner, eth.blockchain.Config(), eth.EventMux(), eth.engine, eth.isLocalBlock)
 	eth.miner.SetExtra(makeExtraData(config.Miner.ExtraData))
 
+	// Let remote miners request work packages paying their own address, and keep
+	// a history of the work issued to them across restarts
+	if cl, ok := eth.engine.(*beacon.Beacon); ok {
+		if e, ok := cl.InnerEngine().(*ethash.Ethash); ok {
+			e.SetPayoutBuilder(eth.miner.BuildPayoutBlock)
+			e.SetWorkHistory(chainDb)
+
+			if config.Miner.Pool.Enabled {
+				eth.pool = pool.New(config.Miner.Pool, chainDb, eth.blockchain, eth.EventMux())
+				e.SetShareRecorder(eth.pool)
+			}
+			if config.Miner.Stratum != "" {
+				if err := e.StartStratum(config.Miner.Stratum, config.Miner.StratumDifficulty); err != nil {
+					return nil, fmt.Errorf("failed to start stratum server: %v", err)
+				}
+			}
+		}
+	}
+
 	eth.APIBackend = &EthAPIBackend{stack.Config().ExtRPCEnabled(), stack.Config().AllowUnprotectedTxs, eth, nil}
 	if eth.APIBackend.allowUnprotectedTxs {
 		log.Info("Unprotected transactions allowed")
@@ -293,6 +336,10 @@ func (s *Ethereum) APIs() []rpc.API {
 	// Append any APIs exposed explicitly by the consensus engine
 	apis = append(apis, s.engine.APIs(s.BlockChain())...)
 
+	// Append the pool ledger if share accounting is enabled
+	if s.pool != nil {
+		apis = append(apis, s.pool.APIs()...)
+	}
 	// Append all the local APIs and return
 	return append(apis, []rpc.API{
 		{
@@ -301,6 +348,18 @@ func (s *Ethereum) APIs() []rpc.API {
 		}, {
 			Namespace: "miner",
 			Service:   NewMinerAPI(s),
+		}, {
+			Namespace: "liberty",
+			Service:   NewLibertyAPI(s),
+		}, {
+			Namespace: "eth",
+			Service:   NewBundleAPI(s),
+		}, {
+			Namespace: "txpool",
+			Service:   NewTxPoolPolicyAPI(s),
+		}, {
+			Namespace: "eth",
+			Service:   NewTxPoolEventsAPI(s),
 		}, {
 			Namespace: "eth",
 			Service:   downloader.NewDownloaderAPI(s.handler.downloader, s.eventMux),
@@ -469,6 +528,7 @@ func (s *Ethereum) Miner() *miner.Miner { return s.miner }
 func (s *Ethereum) AccountManager() *accounts.Manager  { return s.accountManager }
 func (s *Ethereum) BlockChain() *core.BlockChain       { return s.blockchain }
 func (s *Ethereum) TxPool() *txpool.TxPool             { return s.txPool }
+func (s *Ethereum) BundlePool() *txpool.BundlePool     { return s.bundlePool }
 func (s *Ethereum) EventMux() *event.TypeMux           { return s.eventMux }
 func (s *Ethereum) Engine() consensus.Engine           { return s.engine }
 func (s *Ethereum) ChainDb() ethdb.Database            { return s.chainDb }
@@ -528,9 +588,13 @@ func (s *Ethereum) Stop() error {
 
 	// Then stop everything else.
 	s.bloomIndexer.Close()
+	s.supplyIndexer.Close()
 	close(s.closeBloomHandler)
 	s.txPool.Stop()
 	s.miner.Close()
+	if s.pool != nil {
+		s.pool.Stop()
+	}
 	s.blockchain.Stop()
 	s.engine.Close()
 
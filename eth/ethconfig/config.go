@@ -205,6 +205,15 @@ type Config struct {
 	// CheckpointOracle is the configuration for checkpoint oracle.
 	CheckpointOracle *params.CheckpointOracleConfig `toml:",omitempty"`
 
+	// Finality enables the finality checkpoints: reorgs dropping the block of
+	// the trusted checkpoint of the network, or of a checkpoint signed by the
+	// signers of the configured checkpoint oracle, are refused.
+	Finality bool `toml:",omitempty"`
+
+	// MaxReorg is the maximum number of blocks a chain reorg may drop, 0 for no
+	// limit. The checkpoint oracle signers can declare blocks final regardless.
+	MaxReorg uint64 `toml:",omitempty"`
+
 	// OverrideShanghai (TODO: remove after the fork)
 	OverrideShanghai *uint64 `toml:",omitempty"`
 }
@@ -261,6 +270,8 @@ func CreateConsensusEngine(
 			log.Warn("Ethash used in test mode")
 		case ethash.ModeShared:
 			log.Warn("Ethash used in shared mode")
+		case ethash.ModeLegacy, ethash.ModeLegacyTest:
+			log.Warn("Ethash used in legacy DAG mode")
 		}
 
 		// Resolve the keystore path
@@ -60,6 +60,8 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		RPCTxFeeCap             float64
 		Checkpoint              *params.TrustedCheckpoint      `toml:",omitempty"`
 		CheckpointOracle        *params.CheckpointOracleConfig `toml:",omitempty"`
+		Finality                bool                           `toml:",omitempty"`
+		MaxReorg                uint64                         `toml:",omitempty"`
 		OverrideShanghai        *uint64                        `toml:",omitempty"`
 	}
 	var enc Config
@@ -105,6 +107,8 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.RPCTxFeeCap = c.RPCTxFeeCap
 	enc.Checkpoint = c.Checkpoint
 	enc.CheckpointOracle = c.CheckpointOracle
+	enc.Finality = c.Finality
+	enc.MaxReorg = c.MaxReorg
 	enc.OverrideShanghai = c.OverrideShanghai
 	return &enc, nil
 }
@@ -154,6 +158,8 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		RPCTxFeeCap             *float64
 		Checkpoint              *params.TrustedCheckpoint      `toml:",omitempty"`
 		CheckpointOracle        *params.CheckpointOracleConfig `toml:",omitempty"`
+		Finality                *bool                          `toml:",omitempty"`
+		MaxReorg                *uint64                        `toml:",omitempty"`
 		OverrideShanghai        *uint64                        `toml:",omitempty"`
 	}
 	var dec Config
@@ -286,6 +292,12 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.CheckpointOracle != nil {
 		c.CheckpointOracle = dec.CheckpointOracle
 	}
+	if dec.Finality != nil {
+		c.Finality = *dec.Finality
+	}
+	if dec.MaxReorg != nil {
+		c.MaxReorg = *dec.MaxReorg
+	}
 	if dec.OverrideShanghai != nil {
 		c.OverrideShanghai = dec.OverrideShanghai
 	}
@@ -34,6 +34,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/mclock"
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	ethproto "github.com/ethereum/go-ethereum/eth/protocols/eth"
@@ -78,6 +79,7 @@ type fullNodeBackend interface {
 	backend
 	Miner() *miner.Miner
 	BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error)
+	BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error)
 	CurrentBlock() *types.Block
 	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
 }
@@ -98,6 +100,9 @@ type Service struct {
 
 	headSub event.Subscription
 	txSub   event.Subscription
+
+	networkHead  common.Hash          // Head the network statistics were last estimated at
+	networkStats *ethash.NetworkStats // Network statistics estimated at networkHead
 }
 
 // connWrapper is a wrapper to prevent concurrent-write or concurrent-read on the
@@ -766,6 +771,32 @@ type nodeStats struct {
 	Peers    int  `json:"peers"`
 	GasPrice int  `json:"gasPrice"`
 	Uptime   int  `json:"uptime"`
+
+	Network *ethash.NetworkStats `json:"network,omitempty"` // Mining statistics of the network, full nodes only
+}
+
+// estimateNetworkStats returns the mining statistics of the network over the
+// most recent blocks, only estimating them anew if the chain head changed.
+func (s *Service) estimateNetworkStats(backend fullNodeBackend) *ethash.NetworkStats {
+	head := backend.CurrentHeader()
+	if head.Hash() == s.networkHead {
+		return s.networkStats
+	}
+	stats, err := ethash.EstimateNetworkStats(head.Number.Uint64(), ethash.DefaultStatsWindow, func(number uint64) *types.Header {
+		header, _ := backend.HeaderByNumber(context.Background(), rpc.BlockNumber(number))
+		return header
+	}, func(hash common.Hash, number uint64) []*types.Header {
+		if block, _ := backend.BlockByHash(context.Background(), hash); block != nil {
+			return block.Uncles()
+		}
+		return nil
+	})
+	if err != nil {
+		log.Debug("Failed to estimate network stats", "err", err)
+		return nil
+	}
+	s.networkHead, s.networkStats = head.Hash(), stats
+	return stats
 }
 
 // reportStats retrieves various stats about the node at the networking and
@@ -777,6 +808,7 @@ func (s *Service) reportStats(conn *connWrapper) error {
 		hashrate int
 		syncing  bool
 		gasprice int
+		network  *ethash.NetworkStats
 	)
 	// check if backend is a full node
 	fullBackend, ok := s.backend.(fullNodeBackend)
@@ -792,6 +824,7 @@ func (s *Service) reportStats(conn *connWrapper) error {
 		if basefee := fullBackend.CurrentHeader().BaseFee; basefee != nil {
 			gasprice += int(basefee.Uint64())
 		}
+		network = s.estimateNetworkStats(fullBackend)
 	} else {
 		sync := s.backend.SyncProgress()
 		syncing = s.backend.CurrentHeader().Number.Uint64() >= sync.HighestBlock
@@ -809,6 +842,7 @@ func (s *Service) reportStats(conn *connWrapper) error {
 			GasPrice: gasprice,
 			Syncing:  syncing,
 			Uptime:   100,
+			Network:  network,
 		},
 	}
 	report := map[string][]interface{}{
@@ -0,0 +1,131 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// maxSimulatedBundles is the maximum number of bundles simulated for a sealing
+// block. Bundles beyond it, the lowest priced ones, are not considered.
+const maxSimulatedBundles = 32
+
+// simulatedBundle is a bundle along with the profit it yields to the coinbase
+// when included on top of the sealing block.
+type simulatedBundle struct {
+	bundle *txpool.Bundle
+	profit *big.Int
+}
+
+// commitBundles includes the bundles targeting the sealing block, the most
+// profitable first. Up to maxSimulatedBundles bundles are simulated against the
+// sealing state alone to rank them, and re-executed on top of the previously
+// included bundles, which may make them fail and be skipped.
+func (w *worker) commitBundles(env *environment, interrupt *atomic.Int32) error {
+	bundles := w.eth.BundlePool().Bundles(env.header.Number.Uint64(), env.header.Time)
+	if len(bundles) == 0 {
+		return nil
+	}
+	if len(bundles) > maxSimulatedBundles {
+		log.Debug("Too many bundles to simulate", "number", env.header.Number, "bundles", len(bundles), "limit", maxSimulatedBundles)
+		bundles = bundles[:maxSimulatedBundles]
+	}
+	if env.gasPool == nil {
+		env.gasPool = new(core.GasPool).AddGas(env.header.GasLimit)
+	}
+	simulated := make([]*simulatedBundle, 0, len(bundles))
+	for _, bundle := range bundles {
+		// Check interruption signal and abort building if it's fired.
+		if interrupt != nil {
+			if signal := interrupt.Load(); signal != commitInterruptNone {
+				return signalToErr(signal)
+			}
+		}
+		profit, err := w.commitBundle(env.copy(), bundle)
+		if err != nil {
+			log.Debug("Bundle simulation failed", "hash", bundle.Hash(), "err", err)
+			continue
+		}
+		simulated = append(simulated, &simulatedBundle{bundle: bundle, profit: profit})
+	}
+	sort.SliceStable(simulated, func(i, j int) bool {
+		return simulated[i].profit.Cmp(simulated[j].profit) > 0
+	})
+	for _, sim := range simulated {
+		// Check interruption signal and abort building if it's fired.
+		if interrupt != nil {
+			if signal := interrupt.Load(); signal != commitInterruptNone {
+				return signalToErr(signal)
+			}
+		}
+		if _, err := w.commitBundle(env, sim.bundle); err != nil {
+			log.Debug("Bundle skipped", "hash", sim.bundle.Hash(), "err", err)
+			continue
+		}
+		log.Trace("Included bundle", "hash", sim.bundle.Hash(), "txs", len(sim.bundle.Txs), "profit", sim.profit)
+	}
+	return nil
+}
+
+// commitBundle applies all the transactions of a bundle to the sealing block,
+// returning the increase of the coinbase balance. If any of them fails, or
+// reverts without being allowed to, none is included.
+func (w *worker) commitBundle(env *environment, bundle *txpool.Bundle) (*big.Int, error) {
+	// Snapshots don't survive the finalisation between transactions, so keep a
+	// copy of the state to restore if the bundle fails.
+	var (
+		state    = env.state.Copy()
+		gas      = env.gasPool.Gas()
+		gasUsed  = env.header.GasUsed
+		tcount   = env.tcount
+		included = len(env.txs)
+		balance  = env.state.GetBalance(env.coinbase)
+	)
+	revert := func() {
+		env.state = state
+		env.gasPool.SetGas(gas)
+		env.header.GasUsed = gasUsed
+		env.tcount = tcount
+		env.txs = env.txs[:included]
+		env.receipts = env.receipts[:included]
+	}
+	for _, tx := range bundle.Txs {
+		if tx.Protected() && !w.chainConfig.IsEIP155(env.header.Number) {
+			revert()
+			return nil, fmt.Errorf("replay protected transaction %x before EIP155", tx.Hash())
+		}
+		env.state.SetTxContext(tx.Hash(), env.tcount)
+		if _, err := w.commitTransaction(env, tx); err != nil {
+			revert()
+			return nil, fmt.Errorf("transaction %x failed: %w", tx.Hash(), err)
+		}
+		if env.receipts[len(env.receipts)-1].Status == types.ReceiptStatusFailed && !bundle.AllowsRevert(tx.Hash()) {
+			revert()
+			return nil, fmt.Errorf("transaction %x reverted", tx.Hash())
+		}
+		env.tcount++
+	}
+	return new(big.Int).Sub(env.state.GetBalance(env.coinbase), balance), nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the worker includes the valid bundles targeting the sealing block
+// ahead of the regular transactions, the most profitable first, and drops the
+// bundles with unexpectedly reverting transactions as a whole.
+func TestCommitBundles(t *testing.T) {
+	var (
+		coinbase = common.HexToAddress("0xc0ffee")
+		reverter = common.HexToAddress("0xdead")
+		keys     = make([]*ecdsa.PrivateKey, 7)
+		addrs    = make([]common.Address, len(keys))
+		alloc    = core.GenesisAlloc{
+			reverter: {Code: common.FromHex("0x60006000fd"), Balance: new(big.Int)}, // revert(0, 0)
+		}
+	)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		addrs[i] = crypto.PubkeyToAddress(keys[i].PublicKey)
+		alloc[addrs[i]] = core.GenesisAccount{Balance: testBankFunds}
+	}
+	w, b := newFundedTestWorker(t, testConfig, alloc)
+
+	signer := types.LatestSigner(w.chainConfig)
+	newTx := func(sender int, to common.Address, value int64, price int64) *types.Transaction {
+		return types.MustSignNewTx(keys[sender], signer, &types.LegacyTx{
+			To:       &to,
+			Value:    big.NewInt(value),
+			Gas:      100000,
+			GasPrice: big.NewInt(price * params.InitialBaseFee),
+		})
+	}
+	// Account 0 sends a regular transaction paying the highest tip
+	if err := b.txPool.AddRemotesSync([]*types.Transaction{newTx(0, testUserAddress, 1000, 10)})[0]; err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	revert := newTx(5, reverter, 0, 2)
+	bundles := []*txpool.Bundle{
+		// Low profit bundle with an allowed revert
+		{Txs: types.Transactions{revert}, BlockNumber: 1, RevertingTxHashes: []common.Hash{revert.Hash()}},
+		// Valid first transaction, unexpectedly reverting second one
+		{Txs: types.Transactions{newTx(4, testUserAddress, 1000, 5), newTx(6, reverter, 0, 5)}, BlockNumber: 1},
+		// Medium profit bundle from gas fees only
+		{Txs: types.Transactions{newTx(3, testUserAddress, 1000, 3)}, BlockNumber: 1},
+		// High profit bundle paying the coinbase directly
+		{Txs: types.Transactions{newTx(1, testUserAddress, 1000, 2), newTx(2, coinbase, params.Ether/10, 2)}, BlockNumber: 1},
+		// Bundle targeting a later block
+		{Txs: types.Transactions{newTx(0, testUserAddress, 1000, 20)}, BlockNumber: 2},
+	}
+	for i, bundle := range bundles {
+		if _, err := b.bundlePool.Add(bundle); err != nil {
+			t.Fatalf("bundle %d: failed to add: %v", i, err)
+		}
+	}
+	genesis := b.chain.Genesis()
+	block, _, err := w.getSealingBlock(genesis.Hash(), genesis.Time()+1, coinbase, common.Hash{}, nil, false)
+	if err != nil {
+		t.Fatalf("failed to build block: %v", err)
+	}
+	want := []int{1, 2, 3, 5, 0}
+
+	txs := block.Transactions()
+	if len(txs) != len(want) {
+		t.Fatalf("transaction count mismatch: have %d, want %d", len(txs), len(want))
+	}
+	for i, tx := range txs {
+		if from, _ := types.Sender(signer, tx); from != addrs[want[i]] {
+			t.Errorf("transaction %d: sender mismatch: have %x, want %x (account %d)", i, from, addrs[want[i]], want[i])
+		}
+	}
+}
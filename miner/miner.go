@@ -33,6 +33,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/miner/pool"
 	"github.com/ethereum/go-ethereum/params"
 )
 
@@ -46,6 +47,7 @@ import (
 type Backend interface {
 	BlockChain() *core.BlockChain
 	TxPool() *txpool.TxPool
+	BundlePool() *txpool.BundlePool
 }
 
 // Config is the configuration parameters of mining.
@@ -60,7 +62,14 @@ type Config struct {
 	Recommit   time.Duration  // The time interval for miner to re-create mining work.
 	Noverify   bool           // Disable remote mining solution verification(only useful in ethash).
 
+	Stratum           string      `toml:",omitempty"` // Listen address of the stratum+tcp server for remote miners (only useful in ethash).
+	StratumDifficulty uint64      `toml:",omitempty"` // Initial and minimum share difficulty of stratum sessions, raised by vardiff
+	Pool              pool.Config // Share accounting and payouts of stratum miners
+
 	NewPayloadTimeout time.Duration // The maximum time allowance for creating a new payload
+
+	Ordering string             `toml:",omitempty"` // Transaction ordering policy of the blocks built (local, price, fifo or lanes)
+	Lanes    [][]common.Address `toml:",omitempty"` // Priority lanes of senders for the lanes ordering policy, highest first
 }
 
 // DefaultConfig contains default settings for miner.
@@ -74,6 +83,8 @@ var DefaultConfig = Config{
 	// run 3 rounds.
 	Recommit:          2 * time.Second,
 	NewPayloadTimeout: 2 * time.Second,
+	StratumDifficulty: 256,
+	Pool:              pool.DefaultConfig,
 }
 
 // Miner creates blocks and searches for proof-of-work values.
@@ -256,6 +267,12 @@ func (miner *Miner) SubscribePendingLogs(ch chan<- []*types.Log) event.Subscript
 	return miner.worker.pendingLogsFeed.Subscribe(ch)
 }
 
+// BuildPayoutBlock builds a sealing block on top of the given parent paying the
+// given coinbase, used to hand out per-address work packages to remote miners.
+func (miner *Miner) BuildPayoutBlock(parent common.Hash, timestamp uint64, coinbase common.Address) (*types.Block, error) {
+	return miner.worker.getPayoutBlock(parent, timestamp, coinbase)
+}
+
 // BuildPayload builds the payload according to the provided parameters.
 func (miner *Miner) BuildPayload(args *BuildPayloadArgs) (*Payload, error) {
 	return miner.worker.buildPayload(args)
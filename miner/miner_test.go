@@ -37,14 +37,16 @@ import (
 )
 
 type mockBackend struct {
-	bc     *core.BlockChain
-	txPool *txpool.TxPool
+	bc         *core.BlockChain
+	txPool     *txpool.TxPool
+	bundlePool *txpool.BundlePool
 }
 
 func NewMockBackend(bc *core.BlockChain, txPool *txpool.TxPool) *mockBackend {
 	return &mockBackend{
-		bc:     bc,
-		txPool: txPool,
+		bc:         bc,
+		txPool:     txPool,
+		bundlePool: txpool.NewBundlePool(bc.Config(), bc),
 	}
 }
 
@@ -56,6 +58,10 @@ func (m *mockBackend) TxPool() *txpool.TxPool {
 	return m.txPool
 }
 
+func (m *mockBackend) BundlePool() *txpool.BundlePool {
+	return m.bundlePool
+}
+
 func (m *mockBackend) StateAtBlock(block *types.Block, reexec uint64, base *state.StateDB, checkLive bool, preferDisk bool) (statedb *state.StateDB, err error) {
 	return nil, errors.New("not supported")
 }
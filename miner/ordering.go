@@ -0,0 +1,251 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+package miner
+
+import (
+	"bytes"
+	"container/heap"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Names of the built-in transaction ordering policies.
+const (
+	OrderingLocalFirst = "local" // Local transactions first, then remote ones, each by price
+	OrderingPrice      = "price" // All transactions by price, regardless of origin
+	OrderingFIFO       = "fifo"  // All transactions in the order they arrived
+	OrderingLanes      = "lanes" // Senders of the priority lanes first, lane by lane, each by price
+)
+
+// TransactionSet is a stream of transactions to fill a block with, honouring
+// the nonce order of every sender.
+type TransactionSet interface {
+	// Peek returns the next transaction to include, nil if the set is exhausted.
+	Peek() *types.Transaction
+
+	// Shift replaces the next transaction with the following one of the same
+	// sender, after the next transaction was included.
+	Shift()
+
+	// Pop drops the next transaction along with every following one of the
+	// same sender, after the next transaction failed to execute.
+	Pop()
+}
+
+// OrderingPolicy decides the order in which pending transactions are included
+// into the blocks built by the worker.
+type OrderingPolicy interface {
+	// Order assembles the pending transactions, nonce sorted per sender, into
+	// the transaction set a block is filled from. The pending map is owned by
+	// the policy afterwards.
+	Order(signer types.Signer, baseFee *big.Int, pending map[common.Address]types.Transactions, locals []common.Address) TransactionSet
+}
+
+// NewOrderingPolicy creates the transaction ordering policy selected by the
+// miner config.
+func NewOrderingPolicy(config *Config) (OrderingPolicy, error) {
+	switch config.Ordering {
+	case "", OrderingLocalFirst:
+		return localFirstOrdering{}, nil
+	case OrderingPrice:
+		return priceOrdering{}, nil
+	case OrderingFIFO:
+		return fifoOrdering{}, nil
+	case OrderingLanes:
+		if len(config.Lanes) == 0 {
+			return nil, fmt.Errorf("ordering policy %q needs at least one priority lane", OrderingLanes)
+		}
+		return newLaneOrdering(config.Lanes), nil
+	default:
+		return nil, fmt.Errorf("unknown ordering policy %q", config.Ordering)
+	}
+}
+
+// priceOrdering includes the transactions paying the highest tip first.
+type priceOrdering struct{}
+
+func (priceOrdering) Order(signer types.Signer, baseFee *big.Int, pending map[common.Address]types.Transactions, locals []common.Address) TransactionSet {
+	return types.NewTransactionsByPriceAndNonce(signer, pending, baseFee)
+}
+
+// localFirstOrdering includes the transactions of local accounts before any
+// remote one, each group ordered by price.
+type localFirstOrdering struct{}
+
+func (localFirstOrdering) Order(signer types.Signer, baseFee *big.Int, pending map[common.Address]types.Transactions, locals []common.Address) TransactionSet {
+	lanes := splitLanes(pending, [][]common.Address{locals})
+	return &chainedSet{
+		types.NewTransactionsByPriceAndNonce(signer, lanes[0], baseFee),
+		types.NewTransactionsByPriceAndNonce(signer, pending, baseFee),
+	}
+}
+
+// laneOrdering includes the transactions of the senders of each priority lane
+// before those of the next lane, and all other transactions last. Every lane is
+// ordered by price.
+type laneOrdering struct {
+	lanes [][]common.Address
+}
+
+func newLaneOrdering(lanes [][]common.Address) *laneOrdering {
+	return &laneOrdering{lanes: lanes}
+}
+
+func (o *laneOrdering) Order(signer types.Signer, baseFee *big.Int, pending map[common.Address]types.Transactions, locals []common.Address) TransactionSet {
+	set := make(chainedSet, 0, len(o.lanes)+1)
+	for _, lane := range splitLanes(pending, o.lanes) {
+		set = append(set, types.NewTransactionsByPriceAndNonce(signer, lane, baseFee))
+	}
+	set = append(set, types.NewTransactionsByPriceAndNonce(signer, pending, baseFee))
+	return &set
+}
+
+// splitLanes moves the transactions of the senders of each lane out of the
+// pending ones, returning them lane by lane. Senders listed in several lanes
+// belong to the first.
+func splitLanes(pending map[common.Address]types.Transactions, lanes [][]common.Address) []map[common.Address]types.Transactions {
+	split := make([]map[common.Address]types.Transactions, len(lanes))
+	for i, lane := range lanes {
+		split[i] = make(map[common.Address]types.Transactions)
+		for _, account := range lane {
+			if txs := pending[account]; len(txs) > 0 {
+				delete(pending, account)
+				split[i][account] = txs
+			}
+		}
+	}
+	return split
+}
+
+// chainedSet includes the transactions of each set before those of the next.
+// The sets must not share senders.
+type chainedSet []TransactionSet
+
+func (s *chainedSet) Peek() *types.Transaction {
+	for len(*s) > 0 {
+		if tx := (*s)[0].Peek(); tx != nil {
+			return tx
+		}
+		*s = (*s)[1:]
+	}
+	return nil
+}
+
+func (s *chainedSet) Shift() {
+	if s.Peek() != nil {
+		(*s)[0].Shift()
+	}
+}
+
+func (s *chainedSet) Pop() {
+	if s.Peek() != nil {
+		(*s)[0].Pop()
+	}
+}
+
+// fifoOrdering includes the transactions in the order they were first seen,
+// regardless of the tip they pay.
+type fifoOrdering struct{}
+
+func (fifoOrdering) Order(signer types.Signer, baseFee *big.Int, pending map[common.Address]types.Transactions, locals []common.Address) TransactionSet {
+	return newTransactionsByTime(signer, pending, baseFee)
+}
+
+// transactionsByTime is a transaction set returning the transactions in the
+// order they arrived, in a nonce-honouring way.
+type transactionsByTime struct {
+	txs     map[common.Address]types.Transactions // Per account nonce-sorted list of transactions
+	heads   txsByTime                             // Next transaction for each unique account (arrival heap)
+	signer  types.Signer
+	baseFee *big.Int
+}
+
+func newTransactionsByTime(signer types.Signer, txs map[common.Address]types.Transactions, baseFee *big.Int) *transactionsByTime {
+	set := &transactionsByTime{
+		txs:     txs,
+		heads:   make(txsByTime, 0, len(txs)),
+		signer:  signer,
+		baseFee: baseFee,
+	}
+	for from, accTxs := range txs {
+		// Drop the account if the sender doesn't match or the fee cap is too low
+		if acc, _ := types.Sender(signer, accTxs[0]); acc != from || !set.payable(accTxs[0]) {
+			delete(txs, from)
+			continue
+		}
+		set.heads = append(set.heads, accTxs[0])
+		txs[from] = accTxs[1:]
+	}
+	heap.Init(&set.heads)
+	return set
+}
+
+// payable reports whether the transaction covers the base fee.
+func (t *transactionsByTime) payable(tx *types.Transaction) bool {
+	_, err := tx.EffectiveGasTip(t.baseFee)
+	return err == nil
+}
+
+func (t *transactionsByTime) Peek() *types.Transaction {
+	if len(t.heads) == 0 {
+		return nil
+	}
+	return t.heads[0]
+}
+
+func (t *transactionsByTime) Shift() {
+	acc, _ := types.Sender(t.signer, t.heads[0])
+	if txs := t.txs[acc]; len(txs) > 0 && t.payable(txs[0]) {
+		t.heads[0], t.txs[acc] = txs[0], txs[1:]
+		heap.Fix(&t.heads, 0)
+		return
+	}
+	heap.Pop(&t.heads)
+}
+
+func (t *transactionsByTime) Pop() {
+	heap.Pop(&t.heads)
+}
+
+// txsByTime is a heap of transactions, earliest seen first. Transactions seen
+// at the same time are ordered by hash to stay deterministic.
+type txsByTime []*types.Transaction
+
+func (s txsByTime) Len() int { return len(s) }
+func (s txsByTime) Less(i, j int) bool {
+	if ti, tj := s[i].Time(), s[j].Time(); !ti.Equal(tj) {
+		return ti.Before(tj)
+	}
+	hi, hj := s[i].Hash(), s[j].Hash()
+	return bytes.Compare(hi[:], hj[:]) < 0
+}
+func (s txsByTime) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+func (s *txsByTime) Push(x interface{}) {
+	*s = append(*s, x.(*types.Transaction))
+}
+
+func (s *txsByTime) Pop() interface{} {
+	old := *s
+	n := len(old)
+	x := old[n-1]
+	old[n-1] = nil
+	*s = old[0 : n-1]
+	return x
+}
@@ -0,0 +1,144 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the ordering policies are selected by the miner config.
+func TestNewOrderingPolicy(t *testing.T) {
+	lanes := [][]common.Address{{testUserAddress}}
+	tests := []struct {
+		ordering string
+		lanes    [][]common.Address
+		want     OrderingPolicy
+		fail     bool
+	}{
+		{ordering: "", want: localFirstOrdering{}},
+		{ordering: OrderingLocalFirst, want: localFirstOrdering{}},
+		{ordering: OrderingPrice, want: priceOrdering{}},
+		{ordering: OrderingFIFO, want: fifoOrdering{}},
+		{ordering: OrderingLanes, lanes: lanes, want: newLaneOrdering(lanes)},
+		{ordering: OrderingLanes, fail: true},
+		{ordering: "random", fail: true},
+	}
+	for i, tt := range tests {
+		policy, err := NewOrderingPolicy(&Config{Ordering: tt.ordering, Lanes: tt.lanes})
+		if tt.fail {
+			if err == nil {
+				t.Errorf("test %d: expected error for ordering %q", i, tt.ordering)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %d: failed to create ordering %q: %v", i, tt.ordering, err)
+			continue
+		}
+		if lanes, ok := policy.(*laneOrdering); ok {
+			if len(lanes.lanes) != len(tt.lanes) {
+				t.Errorf("test %d: lane count mismatch: have %d, want %d", i, len(lanes.lanes), len(tt.lanes))
+			}
+		} else if policy != tt.want {
+			t.Errorf("test %d: ordering mismatch: have %T, want %T", i, policy, tt.want)
+		}
+	}
+}
+
+// Tests that the worker fills blocks in the order of the configured policy.
+func TestOrderingPolicies(t *testing.T) {
+	keys := make([]*ecdsa.PrivateKey, 4)
+	addrs := make([]common.Address, len(keys))
+	alloc := make(core.GenesisAlloc)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		addrs[i] = crypto.PubkeyToAddress(keys[i].PublicKey)
+		alloc[addrs[i]] = core.GenesisAccount{Balance: testBankFunds}
+	}
+	// Transactions in arrival order: sender, gas price multiplier and origin.
+	// Account 1 is the only local one.
+	arrivals := []struct {
+		sender int
+		price  int64
+		local  bool
+	}{
+		{sender: 2, price: 2},
+		{sender: 1, price: 3, local: true},
+		{sender: 0, price: 5},
+		{sender: 3, price: 4},
+	}
+	tests := []struct {
+		ordering string
+		lanes    [][]common.Address
+		want     []int // Senders in expected block order
+	}{
+		{ordering: OrderingLocalFirst, want: []int{1, 0, 3, 2}},
+		{ordering: OrderingPrice, want: []int{0, 3, 1, 2}},
+		{ordering: OrderingFIFO, want: []int{2, 1, 0, 3}},
+		{ordering: OrderingLanes, lanes: [][]common.Address{{addrs[3]}, {addrs[2], addrs[1]}}, want: []int{3, 1, 2, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ordering, func(t *testing.T) {
+			config := *testConfig
+			config.Ordering, config.Lanes = tt.ordering, tt.lanes
+			w, b := newFundedTestWorker(t, &config, alloc)
+
+			signer := types.LatestSigner(w.chainConfig)
+			for _, arrival := range arrivals {
+				tx := types.MustSignNewTx(keys[arrival.sender], signer, &types.LegacyTx{
+					To:       &testUserAddress,
+					Value:    big.NewInt(1000),
+					Gas:      params.TxGas,
+					GasPrice: big.NewInt(arrival.price * params.InitialBaseFee),
+				})
+				var err error
+				if arrival.local {
+					err = b.txPool.AddLocal(tx)
+				} else {
+					err = b.txPool.AddRemotesSync([]*types.Transaction{tx})[0]
+				}
+				if err != nil {
+					t.Fatalf("failed to add transaction: %v", err)
+				}
+				time.Sleep(time.Millisecond) // Keep arrival times apart
+			}
+			genesis := b.chain.Genesis()
+			block, _, err := w.getSealingBlock(genesis.Hash(), genesis.Time()+1, testBankAddress, common.Hash{}, nil, false)
+			if err != nil {
+				t.Fatalf("failed to build block: %v", err)
+			}
+			txs := block.Transactions()
+			if len(txs) != len(tt.want) {
+				t.Fatalf("transaction count mismatch: have %d, want %d", len(txs), len(tt.want))
+			}
+			for i, tx := range txs {
+				if from, _ := types.Sender(signer, tx); from != addrs[tt.want[i]] {
+					t.Errorf("transaction %d: sender mismatch: have %x, want %x (account %d)", i, from, addrs[tt.want[i]], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pool
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// API exposes the pool ledger for the RPC interface.
+type API struct {
+	pool *Pool
+}
+
+// RPCBalance is the reward credited to an account.
+type RPCBalance struct {
+	Immature *hexutil.Big `json:"immature"`
+	Matured  *hexutil.Big `json:"matured"`
+}
+
+// RPCPayout is the reward credited to an account for a found block.
+type RPCPayout struct {
+	Account string       `json:"account"`
+	Amount  *hexutil.Big `json:"amount"`
+}
+
+// RPCBlock is a block found by the pool.
+type RPCBlock struct {
+	Number  hexutil.Uint64 `json:"number"`
+	Hash    common.Hash    `json:"hash"`
+	Reward  *hexutil.Big   `json:"reward"`
+	Status  string         `json:"status"`
+	Payouts []RPCPayout    `json:"payouts"`
+}
+
+// RPCStats summarizes the current round of the pool.
+type RPCStats struct {
+	Scheme   string                  `json:"scheme"`
+	Window   hexutil.Uint64          `json:"window"`
+	Maturity hexutil.Uint64          `json:"maturity"`
+	Shares   hexutil.Uint64          `json:"shares"`
+	Accounts map[string]*hexutil.Big `json:"accounts"` // Difficulty contributed in the current round
+}
+
+// GetBalance returns the immature and matured rewards credited to an account.
+func (api *API) GetBalance(account string) *RPCBalance {
+	api.pool.lock.Lock()
+	defer api.pool.lock.Unlock()
+
+	bal := readBalance(api.pool.db, account)
+	return &RPCBalance{Immature: (*hexutil.Big)(bal.Immature), Matured: (*hexutil.Big)(bal.Matured)}
+}
+
+// GetBlocks returns the most recent blocks found by the pool along with their
+// payouts, newest first. A zero count returns all of them.
+func (api *API) GetBlocks(count hexutil.Uint64) []*RPCBlock {
+	api.pool.lock.Lock()
+	defer api.pool.lock.Unlock()
+
+	var res []*RPCBlock
+	for _, block := range readBlocks(api.pool.db, int(count)) {
+		rpcBlock := &RPCBlock{
+			Number:  hexutil.Uint64(block.Number),
+			Hash:    block.Hash,
+			Reward:  (*hexutil.Big)(block.Reward),
+			Status:  block.Status.String(),
+			Payouts: make([]RPCPayout, 0, len(block.Payouts)),
+		}
+		for _, payout := range block.Payouts {
+			rpcBlock.Payouts = append(rpcBlock.Payouts, RPCPayout{Account: payout.Account, Amount: (*hexutil.Big)(payout.Amount)})
+		}
+		res = append(res, rpcBlock)
+	}
+	return res
+}
+
+// GetStats returns the shares submitted in the current round.
+func (api *API) GetStats() *RPCStats {
+	api.pool.lock.Lock()
+	defer api.pool.lock.Unlock()
+
+	m := readMeta(api.pool.db)
+	stats := &RPCStats{
+		Scheme:   api.pool.config.Scheme,
+		Window:   hexutil.Uint64(api.pool.config.Window),
+		Maturity: hexutil.Uint64(api.pool.config.Maturity),
+		Shares:   hexutil.Uint64(m.NextShare - m.RoundStart),
+		Accounts: make(map[string]*hexutil.Big),
+	}
+	for seq := m.RoundStart; seq < m.NextShare; seq++ {
+		s := readShare(api.pool.db, seq)
+		if s == nil {
+			continue
+		}
+		if stats.Accounts[s.Account] == nil {
+			stats.Accounts[s.Account] = new(hexutil.Big)
+		}
+		total := (*big.Int)(stats.Accounts[s.Account])
+		total.Add(total, new(big.Int).SetUint64(s.Difficulty))
+	}
+	return stats
+}
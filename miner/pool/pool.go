@@ -0,0 +1,381 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package pool implements share accounting and reward payouts for mining pools
+// operated directly by a node, on top of the ethash stratum server.
+package pool
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Payout schemes supported by the pool.
+const (
+	SchemePPLNS = "pplns" // Pay per last N shares, N being a multiple of the block difficulty
+	SchemePROP  = "prop"  // Proportional to the shares of the round that found the block
+)
+
+// maxSolutions is the number of block solutions found by stratum miners that
+// are remembered while waiting for the sealed block to be announced.
+const maxSolutions = 64
+
+// Config are the configuration parameters of the pool.
+type Config struct {
+	Enabled  bool   `toml:",omitempty"` // Whether to account shares and payouts at all
+	Scheme   string `toml:",omitempty"` // Payout scheme, "pplns" or "prop"
+	Window   uint64 `toml:",omitempty"` // PPLNS window, as a multiple of the found block's difficulty
+	Maturity uint64 `toml:",omitempty"` // Confirmations before the rewards of a found block are final
+}
+
+// DefaultConfig contains the default settings of the pool.
+var DefaultConfig = Config{
+	Scheme:   SchemePPLNS,
+	Window:   2,
+	Maturity: 60,
+}
+
+// sanitize checks the provided user configurations and changes anything that's
+// unreasonable or unworkable.
+func (config *Config) sanitize() Config {
+	conf := *config
+	if conf.Scheme != SchemePPLNS && conf.Scheme != SchemePROP {
+		log.Warn("Sanitizing invalid pool scheme", "provided", conf.Scheme, "updated", DefaultConfig.Scheme)
+		conf.Scheme = DefaultConfig.Scheme
+	}
+	if conf.Window == 0 {
+		log.Warn("Sanitizing invalid pool window", "provided", conf.Window, "updated", DefaultConfig.Window)
+		conf.Window = DefaultConfig.Window
+	}
+	if conf.Maturity == 0 {
+		log.Warn("Sanitizing invalid pool maturity", "provided", conf.Maturity, "updated", DefaultConfig.Maturity)
+		conf.Maturity = DefaultConfig.Maturity
+	}
+	return conf
+}
+
+// Chain is the subset of the blockchain the pool needs to track found blocks.
+type Chain interface {
+	Config() *params.ChainConfig
+	Engine() consensus.Engine
+	GetHeaderByNumber(number uint64) *types.Header
+	GetReceiptsByHash(hash common.Hash) types.Receipts
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+}
+
+// Pool records the shares submitted by miners and, whenever they find a block,
+// splits the mining earnings of the block among them according to the
+// configured scheme. The rewards stay immature until the block is buried deep
+// enough in the chain.
+type Pool struct {
+	config Config
+	db     ethdb.Database // Pool table within the chain database
+	chain  Chain
+
+	lock      sync.Mutex                          // Protects the ledger against concurrent updates
+	solutions lru.BasicLRU[common.Hash, struct{}] // Seal hashes of the blocks found by stratum miners
+
+	minedSub *event.TypeMuxSubscription
+	headCh   chan core.ChainHeadEvent
+	headSub  event.Subscription
+	wg       sync.WaitGroup
+}
+
+// New creates a pool storing its ledger in a dedicated table of the given
+// database, crediting the blocks announced as mined on the event mux.
+func New(config Config, db ethdb.Database, chain Chain, mux *event.TypeMux) *Pool {
+	p := &Pool{
+		config:    config.sanitize(),
+		db:        rawdb.NewTable(db, tablePrefix),
+		chain:     chain,
+		solutions: lru.NewBasicLRU[common.Hash, struct{}](maxSolutions),
+		headCh:    make(chan core.ChainHeadEvent, 16),
+	}
+	p.minedSub = mux.Subscribe(core.NewMinedBlockEvent{})
+	p.headSub = chain.SubscribeChainHeadEvent(p.headCh)
+
+	p.wg.Add(1)
+	go p.loop()
+
+	log.Info("Mining pool accounting enabled", "scheme", p.config.Scheme, "window", p.config.Window, "maturity", p.config.Maturity)
+	return p
+}
+
+// Stop terminates the event processing of the pool.
+func (p *Pool) Stop() {
+	p.minedSub.Unsubscribe()
+	p.headSub.Unsubscribe()
+	p.wg.Wait()
+}
+
+// loop credits mined blocks and matures their rewards as the chain progresses.
+func (p *Pool) loop() {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case ev, ok := <-p.minedSub.Chan():
+			if !ok {
+				return
+			}
+			if mined, ok := ev.Data.(core.NewMinedBlockEvent); ok {
+				p.blockFound(mined.Block)
+			}
+		case ev := <-p.headCh:
+			p.processHead(ev.Block.NumberU64())
+		case <-p.headSub.Err():
+			return
+		}
+	}
+}
+
+// account returns the account a worker's shares are credited to. Miners usually
+// log in as "account.rig", all rigs of an account share the same balance.
+func account(worker string) string {
+	if i := strings.IndexByte(worker, '.'); i >= 0 {
+		return worker[:i]
+	}
+	return worker
+}
+
+// RecordShare implements ethash.ShareRecorder, storing a share accepted by the
+// stratum server.
+func (p *Pool) RecordShare(worker string, difficulty uint64, number uint64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	m := readMeta(p.db)
+	writeShare(p.db, m.NextShare, &share{
+		Account:    account(worker),
+		Difficulty: difficulty,
+		Number:     number,
+		Time:       uint64(time.Now().Unix()),
+	})
+	m.NextShare++
+	writeMeta(p.db, m)
+}
+
+// RecordSolution implements ethash.ShareRecorder, remembering a share meeting
+// the block target so that the resulting block is credited to the pool.
+func (p *Pool) RecordSolution(sealhash common.Hash) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.solutions.Add(sealhash, struct{}{})
+}
+
+// blockFound splits the mining earnings of a block found by the stratum miners
+// among the shares of the payout window, crediting them as immature. Blocks the
+// node sealed by any other means are ignored.
+func (p *Pool) blockFound(block *types.Block) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if readBlock(p.db, block.NumberU64(), block.Hash()) != nil {
+		return // Already credited
+	}
+	sealhash := p.chain.Engine().SealHash(block.Header())
+	if !p.solutions.Contains(sealhash) {
+		return // Not found by the stratum miners
+	}
+	p.solutions.Remove(sealhash)
+
+	reward, err := p.earnings(block)
+	if err != nil {
+		log.Error("Failed to compute pool block earnings", "number", block.NumberU64(), "hash", block.Hash(), "err", err)
+		return
+	}
+	m := readMeta(p.db)
+
+	// Gather the difficulty contributed by each account within the window
+	start, weights := p.window(m, block.Difficulty())
+	payouts := split(reward, weights)
+
+	writeBlock(p.db, &Block{
+		Number:  block.NumberU64(),
+		Hash:    block.Hash(),
+		Reward:  reward,
+		Status:  StatusImmature,
+		Payouts: payouts,
+	})
+	for _, payout := range payouts {
+		bal := readBalance(p.db, payout.Account)
+		bal.Immature.Add(bal.Immature, payout.Amount)
+		writeBalance(p.db, payout.Account, bal)
+	}
+	// Start a new round and drop the shares preceding this block's window. A
+	// later PPLNS window stops at the pruned shares, even if the difficulty of
+	// its block grew enough for it to reach further back.
+	m.RoundStart = m.NextShare
+	writeMeta(p.db, m)
+	deleteShares(p.db, start)
+
+	log.Info("Pool block found", "number", block.NumberU64(), "hash", block.Hash(), "reward", reward, "accounts", len(payouts))
+}
+
+// earnings returns what the coinbase of the block earned by sealing it: the
+// miner reward of the block, including the bonus for the uncles it includes, and
+// the priority fees paid by its transactions. Transfers into the coinbase made
+// within the block are not mining earnings and aren't paid out.
+func (p *Pool) earnings(block *types.Block) (*big.Int, error) {
+	earned := new(big.Int)
+	for _, reward := range ethash.BlockRewards(p.chain.Config(), block.Header(), block.Uncles()) {
+		if reward.Kind == ethash.RewardMiner {
+			earned.Add(earned, reward.Amount)
+		}
+	}
+	txs := block.Transactions()
+	if len(txs) == 0 {
+		return earned, nil
+	}
+	receipts := p.chain.GetReceiptsByHash(block.Hash())
+	if len(receipts) != len(txs) {
+		return nil, errors.New("missing receipts")
+	}
+	for i, tx := range txs {
+		tip, err := tx.EffectiveGasTip(block.BaseFee())
+		if err != nil {
+			return nil, err
+		}
+		earned.Add(earned, new(big.Int).Mul(tip, new(big.Int).SetUint64(receipts[i].GasUsed)))
+	}
+	return earned, nil
+}
+
+// window returns the sequence number of the first share of the payout window,
+// and the difficulty contributed by each account within the window.
+func (p *Pool) window(m meta, difficulty *big.Int) (uint64, map[string]*big.Int) {
+	weights := make(map[string]*big.Int)
+	credit := func(s *share) {
+		if weights[s.Account] == nil {
+			weights[s.Account] = new(big.Int)
+		}
+		weights[s.Account].Add(weights[s.Account], new(big.Int).SetUint64(s.Difficulty))
+	}
+	if p.config.Scheme == SchemePROP {
+		for seq := m.RoundStart; seq < m.NextShare; seq++ {
+			if s := readShare(p.db, seq); s != nil {
+				credit(s)
+			}
+		}
+		return m.RoundStart, weights
+	}
+	// PPLNS, walk back from the latest share until the window is filled
+	var (
+		limit = new(big.Int).Mul(difficulty, new(big.Int).SetUint64(p.config.Window))
+		total = new(big.Int)
+		seq   = m.NextShare
+	)
+	for seq > 0 && total.Cmp(limit) < 0 {
+		s := readShare(p.db, seq-1)
+		if s == nil {
+			break // Pruned or never recorded, the window can't extend further
+		}
+		seq--
+		credit(s)
+		total.Add(total, new(big.Int).SetUint64(s.Difficulty))
+	}
+	return seq, weights
+}
+
+// split divides the reward proportionally to the weights. The remainder of the
+// integer division goes to the account with the highest weight.
+func split(reward *big.Int, weights map[string]*big.Int) []Payout {
+	total := new(big.Int)
+	accounts := make([]string, 0, len(weights))
+	for acc, weight := range weights {
+		total.Add(total, weight)
+		accounts = append(accounts, acc)
+	}
+	if total.Sign() == 0 {
+		return nil
+	}
+	sort.Strings(accounts)
+
+	var (
+		payouts = make([]Payout, 0, len(accounts))
+		paid    = new(big.Int)
+		top     int
+	)
+	for i, acc := range accounts {
+		amount := new(big.Int).Mul(reward, weights[acc])
+		amount.Div(amount, total)
+		paid.Add(paid, amount)
+		payouts = append(payouts, Payout{Account: acc, Amount: amount})
+
+		if weights[acc].Cmp(weights[accounts[top]]) > 0 {
+			top = i
+		}
+	}
+	payouts[top].Amount.Add(payouts[top].Amount, new(big.Int).Sub(reward, paid))
+	return payouts
+}
+
+// processHead matures the rewards of the blocks buried deep enough under the
+// new head, or voids them if the block was reorged out of the chain.
+func (p *Pool) processHead(head uint64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for _, block := range readImmatureBlocks(p.db) {
+		if block.Number+p.config.Maturity > head {
+			break // Ordered by number, the rest is even younger
+		}
+		header := p.chain.GetHeaderByNumber(block.Number)
+		if header != nil && header.Hash() == block.Hash {
+			block.Status = StatusMatured
+		} else {
+			block.Status = StatusOrphaned
+		}
+		for _, payout := range block.Payouts {
+			bal := readBalance(p.db, payout.Account)
+			bal.Immature.Sub(bal.Immature, payout.Amount)
+			if block.Status == StatusMatured {
+				bal.Matured.Add(bal.Matured, payout.Amount)
+			}
+			writeBalance(p.db, payout.Account, bal)
+		}
+		writeBlock(p.db, block)
+		log.Info("Pool block rewards settled", "number", block.Number, "hash", block.Hash, "status", block.Status)
+	}
+}
+
+// APIs returns the user facing RPC APIs of the pool.
+func (p *Pool) APIs() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "pool",
+			Service:   &API{p},
+		},
+	}
+}
@@ -0,0 +1,317 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pool
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/shared"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+var (
+	// testCoinbase is the etherbase of the blocks found in the tests.
+	testCoinbase = common.HexToAddress("0x1000000000000000000000000000000000000001")
+
+	// testMinerReward is the miner reward of the blocks found in the tests.
+	testMinerReward = shared.GetCurrentEpoch(params.TestChainConfig, 1).MinerReward
+)
+
+// testChain is a canonical chain of headers the pool can check found blocks
+// against, along with the receipts of the blocks created through it.
+type testChain struct {
+	config   *params.ChainConfig
+	engine   consensus.Engine
+	lock     sync.Mutex
+	headers  map[uint64]*types.Header
+	receipts map[common.Hash]types.Receipts
+	feed     event.Feed
+}
+
+func newTestChain() *testChain {
+	return &testChain{
+		config:   params.TestChainConfig,
+		engine:   ethash.NewFaker(),
+		headers:  make(map[uint64]*types.Header),
+		receipts: make(map[common.Hash]types.Receipts),
+	}
+}
+
+func (c *testChain) Config() *params.ChainConfig { return c.config }
+func (c *testChain) Engine() consensus.Engine    { return c.engine }
+
+func (c *testChain) GetHeaderByNumber(number uint64) *types.Header {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.headers[number]
+}
+
+func (c *testChain) GetReceiptsByHash(hash common.Hash) types.Receipts {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.receipts[hash]
+}
+
+func (c *testChain) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return c.feed.Subscribe(ch)
+}
+
+func (c *testChain) setCanonical(header *types.Header) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.headers[header.Number.Uint64()] = header
+}
+
+// newBlock creates a block of the given number and difficulty, with a single
+// transaction paying the given priority fees to the coinbase and transferring
+// some ether to it on top. The extra data allows creating competing blocks at
+// the same height.
+func (c *testChain) newBlock(number uint64, difficulty int64, extra byte, fees *big.Int) *types.Block {
+	header := &types.Header{
+		Coinbase:   testCoinbase,
+		Number:     new(big.Int).SetUint64(number),
+		Difficulty: big.NewInt(difficulty),
+		GasUsed:    1,
+		Extra:      []byte{extra},
+	}
+	var (
+		txs      = []*types.Transaction{types.NewTransaction(0, testCoinbase, big.NewInt(params.Ether), 1, fees, nil)}
+		receipts = []*types.Receipt{{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 1, GasUsed: 1}}
+		block    = types.NewBlock(header, txs, nil, receipts, trie.NewStackTrie(nil))
+	)
+	c.lock.Lock()
+	c.receipts[block.Hash()] = receipts
+	c.lock.Unlock()
+
+	return block
+}
+
+// earned returns the earnings of a block created with the given fees.
+func earned(fees *big.Int) *big.Int {
+	return new(big.Int).Add(testMinerReward, fees)
+}
+
+// newTestPool creates a pool without starting its event loop, the tests drive
+// the ledger directly.
+func newTestPool(config Config) (*Pool, *testChain) {
+	chain := newTestChain()
+	return &Pool{
+		config:    config.sanitize(),
+		db:        rawdb.NewTable(rawdb.NewMemoryDatabase(), tablePrefix),
+		chain:     chain,
+		solutions: lru.NewBasicLRU[common.Hash, struct{}](maxSolutions),
+	}, chain
+}
+
+// mine credits a block to the pool as if it was found by a stratum miner.
+func mine(pool *Pool, chain *testChain, block *types.Block) {
+	pool.RecordSolution(chain.engine.SealHash(block.Header()))
+	pool.blockFound(block)
+}
+
+// Tests that PPLNS pays the last shares up to the window, regardless of rounds.
+func TestPPLNSPayouts(t *testing.T) {
+	pool, chain := newTestPool(Config{Scheme: SchemePPLNS, Window: 2, Maturity: 10})
+
+	// An old share out of the window, then the window itself
+	pool.RecordShare("alice.rig1", 100, 1)
+	pool.RecordShare("alice.rig2", 50, 1)
+	pool.RecordShare("bob", 150, 1)
+
+	fees := big.NewInt(2 * params.GWei)
+	block := chain.newBlock(1, 100, 0, fees)
+	mine(pool, chain, block)
+
+	reward := earned(fees)
+
+	stored := readBlock(pool.db, 1, block.Hash())
+	if stored == nil || stored.Status != StatusImmature {
+		t.Fatalf("found block not stored as immature: %v", stored)
+	}
+	if len(stored.Payouts) != 2 {
+		t.Fatalf("payout count mismatch: have %d, want 2", len(stored.Payouts))
+	}
+	// Window of 200 difficulty: 150 from bob, then alice's 50, alice.rig1 is out
+	want := map[string]*big.Int{
+		"alice": new(big.Int).Div(reward, big.NewInt(4)),
+		"bob":   new(big.Int).Sub(reward, new(big.Int).Div(reward, big.NewInt(4))),
+	}
+	for _, payout := range stored.Payouts {
+		if payout.Amount.Cmp(want[payout.Account]) != 0 {
+			t.Errorf("payout of %s mismatch: have %v, want %v", payout.Account, payout.Amount, want[payout.Account])
+		}
+		if bal := readBalance(pool.db, payout.Account); bal.Immature.Cmp(want[payout.Account]) != 0 || bal.Matured.Sign() != 0 {
+			t.Errorf("balance of %s mismatch: have %v/%v, want %v/0", payout.Account, bal.Immature, bal.Matured, want[payout.Account])
+		}
+	}
+	// Shares outside of the window must have been pruned, the others kept for
+	// the next block even though a new round started
+	if readShare(pool.db, 0) != nil {
+		t.Errorf("share out of the window not pruned")
+	}
+	if readShare(pool.db, 1) == nil || readShare(pool.db, 2) == nil {
+		t.Errorf("shares within the window pruned")
+	}
+	pool.RecordShare("carol", 200, 2)
+	block = chain.newBlock(2, 150, 0, fees)
+	mine(pool, chain, block)
+
+	stored = readBlock(pool.db, 2, block.Hash())
+	if len(stored.Payouts) != 2 {
+		t.Fatalf("payout count mismatch: have %d, want 2", len(stored.Payouts))
+	}
+	for _, payout := range stored.Payouts {
+		if payout.Account == "alice" {
+			t.Errorf("share out of the window paid")
+		}
+	}
+}
+
+// Tests that PROP only pays the shares of the round that found the block.
+func TestPROPPayouts(t *testing.T) {
+	pool, chain := newTestPool(Config{Scheme: SchemePROP, Window: 1, Maturity: 10})
+
+	first := big.NewInt(1000003)
+	pool.RecordShare("alice", 100, 1)
+	pool.RecordShare("bob", 200, 1)
+	mine(pool, chain, chain.newBlock(1, 1000000, 0, first))
+
+	second := big.NewInt(params.Ether)
+	pool.RecordShare("bob", 100, 2)
+	block := chain.newBlock(2, 1, 0, second)
+	mine(pool, chain, block)
+
+	stored := readBlock(pool.db, 2, block.Hash())
+	if len(stored.Payouts) != 1 || stored.Payouts[0].Account != "bob" {
+		t.Fatalf("round payouts mismatch: %v", stored.Payouts)
+	}
+	if stored.Payouts[0].Amount.Cmp(earned(second)) != 0 {
+		t.Fatalf("round reward mismatch: have %v, want %v", stored.Payouts[0].Amount, earned(second))
+	}
+	// The reward must be fully paid out even if it's not evenly divisible
+	bal := readBalance(pool.db, "alice")
+	total := new(big.Int).Add(bal.Immature, readBalance(pool.db, "bob").Immature)
+	total.Sub(total, stored.Payouts[0].Amount)
+	if total.Cmp(earned(first)) != 0 {
+		t.Fatalf("first round payouts mismatch: have %v, want %v", total, earned(first))
+	}
+}
+
+// Tests that only the blocks found by the stratum miners are credited, with the
+// miner reward and the fees of the block, but not the transfers into the coinbase.
+func TestFoundBlockEarnings(t *testing.T) {
+	pool, chain := newTestPool(Config{Scheme: SchemePROP, Maturity: 10})
+
+	// Blocks sealed by other means must not be paid to the stratum miners
+	fees := big.NewInt(params.GWei)
+	pool.RecordShare("alice", 1, 1)
+	block := chain.newBlock(1, 1, 0, fees)
+	pool.blockFound(block)
+	if stored := readBlock(pool.db, 1, block.Hash()); stored != nil {
+		t.Fatalf("foreign block credited: %v", stored)
+	}
+	// Blocks found through stratum pay the reward and the fees only
+	mine(pool, chain, block)
+	if bal := readBalance(pool.db, "alice"); bal.Immature.Cmp(earned(fees)) != 0 {
+		t.Fatalf("immature balance mismatch: have %v, want %v", bal.Immature, earned(fees))
+	}
+	// Blocks without receipts can't be valued and aren't credited
+	pool.RecordShare("alice", 1, 2)
+	block = chain.newBlock(2, 1, 0, fees)
+	delete(chain.receipts, block.Hash())
+	mine(pool, chain, block)
+	if stored := readBlock(pool.db, 2, block.Hash()); stored != nil {
+		t.Fatalf("block without receipts credited: %v", stored)
+	}
+}
+
+// Tests that found blocks mature once buried deep enough, or get voided when
+// reorged out of the chain.
+func TestMaturity(t *testing.T) {
+	pool, chain := newTestPool(Config{Scheme: SchemePROP, Maturity: 10})
+
+	fees := big.NewInt(params.GWei)
+	canonical, orphan := chain.newBlock(1, 1, 0, fees), chain.newBlock(2, 1, 1, fees)
+	chain.setCanonical(canonical.Header())
+	chain.setCanonical(chain.newBlock(2, 1, 2, fees).Header())
+
+	reward := earned(fees)
+
+	pool.RecordShare("alice", 1, 1)
+	mine(pool, chain, canonical)
+	pool.RecordShare("alice", 1, 2)
+	mine(pool, chain, orphan)
+
+	total := new(big.Int).Mul(reward, big.NewInt(2))
+	if bal := readBalance(pool.db, "alice"); bal.Immature.Cmp(total) != 0 {
+		t.Fatalf("immature balance mismatch: have %v, want %v", bal.Immature, total)
+	}
+	// Not deep enough yet, nothing may change
+	pool.processHead(10)
+	if blocks := readImmatureBlocks(pool.db); len(blocks) != 2 {
+		t.Fatalf("immature block count mismatch: have %d, want 2", len(blocks))
+	}
+	// Bury both blocks, one being reorged out
+	pool.processHead(12)
+	if blocks := readImmatureBlocks(pool.db); len(blocks) != 0 {
+		t.Fatalf("immature block count mismatch: have %d, want 0", len(blocks))
+	}
+	if b := readBlock(pool.db, 1, canonical.Hash()); b.Status != StatusMatured {
+		t.Errorf("canonical block status mismatch: have %v, want %v", b.Status, StatusMatured)
+	}
+	if b := readBlock(pool.db, 2, orphan.Hash()); b.Status != StatusOrphaned {
+		t.Errorf("orphaned block status mismatch: have %v, want %v", b.Status, StatusOrphaned)
+	}
+	bal := readBalance(pool.db, "alice")
+	if bal.Immature.Sign() != 0 || bal.Matured.Cmp(reward) != 0 {
+		t.Fatalf("settled balance mismatch: have %v/%v, want 0/%v", bal.Immature, bal.Matured, reward)
+	}
+	// Newest blocks are reported first
+	if blocks := readBlocks(pool.db, 1); len(blocks) != 1 || blocks[0].Number != 2 {
+		t.Fatalf("recent blocks mismatch: %v", blocks)
+	}
+}
+
+// Tests that mined blocks announced on the event mux get credited.
+func TestMinedBlockEvent(t *testing.T) {
+	var (
+		mux   = new(event.TypeMux)
+		chain = newTestChain()
+		pool  = New(Config{Enabled: true}, rawdb.NewMemoryDatabase(), chain, mux)
+	)
+	pool.RecordShare("alice", 1, 1)
+	block := chain.newBlock(1, 1, 0, big.NewInt(params.Ether))
+	pool.RecordSolution(chain.engine.SealHash(block.Header()))
+	mux.Post(core.NewMinedBlockEvent{Block: block})
+	pool.Stop() // Ensure the event was processed
+
+	blocks := (&API{pool}).GetBlocks(0)
+	if len(blocks) != 1 || blocks[0].Hash != block.Hash() {
+		t.Fatalf("mined block not credited: %v", blocks)
+	}
+}
@@ -0,0 +1,291 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pool
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// The pool ledger lives in its own table of the chain database, using the keys
+// below.
+var (
+	tablePrefix = "pool-" // Prefix of the pool table within the chain database
+
+	metaKey           = []byte("meta") // metaKey tracks the share counters
+	sharePrefix       = []byte("s")    // sharePrefix + seq (uint64 big endian) -> share
+	blockPrefix       = []byte("b")    // blockPrefix + num (uint64 big endian) + hash -> block
+	immaturePrefix    = []byte("i")    // immaturePrefix + num (uint64 big endian) + hash -> empty, index of immature blocks
+	balancePrefix     = []byte("w")    // balancePrefix + account -> balance
+	blockKeyLength    = len(blockPrefix) + 8 + common.HashLength
+	shareKeyLength    = len(sharePrefix) + 8
+	immatureKeyOffset = len(immaturePrefix)
+)
+
+// Status is the maturity state of a block found by the pool.
+type Status uint8
+
+const (
+	StatusImmature Status = iota // Found, but not yet deep enough in the chain
+	StatusMatured                // Deep enough in the canonical chain, rewards are final
+	StatusOrphaned               // Reorged out of the canonical chain, rewards are void
+)
+
+// String implements fmt.Stringer.
+func (s Status) String() string {
+	switch s {
+	case StatusImmature:
+		return "immature"
+	case StatusMatured:
+		return "matured"
+	case StatusOrphaned:
+		return "orphaned"
+	default:
+		return "unknown"
+	}
+}
+
+// meta is the persisted share bookkeeping of the pool.
+type meta struct {
+	NextShare  uint64 // Sequence number of the next recorded share
+	RoundStart uint64 // Sequence number of the first share of the current round
+}
+
+// share is a single unit of work contributed by a miner.
+type share struct {
+	Account    string
+	Difficulty uint64
+	Number     uint64 // Height of the block the share was mined for
+	Time       uint64
+}
+
+// Payout is the reward credited to an account for a found block.
+type Payout struct {
+	Account string
+	Amount  *big.Int
+}
+
+// Block is a block found by the pool together with the payouts it yielded.
+type Block struct {
+	Number  uint64
+	Hash    common.Hash
+	Reward  *big.Int
+	Status  Status
+	Payouts []Payout
+}
+
+// Balance is the reward credited to an account, split by maturity.
+type Balance struct {
+	Immature *big.Int
+	Matured  *big.Int
+}
+
+func encodeNumber(number uint64) []byte {
+	enc := make([]byte, 8)
+	binary.BigEndian.PutUint64(enc, number)
+	return enc
+}
+
+func shareKey(seq uint64) []byte {
+	return append(append([]byte{}, sharePrefix...), encodeNumber(seq)...)
+}
+
+func blockKey(number uint64, hash common.Hash) []byte {
+	return append(append(append([]byte{}, blockPrefix...), encodeNumber(number)...), hash.Bytes()...)
+}
+
+func immatureKey(number uint64, hash common.Hash) []byte {
+	return append(append(append([]byte{}, immaturePrefix...), encodeNumber(number)...), hash.Bytes()...)
+}
+
+func balanceKey(account string) []byte {
+	return append(append([]byte{}, balancePrefix...), account...)
+}
+
+// readMeta retrieves the share bookkeeping, zero if none is stored yet.
+func readMeta(db ethdb.KeyValueReader) meta {
+	var m meta
+	if blob, _ := db.Get(metaKey); len(blob) > 0 {
+		if err := rlp.DecodeBytes(blob, &m); err != nil {
+			log.Error("Invalid pool metadata", "err", err)
+		}
+	}
+	return m
+}
+
+// writeMeta stores the share bookkeeping.
+func writeMeta(db ethdb.KeyValueWriter, m meta) {
+	blob, err := rlp.EncodeToBytes(&m)
+	if err != nil {
+		log.Crit("Failed to encode pool metadata", "err", err)
+	}
+	if err := db.Put(metaKey, blob); err != nil {
+		log.Crit("Failed to store pool metadata", "err", err)
+	}
+}
+
+// readShare retrieves the share with the given sequence number.
+func readShare(db ethdb.KeyValueReader, seq uint64) *share {
+	blob, _ := db.Get(shareKey(seq))
+	if len(blob) == 0 {
+		return nil
+	}
+	s := new(share)
+	if err := rlp.DecodeBytes(blob, s); err != nil {
+		log.Error("Invalid pool share", "seq", seq, "err", err)
+		return nil
+	}
+	return s
+}
+
+// writeShare stores the share with the given sequence number.
+func writeShare(db ethdb.KeyValueWriter, seq uint64, s *share) {
+	blob, err := rlp.EncodeToBytes(s)
+	if err != nil {
+		log.Crit("Failed to encode pool share", "err", err)
+	}
+	if err := db.Put(shareKey(seq), blob); err != nil {
+		log.Crit("Failed to store pool share", "err", err)
+	}
+}
+
+// deleteShares removes all shares with a sequence number below the limit.
+func deleteShares(db ethdb.Database, limit uint64) {
+	it := db.NewIterator(sharePrefix, nil)
+	defer it.Release()
+
+	batch := db.NewBatch()
+	for it.Next() {
+		if len(it.Key()) != shareKeyLength {
+			continue
+		}
+		if binary.BigEndian.Uint64(it.Key()[len(sharePrefix):]) >= limit {
+			break
+		}
+		batch.Delete(it.Key())
+	}
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to prune pool shares", "err", err)
+	}
+}
+
+// readBlock retrieves a block found by the pool.
+func readBlock(db ethdb.KeyValueReader, number uint64, hash common.Hash) *Block {
+	blob, _ := db.Get(blockKey(number, hash))
+	if len(blob) == 0 {
+		return nil
+	}
+	b := new(Block)
+	if err := rlp.DecodeBytes(blob, b); err != nil {
+		log.Error("Invalid pool block", "number", number, "hash", hash, "err", err)
+		return nil
+	}
+	return b
+}
+
+// writeBlock stores a block found by the pool, tracking it in the immature
+// index as long as its rewards are not final.
+func writeBlock(db ethdb.KeyValueWriter, b *Block) {
+	blob, err := rlp.EncodeToBytes(b)
+	if err != nil {
+		log.Crit("Failed to encode pool block", "err", err)
+	}
+	if err := db.Put(blockKey(b.Number, b.Hash), blob); err != nil {
+		log.Crit("Failed to store pool block", "err", err)
+	}
+	if b.Status == StatusImmature {
+		err = db.Put(immatureKey(b.Number, b.Hash), nil)
+	} else {
+		err = db.Delete(immatureKey(b.Number, b.Hash))
+	}
+	if err != nil {
+		log.Crit("Failed to update pool maturity index", "err", err)
+	}
+}
+
+// readImmatureBlocks retrieves all blocks whose rewards are not yet final,
+// ordered by number.
+func readImmatureBlocks(db ethdb.Database) []*Block {
+	it := db.NewIterator(immaturePrefix, nil)
+	defer it.Release()
+
+	var blocks []*Block
+	for it.Next() {
+		key := it.Key()[immatureKeyOffset:]
+		if len(key) != 8+common.HashLength {
+			continue
+		}
+		if b := readBlock(db, binary.BigEndian.Uint64(key), common.BytesToHash(key[8:])); b != nil {
+			blocks = append(blocks, b)
+		}
+	}
+	return blocks
+}
+
+// readBlocks retrieves the most recent blocks found by the pool, newest first.
+func readBlocks(db ethdb.Database, limit int) []*Block {
+	it := db.NewIterator(blockPrefix, nil)
+	defer it.Release()
+
+	var blocks []*Block
+	for it.Next() {
+		if len(it.Key()) != blockKeyLength {
+			continue
+		}
+		b := new(Block)
+		if err := rlp.DecodeBytes(it.Value(), b); err != nil {
+			log.Error("Invalid pool block", "key", it.Key(), "err", err)
+			continue
+		}
+		blocks = append(blocks, b)
+	}
+	// Reverse and trim, the iterator returns ascending numbers
+	for i, j := 0, len(blocks)-1; i < j; i, j = i+1, j-1 {
+		blocks[i], blocks[j] = blocks[j], blocks[i]
+	}
+	if limit > 0 && len(blocks) > limit {
+		blocks = blocks[:limit]
+	}
+	return blocks
+}
+
+// readBalance retrieves the rewards credited to an account.
+func readBalance(db ethdb.KeyValueReader, account string) *Balance {
+	bal := &Balance{Immature: new(big.Int), Matured: new(big.Int)}
+	if blob, _ := db.Get(balanceKey(account)); len(blob) > 0 {
+		if err := rlp.DecodeBytes(blob, bal); err != nil {
+			log.Error("Invalid pool balance", "account", account, "err", err)
+		}
+	}
+	return bal
+}
+
+// writeBalance stores the rewards credited to an account.
+func writeBalance(db ethdb.KeyValueWriter, account string, bal *Balance) {
+	blob, err := rlp.EncodeToBytes(bal)
+	if err != nil {
+		log.Crit("Failed to encode pool balance", "err", err)
+	}
+	if err := db.Put(balanceKey(account), blob); err != nil {
+		log.Crit("Failed to store pool balance", "err", err)
+	}
+}
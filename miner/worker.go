@@ -266,6 +266,9 @@ type worker struct {
 	// payload in proof-of-stake stage.
 	recommit time.Duration
 
+	// ordering decides the order pending transactions are included in blocks.
+	ordering OrderingPolicy
+
 	// External functions
 	isLocalBlock func(header *types.Header) bool // Function used to determine whether the specified block is mined by local miner.
 
@@ -341,6 +344,16 @@ func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus
 	}
 	worker.newpayloadTimeout = newpayloadTimeout
 
+	// Select the transaction ordering policy, falling back to the default one.
+	ordering, err := NewOrderingPolicy(worker.config)
+	if err != nil {
+		log.Error("Invalid transaction ordering policy, using default", "err", err)
+		ordering = localFirstOrdering{}
+	} else if worker.config.Ordering != "" {
+		log.Info("Using transaction ordering policy", "policy", worker.config.Ordering, "lanes", len(worker.config.Lanes))
+	}
+	worker.ordering = ordering
+
 	worker.wg.Add(4)
 	go worker.mainLoop()
 	go worker.newWorkLoop(recommit)
@@ -752,7 +765,7 @@ func (w *worker) mainLoop() {
 					acc, _ := types.Sender(w.current.signer, tx)
 					txs[acc] = append(txs[acc], tx)
 				}
-				txset := types.NewTransactionsByPriceAndNonce(w.current.signer, txs, w.current.header.BaseFee)
+				txset := w.ordering.Order(w.current.signer, w.current.header.BaseFee, txs, w.eth.TxPool().Locals())
 				tcount := w.current.tcount
 				w.commitTransactions(w.current, txset, nil)
 				if tcount != w.current.tcount {
@@ -1065,7 +1078,7 @@ func (w *worker) commitTransaction(env *environment, tx *types.Transaction) ([]*
 	return receipt.Logs, nil
 }
 
-func (w *worker) commitTransactions(env *environment, txs *types.TransactionsByPriceAndNonce, interrupt *atomic.Int32) error {
+func (w *worker) commitTransactions(env *environment, txs TransactionSet, interrupt *atomic.Int32) error {
 	gasLimit := env.header.GasLimit
 	if env.gasPool == nil {
 		env.gasPool = new(core.GasPool).AddGas(gasLimit)
@@ -1152,6 +1165,7 @@ type generateParams struct {
 	withdrawals types.Withdrawals // List of withdrawals to include in block.
 	noUncle     bool              // Flag whether the uncle block inclusion is allowed
 	noTxs       bool              // Flag whether an empty block without any transaction is expected
+	pending     bool              // Flag whether the block is kept as a pending task for remote sealing
 }
 
 // prepareWork constructs the sealing task according to the given parameters,
@@ -1237,33 +1251,20 @@ func (w *worker) prepareWork(genParams *generateParams) (*environment, error) {
 	return env, nil
 }
 
-// fillTransactions retrieves the pending transactions from the txpool and fills them
-// into the given sealing block. The transaction selection and ordering strategy can
-// be customized with the plugin in the future.
+// fillTransactions retrieves the bundles and pending transactions from the pools
+// and fills them into the given sealing block, the bundles first and then the
+// transactions in the order decided by the ordering policy.
 func (w *worker) fillTransactions(interrupt *atomic.Int32, env *environment) error {
-	// Split the pending transactions into locals and remotes
-	// Fill the block with all available pending transactions.
-	pending := w.eth.TxPool().Pending(true)
-	localTxs, remoteTxs := make(map[common.Address]types.Transactions), pending
-	for _, account := range w.eth.TxPool().Locals() {
-		if txs := remoteTxs[account]; len(txs) > 0 {
-			delete(remoteTxs, account)
-			localTxs[account] = txs
-		}
-	}
-	if len(localTxs) > 0 {
-		txs := types.NewTransactionsByPriceAndNonce(env.signer, localTxs, env.header.BaseFee)
-		if err := w.commitTransactions(env, txs, interrupt); err != nil {
-			return err
-		}
+	// Include the most profitable bundles ahead of the regular transactions
+	if err := w.commitBundles(env, interrupt); err != nil {
+		return err
 	}
-	if len(remoteTxs) > 0 {
-		txs := types.NewTransactionsByPriceAndNonce(env.signer, remoteTxs, env.header.BaseFee)
-		if err := w.commitTransactions(env, txs, interrupt); err != nil {
-			return err
-		}
+	pending := w.eth.TxPool().Pending(true)
+	if len(pending) == 0 {
+		return nil
 	}
-	return nil
+	txs := w.ordering.Order(env.signer, env.header.BaseFee, pending, w.eth.TxPool().Locals())
+	return w.commitTransactions(env, txs, interrupt)
 }
 
 // generateWork generates a sealing block based on the given parameters.
@@ -1290,6 +1291,16 @@ func (w *worker) generateWork(params *generateParams) (*types.Block, *big.Int, e
 	if err != nil {
 		return nil, nil, err
 	}
+	if params.pending {
+		w.pendingMu.Lock()
+		w.pendingTasks[w.engine.SealHash(block.Header())] = &task{
+			receipts:  work.receipts,
+			state:     work.state,
+			block:     block,
+			createdAt: time.Now(),
+		}
+		w.pendingMu.Unlock()
+	}
 	return block, totalFees(block, work.receipts), nil
 }
 
@@ -1494,6 +1505,33 @@ func (w *worker) getSealingBlock(parent common.Hash, timestamp uint64, coinbase
 	}
 }
 
+// getPayoutBlock generates a sealing block on top of the given parent, crediting
+// the rewards to the given coinbase. The block is kept as a pending task, so a
+// solution submitted by a remote miner is written to the chain just like the
+// locally generated work.
+func (w *worker) getPayoutBlock(parent common.Hash, timestamp uint64, coinbase common.Address) (*types.Block, error) {
+	req := &getWorkReq{
+		params: &generateParams{
+			timestamp:  timestamp,
+			forceTime:  true,
+			parentHash: parent,
+			coinbase:   coinbase,
+			pending:    true,
+		},
+		result: make(chan *newPayloadResult, 1),
+	}
+	select {
+	case w.getWorkCh <- req:
+		result := <-req.result
+		if result.err != nil {
+			return nil, result.err
+		}
+		return result.block, nil
+	case <-w.exitCh:
+		return nil, errors.New("miner closed")
+	}
+}
+
 // isTTDReached returns the indicator if the given block has reached the total
 // terminal difficulty for The Merge transition.
 func (w *worker) isTTDReached(header *types.Header) bool {
@@ -111,6 +111,7 @@ func init() {
 type testWorkerBackend struct {
 	db         ethdb.Database
 	txPool     *txpool.TxPool
+	bundlePool *txpool.BundlePool
 	chain      *core.BlockChain
 	genesis    *core.Genesis
 	uncleBlock *types.Block
@@ -136,6 +137,7 @@ func newTestWorkerBackend(t *testing.T, chainConfig *params.ChainConfig, engine
 	if err != nil {
 		t.Fatalf("core.NewBlockChain failed: %v", err)
 	}
+	bundlePool := txpool.NewBundlePool(chainConfig, chain)
 	txpool := txpool.NewTxPool(testTxPoolConfig, chainConfig, chain)
 
 	// Generate a small n-block chain and an uncle block for it
@@ -162,13 +164,15 @@ func newTestWorkerBackend(t *testing.T, chainConfig *params.ChainConfig, engine
 		db:         db,
 		chain:      chain,
 		txPool:     txpool,
+		bundlePool: bundlePool,
 		genesis:    gspec,
 		uncleBlock: uncle,
 	}
 }
 
-func (b *testWorkerBackend) BlockChain() *core.BlockChain { return b.chain }
-func (b *testWorkerBackend) TxPool() *txpool.TxPool       { return b.txPool }
+func (b *testWorkerBackend) BlockChain() *core.BlockChain   { return b.chain }
+func (b *testWorkerBackend) TxPool() *txpool.TxPool         { return b.txPool }
+func (b *testWorkerBackend) BundlePool() *txpool.BundlePool { return b.bundlePool }
 func (b *testWorkerBackend) StateAtBlock(block *types.Block, reexec uint64, base *state.StateDB, checkLive bool, preferDisk bool) (statedb *state.StateDB, err error) {
 	return nil, errors.New("not supported")
 }
@@ -208,6 +212,35 @@ func newTestWorker(t *testing.T, chainConfig *params.ChainConfig, engine consens
 	return w, backend
 }
 
+// newFundedTestWorker creates a worker on top of a fresh ethash chain with the
+// given genesis allocation, which is torn down when the test ends.
+func newFundedTestWorker(t *testing.T, config *Config, alloc core.GenesisAlloc) (*worker, *testWorkerBackend) {
+	var (
+		db          = rawdb.NewMemoryDatabase()
+		engine      = ethash.NewFaker()
+		chainConfig = params.AllEthashProtocolChanges
+		gspec       = &core.Genesis{Config: chainConfig, Alloc: alloc}
+	)
+	chain, err := core.NewBlockChain(db, nil, gspec, nil, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	backend := &testWorkerBackend{
+		db:         db,
+		chain:      chain,
+		txPool:     txpool.NewTxPool(testTxPoolConfig, chainConfig, chain),
+		bundlePool: txpool.NewBundlePool(chainConfig, chain),
+		genesis:    gspec,
+	}
+	w := newWorker(config, chainConfig, engine, backend, new(event.TypeMux), nil, false)
+	t.Cleanup(func() {
+		w.close()
+		backend.txPool.Stop()
+		chain.Stop()
+	})
+	return w, backend
+}
+
 func TestGenerateBlockAndImportEthash(t *testing.T) {
 	testGenerateBlockAndImport(t, false)
 }
@@ -664,3 +697,35 @@ func testGetSealingWork(t *testing.T, chainConfig *params.ChainConfig, engine co
 		}
 	}
 }
+
+// Tests that blocks built for remote payout addresses credit the given coinbase
+// and are kept as pending tasks, so their remote solutions can be imported.
+func TestGetPayoutBlock(t *testing.T) {
+	engine := ethash.NewFaker()
+	defer engine.Close()
+
+	w, b := newTestWorker(t, ethashChainConfig, engine, rawdb.NewMemoryDatabase(), 0)
+	defer w.close()
+
+	var (
+		parent    = b.chain.CurrentBlock()
+		payout    = common.HexToAddress("0xdeadbeef")
+		timestamp = parent.Time + 10
+	)
+	block, err := w.getPayoutBlock(parent.Hash(), timestamp, payout)
+	if err != nil {
+		t.Fatalf("failed to build payout block: %v", err)
+	}
+	if block.Coinbase() != payout {
+		t.Errorf("coinbase mismatch: have %x, want %x", block.Coinbase(), payout)
+	}
+	if block.ParentHash() != parent.Hash() || block.Time() != timestamp {
+		t.Errorf("payout block not built on the requested parent and time")
+	}
+	w.pendingMu.RLock()
+	task := w.pendingTasks[engine.SealHash(block.Header())]
+	w.pendingMu.RUnlock()
+	if task == nil || task.block != block {
+		t.Fatalf("payout block not kept as pending task")
+	}
+}
@@ -63,6 +63,12 @@ var GoerliBootnodes = []string{
 	"enode://d2b720352e8216c9efc470091aa91ddafc53e222b32780f505c817ceef69e01d5b0b0797b69db254c586f493872352f5a022b4d8479a00fc92ec55f9ad46a27e@88.99.70.182:30303",
 }
 
+// LibertyBootnodes are the enode URLs of the P2P bootstrap nodes running on the
+// Liberty Project network.
+var LibertyBootnodes = []string{
+	"enode://dc6a8df6a883eee5721aee83d3e89be69000b69b532954641a9e6c6fa1d3bedade9b2b481514a2d75738903c0fddb58ae839d16dbcc7b93d98b1b461ff9e4463@65.109.20.251:40404",
+}
+
 var V5Bootnodes = []string{
 	// Teku team's bootnode
 	"enr:-KG4QOtcP9X1FbIMOe17QNMKqDxCpm14jcX5tiOE4_TyMrFqbmhPZHK_ZPG2Gxb1GE2xdtodOfx9-cgvNtxnRyHEmC0ghGV0aDKQ9aX9QgAAAAD__________4JpZIJ2NIJpcIQDE8KdiXNlY3AyNTZrMaEDhpehBDbZjM_L9ek699Y7vhUJ-eAdMyQW_Fil522Y0fODdGNwgiMog3VkcIIjKA",
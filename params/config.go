@@ -20,6 +20,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"math/big"
+	"sort"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -396,6 +397,35 @@ type CheckpointOracleConfig struct {
 	Threshold uint64           `json:"threshold"`
 }
 
+// finalityPrefix sets the finality votes of the oracle signers apart from their
+// checkpoint votes, so that signatures can't be replayed between the two.
+var finalityPrefix = []byte("finality")
+
+// FinalityHash returns the hash the oracle signers sign to declare the block of
+// the given number and hash final. Like the checkpoint votes it is EIP-191 data
+// with the oracle contract as intended validator.
+func (c *CheckpointOracleConfig) FinalityHash(number uint64, hash common.Hash) common.Hash {
+	var num [8]byte
+	binary.BigEndian.PutUint64(num[:], number)
+
+	w := sha3.NewLegacyKeccak256()
+	w.Write([]byte{0x19, 0x00})
+	w.Write(c.Address[:])
+	w.Write(finalityPrefix)
+	w.Write(num[:])
+	w.Write(hash[:])
+
+	var h common.Hash
+	w.Sum(h[:0])
+	return h
+}
+
+// FinalBlock returns the number and hash of the block a trusted checkpoint makes
+// final, the head of its section.
+func (c *TrustedCheckpoint) FinalBlock() (uint64, common.Hash) {
+	return (c.SectionIndex+1)*CHTFrequency - 1, c.SectionHead
+}
+
 // ChainConfig is the core config which determines the blockchain settings.
 //
 // ChainConfig is stored in the database on a per block basis. This means
@@ -443,10 +473,63 @@ type ChainConfig struct {
 	// Various consensus engines
 	Ethash *EthashConfig `json:"ethash,omitempty"`
 	Clique *CliqueConfig `json:"clique,omitempty"`
+
+	// Liberty holds the chain specific reward schedule. If nil, the default
+	// schedule of the Liberty Project network is used.
+	Liberty *LibertyConfig `json:"liberty,omitempty"`
 }
 
 // EthashConfig is the consensus engine configs for proof-of-work based sealing.
-type EthashConfig struct{}
+type EthashConfig struct {
+	// Iterations schedules the number of Blake3 rounds a seal is computed with,
+	// ordered by activation block. Blocks before the first entry, or all blocks
+	// if none are given, use DefaultBlake3Iterations.
+	Iterations []EthashIterations `json:"iterations,omitempty"`
+}
+
+// EthashIterations is the Blake3 round count in effect from a fork block up to
+// the block of the next entry.
+type EthashIterations struct {
+	Block      uint64 `json:"block"`
+	Iterations uint64 `json:"iterations"`
+}
+
+// Blake3Iterations returns the number of Blake3 rounds required by the seal of
+// the block with the given number. It is safe to call on a nil config.
+func (c *EthashConfig) Blake3Iterations(num *big.Int) uint64 {
+	if c != nil {
+		for i := len(c.Iterations) - 1; i >= 0; i-- {
+			if isBlockForked(new(big.Int).SetUint64(c.Iterations[i].Block), num) {
+				return c.Iterations[i].Iterations
+			}
+		}
+	}
+	return DefaultBlake3Iterations
+}
+
+// CheckIterations verifies that the iteration schedule is ordered by strictly
+// ascending blocks and that no fork disables the proof-of-work.
+func (c *EthashConfig) CheckIterations() error {
+	for i, rule := range c.Iterations {
+		if rule.Iterations == 0 {
+			return fmt.Errorf("ethash iterations at block %d must be positive", rule.Block)
+		}
+		if i > 0 && rule.Block <= c.Iterations[i-1].Block {
+			return fmt.Errorf("ethash iterations at block %d, after iterations at block %d", rule.Block, c.Iterations[i-1].Block)
+		}
+	}
+	return nil
+}
+
+// ForkBlocks returns the block numbers at which the proof-of-work parameters
+// change.
+func (c *EthashConfig) ForkBlocks() []uint64 {
+	var forks []uint64
+	for _, rule := range c.Iterations {
+		forks = append(forks, rule.Block)
+	}
+	return forks
+}
 
 // String implements the stringer interface, returning the consensus engine details.
 func (c *EthashConfig) String() string {
@@ -759,6 +842,21 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 			lastFork = cur
 		}
 	}
+	// The proof-of-work parameters, reward epochs and recipients are scheduled
+	// by block too, ensure they're sane
+	if c.Ethash != nil {
+		if err := c.Ethash.CheckIterations(); err != nil {
+			return err
+		}
+	}
+	if c.Liberty != nil {
+		if err := c.Liberty.CheckEpochs(); err != nil {
+			return err
+		}
+		if err := c.Liberty.CheckRecipients(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -827,6 +925,46 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headNumber *big.Int,
 	if isForkTimestampIncompatible(c.PragueTime, newcfg.PragueTime, headTimestamp) {
 		return newTimestampCompatError("Prague fork timestamp", c.PragueTime, newcfg.PragueTime)
 	}
+	if diverged := libertyScheduleDivergence(c.LibertySchedule(), newcfg.LibertySchedule()); isBlockForked(diverged, headNumber) {
+		return newBlockCompatError("Liberty reward schedule", diverged, diverged)
+	}
+	if diverged := libertyRecipientsDivergence(c.LibertySchedule(), newcfg.LibertySchedule()); isBlockForked(diverged, headNumber) {
+		return newBlockCompatError("Liberty reward recipients", diverged, diverged)
+	}
+	if diverged := ethashIterationsDivergence(c.Ethash, newcfg.Ethash); isBlockForked(diverged, headNumber) {
+		return newBlockCompatError("Ethash iterations", diverged, diverged)
+	}
+	if cl, nl := c.LibertySchedule(), newcfg.LibertySchedule(); isForkBlockIncompatible(cl.UncleRewardBlock, nl.UncleRewardBlock, headNumber) {
+		return newBlockCompatError("Liberty uncle reward fork block", cl.UncleRewardBlock, nl.UncleRewardBlock)
+	} else if cl.IsUncleRewards(headNumber) && cl.NephewDivisor() != nl.NephewDivisor() {
+		return newBlockCompatError("Liberty nephew reward divisor", cl.UncleRewardBlock, nl.UncleRewardBlock)
+	}
+	if cl, nl := c.LibertySchedule(), newcfg.LibertySchedule(); isForkBlockIncompatible(cl.DifficultyBlock, nl.DifficultyBlock, headNumber) {
+		return newBlockCompatError("Liberty difficulty fork block", cl.DifficultyBlock, nl.DifficultyBlock)
+	} else if cl.IsLibertyDifficulty(headNumber) && (cl.BlockTime() != nl.BlockTime() || cl.Window() != nl.Window()) {
+		return newBlockCompatError("Liberty difficulty parameters", cl.DifficultyBlock, nl.DifficultyBlock)
+	}
+	return nil
+}
+
+// ethashIterationsDivergence returns the lowest block number from which the two
+// configs require a different number of Blake3 rounds, or nil if they agree.
+func ethashIterationsDivergence(a, b *EthashConfig) *big.Int {
+	boundaries := []uint64{0}
+	if a != nil {
+		boundaries = append(boundaries, a.ForkBlocks()...)
+	}
+	if b != nil {
+		boundaries = append(boundaries, b.ForkBlocks()...)
+	}
+	sort.Slice(boundaries, func(i, j int) bool { return boundaries[i] < boundaries[j] })
+
+	for _, number := range boundaries {
+		num := new(big.Int).SetUint64(number)
+		if a.Blake3Iterations(num) != b.Blake3Iterations(num) {
+			return num
+		}
+	}
 	return nil
 }
 
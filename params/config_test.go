@@ -22,6 +22,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
 )
 
@@ -109,7 +110,77 @@ func TestCheckCompatible(t *testing.T) {
 				RewindToTime: 9,
 			},
 		},
+		{
+			stored:    &ChainConfig{},
+			new:       &ChainConfig{Liberty: testLibertyConfig(1, 100)},
+			headBlock: 99,
+			wantErr:   nil,
+		},
+		{
+			stored:    &ChainConfig{},
+			new:       &ChainConfig{Liberty: testLibertyConfig(1, 100)},
+			headBlock: 100,
+			wantErr: &ConfigCompatError{
+				What:          "Liberty reward schedule",
+				StoredBlock:   big.NewInt(100),
+				NewBlock:      big.NewInt(100),
+				RewindToBlock: 99,
+			},
+		},
+	}
+
+	rotated := &LibertyConfig{
+		Epochs: DefaultLibertyConfig.Epochs,
+		Recipients: []LibertyRecipientSet{
+			DefaultLibertyConfig.Recipients[0],
+			{Block: 50, DevFund: []LibertyRecipient{{Address: common.Address{0x01}, Weight: 1}}},
+		},
 	}
+	tests = append(tests, []test{
+		{stored: &ChainConfig{}, new: &ChainConfig{Liberty: rotated}, headBlock: 49, wantErr: nil},
+		{
+			stored:    &ChainConfig{},
+			new:       &ChainConfig{Liberty: rotated},
+			headBlock: 50,
+			wantErr: &ConfigCompatError{
+				What:          "Liberty reward recipients",
+				StoredBlock:   big.NewInt(50),
+				NewBlock:      big.NewInt(50),
+				RewindToBlock: 49,
+			},
+		},
+	}...)
+
+	asert := func(block int64, target uint64) *ChainConfig {
+		return &ChainConfig{Liberty: &LibertyConfig{Epochs: DefaultLibertyConfig.Epochs, DifficultyBlock: big.NewInt(block), TargetBlockTime: target}}
+	}
+	tests = append(tests, []test{
+		{stored: asert(30, 0), new: asert(40, 0), headBlock: 29, wantErr: nil},
+		{stored: asert(30, 0), new: asert(30, 15), headBlock: 29, wantErr: nil},
+		{stored: asert(30, 0), new: asert(30, DefaultTargetBlockTime), headBlock: 50, wantErr: nil},
+		{
+			stored:    asert(30, 0),
+			new:       asert(40, 0),
+			headBlock: 30,
+			wantErr: &ConfigCompatError{
+				What:          "Liberty difficulty fork block",
+				StoredBlock:   big.NewInt(30),
+				NewBlock:      big.NewInt(40),
+				RewindToBlock: 29,
+			},
+		},
+		{
+			stored:    asert(30, 0),
+			new:       asert(30, 15),
+			headBlock: 30,
+			wantErr: &ConfigCompatError{
+				What:          "Liberty difficulty parameters",
+				StoredBlock:   big.NewInt(30),
+				NewBlock:      big.NewInt(30),
+				RewindToBlock: 29,
+			},
+		},
+	}...)
 
 	for _, test := range tests {
 		err := test.stored.CheckCompatible(test.new, test.headBlock, test.headTimestamp)
@@ -136,3 +207,67 @@ func TestConfigRules(t *testing.T) {
 		t.Errorf("expected %v to be shanghai", stamp)
 	}
 }
+
+// testLibertyConfig creates a reward schedule with an epoch starting at each of
+// the given blocks, every one of them paying out a different miner reward.
+func testLibertyConfig(starts ...uint64) *LibertyConfig {
+	config := new(LibertyConfig)
+	for i, start := range starts {
+		config.Epochs = append(config.Epochs, DefaultLibertyConfig.Epochs[i])
+		config.Epochs[i].StartBlock = start
+	}
+	return config
+}
+
+func TestCheckLibertyEpochs(t *testing.T) {
+	tests := []struct {
+		config  *LibertyConfig
+		wantErr bool
+	}{
+		{config: DefaultLibertyConfig},
+		{config: testLibertyConfig(0, 10, 20)},
+		{config: new(LibertyConfig), wantErr: true},
+		{config: testLibertyConfig(1, 10, 10), wantErr: true},
+		{config: testLibertyConfig(1, 20, 10), wantErr: true},
+		{config: &LibertyConfig{Epochs: []LibertyEpoch{{Name: "Broken", MinerReward: big.NewInt(1), DevFund: big.NewInt(1)}}}, wantErr: true},
+		{config: &LibertyConfig{Epochs: []LibertyEpoch{{Name: "Negative", MinerReward: big.NewInt(-1), StakingReward: new(big.Int), DevFund: new(big.Int)}}}, wantErr: true},
+	}
+	for i, test := range tests {
+		if err := test.config.CheckEpochs(); (err != nil) != test.wantErr {
+			t.Errorf("test %d: error mismatch: have %v, want error %v", i, err, test.wantErr)
+		}
+	}
+	// The epoch lookup must resolve the boundaries to the right epochs
+	schedule := testLibertyConfig(1, 10, 20)
+	for number, want := range map[uint64]string{0: "Freedom", 1: "Freedom", 9: "Freedom", 10: "Unity", 19: "Unity", 20: "Justice", 1000: "Justice"} {
+		if have := schedule.EpochAt(number).Name; have != want {
+			t.Errorf("block %d: epoch mismatch: have %s, want %s", number, have, want)
+		}
+	}
+}
+
+func TestCheckLibertyRecipients(t *testing.T) {
+	var (
+		alice = common.Address{0x01}
+		bob   = common.Address{0x02}
+	)
+	tests := []struct {
+		sets    []LibertyRecipientSet
+		wantErr bool
+	}{
+		{sets: nil},
+		{sets: DefaultLibertyConfig.Recipients},
+		{sets: []LibertyRecipientSet{{Block: 0}, {Block: 10, DevFund: []LibertyRecipient{{Address: alice, Weight: 1}, {Address: bob, Weight: 3}}}}},
+		{sets: []LibertyRecipientSet{{Block: 1}}, wantErr: true},
+		{sets: []LibertyRecipientSet{{Block: 0}, {Block: 0}}, wantErr: true},
+		{sets: []LibertyRecipientSet{{Block: 0}, {Block: 20}, {Block: 10}}, wantErr: true},
+		{sets: []LibertyRecipientSet{{Block: 0, Staking: []LibertyRecipient{{Address: alice, Weight: 0}}}}, wantErr: true},
+		{sets: []LibertyRecipientSet{{Block: 0, DevFund: []LibertyRecipient{{Address: alice, Weight: 1}, {Address: alice, Weight: 2}}}}, wantErr: true},
+	}
+	for i, test := range tests {
+		config := &LibertyConfig{Epochs: DefaultLibertyConfig.Epochs, Recipients: test.sets}
+		if err := config.CheckRecipients(); (err != nil) != test.wantErr {
+			t.Errorf("test %d: error mismatch: have %v, want error %v", i, err, test.wantErr)
+		}
+	}
+}
@@ -0,0 +1,378 @@
+// Copyright 2023 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package params
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultNephewRewardDivisor is the default divisor of the miner reward paid to
+// a block for every uncle it includes, matching Ethereum's 1/32.
+const DefaultNephewRewardDivisor = 32
+
+const (
+	// DefaultTargetBlockTime is the default number of seconds the Liberty
+	// difficulty adjustment aims to keep between consecutive blocks.
+	DefaultTargetBlockTime = 13
+
+	// DefaultDifficultyWindow is the default number of blocks a chain has to
+	// run ahead of (or behind) its schedule for the Liberty difficulty
+	// adjustment to double (or halve) the difficulty.
+	DefaultDifficultyWindow = 288
+)
+
+// LibertyStakingAddress is the address the staking reward distributor contract
+// is predeployed at in the genesis block of new Liberty networks.
+var LibertyStakingAddress = common.HexToAddress("0x0000000000000000000000000000000000001000")
+
+// DefaultLibertyConfig is the reward schedule used by chains whose genesis does
+// not carry a liberty section. It matches the table the network launched with.
+var DefaultLibertyConfig = &LibertyConfig{
+	Epochs: []LibertyEpoch{
+		{Name: "Freedom", StartBlock: 1, MinerReward: coinsToWei("7.5"), StakingReward: coinsToWei("0.0"), DevFund: coinsToWei("1.5")},
+		{Name: "Unity", StartBlock: 500001, MinerReward: coinsToWei("5.8"), StakingReward: coinsToWei("1.7"), DevFund: coinsToWei("1.5")},
+		{Name: "Justice", StartBlock: 1050001, MinerReward: coinsToWei("5.1"), StakingReward: coinsToWei("2.2"), DevFund: coinsToWei("1.2")},
+		{Name: "Equality", StartBlock: 1650001, MinerReward: coinsToWei("4.2"), StakingReward: coinsToWei("2.8"), DevFund: coinsToWei("1.0")},
+		{Name: "Prosperity", StartBlock: 2300001, MinerReward: coinsToWei("3.0"), StakingReward: coinsToWei("3.6"), DevFund: coinsToWei("0.9")},
+		{Name: "Integrity", StartBlock: 3300001, MinerReward: coinsToWei("3.2"), StakingReward: coinsToWei("3.0"), DevFund: coinsToWei("0.75")},
+		{Name: "Valor", StartBlock: 4400001, MinerReward: coinsToWei("3.4"), StakingReward: coinsToWei("2.5"), DevFund: coinsToWei("0.6")},
+		{Name: "Wisdom", StartBlock: 5700001, MinerReward: coinsToWei("3.7"), StakingReward: coinsToWei("1.8"), DevFund: coinsToWei("0.5")},
+		{Name: "Peace", StartBlock: 7500001, MinerReward: coinsToWei("3.8"), StakingReward: coinsToWei("1.3"), DevFund: coinsToWei("0.4")},
+		{Name: "Legacy", StartBlock: 9400001, MinerReward: coinsToWei("3.6"), StakingReward: coinsToWei("1.0"), DevFund: coinsToWei("0.4")},
+		{Name: "Finality", StartBlock: 11400001, MinerReward: coinsToWei("3.3"), StakingReward: coinsToWei("0.8"), DevFund: coinsToWei("0.4")},
+	},
+	Recipients: []LibertyRecipientSet{
+		{
+			Block: 0,
+			DevFund: []LibertyRecipient{
+				{Address: common.HexToAddress("0x8c80A3F122Ea3e9E9b863b8535d33F3B96eE1C92"), Weight: 1},
+				{Address: common.HexToAddress("0x2357EfDB1107eA9a316A32E33321FF405Eaff788"), Weight: 1},
+				{Address: common.HexToAddress("0xE951BA28D6945AF6798D2e9463fec3e207A6CC06"), Weight: 1},
+			},
+			Staking: []LibertyRecipient{
+				{Address: common.HexToAddress("0xc92Ca93e847CD42FD824eD17e29Bc9cb96417C08"), Weight: 1},
+				{Address: common.HexToAddress("0xBE2c7A73BAE39B92320e932E12b91D8aBC28AfE3"), Weight: 1},
+			},
+		},
+	},
+}
+
+// LibertyConfig contains the Liberty Project specific consensus parameters
+// on top of the ethash proof-of-work engine.
+type LibertyConfig struct {
+	Epochs     []LibertyEpoch        `json:"epochs"`               // Block reward schedule, ordered by start block
+	Recipients []LibertyRecipientSet `json:"recipients,omitempty"` // Dev-fund and staking recipients, ordered by activation block
+
+	UncleRewardBlock    *big.Int `json:"uncleRewardBlock,omitempty"`    // Uncle and nephew reward switch block (nil = no fork, 0 = already activated)
+	NephewRewardDivisor uint64   `json:"nephewRewardDivisor,omitempty"` // Share of the miner reward paid per included uncle (0 = default of 32)
+
+	DifficultyBlock  *big.Int `json:"difficultyBlock,omitempty"`  // Liberty difficulty adjustment switch block (nil = no fork, 0 = already activated)
+	TargetBlockTime  uint64   `json:"targetBlockTime,omitempty"`  // Seconds targeted between blocks (0 = default of 13)
+	DifficultyWindow uint64   `json:"difficultyWindow,omitempty"` // Blocks of schedule drift doubling or halving the difficulty (0 = default of 288)
+}
+
+// LibertyEpoch is a single entry of the block reward schedule. An epoch is in
+// effect from its start block up to the start block of the next epoch.
+type LibertyEpoch struct {
+	Name          string   `json:"name"`
+	StartBlock    uint64   `json:"startBlock"`
+	MinerReward   *big.Int `json:"minerReward"`   // Reward in wei credited to the block coinbase
+	StakingReward *big.Int `json:"stakingReward"` // Reward in wei split between the staking recipients
+	DevFund       *big.Int `json:"devFund"`       // Reward in wei split between the dev-fund recipients
+}
+
+// LibertyRecipientSet is the set of addresses receiving the dev-fund and staking
+// shares of the block reward. A set is in effect from its activation block up to
+// the activation block of the next set, allowing recipients to be rotated by a
+// scheduled fork.
+type LibertyRecipientSet struct {
+	Block   uint64             `json:"block"`   // Activation block of the set
+	DevFund []LibertyRecipient `json:"devFund"` // Recipients of the dev-fund share
+	Staking []LibertyRecipient `json:"staking"` // Recipients of the staking share
+}
+
+// LibertyRecipient is a single reward recipient. Rewards are split between the
+// recipients of a set proportionally to their weights.
+type LibertyRecipient struct {
+	Address common.Address `json:"address"`
+	Weight  uint64         `json:"weight"`
+}
+
+// TotalReward returns the sum of all rewards issued by a block in this epoch.
+func (e *LibertyEpoch) TotalReward() *big.Int {
+	total := new(big.Int).Add(e.MinerReward, e.StakingReward)
+	return total.Add(total, e.DevFund)
+}
+
+// EpochAt returns the reward epoch in effect at the given block number. Blocks
+// preceding the first epoch are attributed to the first epoch. Nil is only
+// returned if the schedule is empty.
+func (c *LibertyConfig) EpochAt(number uint64) *LibertyEpoch {
+	for i := len(c.Epochs) - 1; i >= 0; i-- {
+		if number >= c.Epochs[i].StartBlock {
+			return &c.Epochs[i]
+		}
+	}
+	if len(c.Epochs) == 0 {
+		return nil
+	}
+	return &c.Epochs[0]
+}
+
+// CheckEpochs verifies that the reward schedule is well formed: it must contain
+// at least one epoch, every amount must be set and non-negative, and the start
+// blocks must be strictly ascending so that epochs neither overlap nor appear
+// out of order.
+func (c *LibertyConfig) CheckEpochs() error {
+	if len(c.Epochs) == 0 {
+		return fmt.Errorf("liberty reward schedule has no epochs")
+	}
+	for i, epoch := range c.Epochs {
+		for _, amount := range []struct {
+			name  string
+			value *big.Int
+		}{
+			{"minerReward", epoch.MinerReward},
+			{"stakingReward", epoch.StakingReward},
+			{"devFund", epoch.DevFund},
+		} {
+			if amount.value == nil {
+				return fmt.Errorf("liberty epoch %q missing %s", epoch.Name, amount.name)
+			}
+			if amount.value.Sign() < 0 {
+				return fmt.Errorf("liberty epoch %q has negative %s: %v", epoch.Name, amount.name, amount.value)
+			}
+		}
+		if i == 0 {
+			continue
+		}
+		prev := c.Epochs[i-1]
+		switch {
+		case epoch.StartBlock == prev.StartBlock:
+			return fmt.Errorf("liberty epoch %q overlaps epoch %q at block %d", epoch.Name, prev.Name, epoch.StartBlock)
+		case epoch.StartBlock < prev.StartBlock:
+			return fmt.Errorf("liberty epoch %q starts at block %d, before epoch %q at block %d", epoch.Name, epoch.StartBlock, prev.Name, prev.StartBlock)
+		}
+	}
+	return nil
+}
+
+// RecipientsAt returns the recipient set in effect at the given block number. If
+// the config does not define any recipient sets, the recipients of the default
+// schedule are used.
+func (c *LibertyConfig) RecipientsAt(number uint64) *LibertyRecipientSet {
+	sets := c.Recipients
+	if len(sets) == 0 {
+		sets = DefaultLibertyConfig.Recipients
+	}
+	for i := len(sets) - 1; i >= 0; i-- {
+		if number >= sets[i].Block {
+			return &sets[i]
+		}
+	}
+	return nil
+}
+
+// CheckRecipients verifies that the recipient sets are well formed: the first
+// set must be active from genesis, activation blocks must be strictly ascending
+// and every recipient must have a positive weight and appear only once per list.
+func (c *LibertyConfig) CheckRecipients() error {
+	for i, set := range c.Recipients {
+		if i == 0 && set.Block != 0 {
+			return fmt.Errorf("liberty recipient set at block %d, first set must be active from genesis", set.Block)
+		}
+		if i > 0 && set.Block <= c.Recipients[i-1].Block {
+			return fmt.Errorf("liberty recipient set at block %d, after set at block %d", set.Block, c.Recipients[i-1].Block)
+		}
+		for _, list := range []struct {
+			name       string
+			recipients []LibertyRecipient
+		}{
+			{"devFund", set.DevFund},
+			{"staking", set.Staking},
+		} {
+			seen := make(map[common.Address]bool)
+			for _, recipient := range list.recipients {
+				if recipient.Weight == 0 {
+					return fmt.Errorf("liberty %s recipient %v at block %d has zero weight", list.name, recipient.Address, set.Block)
+				}
+				if seen[recipient.Address] {
+					return fmt.Errorf("liberty %s recipient %v at block %d listed twice", list.name, recipient.Address, set.Block)
+				}
+				seen[recipient.Address] = true
+			}
+		}
+	}
+	return nil
+}
+
+// IsUncleRewards returns whether num is either equal to the uncle reward fork
+// block or greater.
+func (c *LibertyConfig) IsUncleRewards(num *big.Int) bool {
+	return isBlockForked(c.UncleRewardBlock, num)
+}
+
+// NephewDivisor returns the divisor applied to the miner reward to calculate
+// the bonus a block receives for every uncle it includes.
+func (c *LibertyConfig) NephewDivisor() uint64 {
+	if c.NephewRewardDivisor == 0 {
+		return DefaultNephewRewardDivisor
+	}
+	return c.NephewRewardDivisor
+}
+
+// IsLibertyDifficulty returns whether num is either equal to the Liberty
+// difficulty adjustment fork block or greater.
+func (c *LibertyConfig) IsLibertyDifficulty(num *big.Int) bool {
+	return isBlockForked(c.DifficultyBlock, num)
+}
+
+// BlockTime returns the number of seconds the Liberty difficulty adjustment
+// aims to keep between consecutive blocks.
+func (c *LibertyConfig) BlockTime() uint64 {
+	if c.TargetBlockTime == 0 {
+		return DefaultTargetBlockTime
+	}
+	return c.TargetBlockTime
+}
+
+// Window returns the number of blocks worth of schedule drift that doubles or
+// halves the difficulty under the Liberty difficulty adjustment.
+func (c *LibertyConfig) Window() uint64 {
+	if c.DifficultyWindow == 0 {
+		return DefaultDifficultyWindow
+	}
+	return c.DifficultyWindow
+}
+
+// ForkBlocks returns the block numbers at which the liberty specific consensus
+// rules change, apart from the reward epochs.
+func (c *LibertyConfig) ForkBlocks() []uint64 {
+	var forks []uint64
+	for _, set := range c.Recipients {
+		forks = append(forks, set.Block)
+	}
+	if c.UncleRewardBlock != nil {
+		forks = append(forks, c.UncleRewardBlock.Uint64())
+	}
+	if c.DifficultyBlock != nil {
+		forks = append(forks, c.DifficultyBlock.Uint64())
+	}
+	return forks
+}
+
+// rewardsEqual returns whether two epochs issue identical rewards. The epoch
+// names are purely cosmetic and are not compared.
+func (e *LibertyEpoch) rewardsEqual(other *LibertyEpoch) bool {
+	return configBlockEqual(e.MinerReward, other.MinerReward) &&
+		configBlockEqual(e.StakingReward, other.StakingReward) &&
+		configBlockEqual(e.DevFund, other.DevFund)
+}
+
+// libertyScheduleDivergence returns the lowest block number from which the two
+// reward schedules issue different rewards, or nil if they are equivalent.
+func libertyScheduleDivergence(a, b *LibertyConfig) *big.Int {
+	boundaries := []uint64{0}
+	for _, epoch := range a.Epochs {
+		boundaries = append(boundaries, epoch.StartBlock)
+	}
+	for _, epoch := range b.Epochs {
+		boundaries = append(boundaries, epoch.StartBlock)
+	}
+	sort.Slice(boundaries, func(i, j int) bool { return boundaries[i] < boundaries[j] })
+
+	for _, number := range boundaries {
+		ea, eb := a.EpochAt(number), b.EpochAt(number)
+		if ea == nil || eb == nil {
+			if ea != eb {
+				return new(big.Int).SetUint64(number)
+			}
+			continue
+		}
+		if !ea.rewardsEqual(eb) {
+			return new(big.Int).SetUint64(number)
+		}
+	}
+	return nil
+}
+
+// recipientsEqual returns whether two recipient lists are identical, including
+// their order, which decides who receives the rounding remainder.
+func recipientsEqual(a, b []LibertyRecipient) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// libertyRecipientsDivergence returns the lowest block number from which the two
+// configs pay the dev-fund or staking rewards to different recipients, or nil if
+// they are equivalent.
+func libertyRecipientsDivergence(a, b *LibertyConfig) *big.Int {
+	boundaries := []uint64{0}
+	for _, set := range a.Recipients {
+		boundaries = append(boundaries, set.Block)
+	}
+	for _, set := range b.Recipients {
+		boundaries = append(boundaries, set.Block)
+	}
+	sort.Slice(boundaries, func(i, j int) bool { return boundaries[i] < boundaries[j] })
+
+	for _, number := range boundaries {
+		sa, sb := a.RecipientsAt(number), b.RecipientsAt(number)
+		if !recipientsEqual(sa.DevFund, sb.DevFund) || !recipientsEqual(sa.Staking, sb.Staking) {
+			return new(big.Int).SetUint64(number)
+		}
+	}
+	return nil
+}
+
+// LibertySchedule returns the block reward schedule of the chain, falling back
+// to the default schedule if the chain config does not define one.
+func (c *ChainConfig) LibertySchedule() *LibertyConfig {
+	if c.Liberty != nil {
+		return c.Liberty
+	}
+	return DefaultLibertyConfig
+}
+
+// coinsToWei converts a decimal coin amount into wei.
+func coinsToWei(coins string) *big.Int {
+	parts := strings.SplitN(coins, ".", 2)
+	frac := ""
+	if len(parts) > 1 {
+		frac = parts[1]
+	}
+	if len(frac) > 18 {
+		frac = frac[:18]
+	}
+	wei, ok := new(big.Int).SetString(parts[0]+frac+strings.Repeat("0", 18-len(frac)), 10)
+	if !ok {
+		panic("invalid coin amount: " + coins)
+	}
+	return wei
+}
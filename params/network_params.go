@@ -32,6 +32,14 @@ const (
 	// considered probably final and its rotated bits are calculated.
 	BloomConfirms = 256
 
+	// SupplyIndexBlocks is the number of blocks between two checkpoints of the
+	// total supply index.
+	SupplyIndexBlocks uint64 = 4096
+
+	// SupplyIndexConfirms is the number of confirmation blocks before a supply
+	// section is considered probably final and its checkpoint is written.
+	SupplyIndexConfirms = 256
+
 	// CHTFrequency is the block frequency for creating CHTs
 	CHTFrequency = 32768
 
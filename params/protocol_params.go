@@ -162,6 +162,8 @@ const (
 
 	BlobTxMinDataGasprice            = 1       // Minimum gas price for data blobs
 	BlobTxDataGaspriceUpdateFraction = 2225652 // Controls the maximum rate of change for data gas price
+
+	DefaultBlake3Iterations uint64 = 312688 // Number of chained Blake3 rounds in a proof-of-work seal, unless scheduled otherwise.
 )
 
 // Gas discount table for BLS12-381 G1 and G2 multi exponentiation operations
@@ -1,22 +1,54 @@
 package shared
 
 import (
+	"math/big"
+
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
 )
 
-// GetDeveloperAddresses returns the list of developer addresses
-func GetDeveloperAddresses() []common.Address {
-	return []common.Address{
-		common.HexToAddress("0x8c80A3F122Ea3e9E9b863b8535d33F3B96eE1C92"),
-		common.HexToAddress("0x2357EfDB1107eA9a316A32E33321FF405Eaff788"),
-		common.HexToAddress("0xE951BA28D6945AF6798D2e9463fec3e207A6CC06"),
-	}
+// Payout is a single balance credit issued as part of a block reward
+type Payout struct {
+	Address common.Address
+	Amount  *big.Int
+}
+
+// GetRecipients returns the dev-fund and staking recipients active at the block number
+func GetRecipients(config *params.ChainConfig, blockNumber uint64) *params.LibertyRecipientSet {
+	return config.LibertySchedule().RecipientsAt(blockNumber)
 }
 
-// GetStakingAddresses returns the list of staking addresses
-func GetStakingAddresses() []common.Address {
-	return []common.Address{
-		common.HexToAddress("0xc92Ca93e847CD42FD824eD17e29Bc9cb96417C08"),
-		common.HexToAddress("0xBE2c7A73BAE39B92320e932E12b91D8aBC28AfE3"),
+// DistributeReward splits the amount between the recipients proportionally to their
+// weights. The rounding remainder goes to the recipient with the highest weight, the
+// first listed one on a tie, which for equal weights is the first recipient.
+func DistributeReward(amount *big.Int, recipients []params.LibertyRecipient) []Payout {
+	if len(recipients) == 0 {
+		return nil
+	}
+	var (
+		total   = new(big.Int)
+		largest = 0
+	)
+	for i, recipient := range recipients {
+		total.Add(total, new(big.Int).SetUint64(recipient.Weight))
+		if recipient.Weight > recipients[largest].Weight {
+			largest = i
+		}
+	}
+	if total.Sign() == 0 {
+		return nil
+	}
+	var (
+		payouts   = make([]Payout, len(recipients))
+		remainder = new(big.Int).Set(amount)
+	)
+	for i, recipient := range recipients {
+		share := new(big.Int).Mul(amount, new(big.Int).SetUint64(recipient.Weight))
+		share.Div(share, total)
+
+		payouts[i] = Payout{Address: recipient.Address, Amount: share}
+		remainder.Sub(remainder, share)
 	}
+	payouts[largest].Amount.Add(payouts[largest].Amount, remainder)
+	return payouts
 }
@@ -0,0 +1,44 @@
+package shared
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestDistributeReward(t *testing.T) {
+	var (
+		alice = common.Address{0x01}
+		bob   = common.Address{0x02}
+		carol = common.Address{0x03}
+	)
+	tests := []struct {
+		amount     int64
+		recipients []params.LibertyRecipient
+		want       []int64
+	}{
+		// Equal weights keep the legacy split, remainder to the first recipient
+		{10, []params.LibertyRecipient{{Address: alice, Weight: 1}, {Address: bob, Weight: 1}, {Address: carol, Weight: 1}}, []int64{4, 3, 3}},
+		// Weighted split, remainder to the heaviest recipient
+		{10, []params.LibertyRecipient{{Address: alice, Weight: 1}, {Address: bob, Weight: 2}}, []int64{3, 7}},
+		{100, []params.LibertyRecipient{{Address: alice, Weight: 1}, {Address: bob, Weight: 3}}, []int64{25, 75}},
+		{0, []params.LibertyRecipient{{Address: alice, Weight: 1}}, []int64{0}},
+		{10, nil, nil},
+	}
+	for i, test := range tests {
+		payouts := DistributeReward(big.NewInt(test.amount), test.recipients)
+		if len(payouts) != len(test.want) {
+			t.Fatalf("test %d: payout count mismatch: have %d, want %d", i, len(payouts), len(test.want))
+		}
+		for j, payout := range payouts {
+			if payout.Address != test.recipients[j].Address {
+				t.Errorf("test %d, payout %d: address mismatch: have %v, want %v", i, j, payout.Address, test.recipients[j].Address)
+			}
+			if payout.Amount.Cmp(big.NewInt(test.want[j])) != 0 {
+				t.Errorf("test %d, payout %d: amount mismatch: have %v, want %v", i, j, payout.Amount, test.want[j])
+			}
+		}
+	}
+}